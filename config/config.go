@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 	"github.com/joho/godotenv"
@@ -11,11 +12,20 @@ import (
 type (
 	// Config -.
 	Config struct {
-		App  App
-		HTTP HTTP
-		Log  Log
-		PG   PG
-		RMQ  RMQ
+		App   App
+		HTTP  HTTP
+		Log   Log
+		PG    PG
+		RMQ   RMQ
+		JWT   JWT
+		Media Media
+		S3    S3
+		Feed  Feed
+
+		Reservation Reservation
+		Notify      Notify
+		Telegram    Telegram
+		Trash       Trash
 	}
 
 	// App -.
@@ -40,6 +50,68 @@ type (
 		URL     string `env:"PG_URL,required"`
 	}
 
+	// JWT -.
+	JWT struct {
+		Secret     string        `env:"JWT_SECRET,required"`
+		AccessTTL  time.Duration `env:"JWT_ACCESS_TTL"  envDefault:"15m"`
+		RefreshTTL time.Duration `env:"JWT_REFRESH_TTL" envDefault:"720h"`
+	}
+
+	// Media -.
+	Media struct {
+		Storage        string `env:"MEDIA_STORAGE"         envDefault:"local"`
+		Dir            string `env:"MEDIA_DIR"             envDefault:"./media"`
+		PublicURL      string `env:"MEDIA_PUBLIC_URL"      envDefault:"/v1/media"`
+		MaxSize        int64  `env:"MEDIA_MAX_SIZE"        envDefault:"10485760"`
+		ThumbnailSizes []int  `env:"MEDIA_THUMBNAIL_SIZES" envDefault:"160,480,1024"`
+	}
+
+	// S3 is used when MEDIA_STORAGE is s3.
+	S3 struct {
+		Endpoint  string `env:"S3_ENDPOINT"`
+		Region    string `env:"S3_REGION"`
+		Bucket    string `env:"S3_BUCKET"     envDefault:"media"`
+		AccessKey string `env:"S3_ACCESS_KEY"`
+		SecretKey string `env:"S3_SECRET_KEY"`
+		UseSSL    bool   `env:"S3_USE_SSL"    envDefault:"true"`
+	}
+
+	// Feed describes the shop in marketplace feeds. FEED_PRODUCT_URL is the
+	// product page link with {id} for the product ID, relative to FEED_SHOP_URL.
+	Feed struct {
+		ShopName   string `env:"FEED_SHOP_NAME"   envDefault:"AI Seller"`
+		Company    string `env:"FEED_COMPANY"     envDefault:"AI Seller"`
+		ShopURL    string `env:"FEED_SHOP_URL"    envDefault:"http://localhost:8080"`
+		ProductURL string `env:"FEED_PRODUCT_URL" envDefault:"/product/{id}"`
+	}
+
+	// Reservation sets how long stock stays reserved for an unpaid order and
+	// how often expired reservations are swept.
+	Reservation struct {
+		TTL           time.Duration `env:"RESERVATION_TTL"            envDefault:"30m"`
+		SweepInterval time.Duration `env:"RESERVATION_SWEEP_INTERVAL" envDefault:"1m"`
+	}
+
+	// Notify selects where low-stock alerts and back-in-stock messages go:
+	// log or telegram.
+	Notify struct {
+		Channel string `env:"NOTIFY_CHANNEL" envDefault:"log"`
+	}
+
+	// Telegram is used when NOTIFY_CHANNEL is telegram. Low-stock alerts go
+	// to TELEGRAM_MANAGER_CHAT_ID.
+	Telegram struct {
+		BotToken      string `env:"TELEGRAM_BOT_TOKEN"`
+		ManagerChatID string `env:"TELEGRAM_MANAGER_CHAT_ID"`
+	}
+
+	// Trash sets how long deleted products, categories and users can be
+	// restored and how often the expired ones are purged.
+	Trash struct {
+		Retention     time.Duration `env:"TRASH_RETENTION"      envDefault:"720h"`
+		PurgeInterval time.Duration `env:"TRASH_PURGE_INTERVAL" envDefault:"1h"`
+	}
+
 	// RMQ -.
 	RMQ struct {
 		ServerExchange string `env:"RMQ_RPC_SERVER,required"`
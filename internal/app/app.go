@@ -2,20 +2,34 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"ai-seller/config"
 	v1 "ai-seller/internal/controller/http"
+	"ai-seller/internal/entity"
+	"ai-seller/internal/repo"
 	"ai-seller/internal/repo/persistent"
 	"ai-seller/internal/usecase/product"
+	"ai-seller/pkg/hasher"
 	"ai-seller/pkg/httpserver"
 	"ai-seller/pkg/logger"
+	"ai-seller/pkg/notifier/lognotify"
+	"ai-seller/pkg/notifier/telegram"
 	"ai-seller/pkg/postgres"
+	"ai-seller/pkg/storage/local"
+	"ai-seller/pkg/storage/s3"
+	"ai-seller/pkg/token"
 )
 
+// _backgroundShutdownTimeout bounds how long shutdown waits for background
+// work such as notifications and imports.
+const _backgroundShutdownTimeout = 30 * time.Second
+
 // Run creates objects via constructors.
 func Run(cfg *config.Config) {
 	// Logger
@@ -28,13 +42,59 @@ func Run(cfg *config.Config) {
 	}
 	defer pg.Close()
 
+	// Media storage
+	media, err := newMediaStorage(cfg)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - newMediaStorage: %w", err))
+	}
+
+	// Notifier
+	notifier, err := newNotifier(cfg, l)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - newNotifier: %w", err))
+	}
+
 	// Use case
 	useCases := product.New(
 		persistent.NewAuthRepo(pg),
 		persistent.NewProductRepo(pg),
 		persistent.NewIntegrationRepo(pg),
+		hasher.New(),
+		token.New(cfg.JWT.Secret, token.AccessTTL(cfg.JWT.AccessTTL), token.RefreshTTL(cfg.JWT.RefreshTTL)),
+		pg,
+		media,
+		notifier,
+		product.Logger(l),
+		product.MediaMaxSize(cfg.Media.MaxSize),
+		product.MediaURL(cfg.Media.PublicURL),
+		product.ThumbnailSizes(cfg.Media.ThumbnailSizes...),
+		product.FeedSettings(entity.FeedSettings{
+			ShopName:   cfg.Feed.ShopName,
+			Company:    cfg.Feed.Company,
+			ShopURL:    cfg.Feed.ShopURL,
+			ProductURL: cfg.Feed.ProductURL,
+		}),
+		product.ReservationTTL(cfg.Reservation.TTL),
+		product.TrashRetention(cfg.Trash.Retention),
 	)
 
+	// Imports interrupted by the last shutdown
+	n, err := useCases.RecoverImportJobs(context.Background())
+	if err != nil {
+		l.Error(fmt.Errorf("app - Run - useCases.RecoverImportJobs: %w", err))
+	}
+
+	if n > 0 {
+		l.Warn("app - Run - failed interrupted import jobs: %d", n)
+	}
+
+	// Reservation and trash sweepers
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go sweepReservations(ctx, useCases, cfg.Reservation.SweepInterval, l)
+	go purgeTrash(ctx, useCases, cfg.Trash.PurgeInterval, l)
+
 	// HTTP Server
 	httpServer := httpserver.New(httpserver.Port(cfg.HTTP.Port))
 	v1.NewRouter(httpServer.Engine, l, useCases)
@@ -53,8 +113,51 @@ func Run(cfg *config.Config) {
 	}
 
 	// Shutdown
+	cancel()
+
 	err = httpServer.Shutdown()
 	if err != nil {
 		l.Error(fmt.Errorf("app - Run - httpServer.Shutdown: %w", err))
 	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), _backgroundShutdownTimeout)
+	defer shutdownCancel()
+
+	err = useCases.Shutdown(shutdownCtx)
+	if err != nil {
+		l.Error(fmt.Errorf("app - Run - useCases.Shutdown: %w", err))
+	}
+}
+
+func newMediaStorage(cfg *config.Config) (repo.MediaStorage, error) {
+	switch cfg.Media.Storage {
+	case "local":
+		return local.New(cfg.Media.Dir)
+	case "s3":
+		return s3.New(cfg.S3.Endpoint, cfg.S3.AccessKey, cfg.S3.SecretKey, cfg.S3.Bucket,
+			s3.Region(cfg.S3.Region), s3.UseSSL(cfg.S3.UseSSL))
+	default:
+		return nil, fmt.Errorf("unknown media storage %q", cfg.Media.Storage)
+	}
+}
+
+func newNotifier(cfg *config.Config, l logger.Interface) (repo.Notifier, error) {
+	switch cfg.Notify.Channel {
+	case "log":
+		return lognotify.New(l), nil
+	case "telegram":
+		if cfg.Telegram.BotToken == "" {
+			return nil, fmt.Errorf("TELEGRAM_BOT_TOKEN is required for notify channel telegram")
+		}
+
+		// Low-stock alerts have no chat of their own; without the managers'
+		// chat every one of them would fail and be retried.
+		if cfg.Telegram.ManagerChatID == "" {
+			return nil, fmt.Errorf("TELEGRAM_MANAGER_CHAT_ID is required for notify channel telegram")
+		}
+
+		return telegram.New(cfg.Telegram.BotToken, cfg.Telegram.ManagerChatID), nil
+	default:
+		return nil, fmt.Errorf("unknown notify channel %q", cfg.Notify.Channel)
+	}
 }
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ai-seller/internal/usecase/product"
+	"ai-seller/pkg/logger"
+)
+
+// sweepReservations cancels orders whose stock reservations expired every
+// interval until ctx is done.
+func sweepReservations(ctx context.Context, uc *product.UseCase, interval time.Duration, l logger.Interface) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := uc.ExpireReservations(ctx)
+			if err != nil {
+				l.Error(fmt.Errorf("app - sweepReservations - uc.ExpireReservations: %w", err))
+			}
+
+			if n > 0 {
+				l.Info("app - sweepReservations - expired orders: %d", n)
+			}
+		}
+	}
+}
+
+// purgeTrash removes the records whose retention in the trash has passed
+// every interval until ctx is done.
+func purgeTrash(ctx context.Context, uc *product.UseCase, interval time.Duration, l logger.Interface) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := uc.PurgeTrash(ctx)
+			if err != nil {
+				l.Error(fmt.Errorf("app - purgeTrash - uc.PurgeTrash: %w", err))
+			}
+
+			if purged.Total() > 0 {
+				l.Info("app - purgeTrash - purged products: %d, categories: %d, users: %d", purged.Products, purged.Categories, purged.Users)
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"ai-seller/internal/entity"
+	"ai-seller/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// UserIDKey -.
+	UserIDKey = "user_id"
+	// RoleIDKey -.
+	RoleIDKey = "role_id"
+
+	_bearerPrefix = "Bearer "
+)
+
+// Auth verifies the bearer access token and stores the caller in the context.
+func Auth(t usecase.Auth) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		header := ctx.GetHeader("Authorization")
+		if !strings.HasPrefix(header, _bearerPrefix) {
+			_ = ctx.Error(fmt.Errorf("http - middleware - Auth - missing bearer token: %w", entity.ErrInvalidToken))
+			ctx.Abort()
+
+			return
+		}
+
+		claims, err := t.VerifyAccessToken(strings.TrimPrefix(header, _bearerPrefix))
+		if err != nil {
+			_ = ctx.Error(fmt.Errorf("http - middleware - Auth: %w", err))
+			ctx.Abort()
+
+			return
+		}
+
+		ctx.Set(UserIDKey, claims.UserID)
+		ctx.Set(RoleIDKey, claims.RoleID)
+
+		ctx.Next()
+	}
+}
+
+// OptionalAuth lets anonymous callers through and authenticates the others
+// like Auth.
+func OptionalAuth(t usecase.Auth) gin.HandlerFunc {
+	auth := Auth(t)
+
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader("Authorization") == "" {
+			ctx.Next()
+
+			return
+		}
+
+		auth(ctx)
+	}
+}
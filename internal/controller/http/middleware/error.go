@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"ai-seller/internal/entity"
+	"ai-seller/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+const _problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 error body.
+type Problem struct {
+	Type     string `json:"type"               example:"about:blank"`
+	Title    string `json:"title"              example:"Not Found"`
+	Status   int    `json:"status"             example:"404"`
+	Detail   string `json:"detail,omitempty"   example:"resource not found"`
+	Instance string `json:"instance,omitempty" example:"/v1/product/0b6f3a8e-5a4c-4a55-8a3e-0e7f1f8f6b1a"`
+}
+
+// Errors translates the last error attached by a handler via ctx.Error into
+// an application/problem+json response.
+func Errors(l logger.Interface) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		last := ctx.Errors.Last()
+		if last == nil || ctx.Writer.Written() {
+			return
+		}
+
+		status, detail := classify(last)
+		if status >= http.StatusInternalServerError {
+			l.Error(last.Err, buildRequestMessage(ctx))
+		} else {
+			l.Debug(last.Err, buildRequestMessage(ctx))
+		}
+
+		writeProblem(ctx, status, detail)
+	}
+}
+
+func writeProblem(ctx *gin.Context, status int, detail string) {
+	ctx.Header("Content-Type", _problemContentType)
+	ctx.AbortWithStatusJSON(status, Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: ctx.Request.URL.Path,
+	})
+}
+
+func classify(e *gin.Error) (status int, detail string) {
+	err := e.Err
+
+	var validationErr *entity.ValidationError
+	if errors.As(err, &validationErr) {
+		return http.StatusUnprocessableEntity, validationErr.Error()
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		return http.StatusUnprocessableEntity, describeFieldErrors(fieldErrs)
+	}
+
+	if e.IsType(gin.ErrorTypeBind) {
+		return http.StatusBadRequest, "malformed request"
+	}
+
+	for _, m := range []struct {
+		target error
+		status int
+	}{
+		// Authentication failures come first: they must not tell whether
+		// the user or token exists.
+		{entity.ErrInvalidCredentials, http.StatusUnauthorized},
+		{entity.ErrInvalidToken, http.StatusUnauthorized},
+		{entity.ErrNotFound, http.StatusNotFound},
+		{entity.ErrConflict, http.StatusConflict},
+		{entity.ErrInUse, http.StatusConflict},
+		{entity.ErrInsufficientStock, http.StatusConflict},
+		{entity.ErrInvalidTransition, http.StatusConflict},
+		{entity.ErrPreconditionFailed, http.StatusPreconditionFailed},
+		{entity.ErrPreconditionRequired, http.StatusPreconditionRequired},
+		{entity.ErrTooLarge, http.StatusRequestEntityTooLarge},
+		{entity.ErrUnsupportedMedia, http.StatusUnsupportedMediaType},
+		{entity.ErrForeignKey, http.StatusUnprocessableEntity},
+		{entity.ErrValidation, http.StatusUnprocessableEntity},
+		{entity.ErrForbidden, http.StatusForbidden},
+	} {
+		if errors.Is(err, m.target) {
+			return m.status, m.target.Error()
+		}
+	}
+
+	return http.StatusInternalServerError, ""
+}
+
+func describeFieldErrors(errs validator.ValidationErrors) string {
+	parts := make([]string, 0, len(errs))
+	for _, fe := range errs {
+		parts = append(parts, fe.Field()+" failed on '"+fe.Tag()+"'")
+	}
+
+	return strings.Join(parts, "; ")
+}
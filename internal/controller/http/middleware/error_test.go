@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/internal/entity"
+)
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+
+	fieldErr := validator.New().Struct(struct {
+		Name string `validate:"required"`
+	}{})
+
+	tests := []struct {
+		name    string
+		err     error
+		errType gin.ErrorType
+		status  int
+		detail  string
+	}{
+		{
+			name:   "validation error",
+			err:    fmt.Errorf("ProductUseCase - CreateProduct: %w", entity.NewValidationError("cost", "must not be negative")),
+			status: http.StatusUnprocessableEntity,
+			detail: "cost: must not be negative",
+		},
+		{name: "field errors", err: fieldErr, status: http.StatusUnprocessableEntity, detail: "Name failed on 'required'"},
+		{name: "bind error", err: errors.New("invalid character"), errType: gin.ErrorTypeBind, status: http.StatusBadRequest, detail: "malformed request"},
+		{name: "invalid credentials", err: entity.ErrInvalidCredentials, status: http.StatusUnauthorized},
+		{name: "invalid token", err: entity.ErrInvalidToken, status: http.StatusUnauthorized},
+		{name: "not found", err: fmt.Errorf("ProductRepo - GetProduct: %w", entity.ErrNotFound), status: http.StatusNotFound},
+		{name: "conflict", err: entity.ErrConflict, status: http.StatusConflict},
+		{name: "in use", err: entity.ErrInUse, status: http.StatusConflict},
+		{name: "insufficient stock", err: entity.ErrInsufficientStock, status: http.StatusConflict},
+		{name: "invalid transition", err: entity.ErrInvalidTransition, status: http.StatusConflict},
+		{name: "precondition failed", err: entity.ErrPreconditionFailed, status: http.StatusPreconditionFailed},
+		{name: "precondition required", err: entity.ErrPreconditionRequired, status: http.StatusPreconditionRequired},
+		{name: "too large", err: entity.ErrTooLarge, status: http.StatusRequestEntityTooLarge},
+		{name: "unsupported media", err: entity.ErrUnsupportedMedia, status: http.StatusUnsupportedMediaType},
+		{name: "foreign key", err: entity.ErrForeignKey, status: http.StatusUnprocessableEntity},
+		{name: "forbidden", err: entity.ErrForbidden, status: http.StatusForbidden},
+		{
+			name:   "credentials win over not found",
+			err:    errors.Join(entity.ErrNotFound, entity.ErrInvalidCredentials),
+			status: http.StatusUnauthorized,
+		},
+		{name: "unknown", err: errors.New("connection refused"), status: http.StatusInternalServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			errType := tc.errType
+			if errType == 0 {
+				errType = gin.ErrorTypePrivate
+			}
+
+			status, detail := classify(&gin.Error{Err: tc.err, Type: errType})
+			require.Equal(t, tc.status, status)
+
+			switch {
+			case tc.detail != "":
+				require.Equal(t, tc.detail, detail)
+			case status == http.StatusInternalServerError:
+				require.Empty(t, detail)
+			default:
+				require.NotEmpty(t, detail)
+				require.NotContains(t, detail, " - ")
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"fmt"
+
+	"ai-seller/internal/entity"
+	"ai-seller/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Permission allows the request only if the caller's role holds permission.
+// It must be chained after Auth.
+func Permission(t usecase.Auth, permission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ok, err := t.HasPermission(ctx, ctx.GetString(RoleIDKey), permission)
+		if err != nil {
+			_ = ctx.Error(fmt.Errorf("http - middleware - Permission: %w", err))
+			ctx.Abort()
+
+			return
+		}
+
+		if !ok {
+			_ = ctx.Error(fmt.Errorf("http - middleware - Permission - %s: %w", permission, entity.ErrForbidden))
+			ctx.Abort()
+
+			return
+		}
+
+		ctx.Next()
+	}
+}
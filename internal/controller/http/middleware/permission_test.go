@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/internal/entity"
+	"ai-seller/internal/usecase"
+)
+
+// fakeAuth accepts the token "manager", whose role holds product.write, and
+// the token "broken", whose permissions cannot be looked up.
+type fakeAuth struct {
+	usecase.Auth
+}
+
+func (fakeAuth) VerifyAccessToken(accessToken string) (entity.AccessClaims, error) {
+	switch accessToken {
+	case "manager", "broken":
+		return entity.AccessClaims{UserID: "u1", RoleID: accessToken}, nil
+	default:
+		return entity.AccessClaims{}, entity.ErrInvalidToken
+	}
+}
+
+func (fakeAuth) HasPermission(_ context.Context, roleID, permission string) (bool, error) {
+	if roleID == "broken" {
+		return false, errors.New("connection refused")
+	}
+
+	return roleID == "manager" && permission == "product.write", nil
+}
+
+// nopLogger drops everything.
+type nopLogger struct{}
+
+func (nopLogger) Debug(interface{}, ...interface{}) {}
+func (nopLogger) Info(string, ...interface{})       {}
+func (nopLogger) Warn(string, ...interface{})       {}
+func (nopLogger) Error(interface{}, ...interface{}) {}
+func (nopLogger) Fatal(interface{}, ...interface{}) {}
+
+func TestPermission(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		header     string
+		permission string
+		status     int
+	}{
+		{name: "granted", header: "Bearer manager", permission: "product.write", status: http.StatusNoContent},
+		{name: "not granted", header: "Bearer manager", permission: "user.write", status: http.StatusForbidden},
+		{name: "lookup fails", header: "Bearer broken", permission: "product.write", status: http.StatusInternalServerError},
+		{name: "no token", permission: "product.write", status: http.StatusUnauthorized},
+		{name: "invalid token", header: "Bearer guest", permission: "product.write", status: http.StatusUnauthorized},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var reached bool
+
+			r := gin.New()
+			r.Use(Errors(nopLogger{}))
+			r.GET("/product", Auth(fakeAuth{}), Permission(fakeAuth{}, tc.permission), func(ctx *gin.Context) {
+				reached = true
+				ctx.Status(http.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/product", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tc.status, w.Code)
+			require.Equal(t, tc.status == http.StatusNoContent, reached)
+
+			if tc.status != http.StatusNoContent {
+				var p Problem
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+				require.Equal(t, tc.status, p.Status)
+				require.Equal(t, _problemContentType, w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
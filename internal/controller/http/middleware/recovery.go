@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"fmt"
+	"net/http"
 	"runtime/debug"
 	"strings"
 
@@ -32,9 +33,7 @@ func Recovery(l logger.Interface) gin.HandlerFunc {
 				l.Error(buildPanicMessage(c, err))
 
 				// Respond with 500 Internal Server Error
-				c.AbortWithStatusJSON(500, gin.H{
-					"message": "Internal Server Error",
-				})
+				writeProblem(c, http.StatusInternalServerError, "")
 			}
 		}()
 
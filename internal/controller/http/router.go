@@ -26,10 +26,14 @@ import (
 // @version     1.0
 // @host        localhost:8080
 // @BasePath    /v1
+// @securityDefinitions.apikey BearerAuth
+// @in          header
+// @name        Authorization
 func NewRouter(app *gin.Engine, l logger.Interface, t usecase.UseCases) {
 	// Options
 	app.Use(middleware.Logger(l))
 	app.Use(middleware.Recovery(l))
+	app.Use(middleware.Errors(l))
 
 	app.GET("/docs", func(ctx *gin.Context) {
 		htmlContent, err := scalar.ApiReferenceHTML(&scalar.Options{
@@ -58,10 +62,24 @@ func NewRouter(app *gin.Engine, l logger.Interface, t usecase.UseCases) {
 	// K8s probe
 	app.GET("/healthz", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
 
+	// Marketplace feeds
+	v1.NewFeedRoutes(&app.RouterGroup, t, l)
+
 	// Routers
 	apiV1Group := app.Group("/v1")
 	{
-		// v1.NewAuthRoutes(apiV1Group, t, l)
+		v1.NewAuthRoutes(apiV1Group, t, l)
+		v1.NewRoleRoutes(apiV1Group, t, l)
+		v1.NewUserRoutes(apiV1Group, t, l)
 		v1.NewProductRoutes(apiV1Group, t, l)
+		v1.NewCategoryRoutes(apiV1Group, t, l)
+		v1.NewAttributeRoutes(apiV1Group, t, l)
+		v1.NewCatalogRoutes(apiV1Group, t, l)
+		v1.NewOrderRoutes(apiV1Group, t, l)
+		v1.NewCartRoutes(apiV1Group, t, l)
+		v1.NewPromotionRoutes(apiV1Group, t, l)
+		v1.NewCurrencyRoutes(apiV1Group, t, l)
+		v1.NewWarehouseRoutes(apiV1Group, t, l)
+		v1.NewMediaRoutes(apiV1Group, t, l)
 	}
 }
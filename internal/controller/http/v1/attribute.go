@@ -0,0 +1,190 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-seller/internal/controller/http/middleware"
+	"ai-seller/internal/entity"
+	"ai-seller/internal/usecase"
+	"ai-seller/pkg/logger"
+)
+
+type attributeRoutes struct {
+	t usecase.UseCases
+	l logger.Interface
+}
+
+func NewAttributeRoutes(apiV1Group *gin.RouterGroup, t usecase.UseCases, l logger.Interface) {
+	r := &attributeRoutes{t, l}
+
+	auth := middleware.Auth(t)
+
+	attributeGroup := apiV1Group.Group("/attribute")
+	{
+		attributeGroup.GET("/", r.listAttributes)
+		attributeGroup.POST("/", auth, middleware.Permission(t, entity.PermissionProductCreate), r.createAttribute)
+		attributeGroup.GET("/:id", r.getAttribute)
+		attributeGroup.PUT("/:id", auth, middleware.Permission(t, entity.PermissionProductUpdate), r.updateAttribute)
+		attributeGroup.PATCH("/:id", auth, middleware.Permission(t, entity.PermissionProductUpdate), r.patchAttribute)
+	}
+}
+
+// @Summary     Create attribute
+// @Description Define a typed attribute for a category: string, number, enum (with options), boolean or unit (a number with a unit)
+// @ID          create-attribute
+// @Security    BearerAuth
+// @Tags  	    attribute
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.Attribute true "Attribute request"
+// @Success     201 {object} nil
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /attribute [post]
+func (r *attributeRoutes) createAttribute(ctx *gin.Context) {
+	var attribute entity.Attribute
+	if err := ctx.ShouldBindJSON(&attribute); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	err := r.t.CreateAttribute(ctx, attribute)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"message": "attribute created"})
+}
+
+// @Summary     List attributes
+// @Description Page through attributes with keyset pagination
+// @ID          list-attributes
+// @Tags  	    attribute
+// @Produce     json
+// @Param       category_id query string false "Category ID"
+// @Param       sort        query string false "Sort column, '-' prefix for descending" Enums(created_at, -created_at, updated_at, -updated_at, name, -name)
+// @Param       limit       query int    false "Page size, 100 at most"
+// @Param       cursor      query string false "next_cursor of the previous page"
+// @Success     200 {object} entity.Page[entity.Attribute]
+// @Failure     400 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /attribute [get]
+func (r *attributeRoutes) listAttributes(ctx *gin.Context) {
+	var filter entity.AttributeFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	page, err := r.t.ListAttributes(ctx, filter)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, page)
+}
+
+// @Summary     Get attribute
+// @Description Get a attribute by ID
+// @ID          get-attribute
+// @Tags  	    attribute
+// @Produce     json
+// @Param       id path string true "Attribute ID"
+// @Success     200 {object} entity.Attribute
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /attribute/{id} [get]
+func (r *attributeRoutes) getAttribute(ctx *gin.Context) {
+	attribute, err := r.t.GetAttribute(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, attribute)
+}
+
+// @Summary     Update attribute
+// @Description Replace a attribute by ID
+// @ID          update-attribute
+// @Security    BearerAuth
+// @Tags  	    attribute
+// @Accept      json
+// @Produce     json
+// @Param       id      path string           true "Attribute ID"
+// @Param       request body entity.Attribute true "Attribute request"
+// @Success     200 {object} entity.Attribute
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /attribute/{id} [put]
+func (r *attributeRoutes) updateAttribute(ctx *gin.Context) {
+	var attribute entity.Attribute
+	if err := ctx.ShouldBindJSON(&attribute); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	attribute.ID = ctx.Param("id")
+
+	err := r.t.UpdateAttribute(ctx, attribute)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	attribute, err = r.t.GetAttribute(ctx, attribute.ID)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, attribute)
+}
+
+// @Summary     Patch attribute
+// @Description Update only the fields present in a JSON Merge Patch (RFC 7396) document; null clears a field
+// @ID          patch-attribute
+// @Security    BearerAuth
+// @Tags  	    attribute
+// @Accept      json
+// @Produce     json
+// @Param       id      path string                true "Attribute ID"
+// @Param       request body entity.AttributePatch true "Merge patch"
+// @Success     200 {object} entity.Attribute
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /attribute/{id} [patch]
+func (r *attributeRoutes) patchAttribute(ctx *gin.Context) {
+	var patch entity.AttributePatch
+	if err := ctx.ShouldBindJSON(&patch); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	attribute, err := r.t.PatchAttribute(ctx, ctx.Param("id"), patch)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, attribute)
+}
@@ -1,99 +1,166 @@
 package v1
 
-// import (
-// 	"net/http"
-
-// 	"ai-seller/internal/entity"
-// 	"ai-seller/internal/usecase"
-// 	"ai-seller/pkg/logger"
-
-// 	"github.com/gin-gonic/gin"
-// 	"github.com/go-playground/validator/v10"
-// 	"github.com/gofiber/fiber/v2"
-// )
-
-// type authRoutes struct {
-// 	t usecase.Auth
-// 	l logger.Interface
-// 	v *validator.Validate
-// }
-
-// func NewAuthRoutes(apiV1Group *gin.RouterGroup, t usecase.Auth, l logger.Interface) {
-// 	r := &authRoutes{t, l, validator.New(validator.WithRequiredStructEnabled())}
-
-// 	authGroup := apiV1Group.Group("/auth")
-// 	{
-// 		authGroup.GET("/history", r.history)
-// 		authGroup.POST("/do-translate", r.doTranslate)
-// 	}
-// }
-
-
-// // @Summary     Show history
-// // @Description Show all translation history
-// // @ID          history
-// // @Tags  	    translation
-// // @Accept      json
-// // @Produce     json
-// // @Success     200 {object} historyResponse
-// // @Failure     500 {object} response
-// // @Router      /translation/history [get]
-// func (r *authRoutes) history(ctx *fiber.Ctx) error {
-// 	translations, err := r.t.CreateUser(ctx.UserContext())
-// 	if err != nil {
-// 		r.l.Error(err, "http - v1 - history")
-
-// 		return errorResponse(ctx, http.StatusInternalServerError, "database problems")
-// 	}
-
-// 	return ctx.Status(http.StatusOK).JSON(historyResponse{translations})
-// }
-
-// type doTranslateRequest struct {
-// 	Source      string `json:"source"       validate:"required"  example:"auto"`
-// 	Destination string `json:"destination"  validate:"required"  example:"en"`
-// 	Original    string `json:"original"     validate:"required"  example:"текст для перевода"`
-// }
-
-// // @Summary     Translate
-// // @Description Translate a text
-// // @ID          do-translate
-// // @Tags  	    translation
-// // @Accept      json
-// // @Produce     json
-// // @Param       request body doTranslateRequest true "Set up translation"
-// // @Success     200 {object} entity.Translation
-// // @Failure     400 {object} response
-// // @Failure     500 {object} response
-// // @Router      /translation/do-translate [post]
-// func (r *authRoutes) doTranslate(ctx *fiber.Ctx) error {
-// 	var request doTranslateRequest
-
-// 	if err := ctx.BodyParser(&request); err != nil {
-// 		r.l.Error(err, "http - v1 - doTranslate")
-
-// 		return errorResponse(ctx, http.StatusBadRequest, "invalid request body")
-// 	}
-
-// 	if err := r.v.Struct(request); err != nil {
-// 		r.l.Error(err, "http - v1 - doTranslate")
-
-// 		return errorResponse(ctx, http.StatusBadRequest, "invalid request body")
-// 	}
-
-// 	translation, err := r.t.CreateUser(
-// 		ctx.UserContext(),
-// 		entity.Translation{
-// 			Source:      request.Source,
-// 			Destination: request.Destination,
-// 			Original:    request.Original,
-// 		},
-// 	)
-// 	if err != nil {
-// 		r.l.Error(err, "http - v1 - doTranslate")
-
-// 		return errorResponse(ctx, http.StatusInternalServerError, "translation service problems")
-// 	}
-
-// 	return ctx.Status(http.StatusOK).JSON(translation)
-// }
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"ai-seller/internal/controller/http/middleware"
+	"ai-seller/internal/entity"
+	"ai-seller/internal/usecase"
+	"ai-seller/pkg/logger"
+)
+
+type authRoutes struct {
+	t usecase.Auth
+	l logger.Interface
+	v *validator.Validate
+}
+
+func NewAuthRoutes(apiV1Group *gin.RouterGroup, t usecase.Auth, l logger.Interface) {
+	r := &authRoutes{t, l, validator.New(validator.WithRequiredStructEnabled())}
+
+	authGroup := apiV1Group.Group("/auth")
+	{
+		authGroup.POST("/login", r.login)
+		authGroup.POST("/refresh", r.refresh)
+		authGroup.POST("/logout", r.logout)
+		authGroup.GET("/me", middleware.Auth(t), r.me)
+	}
+}
+
+// Response models for swagger.
+type (
+	tokensResponse entity.Tokens
+	userResponse   entity.User
+)
+
+type loginRequest struct {
+	Username string `json:"username" validate:"required" example:"johndoe"`
+	Password string `json:"password" validate:"required" example:"secret"`
+}
+
+// @Summary     Login
+// @Description Exchange username and password for an access and refresh token pair
+// @ID          login
+// @Tags  	    auth
+// @Accept      json
+// @Produce     json
+// @Param       request body loginRequest true "Credentials"
+// @Param       X-Cart-Session header string false "Anonymous cart session token to merge into the user's cart"
+// @Success     200 {object} tokensResponse
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     500 {object} problem
+// @Router      /auth/login [post]
+func (r *authRoutes) login(ctx *gin.Context) {
+	var request loginRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	tokens, err := r.t.Login(ctx, request.Username, request.Password, ctx.GetHeader(_cartSessionHeader))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// @Summary     Refresh tokens
+// @Description Rotate the refresh token and issue a new access token
+// @ID          refresh
+// @Tags  	    auth
+// @Accept      json
+// @Produce     json
+// @Param       request body refreshRequest true "Refresh token"
+// @Success     200 {object} tokensResponse
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     500 {object} problem
+// @Router      /auth/refresh [post]
+func (r *authRoutes) refresh(ctx *gin.Context) {
+	var request refreshRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	tokens, err := r.t.Refresh(ctx, request.RefreshToken)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+// @Summary     Logout
+// @Description Revoke the refresh token and every token rotated from it
+// @ID          logout
+// @Tags  	    auth
+// @Accept      json
+// @Produce     json
+// @Param       request body refreshRequest true "Refresh token"
+// @Success     204 {object} nil
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     500 {object} problem
+// @Router      /auth/logout [post]
+func (r *authRoutes) logout(ctx *gin.Context) {
+	var request refreshRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	err := r.t.Logout(ctx, request.RefreshToken)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary     Current user
+// @Description Show the authenticated user
+// @ID          me
+// @Tags  	    auth
+// @Produce     json
+// @Security    BearerAuth
+// @Success     200 {object} userResponse
+// @Failure     401 {object} problem
+// @Failure     500 {object} problem
+// @Router      /auth/me [get]
+func (r *authRoutes) me(ctx *gin.Context) {
+	user, err := r.t.GetUser(ctx, ctx.GetString(middleware.UserIDKey))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
@@ -0,0 +1,373 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"ai-seller/internal/controller/http/middleware"
+	"ai-seller/internal/entity"
+	"ai-seller/internal/usecase"
+	"ai-seller/pkg/logger"
+)
+
+const (
+	// _cartSessionHeader carries the session token of an anonymous cart. The
+	// token is issued by the server on the first item added without one.
+	_cartSessionHeader = "X-Cart-Session"
+
+	_maxCartSession = 128
+)
+
+type cartRoutes struct {
+	t usecase.UseCases
+	l logger.Interface
+	v *validator.Validate
+}
+
+func NewCartRoutes(apiV1Group *gin.RouterGroup, t usecase.UseCases, l logger.Interface) {
+	r := &cartRoutes{t, l, validator.New(validator.WithRequiredStructEnabled())}
+
+	cartGroup := apiV1Group.Group("/cart", middleware.OptionalAuth(t))
+	{
+		cartGroup.GET("/", r.getCart)
+		cartGroup.DELETE("/", r.clearCart)
+		cartGroup.POST("/item", r.addCartItem)
+		cartGroup.PUT("/item/:item_id", r.updateCartItem)
+		cartGroup.DELETE("/item/:item_id", r.removeCartItem)
+		cartGroup.POST("/merge", middleware.Auth(t), r.mergeCart)
+		cartGroup.POST("/checkout", middleware.Auth(t), r.checkoutCart)
+	}
+}
+
+// cartKey is the cart of the signed-in caller, or else of the anonymous
+// session token, if any.
+func cartKey(ctx *gin.Context) (entity.CartKey, error) {
+	if userID := ctx.GetString(middleware.UserIDKey); userID != "" {
+		return entity.CartKey{UserID: userID}, nil
+	}
+
+	session, err := cartSession(ctx)
+	if err != nil {
+		return entity.CartKey{}, err
+	}
+
+	return entity.CartKey{SessionID: session}, nil
+}
+
+func cartSession(ctx *gin.Context) (string, error) {
+	session := ctx.GetHeader(_cartSessionHeader)
+	if len(session) > _maxCartSession {
+		return "", entity.NewValidationError(_cartSessionHeader, "must be at most 128 characters")
+	}
+
+	return session, nil
+}
+
+// @Summary     Get cart
+// @Description Get the cart of the signed-in caller, or of the anonymous session without a bearer token. Prices, stock and discounts are checked live: an item that cannot be ordered as it is carries a problem and is left out of the totals. Amounts are in minor units of the cart currency.
+// @ID          get-cart
+// @Tags  	    cart
+// @Produce     json
+// @Security    BearerAuth
+// @Param       X-Cart-Session header string false "Anonymous cart session token"
+// @Param       currency       query  string false "Currency code, the base currency if omitted"
+// @Param       coupon         query  []string false "Coupon codes" collectionFormat(multi)
+// @Success     200 {object} entity.Cart
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /cart [get]
+func (r *cartRoutes) getCart(ctx *gin.Context) {
+	k, err := cartKey(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	var query entity.CartQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(query); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	cart, err := r.t.GetCart(ctx, k, query)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+// @Summary     Clear cart
+// @Description Remove every item from the cart
+// @ID          clear-cart
+// @Tags  	    cart
+// @Security    BearerAuth
+// @Param       X-Cart-Session header string false "Anonymous cart session token"
+// @Success     204
+// @Failure     401 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /cart [delete]
+func (r *cartRoutes) clearCart(ctx *gin.Context) {
+	k, err := cartKey(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	err = r.t.ClearCart(ctx, k)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary     Add cart item
+// @Description Put a product in the cart, creating the cart first. Adding an item already in the cart increases its count. The resulting count is checked against the available stock. An anonymous caller without a valid session token gets a new one in the X-Cart-Session header and as session_token, to be sent with every later cart request.
+// @ID          add-cart-item
+// @Tags  	    cart
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       X-Cart-Session header string          false "Anonymous cart session token"
+// @Param       request        body   entity.CartItem true  "Cart item"
+// @Success     200 {object} entity.Cart
+// @Header      200 {string} X-Cart-Session "Session token, when a new one was issued"
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /cart/item [post]
+func (r *cartRoutes) addCartItem(ctx *gin.Context) {
+	k, err := cartKey(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	var request entity.CartItem
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	cart, err := r.t.AddCartItem(ctx, k, request)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	if cart.SessionToken != "" {
+		ctx.Header(_cartSessionHeader, cart.SessionToken)
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+type cartItemCountRequest struct {
+	Count int `json:"count" validate:"gt=0" example:"2"`
+}
+
+// @Summary     Update cart item
+// @Description Set the count of a cart item, checked against the available stock
+// @ID          update-cart-item
+// @Tags  	    cart
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       X-Cart-Session header string               false "Anonymous cart session token"
+// @Param       item_id        path   string               true  "Cart item ID"
+// @Param       request        body   cartItemCountRequest true  "Count"
+// @Success     200 {object} entity.Cart
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /cart/item/{item_id} [put]
+func (r *cartRoutes) updateCartItem(ctx *gin.Context) {
+	k, err := cartKey(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	var request cartItemCountRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	cart, err := r.t.UpdateCartItem(ctx, k, ctx.Param("item_id"), request.Count)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+// @Summary     Remove cart item
+// @Description Take an item out of the cart
+// @ID          remove-cart-item
+// @Tags  	    cart
+// @Produce     json
+// @Security    BearerAuth
+// @Param       X-Cart-Session header string false "Anonymous cart session token"
+// @Param       item_id        path   string true  "Cart item ID"
+// @Success     200 {object} entity.Cart
+// @Failure     401 {object} problem
+// @Failure     404 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /cart/item/{item_id} [delete]
+func (r *cartRoutes) removeCartItem(ctx *gin.Context) {
+	k, err := cartKey(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	cart, err := r.t.RemoveCartItem(ctx, k, ctx.Param("item_id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+// @Summary     Merge cart
+// @Description Move the items of an anonymous session's cart into the caller's cart, adding up the counts of items in both. Login does this too when given the session token.
+// @ID          merge-cart
+// @Tags  	    cart
+// @Produce     json
+// @Security    BearerAuth
+// @Param       X-Cart-Session header string true "Anonymous cart session token"
+// @Success     200 {object} entity.Cart
+// @Failure     401 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /cart/merge [post]
+func (r *cartRoutes) mergeCart(ctx *gin.Context) {
+	session, err := cartSession(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	if session == "" {
+		errorResponse(ctx, entity.NewValidationError(_cartSessionHeader, "is required"))
+		return
+	}
+
+	userID := ctx.GetString(middleware.UserIDKey)
+
+	err = r.t.MergeCart(ctx, userID, session)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	cart, err := r.t.GetCart(ctx, entity.CartKey{UserID: userID}, entity.CartQuery{})
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+// @Summary     Check out cart
+// @Description Place an order of a cart, like placing an order of its items, and empty the cart. Without X-Cart-Session it is the caller's own cart, with it the cart of that session token. The order is for the caller unless user_id names another customer, which, like placing an order for anyone, takes the order:create permission; a chat seller checks out a chat's cart this way. Items that are unavailable or short of stock fail the checkout.
+// @ID          checkout-cart
+// @Tags  	    cart
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       X-Cart-Session header string              false "Anonymous cart session token"
+// @Param       request        body   entity.CartCheckout true  "Checkout request"
+// @Success     201 {object} entity.Order
+// @Header      201 {string} ETag "Row version"
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /cart/checkout [post]
+func (r *cartRoutes) checkoutCart(ctx *gin.Context) {
+	var request entity.CartCheckout
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	userID := ctx.GetString(middleware.UserIDKey)
+
+	if request.UserID != "" && request.UserID != userID {
+		ok, err := r.t.HasPermission(ctx, ctx.GetString(middleware.RoleIDKey), entity.PermissionOrderCreate)
+		if err != nil {
+			errorResponse(ctx, err)
+			return
+		}
+
+		if !ok {
+			errorResponse(ctx, fmt.Errorf("%s: %w", entity.PermissionOrderCreate, entity.ErrForbidden))
+			return
+		}
+	} else {
+		request.UserID = userID
+	}
+
+	session, err := cartSession(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	k := entity.CartKey{UserID: userID}
+	if session != "" {
+		k = entity.CartKey{SessionID: session}
+	}
+
+	order, err := r.t.CheckoutCart(ctx, k, request, userID)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, order.Version)
+	ctx.JSON(http.StatusCreated, order)
+}
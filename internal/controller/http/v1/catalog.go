@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-seller/internal/entity"
+	"ai-seller/internal/usecase"
+	"ai-seller/pkg/logger"
+)
+
+// _attrPrefix marks attribute filters: attr.<attribute_id>=<value>.
+const _attrPrefix = "attr."
+
+type catalogRoutes struct {
+	t usecase.UseCases
+	l logger.Interface
+}
+
+func NewCatalogRoutes(apiV1Group *gin.RouterGroup, t usecase.UseCases, l logger.Interface) {
+	r := &catalogRoutes{t, l}
+
+	catalogGroup := apiV1Group.Group("/catalog")
+	{
+		catalogGroup.GET("/search", r.searchCatalog)
+	}
+}
+
+// @Summary     Search catalog
+// @Description Faceted product search. Filter on attributes with attr.<attribute_id>=<value>; repeat the parameter to match any of several values. Number and unit attributes also take a min..max range, either end may be left out. Attribute filters require category_id. Each facet counts the results without its own filter.
+// @ID          search-catalog
+// @Tags  	    catalog
+// @Produce     json
+// @Param       category_id query string false "Category ID, required with attribute filters"
+// @Param       min_price   query int    false "Minimum price paid, discount included"
+// @Param       max_price   query int    false "Maximum price paid, discount included"
+// @Param       discounted  query bool   false "Only discounted (true) or full price (false) products"
+// @Param       in_stock    query bool   false "Only products in (true) or out of (false) stock"
+// @Param       buckets     query int    false "Number of price buckets, 5 by default and 20 at most"
+// @Param       sort        query string false "Sort column, '-' prefix for descending" Enums(created_at, -created_at, price, -price, name, -name)
+// @Param       limit       query int    false "Page size, 100 at most"
+// @Param       cursor      query string false "next_cursor of the previous page"
+// @Success     200 {object} entity.CatalogResult
+// @Failure     400 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /catalog/search [get]
+func (r *catalogRoutes) searchCatalog(ctx *gin.Context) {
+	var query entity.CatalogQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	query.Attributes = attributeConditions(ctx)
+
+	result, err := r.t.SearchCatalog(ctx, query)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// attributeConditions collects the attr.* query parameters, ordered by
+// attribute ID so that equal queries build equal SQL.
+func attributeConditions(ctx *gin.Context) []entity.AttributeCondition {
+	var conditions []entity.AttributeCondition
+
+	for key, values := range ctx.Request.URL.Query() {
+		id, ok := strings.CutPrefix(key, _attrPrefix)
+		if !ok || id == "" {
+			continue
+		}
+
+		conditions = append(conditions, entity.AttributeCondition{AttributeID: id, Values: values})
+	}
+
+	sort.Slice(conditions, func(i, j int) bool { return conditions[i].AttributeID < conditions[j].AttributeID })
+
+	return conditions
+}
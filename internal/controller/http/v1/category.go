@@ -0,0 +1,473 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"ai-seller/internal/controller/http/middleware"
+	"ai-seller/internal/entity"
+	"ai-seller/internal/usecase"
+	"ai-seller/pkg/logger"
+)
+
+type categoryRoutes struct {
+	t usecase.UseCases
+	l logger.Interface
+	v *validator.Validate
+}
+
+func NewCategoryRoutes(apiV1Group *gin.RouterGroup, t usecase.UseCases, l logger.Interface) {
+	r := &categoryRoutes{t, l, validator.New(validator.WithRequiredStructEnabled())}
+
+	auth := middleware.Auth(t)
+
+	categoryGroup := apiV1Group.Group("/category")
+	{
+		categoryGroup.GET("/", r.listCategories)
+		categoryGroup.POST("/", auth, middleware.Permission(t, entity.PermissionProductCreate), r.createCategory)
+		categoryGroup.GET("/tree", r.getCategoryTree)
+		categoryGroup.GET("/trash", auth, middleware.Permission(t, entity.PermissionProductDelete), r.listDeletedCategories)
+		categoryGroup.PUT("/children", auth, middleware.Permission(t, entity.PermissionProductUpdate), r.reorderRootCategories)
+		categoryGroup.GET("/:id", r.getCategory)
+		categoryGroup.GET("/:id/tree", r.getCategorySubtree)
+		categoryGroup.POST("/:id/move", auth, middleware.Permission(t, entity.PermissionProductUpdate), r.moveCategory)
+		categoryGroup.PUT("/:id/children", auth, middleware.Permission(t, entity.PermissionProductUpdate), r.reorderCategories)
+		categoryGroup.GET("/:id/feed-mapping", r.getFeedMapping)
+		categoryGroup.PUT("/:id/feed-mapping", auth, middleware.Permission(t, entity.PermissionProductUpdate), r.setFeedMapping)
+		categoryGroup.PUT("/:id", auth, middleware.Permission(t, entity.PermissionProductUpdate), r.updateCategory)
+		categoryGroup.PATCH("/:id", auth, middleware.Permission(t, entity.PermissionProductUpdate), r.patchCategory)
+		categoryGroup.DELETE("/:id", auth, middleware.Permission(t, entity.PermissionProductDelete), r.deleteCategory)
+		categoryGroup.POST("/:id/restore", auth, middleware.Permission(t, entity.PermissionProductDelete), r.restoreCategory)
+	}
+}
+
+// @Summary     List categories
+// @Description Page through categories with keyset pagination
+// @ID          list-categories
+// @Tags  	    category
+// @Produce     json
+// @Param       parent_id    query string false "Parent category ID"
+// @Param       root         query bool   false "Only top level categories"
+// @Param       created_from query string false "Created at or after (RFC 3339)"
+// @Param       created_to   query string false "Created before (RFC 3339)"
+// @Param       sort         query string false "Sort column, '-' prefix for descending" Enums(created_at, -created_at, updated_at, -updated_at, name, -name, position, -position)
+// @Param       limit        query int    false "Page size, 100 at most"
+// @Param       cursor       query string false "next_cursor of the previous page"
+// @Success     200 {object} entity.Page[entity.Category]
+// @Failure     400 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /category [get]
+func (r *categoryRoutes) listCategories(ctx *gin.Context) {
+	var filter entity.CategoryFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	page, err := r.t.ListCategories(ctx, filter)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, page)
+}
+
+// @Summary     Create category
+// @Description Create a category, optionally under a parent; it is placed after its last sibling
+// @ID          create-category
+// @Security    BearerAuth
+// @Tags  	    category
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.Category true "Category request"
+// @Success     201 {object} nil
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /category [post]
+func (r *categoryRoutes) createCategory(ctx *gin.Context) {
+	var category entity.Category
+	if err := ctx.ShouldBindJSON(&category); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	err := r.t.CreateCategory(ctx, category)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"message": "category created"})
+}
+
+// @Summary     Get category tree
+// @Description Get all categories as a tree of root categories, children ordered by position
+// @ID          get-category-tree
+// @Tags  	    category
+// @Produce     json
+// @Success     200 {array}  entity.CategoryNode
+// @Failure     500 {object} problem
+// @Router      /category/tree [get]
+func (r *categoryRoutes) getCategoryTree(ctx *gin.Context) {
+	r.categoryTree(ctx, "")
+}
+
+// @Summary     Get category subtree
+// @Description Get a category with all its descendants, children ordered by position
+// @ID          get-category-subtree
+// @Tags  	    category
+// @Produce     json
+// @Param       id path string true "Category ID"
+// @Success     200 {array}  entity.CategoryNode
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /category/{id}/tree [get]
+func (r *categoryRoutes) getCategorySubtree(ctx *gin.Context) {
+	r.categoryTree(ctx, ctx.Param("id"))
+}
+
+func (r *categoryRoutes) categoryTree(ctx *gin.Context, rootID string) {
+	tree, err := r.t.GetCategoryTree(ctx, rootID)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tree)
+}
+
+// @Summary     Get category
+// @Description Get a category by ID
+// @ID          get-category
+// @Tags  	    category
+// @Produce     json
+// @Param       id path string true "Category ID"
+// @Success     200 {object} entity.Category
+// @Header      200 {string} ETag "Row version"
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /category/{id} [get]
+func (r *categoryRoutes) getCategory(ctx *gin.Context) {
+	category, err := r.t.GetCategory(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, category.Version)
+	ctx.JSON(http.StatusOK, category)
+}
+
+// @Summary     Update category
+// @Description Replace a category by ID
+// @ID          update-category
+// @Security    BearerAuth
+// @Tags  	    category
+// @Accept      json
+// @Produce     json
+// @Param       id       path   string          true "Category ID"
+// @Param       request  body   entity.Category true "Category request"
+// @Param       If-Match header string          true "ETag of the last read"
+// @Success     200 {object} entity.Category
+// @Header      200 {string} ETag "Row version"
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     412 {object} problem
+// @Failure     422 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
+// @Router      /category/{id} [put]
+func (r *categoryRoutes) updateCategory(ctx *gin.Context) {
+	var category entity.Category
+	if err := ctx.ShouldBindJSON(&category); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	category.ID = ctx.Param("id")
+
+	version, err := ifMatch(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	category.Version = version
+
+	err = r.t.UpdateCategory(ctx, category)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	category, err = r.t.GetCategory(ctx, category.ID)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, category.Version)
+	ctx.JSON(http.StatusOK, category)
+}
+
+// @Summary     Patch category
+// @Description Update only the fields present in a JSON Merge Patch (RFC 7396) document; null clears a field
+// @ID          patch-category
+// @Security    BearerAuth
+// @Tags  	    category
+// @Accept      json
+// @Produce     json
+// @Param       id       path   string               true "Category ID"
+// @Param       request  body   entity.CategoryPatch true "Merge patch"
+// @Param       If-Match header string               true "ETag of the last read"
+// @Success     200 {object} entity.Category
+// @Header      200 {string} ETag "Row version"
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     412 {object} problem
+// @Failure     422 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
+// @Router      /category/{id} [patch]
+func (r *categoryRoutes) patchCategory(ctx *gin.Context) {
+	var patch entity.CategoryPatch
+	if err := ctx.ShouldBindJSON(&patch); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	version, err := ifMatch(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	patch.Version = version
+
+	category, err := r.t.PatchCategory(ctx, ctx.Param("id"), patch)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, category.Version)
+	ctx.JSON(http.StatusOK, category)
+}
+
+// @Summary     Delete category
+// @Description Move a category to the trash. A category with subcategories, products or attributes cannot be deleted. It can be restored until the retention window has passed.
+// @ID          delete-category
+// @Security    BearerAuth
+// @Tags  	    category
+// @Produce     json
+// @Param       id       path   string true "Category ID"
+// @Param       If-Match header string true "ETag of the last read"
+// @Success     204 {object} nil
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     412 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
+// @Router      /category/{id} [delete]
+func (r *categoryRoutes) deleteCategory(ctx *gin.Context) {
+	version, err := ifMatch(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	err = r.t.DeleteCategory(ctx, ctx.Param("id"), version)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary     List deleted categories
+// @Description Page through the categories in the trash, most recently deleted first
+// @ID          list-deleted-categories
+// @Security    BearerAuth
+// @Tags  	    category
+// @Produce     json
+// @Param       sort   query string false "Sort column, '-' prefix for descending" Enums(deleted_at, -deleted_at, name, -name)
+// @Param       limit  query int    false "Page size, 100 at most"
+// @Param       cursor query string false "next_cursor of the previous page"
+// @Success     200 {object} entity.Page[entity.Category]
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /category/trash [get]
+func (r *categoryRoutes) listDeletedCategories(ctx *gin.Context) {
+	var params entity.ListParams
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	page, err := r.t.ListDeletedCategories(ctx, params)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, page)
+}
+
+// @Summary     Restore category
+// @Description Take a category out of the trash and place it after its siblings. Its parent has to be restored first; a name taken among its siblings in the meantime is a conflict.
+// @ID          restore-category
+// @Security    BearerAuth
+// @Tags  	    category
+// @Produce     json
+// @Param       id path string true "Category ID"
+// @Success     200 {object} entity.Category
+// @Header      200 {string} ETag "Row version"
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /category/{id}/restore [post]
+func (r *categoryRoutes) restoreCategory(ctx *gin.Context) {
+	category, err := r.t.RestoreCategory(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, category.Version)
+	ctx.JSON(http.StatusOK, category)
+}
+
+// @Summary     Move category
+// @Description Move a category under another parent, or to the root when parent_id is empty, at a position among its new siblings. Use the current parent to reorder. Moving under itself or a descendant is rejected.
+// @ID          move-category
+// @Security    BearerAuth
+// @Tags  	    category
+// @Accept      json
+// @Produce     json
+// @Param       id       path   string              true "Category ID"
+// @Param       request  body   entity.CategoryMove true "Target"
+// @Param       If-Match header string              true "ETag of the last read"
+// @Success     200 {object} entity.Category
+// @Header      200 {string} ETag "Row version"
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     412 {object} problem
+// @Failure     422 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
+// @Router      /category/{id}/move [post]
+func (r *categoryRoutes) moveCategory(ctx *gin.Context) {
+	var request entity.CategoryMove
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	version, err := ifMatch(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	request.Version = version
+
+	category, err := r.t.MoveCategory(ctx, ctx.Param("id"), request)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, category.Version)
+	ctx.JSON(http.StatusOK, category)
+}
+
+// @Summary     Reorder root categories
+// @Description Set the order of the root categories. Every root category must be listed exactly once.
+// @ID          reorder-root-categories
+// @Security    BearerAuth
+// @Tags  	    category
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.CategoryOrder true "Root categories in their new order"
+// @Success     200 {array}  entity.Category
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /category/children [put]
+func (r *categoryRoutes) reorderRootCategories(ctx *gin.Context) {
+	r.reorder(ctx, "")
+}
+
+// @Summary     Reorder categories
+// @Description Set the order of the children of a category. Every child must be listed exactly once.
+// @ID          reorder-categories
+// @Security    BearerAuth
+// @Tags  	    category
+// @Accept      json
+// @Produce     json
+// @Param       id      path string               true "Parent category ID"
+// @Param       request body entity.CategoryOrder true "Children in their new order"
+// @Success     200 {array}  entity.Category
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /category/{id}/children [put]
+func (r *categoryRoutes) reorderCategories(ctx *gin.Context) {
+	r.reorder(ctx, ctx.Param("id"))
+}
+
+func (r *categoryRoutes) reorder(ctx *gin.Context, parentID string) {
+	var request entity.CategoryOrder
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	children, err := r.t.ReorderCategories(ctx, parentID, request)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, children)
+}
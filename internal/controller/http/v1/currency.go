@@ -0,0 +1,154 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"ai-seller/internal/controller/http/middleware"
+	"ai-seller/internal/entity"
+	"ai-seller/internal/usecase"
+	"ai-seller/pkg/logger"
+)
+
+type currencyRoutes struct {
+	t usecase.UseCases
+	l logger.Interface
+	v *validator.Validate
+}
+
+func NewCurrencyRoutes(apiV1Group *gin.RouterGroup, t usecase.UseCases, l logger.Interface) {
+	r := &currencyRoutes{t, l, validator.New(validator.WithRequiredStructEnabled())}
+
+	auth, manage := middleware.Auth(t), middleware.Permission(t, entity.PermissionCurrencyManage)
+
+	apiV1Group.GET("/currency", r.listCurrencies)
+
+	rateGroup := apiV1Group.Group("/exchange-rate")
+	{
+		rateGroup.GET("/", r.listExchangeRates)
+		rateGroup.POST("/", auth, manage, r.setExchangeRate)
+		rateGroup.POST("/import", auth, manage, r.importExchangeRates)
+	}
+}
+
+// @Summary     List currencies
+// @Description List the currencies orders can be placed in, the base currency of product costs first. Amounts are integers in minor units, minor_units being the number of decimal places.
+// @ID          list-currencies
+// @Tags  	    currency
+// @Produce     json
+// @Success     200 {array}  entity.Currency
+// @Failure     500 {object} problem
+// @Router      /currency [get]
+func (r *currencyRoutes) listCurrencies(ctx *gin.Context) {
+	currencies, err := r.t.ListCurrencies(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, currencies)
+}
+
+// @Summary     List exchange rates
+// @Description Page through the exchange rate history, rates taking effect later included
+// @ID          list-exchange-rates
+// @Tags  	    currency
+// @Produce     json
+// @Param       currency query string false "Currency code"
+// @Param       sort     query string false "Sort column, '-' prefix for descending" Enums(effective_at, -effective_at, created_at, -created_at)
+// @Param       limit    query int    false "Page size, 100 at most"
+// @Param       cursor   query string false "next_cursor of the previous page"
+// @Success     200 {object} entity.Page[entity.ExchangeRate]
+// @Failure     400 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /exchange-rate [get]
+func (r *currencyRoutes) listExchangeRates(ctx *gin.Context) {
+	var filter entity.ExchangeRateFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	page, err := r.t.ListExchangeRates(ctx, filter)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, page)
+}
+
+// @Summary     Set exchange rate
+// @Description Record the price of one unit of a currency in the base currency. It applies to orders placed from effective_at on, now by default; placed orders keep the rate they were priced at.
+// @ID          set-exchange-rate
+// @Security    BearerAuth
+// @Tags  	    currency
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.NewExchangeRate true "Exchange rate request"
+// @Success     201 {object} entity.ExchangeRate
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /exchange-rate [post]
+func (r *currencyRoutes) setExchangeRate(ctx *gin.Context) {
+	var request entity.NewExchangeRate
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	rate, err := r.t.SetExchangeRate(ctx, request, ctx.GetString(middleware.UserIDKey))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, rate)
+}
+
+// @Summary     Import exchange rates
+// @Description Record a batch of rates taken from an outside source, such as a central bank feed. The batch is stored as a whole or not at all.
+// @ID          import-exchange-rates
+// @Security    BearerAuth
+// @Tags  	    currency
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.ExchangeRateImport true "Exchange rates"
+// @Success     201 {array}  entity.ExchangeRate
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /exchange-rate/import [post]
+func (r *currencyRoutes) importExchangeRates(ctx *gin.Context) {
+	var request entity.ExchangeRateImport
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	rates, err := r.t.ImportExchangeRates(ctx, request, ctx.GetString(middleware.UserIDKey))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, rates)
+}
@@ -1,13 +1,21 @@
 package v1
 
 import (
-	"github.com/gofiber/fiber/v2"
+	"github.com/gin-gonic/gin"
+
+	"ai-seller/internal/controller/http/middleware"
 )
 
-type response struct {
-	Error string `json:"error" example:"message"`
+// problem documents the body written by middleware.Errors.
+type problem middleware.Problem
+
+// errorResponse hands err over to middleware.Errors, which maps it to a status
+// code and writes an application/problem+json body.
+func errorResponse(ctx *gin.Context, err error) {
+	_ = ctx.Error(err)
 }
 
-func errorResponse(ctx *fiber.Ctx, code int, msg string) error {
-	return ctx.Status(code).JSON(response{msg})
+// bindErrorResponse is errorResponse for request decoding and validation failures.
+func bindErrorResponse(ctx *gin.Context, err error) {
+	_ = ctx.Error(err).SetType(gin.ErrorTypeBind)
 }
@@ -0,0 +1,36 @@
+package v1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-seller/internal/entity"
+)
+
+// setETag exposes the row version of the returned representation.
+func setETag(ctx *gin.Context, version int) {
+	ctx.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatch returns the row version the client last saw, taken from the
+// If-Match header. "*" matches any version and yields 0.
+func ifMatch(ctx *gin.Context) (int, error) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" {
+		return 0, entity.ErrPreconditionRequired
+	}
+
+	if header == "*" {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(header, "W/"), `"`))
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("If-Match %s: %w", header, entity.ErrPreconditionFailed)
+	}
+
+	return version, nil
+}
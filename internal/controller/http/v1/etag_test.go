@@ -0,0 +1,84 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/internal/entity"
+)
+
+func testContext(t *testing.T, header http.Header) *gin.Context {
+	t.Helper()
+
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodPatch, "/", http.NoBody)
+
+	for k, v := range header {
+		ctx.Request.Header[k] = v
+	}
+
+	return ctx
+}
+
+func TestIfMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		header  []string
+		version int
+		err     error
+	}{
+		{name: "missing", header: nil, err: entity.ErrPreconditionRequired},
+		{name: "blank", header: []string{"  "}, err: entity.ErrPreconditionRequired},
+		{name: "any", header: []string{"*"}, version: 0},
+		{name: "quoted", header: []string{`"3"`}, version: 3},
+		{name: "weak", header: []string{`W/"7"`}, version: 7},
+		{name: "unquoted", header: []string{"12"}, version: 12},
+		{name: "spaces", header: []string{` "4" `}, version: 4},
+		{name: "zero", header: []string{`"0"`}, err: entity.ErrPreconditionFailed},
+		{name: "negative", header: []string{`"-1"`}, err: entity.ErrPreconditionFailed},
+		{name: "not a number", header: []string{`"abc"`}, err: entity.ErrPreconditionFailed},
+		{name: "list", header: []string{`"1", "2"`}, err: entity.ErrPreconditionFailed},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := http.Header{}
+			if tc.header != nil {
+				header["If-Match"] = tc.header
+			}
+
+			version, err := ifMatch(testContext(t, header))
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.version, version)
+		})
+	}
+}
+
+func TestSetETag(t *testing.T) {
+	t.Parallel()
+
+	ctx := testContext(t, nil)
+	setETag(ctx, 5)
+
+	etag := ctx.Writer.Header().Get("ETag")
+	require.Equal(t, `"5"`, etag)
+
+	// What is sent back as If-Match is what setETag wrote.
+	version, err := ifMatch(testContext(t, http.Header{"If-Match": {etag}}))
+	require.NoError(t, err)
+	require.Equal(t, 5, version)
+}
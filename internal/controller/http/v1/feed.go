@@ -0,0 +1,124 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-seller/internal/entity"
+	"ai-seller/internal/usecase"
+	"ai-seller/pkg/logger"
+)
+
+// _feedCacheControl lets marketplaces and proxies keep a feed for a few
+// minutes and revalidate it with If-None-Match afterwards.
+const _feedCacheControl = "public, max-age=300"
+
+var _feedContentTypes = map[string]string{
+	entity.FeedGoogle:   "application/xml; charset=utf-8",
+	entity.FeedFacebook: "text/csv; charset=utf-8",
+	entity.FeedYandex:   "application/xml; charset=utf-8",
+}
+
+type feedRoutes struct {
+	t usecase.UseCases
+	l logger.Interface
+}
+
+// NewFeedRoutes serves the marketplace feeds. Marketplaces fetch them by
+// fixed URLs, so they live outside the versioned API.
+func NewFeedRoutes(handler *gin.RouterGroup, t usecase.UseCases, l logger.Interface) {
+	r := &feedRoutes{t, l}
+
+	feedGroup := handler.Group("/feeds")
+	{
+		feedGroup.GET("/google.xml", r.getFeed(entity.FeedGoogle))
+		feedGroup.GET("/facebook.csv", r.getFeed(entity.FeedFacebook))
+		feedGroup.GET("/yandex.yml", r.getFeed(entity.FeedYandex))
+	}
+}
+
+func (r *feedRoutes) getFeed(format string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		feed, err := r.t.GetFeed(ctx, format)
+		if err != nil {
+			errorResponse(ctx, err)
+			return
+		}
+
+		etag := strconv.Quote(feed.Version)
+
+		ctx.Header("ETag", etag)
+		ctx.Header("Cache-Control", _feedCacheControl)
+		ctx.Header("Last-Modified", feed.GeneratedAt.UTC().Format(http.TimeFormat))
+
+		if strings.Contains(ctx.GetHeader("If-None-Match"), etag) {
+			ctx.Status(http.StatusNotModified)
+			return
+		}
+
+		ctx.Data(http.StatusOK, _feedContentTypes[format], feed.Data)
+	}
+}
+
+// @Summary     Get category feed mapping
+// @Description Show the Google and Facebook taxonomy categories of a category. Empty values mean the category uses the mapping of its nearest mapped ancestor in feeds.
+// @ID          get-category-feed-mapping
+// @Tags  	    category
+// @Produce     json
+// @Param       id path string true "Category ID"
+// @Success     200 {object} entity.CategoryFeedMapping
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /category/{id}/feed-mapping [get]
+func (r *categoryRoutes) getFeedMapping(ctx *gin.Context) {
+	mapping, err := r.t.GetCategoryFeedMapping(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, mapping)
+}
+
+// @Summary     Set category feed mapping
+// @Description Set the Google product category and Facebook product category used for the category and, unless they have their own, its subcategories in marketplace feeds
+// @ID          set-category-feed-mapping
+// @Security    BearerAuth
+// @Tags  	    category
+// @Accept      json
+// @Produce     json
+// @Param       id      path string                     true "Category ID"
+// @Param       request body entity.CategoryFeedMapping true "Feed mapping"
+// @Success     200 {object} entity.CategoryFeedMapping
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /category/{id}/feed-mapping [put]
+func (r *categoryRoutes) setFeedMapping(ctx *gin.Context) {
+	var request entity.CategoryFeedMapping
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	request.CategoryID = ctx.Param("id")
+
+	mapping, err := r.t.SetCategoryFeedMapping(ctx, request)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, mapping)
+}
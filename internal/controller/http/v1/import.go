@@ -0,0 +1,139 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-seller/internal/controller/http/middleware"
+	"ai-seller/internal/entity"
+)
+
+var _catalogContentTypes = map[string]string{
+	entity.CatalogFormatCSV:  "text/csv; charset=utf-8",
+	entity.CatalogFormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// @Summary     Import products
+// @Description Upload a CSV or XLSX catalog file; the format is detected from the content. The file is checked right away and imported in the background in batches; poll the returned job for progress and per-row errors.
+// @Description Rows are matched to products by sku, or by name when they have no sku, and create a product when nothing matches. Catalog columns are sku, name, category, short_info, description, cost, count, discount_cost, discount and attribute:<name>. Categories are paths such as "Electronics / Phones"; missing categories and attributes are created. Empty cells leave a field unchanged.
+// @ID          import-products
+// @Security    BearerAuth
+// @Tags  	    product
+// @Accept      multipart/form-data
+// @Produce     json
+// @Param       file    formData file   true  "Catalog file"
+// @Param       mapping formData string false "JSON object mapping catalog columns to file headers, e.g. {\"name\":\"Title\",\"cost\":\"Price\"}; by default headers named after catalog columns are used"
+// @Success     202 {object} entity.ImportJob
+// @Header      202 {string} Location "Job status URL"
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     413 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/import [post]
+func (r *productRoutes) importProducts(ctx *gin.Context) {
+	fh, err := ctx.FormFile("file")
+	if err != nil {
+		errorResponse(ctx, entity.NewValidationError("file", "is required"))
+		return
+	}
+
+	request := entity.CatalogImport{
+		FileName:  fh.Filename,
+		CreatedBy: ctx.GetString(middleware.UserIDKey),
+	}
+
+	if mapping := ctx.PostForm("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &request.Mapping); err != nil {
+			errorResponse(ctx, entity.NewValidationError("mapping", "must be a JSON object of strings"))
+			return
+		}
+	}
+
+	file, err := fh.Open()
+	if err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+	defer file.Close()
+
+	job, err := r.t.ImportProducts(ctx, request, file)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.Header("Location", ctx.Request.URL.Path+"/"+job.ID)
+	ctx.JSON(http.StatusAccepted, job)
+}
+
+// @Summary     Get import job
+// @Description Get the progress of a catalog import and the rows that failed
+// @ID          get-import-job
+// @Security    BearerAuth
+// @Tags  	    product
+// @Produce     json
+// @Param       job_id path string true "Job ID"
+// @Success     200 {object} entity.ImportJob
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/import/{job_id} [get]
+func (r *productRoutes) getImportJob(ctx *gin.Context) {
+	job, err := r.t.GetImportJob(ctx, ctx.Param("job_id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, job)
+}
+
+// @Summary     Export products
+// @Description Download products as a CSV or XLSX catalog file in the layout accepted by the import
+// @ID          export-products
+// @Security    BearerAuth
+// @Tags  	    product
+// @Produce     text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param       format              query string false "File format" Enums(csv, xlsx) default(csv)
+// @Param       category_id         query string false "Only products of this category"
+// @Param       include_descendants query bool   false "Also products of its subcategories"
+// @Success     200 {file}   file
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/export [get]
+func (r *productRoutes) exportProducts(ctx *gin.Context) {
+	var request entity.CatalogExport
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if request.Format == "" {
+		request.Format = entity.CatalogFormatCSV
+	}
+
+	var buf bytes.Buffer
+
+	err := r.t.ExportProducts(ctx, request, &buf)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", `attachment; filename="products.`+request.Format+`"`)
+	ctx.Data(http.StatusOK, _catalogContentTypes[request.Format], buf.Bytes())
+}
@@ -0,0 +1,218 @@
+package v1
+
+import (
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-seller/internal/entity"
+	"ai-seller/internal/usecase"
+	"ai-seller/pkg/logger"
+)
+
+const _mediaCacheControl = "public, max-age=31536000, immutable"
+
+type mediaRoutes struct {
+	t usecase.UseCases
+	l logger.Interface
+}
+
+func NewMediaRoutes(apiV1Group *gin.RouterGroup, t usecase.UseCases, l logger.Interface) {
+	r := &mediaRoutes{t, l}
+
+	mediaGroup := apiV1Group.Group("/media")
+	{
+		mediaGroup.GET("/*key", r.getMedia)
+	}
+}
+
+// @Summary     Get media file
+// @Description Serve a stored image or thumbnail by the key in its URL. Files never change once stored, so they may be cached indefinitely.
+// @ID          get-media
+// @Tags  	    media
+// @Produce     image/jpeg,image/png,image/gif,image/webp
+// @Param       key path string true "Storage key"
+// @Success     200 {file}   file
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /media/{key} [get]
+func (r *mediaRoutes) getMedia(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+
+	file, size, err := r.t.OpenMedia(ctx, key)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+	defer file.Close()
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	ctx.DataFromReader(http.StatusOK, size, contentType, file, map[string]string{"Cache-Control": _mediaCacheControl})
+}
+
+// @Summary     List product media
+// @Description List the images of a product in display order with their thumbnails
+// @ID          list-media
+// @Tags  	    product
+// @Produce     json
+// @Param       id path string true "Product ID"
+// @Success     200 {array}  entity.ProductMedia
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/media [get]
+func (r *productRoutes) listMedia(ctx *gin.Context) {
+	media, err := r.t.ListMedia(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, media)
+}
+
+// @Summary     Upload product media
+// @Description Upload one or more images of a product as multipart "file" parts. The type is detected from the content: JPEG, PNG, GIF and WebP are accepted up to the configured size. Thumbnails are generated for every configured size smaller than the image. New images are appended; the first image of a product becomes its primary one.
+// @ID          upload-media
+// @Security    BearerAuth
+// @Tags  	    product
+// @Accept      multipart/form-data
+// @Produce     json
+// @Param       id   path     string true "Product ID"
+// @Param       file formData file   true "Image"
+// @Success     201 {array}  entity.ProductMedia
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     413 {object} problem
+// @Failure     415 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/media [post]
+func (r *productRoutes) uploadMedia(ctx *gin.Context) {
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	files := form.File["file"]
+	if len(files) == 0 {
+		errorResponse(ctx, entity.NewValidationError("file", "is required"))
+		return
+	}
+
+	media := make([]entity.ProductMedia, 0, len(files))
+
+	for _, fh := range files {
+		file, err := fh.Open()
+		if err != nil {
+			bindErrorResponse(ctx, err)
+			return
+		}
+
+		m, err := r.t.UploadMedia(ctx, ctx.Param("id"), file)
+		file.Close()
+
+		if err != nil {
+			errorResponse(ctx, err)
+			return
+		}
+
+		media = append(media, m)
+	}
+
+	ctx.JSON(http.StatusCreated, media)
+}
+
+// @Summary     Reorder product media
+// @Description Set the display order of the images of a product; every image must be listed exactly once
+// @ID          reorder-media
+// @Security    BearerAuth
+// @Tags  	    product
+// @Accept      json
+// @Produce     json
+// @Param       id      path string            true "Product ID"
+// @Param       request body entity.MediaOrder true "Image IDs in their new order"
+// @Success     200 {array}  entity.ProductMedia
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/media [put]
+func (r *productRoutes) reorderMedia(ctx *gin.Context) {
+	var request entity.MediaOrder
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	media, err := r.t.ReorderMedia(ctx, ctx.Param("id"), request)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, media)
+}
+
+// @Summary     Set primary product media
+// @Description Make an image the one that represents its product
+// @ID          set-primary-media
+// @Security    BearerAuth
+// @Tags  	    product
+// @Produce     json
+// @Param       id       path string true "Product ID"
+// @Param       media_id path string true "Media ID"
+// @Success     200 {object} entity.ProductMedia
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/media/{media_id}/primary [post]
+func (r *productRoutes) setPrimaryMedia(ctx *gin.Context) {
+	media, err := r.t.SetPrimaryMedia(ctx, ctx.Param("id"), ctx.Param("media_id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, media)
+}
+
+// @Summary     Delete product media
+// @Description Delete an image of a product with its thumbnails. When it was the primary image the next one takes over.
+// @ID          delete-media
+// @Security    BearerAuth
+// @Tags  	    product
+// @Produce     json
+// @Param       id       path string true "Product ID"
+// @Param       media_id path string true "Media ID"
+// @Success     204 {object} nil
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/media/{media_id} [delete]
+func (r *productRoutes) deleteMedia(ctx *gin.Context) {
+	err := r.t.DeleteMedia(ctx, ctx.Param("id"), ctx.Param("media_id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
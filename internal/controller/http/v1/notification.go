@@ -0,0 +1,179 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-seller/internal/controller/http/middleware"
+	"ai-seller/internal/entity"
+)
+
+// @Summary     Get low-stock threshold
+// @Description Get the count below which managers are alerted about a product. alerted_at is set while the alert is outstanding.
+// @ID          get-low-stock-threshold
+// @Security    BearerAuth
+// @Tags  	    product
+// @Produce     json
+// @Param       id path string true "Product ID"
+// @Success     200 {object} entity.LowStockThreshold
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/low-stock-threshold [get]
+func (r *productRoutes) getLowStockThreshold(ctx *gin.Context) {
+	threshold, err := r.t.GetLowStockThreshold(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, threshold)
+}
+
+// @Summary     Set low-stock threshold
+// @Description Set the count below which managers are alerted about a product. The alert is sent once when the count drops below the threshold and again only after it was back at or above it. A product already below it is alerted right away.
+// @ID          set-low-stock-threshold
+// @Security    BearerAuth
+// @Tags  	    product
+// @Accept      json
+// @Produce     json
+// @Param       id      path string                   true "Product ID"
+// @Param       request body entity.LowStockThreshold true "Threshold request"
+// @Success     200 {object} entity.LowStockThreshold
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/low-stock-threshold [put]
+func (r *productRoutes) setLowStockThreshold(ctx *gin.Context) {
+	var request entity.LowStockThreshold
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	request.ProductID = ctx.Param("id")
+
+	threshold, err := r.t.SetLowStockThreshold(ctx, request)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, threshold)
+}
+
+// @Summary     Delete low-stock threshold
+// @Description Stop low-stock alerts for a product
+// @ID          delete-low-stock-threshold
+// @Security    BearerAuth
+// @Tags  	    product
+// @Param       id path string true "Product ID"
+// @Success     204
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/low-stock-threshold [delete]
+func (r *productRoutes) deleteLowStockThreshold(ctx *gin.Context) {
+	err := r.t.DeleteLowStockThreshold(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary     List stock subscriptions
+// @Description List the customers waiting for a product to be back in stock, oldest first
+// @ID          list-stock-subscriptions
+// @Security    BearerAuth
+// @Tags  	    product
+// @Produce     json
+// @Param       id path string true "Product ID"
+// @Success     200 {array}  entity.StockSubscription
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/subscription [get]
+func (r *productRoutes) listStockSubscriptions(ctx *gin.Context) {
+	subscriptions, err := r.t.ListStockSubscriptions(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subscriptions)
+}
+
+// @Summary     Subscribe to stock
+// @Description Get a message once an out-of-stock product is available again. The message goes to chat_id, the numeric id of the caller's Telegram chat with the bot. The subscription ends with the message.
+// @ID          subscribe-to-stock
+// @Security    BearerAuth
+// @Tags  	    product
+// @Accept      json
+// @Produce     json
+// @Param       id      path string                   true "Product ID"
+// @Param       request body entity.StockSubscription true "Subscription request"
+// @Success     201 {object} entity.StockSubscription
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     404 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/subscription [post]
+func (r *productRoutes) subscribeToStock(ctx *gin.Context) {
+	var request entity.StockSubscription
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	request.UserID = ctx.GetString(middleware.UserIDKey)
+	request.ProductID = ctx.Param("id")
+
+	subscription, err := r.t.SubscribeToStock(ctx, request)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, subscription)
+}
+
+// @Summary     Unsubscribe from stock
+// @Description Stop waiting for a product to be back in stock
+// @ID          unsubscribe-from-stock
+// @Security    BearerAuth
+// @Tags  	    product
+// @Param       id path string true "Product ID"
+// @Success     204
+// @Failure     401 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/subscription [delete]
+func (r *productRoutes) unsubscribeFromStock(ctx *gin.Context) {
+	err := r.t.UnsubscribeFromStock(ctx, ctx.GetString(middleware.UserIDKey), ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
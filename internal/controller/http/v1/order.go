@@ -0,0 +1,341 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"ai-seller/internal/controller/http/middleware"
+	"ai-seller/internal/entity"
+	"ai-seller/internal/usecase"
+	"ai-seller/pkg/logger"
+)
+
+type orderRoutes struct {
+	t usecase.UseCases
+	l logger.Interface
+	v *validator.Validate
+}
+
+func NewOrderRoutes(apiV1Group *gin.RouterGroup, t usecase.UseCases, l logger.Interface) {
+	r := &orderRoutes{t, l, validator.New(validator.WithRequiredStructEnabled())}
+
+	orderGroup := apiV1Group.Group("/order", middleware.Auth(t))
+	{
+		orderGroup.GET("/", middleware.Permission(t, entity.PermissionOrderRead), r.listOrders)
+		orderGroup.POST("/", middleware.Permission(t, entity.PermissionOrderCreate), r.placeOrder)
+		orderGroup.POST("/quote", middleware.Permission(t, entity.PermissionOrderCreate), r.quoteOrder)
+		orderGroup.GET("/:id", middleware.Permission(t, entity.PermissionOrderRead), r.getOrder)
+		orderGroup.PUT("/:id", middleware.Permission(t, entity.PermissionOrderUpdate), r.updateOrder)
+		orderGroup.PATCH("/:id", middleware.Permission(t, entity.PermissionOrderUpdate), r.patchOrder)
+		orderGroup.POST("/:id/status", middleware.Permission(t, entity.PermissionOrderUpdate), r.changeOrderStatus)
+		orderGroup.GET("/:id/history", middleware.Permission(t, entity.PermissionOrderRead), r.getOrderTimeline)
+	}
+}
+
+// @Summary     List orders
+// @Description Page through orders with filters and keyset pagination
+// @ID          list-orders
+// @Tags  	    order
+// @Produce     json
+// @Security    BearerAuth
+// @Param       status         query string false "Order status"
+// @Param       user_id        query string false "User ID"
+// @Param       integration_id query string false "Integration ID"
+// @Param       created_from   query string false "Created at or after (RFC 3339)"
+// @Param       created_to     query string false "Created before (RFC 3339)"
+// @Param       sort           query string false "Sort column, '-' prefix for descending" Enums(created_at, -created_at, updated_at, -updated_at, status_changed_time, -status_changed_time, total_cost, -total_cost)
+// @Param       limit          query int    false "Page size, 100 at most"
+// @Param       cursor         query string false "next_cursor of the previous page"
+// @Success     200 {object} entity.Page[entity.Order]
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /order [get]
+func (r *orderRoutes) listOrders(ctx *gin.Context) {
+	var filter entity.OrderFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	page, err := r.t.ListOrders(ctx, filter)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, page)
+}
+
+// @Summary     Place order
+// @Description Place an order atomically: available stock is checked and reserved until the order is paid or the reservation expires, when the order is cancelled; prices, promotions, coupons and the total are computed by the server. Amounts are in minor units of the order currency, the base currency unless given; prices come from the currency's price list or are converted at the current exchange rate, which the order records.
+// @ID          place-order
+// @Tags  	    order
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       request body entity.NewOrder true "Order request"
+// @Success     201 {object} entity.Order
+// @Header      201 {string} ETag "Row version"
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /order [post]
+func (r *orderRoutes) placeOrder(ctx *gin.Context) {
+	var request entity.NewOrder
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	request.PlacedBy = ctx.GetString(middleware.UserIDKey)
+
+	order, err := r.t.PlaceOrder(ctx, request)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, order.Version)
+	ctx.JSON(http.StatusCreated, order)
+}
+
+// @Summary     Quote order
+// @Description Price an order without placing it: line prices, the discounts of promotions and coupons that would apply with an explanation of each, and the total
+// @ID          quote-order
+// @Tags  	    order
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       request body entity.NewOrder true "Order request"
+// @Success     200 {object} entity.OrderQuote
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /order/quote [post]
+func (r *orderRoutes) quoteOrder(ctx *gin.Context) {
+	var request entity.NewOrder
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	quote, err := r.t.QuoteOrder(ctx, request)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, quote)
+}
+
+// @Summary     Get order
+// @Description Get an order with its lines, discounts and stock reservations
+// @ID          get-order
+// @Tags  	    order
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Order ID"
+// @Success     200 {object} entity.Order
+// @Header      200 {string} ETag "Row version"
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /order/{id} [get]
+func (r *orderRoutes) getOrder(ctx *gin.Context) {
+	order, err := r.t.GetOrder(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, order.Version)
+	ctx.JSON(http.StatusOK, order)
+}
+
+// @Summary     Update order
+// @Description Replace the editable fields of an order; status and total are not taken from the body
+// @ID          update-order
+// @Tags  	    order
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id       path   string       true "Order ID"
+// @Param       request  body   entity.Order true "Order request"
+// @Param       If-Match header string       true "ETag of the last read"
+// @Success     200 {object} entity.Order
+// @Header      200 {string} ETag "Row version"
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     412 {object} problem
+// @Failure     422 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
+// @Router      /order/{id} [put]
+func (r *orderRoutes) updateOrder(ctx *gin.Context) {
+	var order entity.Order
+	if err := ctx.ShouldBindJSON(&order); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	order.ID = ctx.Param("id")
+
+	version, err := ifMatch(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	order.Version = version
+
+	err = r.t.UpdateOrder(ctx, order)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	order, err = r.t.GetOrder(ctx, order.ID)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, order.Version)
+	ctx.JSON(http.StatusOK, order)
+}
+
+// @Summary     Patch order
+// @Description Update only the fields present in a JSON Merge Patch (RFC 7396) document; null clears a field
+// @ID          patch-order
+// @Tags  	    order
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id       path   string            true "Order ID"
+// @Param       request  body   entity.OrderPatch true "Merge patch"
+// @Param       If-Match header string            true "ETag of the last read"
+// @Success     200 {object} entity.Order
+// @Header      200 {string} ETag "Row version"
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     412 {object} problem
+// @Failure     422 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
+// @Router      /order/{id} [patch]
+func (r *orderRoutes) patchOrder(ctx *gin.Context) {
+	var patch entity.OrderPatch
+	if err := ctx.ShouldBindJSON(&patch); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	version, err := ifMatch(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	patch.Version = version
+
+	order, err := r.t.PatchOrder(ctx, ctx.Param("id"), patch)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, order.Version)
+	ctx.JSON(http.StatusOK, order)
+}
+
+// @Summary     Change order status
+// @Description Move an order along its lifecycle: new -> confirmed -> paid -> shipped -> delivered, cancelled before shipping, returned after it. Paying takes the reserved items off the stock; cancelling releases them or, once paid, puts them back.
+// @ID          change-order-status
+// @Tags  	    order
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id      path string                   true "Order ID"
+// @Param       request body entity.OrderStatusUpdate true "New status"
+// @Success     200 {object} entity.Order
+// @Header      200 {string} ETag "Row version"
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /order/{id}/status [post]
+func (r *orderRoutes) changeOrderStatus(ctx *gin.Context) {
+	var request entity.OrderStatusUpdate
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	request.ChangedBy = ctx.GetString(middleware.UserIDKey)
+
+	order, err := r.t.ChangeOrderStatus(ctx, ctx.Param("id"), request)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, order.Version)
+	ctx.JSON(http.StatusOK, order)
+}
+
+// @Summary     Get order timeline
+// @Description Get the status changes of an order, oldest first
+// @ID          get-order-timeline
+// @Tags  	    order
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Order ID"
+// @Success     200 {array}  entity.OrderStatusChange
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /order/{id}/history [get]
+func (r *orderRoutes) getOrderTimeline(ctx *gin.Context) {
+	history, err := r.t.GetOrderTimeline(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, history)
+}
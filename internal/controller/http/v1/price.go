@@ -0,0 +1,92 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-seller/internal/entity"
+)
+
+// @Summary     List product prices
+// @Description List the prices of a product and its variants in currencies other than the base one, in minor units
+// @ID          list-product-prices
+// @Tags  	    product
+// @Produce     json
+// @Param       id path string true "Product ID"
+// @Success     200 {array}  entity.ProductPrice
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/price [get]
+func (r *productRoutes) listPrices(ctx *gin.Context) {
+	prices, err := r.t.GetProductPrices(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, prices)
+}
+
+// @Summary     Set product price
+// @Description Set the price of a product, or of one of its variants, in a currency other than the base one. Orders in that currency use it instead of converting the base price; a variant price wins over a product price.
+// @ID          set-product-price
+// @Security    BearerAuth
+// @Tags  	    product
+// @Accept      json
+// @Produce     json
+// @Param       id      path string              true "Product ID"
+// @Param       request body entity.ProductPrice true "Price request"
+// @Success     200 {object} entity.ProductPrice
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/price [put]
+func (r *productRoutes) setPrice(ctx *gin.Context) {
+	var request entity.ProductPrice
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	request.ProductID = ctx.Param("id")
+
+	price, err := r.t.SetProductPrice(ctx, request)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, price)
+}
+
+// @Summary     Delete product price
+// @Description Delete a price of a product. Orders in its currency go back to the converted base price.
+// @ID          delete-product-price
+// @Security    BearerAuth
+// @Tags  	    product
+// @Param       id       path string true "Product ID"
+// @Param       price_id path string true "Price ID"
+// @Success     204
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/price/{price_id} [delete]
+func (r *productRoutes) deletePrice(ctx *gin.Context) {
+	err := r.t.DeleteProductPrice(ctx, ctx.Param("id"), ctx.Param("price_id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"ai-seller/internal/controller/http/middleware"
 	"ai-seller/internal/entity"
 	"ai-seller/internal/usecase"
 	"ai-seller/pkg/logger"
@@ -19,45 +20,119 @@ type productRoutes struct {
 func NewProductRoutes(apiV1Group *gin.RouterGroup, t usecase.UseCases, l logger.Interface) {
 	p := &productRoutes{t, l, validator.New(validator.WithRequiredStructEnabled())}
 
+	auth := middleware.Auth(t)
+
 	productGroup := apiV1Group.Group("/product")
 	{
-		productGroup.POST("/", p.createProduct)
+		productGroup.GET("/", p.listProducts)
+		productGroup.GET("/search", p.searchProducts)
+		productGroup.GET("/suggest", p.suggestProducts)
+		productGroup.GET("/search/synonym", auth, middleware.Permission(t, entity.PermissionProductUpdate), p.listSynonyms)
+		productGroup.POST("/search/synonym", auth, middleware.Permission(t, entity.PermissionProductUpdate), p.createSynonym)
+		productGroup.DELETE("/search/synonym", auth, middleware.Permission(t, entity.PermissionProductUpdate), p.deleteSynonym)
+		productGroup.POST("/import", auth, middleware.Permission(t, entity.PermissionProductImport), p.importProducts)
+		productGroup.GET("/import/:job_id", auth, middleware.Permission(t, entity.PermissionProductImport), p.getImportJob)
+		productGroup.GET("/export", auth, middleware.Permission(t, entity.PermissionProductImport), p.exportProducts)
+		productGroup.GET("/trash", auth, middleware.Permission(t, entity.PermissionProductDelete), p.listDeletedProducts)
+		productGroup.POST("/", auth, middleware.Permission(t, entity.PermissionProductCreate), p.createProduct)
 		productGroup.GET("/:id", p.getProduct)
-		productGroup.PUT("/", p.updateProduct)
-		productGroup.DELETE("/:id", p.deleteProduct)
+		productGroup.PUT("/", auth, middleware.Permission(t, entity.PermissionProductUpdate), p.updateProduct)
+		productGroup.PUT("/:id", auth, middleware.Permission(t, entity.PermissionProductUpdate), p.updateProduct)
+		productGroup.PATCH("/:id", auth, middleware.Permission(t, entity.PermissionProductUpdate), p.patchProduct)
+		productGroup.GET("/:id/breadcrumbs", p.getProductBreadcrumbs)
+		productGroup.GET("/:id/variant", p.listVariants)
+		productGroup.POST("/:id/variant", auth, middleware.Permission(t, entity.PermissionProductUpdate), p.createVariant)
+		productGroup.GET("/:id/variant/:variant_id", p.getVariant)
+		productGroup.PUT("/:id/variant/:variant_id", auth, middleware.Permission(t, entity.PermissionProductUpdate), p.updateVariant)
+		productGroup.DELETE("/:id/variant/:variant_id", auth, middleware.Permission(t, entity.PermissionProductUpdate), p.deleteVariant)
+		productGroup.GET("/:id/media", p.listMedia)
+		productGroup.POST("/:id/media", auth, middleware.Permission(t, entity.PermissionProductUpdate), p.uploadMedia)
+		productGroup.PUT("/:id/media", auth, middleware.Permission(t, entity.PermissionProductUpdate), p.reorderMedia)
+		productGroup.POST("/:id/media/:media_id/primary", auth, middleware.Permission(t, entity.PermissionProductUpdate), p.setPrimaryMedia)
+		productGroup.DELETE("/:id/media/:media_id", auth, middleware.Permission(t, entity.PermissionProductUpdate), p.deleteMedia)
+		productGroup.GET("/:id/price", p.listPrices)
+		productGroup.PUT("/:id/price", auth, middleware.Permission(t, entity.PermissionProductUpdate), p.setPrice)
+		productGroup.DELETE("/:id/price/:price_id", auth, middleware.Permission(t, entity.PermissionProductUpdate), p.deletePrice)
+		productGroup.GET("/:id/low-stock-threshold", auth, middleware.Permission(t, entity.PermissionProductUpdate), p.getLowStockThreshold)
+		productGroup.PUT("/:id/low-stock-threshold", auth, middleware.Permission(t, entity.PermissionProductUpdate), p.setLowStockThreshold)
+		productGroup.DELETE("/:id/low-stock-threshold", auth, middleware.Permission(t, entity.PermissionProductUpdate), p.deleteLowStockThreshold)
+		productGroup.GET("/:id/subscription", auth, middleware.Permission(t, entity.PermissionProductUpdate), p.listStockSubscriptions)
+		productGroup.POST("/:id/subscription", auth, p.subscribeToStock)
+		productGroup.DELETE("/:id/subscription", auth, p.unsubscribeFromStock)
+		productGroup.GET("/:id/attribute", p.getProductAttributes)
+		productGroup.PUT("/:id/attribute", auth, middleware.Permission(t, entity.PermissionProductUpdate), p.setProductAttributes)
+		productGroup.DELETE("/:id", auth, middleware.Permission(t, entity.PermissionProductDelete), p.deleteProduct)
+		productGroup.POST("/:id/restore", auth, middleware.Permission(t, entity.PermissionProductDelete), p.restoreProduct)
 	}
 }
 
 
 // @Summary     Create product
-// @Description Create a new product
+// @Description Create a new product. Its count is recorded as the opening stock of the default warehouse.
 // @ID          create-product
+// @Security    BearerAuth
 // @Tags  	    product
 // @Accept      json
 // @Produce     json
 // @Param       request body entity.Product true "Product request"
 // @Success     201 {object} entity.Product
-// @Failure     400 {object} response
-// @Failure     500 {object} response
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     500 {object} problem
 // @Router      /product [post]
 func (r *productRoutes) createProduct(ctx *gin.Context) {
 	var product entity.Product
 	if err := ctx.ShouldBindJSON(&product); err != nil {
-		r.l.Error(err, "http - v1 - createProduct")
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		bindErrorResponse(ctx, err)
 		return
 	}
 
 	err := r.t.CreateProduct(ctx, product)
 	if err != nil {
-		r.l.Error(err, "http - v1 - createProduct")
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "database problems"})
+		errorResponse(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusCreated, gin.H{"message": "product created"})
 }
 
+// @Summary     List products
+// @Description Page through products with filters and keyset pagination
+// @ID          list-products
+// @Tags  	    product
+// @Produce     json
+// @Param       category_id         query string false "Category ID"
+// @Param       include_descendants query bool   false "Also list products of subcategories of category_id"
+// @Param       min_cost            query int    false "Minimum cost"
+// @Param       max_cost            query int    false "Maximum cost"
+// @Param       in_stock            query bool   false "Only products in (true) or out of (false) stock"
+// @Param       created_from        query string false "Created at or after (RFC 3339)"
+// @Param       created_to          query string false "Created before (RFC 3339)"
+// @Param       sort                query string false "Sort column, '-' prefix for descending" Enums(created_at, -created_at, updated_at, -updated_at, name, -name, cost, -cost, count, -count)
+// @Param       limit               query int    false "Page size, 100 at most"
+// @Param       cursor              query string false "next_cursor of the previous page"
+// @Success     200 {object} entity.Page[entity.Product]
+// @Failure     400 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product [get]
+func (r *productRoutes) listProducts(ctx *gin.Context) {
+	var filter entity.ProductFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	page, err := r.t.ListProducts(ctx, filter)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, page)
+}
+
 // @Summary     Get product
 // @Description Get a product by ID
 // @ID          get-product
@@ -66,71 +141,293 @@ func (r *productRoutes) createProduct(ctx *gin.Context) {
 // @Produce     json
 // @Param       id path string true "Product ID"
 // @Success     200 {object} entity.Product
-// @Failure     404 {object} response
-// @Failure     500 {object} response
+// @Header      200 {string} ETag "Row version"
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
 // @Router      /product/{id} [get]
 func (r *productRoutes) getProduct(ctx *gin.Context) {
 	id := ctx.Param("id")
 	product, err := r.t.GetProduct(ctx, id)
 	if err != nil {
-		r.l.Error(err, "http - v1 - getProduct")
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "database problems"})
+		errorResponse(ctx, err)
 		return
 	}
 
+	setETag(ctx, product.Version)
 	ctx.JSON(http.StatusOK, product)
 }
 
 // @Summary     Update product
-// @Description Update a product by ID
+// @Description Replace a product by ID. A changed count is recorded in the stock ledger as a correction in the default warehouse; receipts, transfers and stocktakes go through /stock.
 // @ID          update-product
+// @Security    BearerAuth
 // @Tags  	    product
 // @Accept      json
 // @Produce     json
-// @Param       id path string true "Product ID"
-// @Param       request body entity.Product true "Product request"
-// @Success     200 {object} response
-// @Failure     400 {object} response
-// @Failure     404 {object} response
-// @Failure     500 {object} response
-// @Router      /product [put]
+// @Param       id       path   string         true "Product ID"
+// @Param       request  body   entity.Product true "Product request"
+// @Param       If-Match header string         true "ETag of the last read"
+// @Success     200 {object} entity.Product
+// @Header      200 {string} ETag "Row version"
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     412 {object} problem
+// @Failure     422 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id} [put]
 func (r *productRoutes) updateProduct(ctx *gin.Context) {
 	var product entity.Product
 	if err := ctx.ShouldBindJSON(&product); err != nil {
-		r.l.Error(err, "http - v1 - updateProduct")
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	// PUT /product/ takes the id from the body.
+	if id := ctx.Param("id"); id != "" {
+		product.ID = id
+	}
+
+	version, err := ifMatch(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	product.Version = version
+
+	err = r.t.UpdateProduct(ctx, product)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	product, err = r.t.GetProduct(ctx, product.ID)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, product.Version)
+	ctx.JSON(http.StatusOK, product)
+}
+
+// @Summary     Patch product
+// @Description Update only the fields present in a JSON Merge Patch (RFC 7396) document; null clears a field
+// @ID          patch-product
+// @Security    BearerAuth
+// @Tags  	    product
+// @Accept      json
+// @Produce     json
+// @Param       id       path   string              true "Product ID"
+// @Param       request  body   entity.ProductPatch true "Merge patch"
+// @Param       If-Match header string              true "ETag of the last read"
+// @Success     200 {object} entity.Product
+// @Header      200 {string} ETag "Row version"
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     412 {object} problem
+// @Failure     422 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id} [patch]
+func (r *productRoutes) patchProduct(ctx *gin.Context) {
+	var patch entity.ProductPatch
+	if err := ctx.ShouldBindJSON(&patch); err != nil {
+		bindErrorResponse(ctx, err)
 		return
 	}
 
-	err := r.t.UpdateProduct(ctx, product)
+	version, err := ifMatch(ctx)
 	if err != nil {
-		r.l.Error(err, "http - v1 - updateProduct")
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "database problems"})
+		errorResponse(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "product updated"})
+	patch.Version = version
+
+	product, err := r.t.PatchProduct(ctx, ctx.Param("id"), patch)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, product.Version)
+	ctx.JSON(http.StatusOK, product)
 }
 
 // @Summary     Delete product
-// @Description Delete a product by ID
+// @Description Move a product to the trash. It can be restored until the retention window has passed; then it is purged for good unless an order refers to it.
 // @ID          delete-product
+// @Security    BearerAuth
 // @Tags  	    product
 // @Accept      json
 // @Produce     json
-// @Param       id path string true "Product ID"
+// @Param       id       path   string true "Product ID"
+// @Param       If-Match header string true "ETag of the last read"
 // @Success     204 {object} nil
-// @Failure     404 {object} response
-// @Failure     500 {object} response
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     412 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
 // @Router      /product/{id} [delete]
 func (r *productRoutes) deleteProduct(ctx *gin.Context) {
-	id := ctx.Param("id")
-	err := r.t.DeleteProduct(ctx, id)
+	version, err := ifMatch(ctx)
 	if err != nil {
-		r.l.Error(err, "http - v1 - deleteProduct")
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "database problems"})
+		errorResponse(ctx, err)
+		return
+	}
+
+	err = r.t.DeleteProduct(ctx, ctx.Param("id"), version)
+	if err != nil {
+		errorResponse(ctx, err)
 		return
 	}
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// @Summary     List deleted products
+// @Description Page through the products in the trash, most recently deleted first
+// @ID          list-deleted-products
+// @Security    BearerAuth
+// @Tags  	    product
+// @Produce     json
+// @Param       sort   query string false "Sort column, '-' prefix for descending" Enums(deleted_at, -deleted_at, name, -name)
+// @Param       limit  query int    false "Page size, 100 at most"
+// @Param       cursor query string false "next_cursor of the previous page"
+// @Success     200 {object} entity.Page[entity.Product]
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/trash [get]
+func (r *productRoutes) listDeletedProducts(ctx *gin.Context) {
+	var params entity.ListParams
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	page, err := r.t.ListDeletedProducts(ctx, params)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, page)
+}
+
+// @Summary     Restore product
+// @Description Take a product out of the trash. Its category has to be restored first; a SKU taken in the meantime is a conflict.
+// @ID          restore-product
+// @Security    BearerAuth
+// @Tags  	    product
+// @Produce     json
+// @Param       id path string true "Product ID"
+// @Success     200 {object} entity.Product
+// @Header      200 {string} ETag "Row version"
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/restore [post]
+func (r *productRoutes) restoreProduct(ctx *gin.Context) {
+	product, err := r.t.RestoreProduct(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, product.Version)
+	ctx.JSON(http.StatusOK, product)
+}
+
+// @Summary     Get product breadcrumbs
+// @Description Get the category path of a product, from the root category down to its own
+// @ID          get-product-breadcrumbs
+// @Tags  	    product
+// @Produce     json
+// @Param       id path string true "Product ID"
+// @Success     200 {array}  entity.Category
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/breadcrumbs [get]
+func (r *productRoutes) getProductBreadcrumbs(ctx *gin.Context) {
+	path, err := r.t.GetProductBreadcrumbs(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, path)
+}
+
+// @Summary     Get product attributes
+// @Description Get the attribute values of a product
+// @ID          get-product-attributes
+// @Tags  	    product
+// @Produce     json
+// @Param       id path string true "Product ID"
+// @Success     200 {array}  entity.ProductAttribute
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/attribute [get]
+func (r *productRoutes) getProductAttributes(ctx *gin.Context) {
+	values, err := r.t.GetProductAttributes(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, values)
+}
+
+// @Summary     Set product attributes
+// @Description Replace the attribute values of a product. Values are checked against the attributes of the product's category
+// @ID          set-product-attributes
+// @Security    BearerAuth
+// @Tags  	    product
+// @Accept      json
+// @Produce     json
+// @Param       id      path string                    true "Product ID"
+// @Param       request body []entity.ProductAttribute true "Attribute values"
+// @Success     200 {array}  entity.ProductAttribute
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/attribute [put]
+func (r *productRoutes) setProductAttributes(ctx *gin.Context) {
+	var values []entity.ProductAttribute
+	if err := ctx.ShouldBindJSON(&values); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Var(values, "dive"); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	values, err := r.t.SetProductAttributes(ctx, ctx.Param("id"), values)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, values)
+}
@@ -0,0 +1,303 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"ai-seller/internal/controller/http/middleware"
+	"ai-seller/internal/entity"
+	"ai-seller/internal/usecase"
+	"ai-seller/pkg/logger"
+)
+
+type promotionRoutes struct {
+	t usecase.UseCases
+	l logger.Interface
+	v *validator.Validate
+}
+
+func NewPromotionRoutes(apiV1Group *gin.RouterGroup, t usecase.UseCases, l logger.Interface) {
+	r := &promotionRoutes{t, l, validator.New(validator.WithRequiredStructEnabled())}
+
+	promotionGroup := apiV1Group.Group("/promotion", middleware.Auth(t), middleware.Permission(t, entity.PermissionPromotionManage))
+	{
+		promotionGroup.GET("/", r.listPromotions)
+		promotionGroup.POST("/", r.createPromotion)
+		promotionGroup.GET("/:id", r.getPromotion)
+		promotionGroup.PUT("/:id", r.updatePromotion)
+		promotionGroup.DELETE("/:id", r.deletePromotion)
+		promotionGroup.GET("/:id/coupon", r.listCoupons)
+		promotionGroup.POST("/:id/coupon", r.createCoupon)
+		promotionGroup.DELETE("/:id/coupon/:coupon_id", r.deleteCoupon)
+	}
+}
+
+// @Summary     List promotions
+// @Description Page through promotions with keyset pagination
+// @ID          list-promotions
+// @Security    BearerAuth
+// @Tags  	    promotion
+// @Produce     json
+// @Param       active query bool   false "Only enabled or disabled promotions"
+// @Param       sort   query string false "Sort column, '-' prefix for descending" Enums(created_at, -created_at, updated_at, -updated_at, priority, -priority)
+// @Param       limit  query int    false "Page size, 100 at most"
+// @Param       cursor query string false "next_cursor of the previous page"
+// @Success     200 {object} entity.Page[entity.Promotion]
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /promotion [get]
+func (r *promotionRoutes) listPromotions(ctx *gin.Context) {
+	var filter entity.PromotionFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	page, err := r.t.ListPromotions(ctx, filter)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, page)
+}
+
+// @Summary     Create promotion
+// @Description Create a discount campaign. percentage takes value percent off the matching items, fixed takes value off their sum, buy_x_get_y takes value percent off get_count of every buy_count+get_count matching items, cheapest first. With min_order_cost, in minor units of the base currency, only orders whose subtotal reaches it qualify. Promotions that are not stackable never combine with others; orders get whichever is better for the customer: all stackable promotions in priority order or the best single other one. Promotions requiring a coupon only apply with one of their codes.
+// @ID          create-promotion
+// @Security    BearerAuth
+// @Tags  	    promotion
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.Promotion true "Promotion request"
+// @Success     201 {object} entity.Promotion
+// @Header      201 {string} ETag "Row version"
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /promotion [post]
+func (r *promotionRoutes) createPromotion(ctx *gin.Context) {
+	var request entity.Promotion
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	promotion, err := r.t.CreatePromotion(ctx, request)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, promotion.Version)
+	ctx.JSON(http.StatusCreated, promotion)
+}
+
+// @Summary     Get promotion
+// @Description Get a promotion with the number of orders it was applied to
+// @ID          get-promotion
+// @Security    BearerAuth
+// @Tags  	    promotion
+// @Produce     json
+// @Param       id path string true "Promotion ID"
+// @Success     200 {object} entity.Promotion
+// @Header      200 {string} ETag "Row version"
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /promotion/{id} [get]
+func (r *promotionRoutes) getPromotion(ctx *gin.Context) {
+	promotion, err := r.t.GetPromotion(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, promotion.Version)
+	ctx.JSON(http.StatusOK, promotion)
+}
+
+// @Summary     Update promotion
+// @Description Replace a promotion by ID. Orders already placed keep their discounts.
+// @ID          update-promotion
+// @Security    BearerAuth
+// @Tags  	    promotion
+// @Accept      json
+// @Produce     json
+// @Param       id       path   string           true "Promotion ID"
+// @Param       request  body   entity.Promotion true "Promotion request"
+// @Param       If-Match header string           true "ETag of the last read"
+// @Success     200 {object} entity.Promotion
+// @Header      200 {string} ETag "Row version"
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     412 {object} problem
+// @Failure     422 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
+// @Router      /promotion/{id} [put]
+func (r *promotionRoutes) updatePromotion(ctx *gin.Context) {
+	var request entity.Promotion
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	version, err := ifMatch(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	request.ID = ctx.Param("id")
+	request.Version = version
+
+	promotion, err := r.t.UpdatePromotion(ctx, request)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, promotion.Version)
+	ctx.JSON(http.StatusOK, promotion)
+}
+
+// @Summary     Delete promotion
+// @Description Delete a promotion with its coupons. Orders keep the explanation of the discounts it gave.
+// @ID          delete-promotion
+// @Security    BearerAuth
+// @Tags  	    promotion
+// @Produce     json
+// @Param       id       path   string true "Promotion ID"
+// @Param       If-Match header string true "ETag of the last read"
+// @Success     204 {object} nil
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     412 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
+// @Router      /promotion/{id} [delete]
+func (r *promotionRoutes) deletePromotion(ctx *gin.Context) {
+	version, err := ifMatch(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	err = r.t.DeletePromotion(ctx, ctx.Param("id"), version)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary     List coupons
+// @Description List the coupon codes of a promotion with the number of times each was used
+// @ID          list-coupons
+// @Security    BearerAuth
+// @Tags  	    promotion
+// @Produce     json
+// @Param       id path string true "Promotion ID"
+// @Success     200 {array}  entity.Coupon
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /promotion/{id}/coupon [get]
+func (r *promotionRoutes) listCoupons(ctx *gin.Context) {
+	coupons, err := r.t.ListCoupons(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, coupons)
+}
+
+// @Summary     Create coupon
+// @Description Add a case insensitive coupon code to a promotion. Its usage limits apply on top of the promotion's.
+// @ID          create-coupon
+// @Security    BearerAuth
+// @Tags  	    promotion
+// @Accept      json
+// @Produce     json
+// @Param       id      path string        true "Promotion ID"
+// @Param       request body entity.Coupon true "Coupon request"
+// @Success     201 {object} entity.Coupon
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /promotion/{id}/coupon [post]
+func (r *promotionRoutes) createCoupon(ctx *gin.Context) {
+	var request entity.Coupon
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	request.PromotionID = ctx.Param("id")
+
+	coupon, err := r.t.CreateCoupon(ctx, request)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, coupon)
+}
+
+// @Summary     Delete coupon
+// @Description Delete a coupon code of a promotion
+// @ID          delete-coupon
+// @Security    BearerAuth
+// @Tags  	    promotion
+// @Produce     json
+// @Param       id        path string true "Promotion ID"
+// @Param       coupon_id path string true "Coupon ID"
+// @Success     204 {object} nil
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /promotion/{id}/coupon/{coupon_id} [delete]
+func (r *promotionRoutes) deleteCoupon(ctx *gin.Context) {
+	err := r.t.DeleteCoupon(ctx, ctx.Param("id"), ctx.Param("coupon_id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,137 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"ai-seller/internal/controller/http/middleware"
+	"ai-seller/internal/entity"
+	"ai-seller/internal/usecase"
+	"ai-seller/pkg/logger"
+)
+
+type roleRoutes struct {
+	t usecase.Auth
+	l logger.Interface
+	v *validator.Validate
+}
+
+func NewRoleRoutes(apiV1Group *gin.RouterGroup, t usecase.Auth, l logger.Interface) {
+	r := &roleRoutes{t, l, validator.New(validator.WithRequiredStructEnabled())}
+
+	adminGroup := apiV1Group.Group("", middleware.Auth(t), middleware.Permission(t, entity.PermissionRoleManage))
+	{
+		adminGroup.GET("/permission", r.getPermissions)
+		adminGroup.GET("/role/:id/permission", r.getRolePermissions)
+		adminGroup.POST("/role/:id/permission", r.addRolePermission)
+		adminGroup.DELETE("/role/:id/permission/:permission_id", r.deleteRolePermission)
+	}
+}
+
+// @Summary     List permissions
+// @Description List every known permission
+// @ID          get-permissions
+// @Tags  	    role
+// @Produce     json
+// @Security    BearerAuth
+// @Success     200 {array} entity.Permission
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     500 {object} problem
+// @Router      /permission [get]
+func (r *roleRoutes) getPermissions(ctx *gin.Context) {
+	permissions, err := r.t.GetPermissions(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, permissions)
+}
+
+// @Summary     List role permissions
+// @Description List permissions granted to a role
+// @ID          get-role-permissions
+// @Tags  	    role
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Role ID"
+// @Success     200 {array} entity.Permission
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     500 {object} problem
+// @Router      /role/{id}/permission [get]
+func (r *roleRoutes) getRolePermissions(ctx *gin.Context) {
+	permissions, err := r.t.GetRolePermissions(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, permissions)
+}
+
+type rolePermissionRequest struct {
+	PermissionID string `json:"permission_id" validate:"required,uuid"`
+}
+
+// @Summary     Grant permission
+// @Description Grant a permission to a role
+// @ID          add-role-permission
+// @Tags  	    role
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Role ID"
+// @Param       request body rolePermissionRequest true "Permission"
+// @Success     204 {object} nil
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     500 {object} problem
+// @Router      /role/{id}/permission [post]
+func (r *roleRoutes) addRolePermission(ctx *gin.Context) {
+	var request rolePermissionRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	err := r.t.AddRolePermission(ctx, ctx.Param("id"), request.PermissionID)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary     Revoke permission
+// @Description Revoke a permission from a role
+// @ID          delete-role-permission
+// @Tags  	    role
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Role ID"
+// @Param       permission_id path string true "Permission ID"
+// @Success     204 {object} nil
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     500 {object} problem
+// @Router      /role/{id}/permission/{permission_id} [delete]
+func (r *roleRoutes) deleteRolePermission(ctx *gin.Context) {
+	err := r.t.DeleteRolePermission(ctx, ctx.Param("id"), ctx.Param("permission_id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,149 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-seller/internal/entity"
+)
+
+// @Summary     Search products
+// @Description Ranked full text search over name, short info and description in Uzbek, Russian and English. Words match as prefixes and are expanded with synonyms; names similar to the query match despite typos. Matches are wrapped in <mark> tags in highlight.
+// @ID          search-products
+// @Tags  	    product
+// @Produce     json
+// @Param       q           query string true  "Search query"
+// @Param       category_id query string false "Category ID"
+// @Param       limit       query int    false "Page size, 100 at most"
+// @Param       cursor      query string false "next_cursor of the previous page"
+// @Success     200 {object} entity.Page[entity.SearchHit]
+// @Failure     400 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/search [get]
+func (r *productRoutes) searchProducts(ctx *gin.Context) {
+	var search entity.ProductSearch
+	if err := ctx.ShouldBindQuery(&search); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	page, err := r.t.SearchProducts(ctx, search)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, page)
+}
+
+// @Summary     Suggest products
+// @Description Autocomplete product names: names starting with q first, then names with a word similar to it
+// @ID          suggest-products
+// @Tags  	    product
+// @Produce     json
+// @Param       q     query string true  "Typed prefix"
+// @Param       limit query int    false "Number of suggestions, 10 by default"
+// @Success     200 {array}  string
+// @Failure     400 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/suggest [get]
+func (r *productRoutes) suggestProducts(ctx *gin.Context) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "0"))
+	if err != nil {
+		errorResponse(ctx, entity.NewValidationError("limit", "must be an integer"))
+		return
+	}
+
+	names, err := r.t.SuggestProducts(ctx, ctx.Query("q"), limit)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, names)
+}
+
+// @Summary     List search synonyms
+// @Description List the word pairs that match each other in product search
+// @ID          list-search-synonyms
+// @Security    BearerAuth
+// @Tags  	    product
+// @Produce     json
+// @Success     200 {array}  entity.SearchSynonym
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/search/synonym [get]
+func (r *productRoutes) listSynonyms(ctx *gin.Context) {
+	synonyms, err := r.t.ListSynonyms(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, synonyms)
+}
+
+// @Summary     Create search synonym
+// @Description Make two words match each other in product search, in both directions
+// @ID          create-search-synonym
+// @Security    BearerAuth
+// @Tags  	    product
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.SearchSynonym true "Synonym pair"
+// @Success     201 {object} entity.SearchSynonym
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/search/synonym [post]
+func (r *productRoutes) createSynonym(ctx *gin.Context) {
+	var request entity.SearchSynonym
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	synonym, err := r.t.CreateSynonym(ctx, request)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, synonym)
+}
+
+// @Summary     Delete search synonym
+// @Description Delete a synonym pair
+// @ID          delete-search-synonym
+// @Security    BearerAuth
+// @Tags  	    product
+// @Produce     json
+// @Param       term    query string true "Term"
+// @Param       synonym query string true "Synonym"
+// @Success     204 {object} nil
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/search/synonym [delete]
+func (r *productRoutes) deleteSynonym(ctx *gin.Context) {
+	err := r.t.DeleteSynonym(ctx, ctx.Query("term"), ctx.Query("synonym"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
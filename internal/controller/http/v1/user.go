@@ -0,0 +1,248 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-seller/internal/controller/http/middleware"
+	"ai-seller/internal/entity"
+	"ai-seller/internal/usecase"
+	"ai-seller/pkg/logger"
+)
+
+type userRoutes struct {
+	t usecase.UseCases
+	l logger.Interface
+}
+
+func NewUserRoutes(apiV1Group *gin.RouterGroup, t usecase.UseCases, l logger.Interface) {
+	r := &userRoutes{t, l}
+
+	userGroup := apiV1Group.Group("/user", middleware.Auth(t), middleware.Permission(t, entity.PermissionUserManage))
+	{
+		userGroup.GET("/trash", r.listDeletedUsers)
+		userGroup.GET("/:id", r.getUser)
+		userGroup.PUT("/:id", r.updateUser)
+		userGroup.PATCH("/:id", r.patchUser)
+		userGroup.DELETE("/:id", r.deleteUser)
+		userGroup.POST("/:id/restore", r.restoreUser)
+	}
+}
+
+// @Summary     Get user
+// @Description Get a user by ID
+// @ID          get-user
+// @Tags  	    user
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "User ID"
+// @Success     200 {object} userResponse
+// @Header      200 {string} ETag "Row version"
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /user/{id} [get]
+func (r *userRoutes) getUser(ctx *gin.Context) {
+	user, err := r.t.GetUser(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, user.Version)
+	ctx.JSON(http.StatusOK, user)
+}
+
+// @Summary     Update user
+// @Description Replace a user by ID; the password is kept when omitted
+// @ID          update-user
+// @Tags  	    user
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id       path   string      true "User ID"
+// @Param       request  body   entity.User true "User request"
+// @Param       If-Match header string      true "ETag of the last read"
+// @Success     200 {object} userResponse
+// @Header      200 {string} ETag "Row version"
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     412 {object} problem
+// @Failure     422 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
+// @Router      /user/{id} [put]
+func (r *userRoutes) updateUser(ctx *gin.Context) {
+	var user entity.User
+	if err := ctx.ShouldBindJSON(&user); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	user.ID = ctx.Param("id")
+
+	version, err := ifMatch(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	user.Version = version
+
+	err = r.t.UpdateUser(ctx, user)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	user, err = r.t.GetUser(ctx, user.ID)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, user.Version)
+	ctx.JSON(http.StatusOK, user)
+}
+
+// @Summary     Patch user
+// @Description Update only the fields present in a JSON Merge Patch (RFC 7396) document; null clears a field
+// @ID          patch-user
+// @Tags  	    user
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id       path   string           true "User ID"
+// @Param       request  body   entity.UserPatch true "Merge patch"
+// @Param       If-Match header string           true "ETag of the last read"
+// @Success     200 {object} userResponse
+// @Header      200 {string} ETag "Row version"
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     412 {object} problem
+// @Failure     422 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
+// @Router      /user/{id} [patch]
+func (r *userRoutes) patchUser(ctx *gin.Context) {
+	var patch entity.UserPatch
+	if err := ctx.ShouldBindJSON(&patch); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	version, err := ifMatch(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	patch.Version = version
+
+	user, err := r.t.PatchUser(ctx, ctx.Param("id"), patch)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, user.Version)
+	ctx.JSON(http.StatusOK, user)
+}
+
+// @Summary     Delete user
+// @Description Move a user to the trash and revoke its refresh tokens. It can be restored until the retention window has passed; then it is purged for good unless it placed an order.
+// @ID          delete-user
+// @Tags  	    user
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id       path   string true "User ID"
+// @Param       If-Match header string true "ETag of the last read"
+// @Success     204 {object} nil
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     412 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
+// @Router      /user/{id} [delete]
+func (r *userRoutes) deleteUser(ctx *gin.Context) {
+	version, err := ifMatch(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	err = r.t.DeleteUser(ctx, ctx.Param("id"), version)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary     List deleted users
+// @Description Page through the users in the trash, most recently deleted first
+// @ID          list-deleted-users
+// @Tags  	    user
+// @Produce     json
+// @Security    BearerAuth
+// @Param       sort   query string false "Sort column, '-' prefix for descending" Enums(deleted_at, -deleted_at, name, -name)
+// @Param       limit  query int    false "Page size, 100 at most"
+// @Param       cursor query string false "next_cursor of the previous page"
+// @Success     200 {object} entity.Page[entity.User]
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /user/trash [get]
+func (r *userRoutes) listDeletedUsers(ctx *gin.Context) {
+	var params entity.ListParams
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	page, err := r.t.ListDeletedUsers(ctx, params)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, page)
+}
+
+// @Summary     Restore user
+// @Description Take a user out of the trash. The user has to sign in again; a username taken in the meantime is a conflict.
+// @ID          restore-user
+// @Tags  	    user
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "User ID"
+// @Success     200 {object} userResponse
+// @Header      200 {string} ETag "Row version"
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     500 {object} problem
+// @Router      /user/{id}/restore [post]
+func (r *userRoutes) restoreUser(ctx *gin.Context) {
+	user, err := r.t.RestoreUser(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, user.Version)
+	ctx.JSON(http.StatusOK, user)
+}
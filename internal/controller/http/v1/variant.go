@@ -0,0 +1,184 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-seller/internal/entity"
+)
+
+// @Summary     List variants
+// @Description List the variants of a product with their options
+// @ID          list-variants
+// @Tags  	    product
+// @Produce     json
+// @Param       id path string true "Product ID"
+// @Success     200 {array}  entity.ProductVariant
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/variant [get]
+func (r *productRoutes) listVariants(ctx *gin.Context) {
+	variants, err := r.t.ListVariants(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, variants)
+}
+
+// @Summary     Create variant
+// @Description Add a variant with its own SKU, price and stock to a product. Options are values of attributes of the product's category and must differ from those of the other variants. Once a product has variants its stock and lowest price follow them and orders must name a variant.
+// @ID          create-variant
+// @Security    BearerAuth
+// @Tags  	    product
+// @Accept      json
+// @Produce     json
+// @Param       id      path string                true "Product ID"
+// @Param       request body entity.ProductVariant true "Variant request"
+// @Success     201 {object} entity.ProductVariant
+// @Header      201 {string} ETag "Row version"
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/variant [post]
+func (r *productRoutes) createVariant(ctx *gin.Context) {
+	var request entity.ProductVariant
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	request.ProductID = ctx.Param("id")
+
+	variant, err := r.t.CreateVariant(ctx, request)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, variant.Version)
+	ctx.JSON(http.StatusCreated, variant)
+}
+
+// @Summary     Get variant
+// @Description Get a variant of a product
+// @ID          get-variant
+// @Tags  	    product
+// @Produce     json
+// @Param       id         path string true "Product ID"
+// @Param       variant_id path string true "Variant ID"
+// @Success     200 {object} entity.ProductVariant
+// @Header      200 {string} ETag "Row version"
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/variant/{variant_id} [get]
+func (r *productRoutes) getVariant(ctx *gin.Context) {
+	variant, err := r.t.GetVariant(ctx, ctx.Param("id"), ctx.Param("variant_id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, variant.Version)
+	ctx.JSON(http.StatusOK, variant)
+}
+
+// @Summary     Update variant
+// @Description Replace a variant of a product, options included. A changed count is recorded as for products.
+// @ID          update-variant
+// @Security    BearerAuth
+// @Tags  	    product
+// @Accept      json
+// @Produce     json
+// @Param       id         path   string                true "Product ID"
+// @Param       variant_id path   string                true "Variant ID"
+// @Param       request    body   entity.ProductVariant true "Variant request"
+// @Param       If-Match   header string                true "ETag of the last read"
+// @Success     200 {object} entity.ProductVariant
+// @Header      200 {string} ETag "Row version"
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     412 {object} problem
+// @Failure     422 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/variant/{variant_id} [put]
+func (r *productRoutes) updateVariant(ctx *gin.Context) {
+	var request entity.ProductVariant
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	version, err := ifMatch(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	request.ID = ctx.Param("variant_id")
+	request.ProductID = ctx.Param("id")
+	request.Version = version
+
+	variant, err := r.t.UpdateVariant(ctx, request)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	setETag(ctx, variant.Version)
+	ctx.JSON(http.StatusOK, variant)
+}
+
+// @Summary     Delete variant
+// @Description Delete a variant of a product; variants that were ordered cannot be deleted
+// @ID          delete-variant
+// @Security    BearerAuth
+// @Tags  	    product
+// @Produce     json
+// @Param       id         path   string true "Product ID"
+// @Param       variant_id path   string true "Variant ID"
+// @Param       If-Match   header string true "ETag of the last read"
+// @Success     204 {object} nil
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     412 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
+// @Router      /product/{id}/variant/{variant_id} [delete]
+func (r *productRoutes) deleteVariant(ctx *gin.Context) {
+	version, err := ifMatch(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	err = r.t.DeleteVariant(ctx, ctx.Param("id"), ctx.Param("variant_id"), version)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
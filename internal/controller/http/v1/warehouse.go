@@ -0,0 +1,364 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"ai-seller/internal/controller/http/middleware"
+	"ai-seller/internal/entity"
+	"ai-seller/internal/usecase"
+	"ai-seller/pkg/logger"
+)
+
+type warehouseRoutes struct {
+	t usecase.UseCases
+	l logger.Interface
+	v *validator.Validate
+}
+
+func NewWarehouseRoutes(apiV1Group *gin.RouterGroup, t usecase.UseCases, l logger.Interface) {
+	r := &warehouseRoutes{t, l, validator.New(validator.WithRequiredStructEnabled())}
+
+	auth, manage := middleware.Auth(t), middleware.Permission(t, entity.PermissionStockManage)
+
+	warehouseGroup := apiV1Group.Group("/warehouse")
+	{
+		warehouseGroup.GET("/", r.listWarehouses)
+		warehouseGroup.POST("/", auth, manage, r.createWarehouse)
+		warehouseGroup.GET("/:id", r.getWarehouse)
+		warehouseGroup.PUT("/:id", auth, manage, r.updateWarehouse)
+		warehouseGroup.DELETE("/:id", auth, manage, r.deleteWarehouse)
+	}
+
+	stockGroup := apiV1Group.Group("/stock")
+	{
+		stockGroup.GET("/", r.listStockLevels)
+		stockGroup.GET("/movement", auth, manage, r.listStockMovements)
+		stockGroup.POST("/receipt", auth, manage, r.receiveStock)
+		stockGroup.POST("/transfer", auth, manage, r.transferStock)
+		stockGroup.POST("/adjustment", auth, manage, r.adjustStock)
+	}
+}
+
+// @Summary     List warehouses
+// @Description List the warehouses stock is kept in, the default one first
+// @ID          list-warehouses
+// @Tags  	    warehouse
+// @Produce     json
+// @Success     200 {array}  entity.Warehouse
+// @Failure     500 {object} problem
+// @Router      /warehouse [get]
+func (r *warehouseRoutes) listWarehouses(ctx *gin.Context) {
+	warehouses, err := r.t.ListWarehouses(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, warehouses)
+}
+
+// @Summary     Create warehouse
+// @Description Add a warehouse. With default set it becomes the warehouse stock edited on products goes to.
+// @ID          create-warehouse
+// @Security    BearerAuth
+// @Tags  	    warehouse
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.Warehouse true "Warehouse request"
+// @Success     201 {object} entity.Warehouse
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /warehouse [post]
+func (r *warehouseRoutes) createWarehouse(ctx *gin.Context) {
+	var request entity.Warehouse
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	warehouse, err := r.t.CreateWarehouse(ctx, request)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, warehouse)
+}
+
+// @Summary     Get warehouse
+// @Description Get a warehouse by ID
+// @ID          get-warehouse
+// @Tags  	    warehouse
+// @Produce     json
+// @Param       id path string true "Warehouse ID"
+// @Success     200 {object} entity.Warehouse
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /warehouse/{id} [get]
+func (r *warehouseRoutes) getWarehouse(ctx *gin.Context) {
+	warehouse, err := r.t.GetWarehouse(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, warehouse)
+}
+
+// @Summary     Update warehouse
+// @Description Replace a warehouse by ID. Setting default moves the default to it; the default cannot be unset, only moved.
+// @ID          update-warehouse
+// @Security    BearerAuth
+// @Tags  	    warehouse
+// @Accept      json
+// @Produce     json
+// @Param       id      path string           true "Warehouse ID"
+// @Param       request body entity.Warehouse true "Warehouse request"
+// @Success     200 {object} entity.Warehouse
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /warehouse/{id} [put]
+func (r *warehouseRoutes) updateWarehouse(ctx *gin.Context) {
+	var request entity.Warehouse
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	request.ID = ctx.Param("id")
+
+	warehouse, err := r.t.UpdateWarehouse(ctx, request)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, warehouse)
+}
+
+// @Summary     Delete warehouse
+// @Description Delete a warehouse that never held stock. The default warehouse cannot be deleted.
+// @ID          delete-warehouse
+// @Security    BearerAuth
+// @Tags  	    warehouse
+// @Param       id path string true "Warehouse ID"
+// @Success     204
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /warehouse/{id} [delete]
+func (r *warehouseRoutes) deleteWarehouse(ctx *gin.Context) {
+	err := r.t.DeleteWarehouse(ctx, ctx.Param("id"))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary     List stock levels
+// @Description List the stock per warehouse of products and variants. A product's count is the sum over its levels, or over those of its variants.
+// @ID          list-stock-levels
+// @Tags  	    stock
+// @Produce     json
+// @Param       warehouse_id query string false "Warehouse ID"
+// @Param       product_id   query string false "Product ID"
+// @Param       variant_id   query string false "Variant ID"
+// @Success     200 {array}  entity.StockLevel
+// @Failure     400 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /stock [get]
+func (r *warehouseRoutes) listStockLevels(ctx *gin.Context) {
+	var filter entity.StockLevelFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	levels, err := r.t.GetStockLevels(ctx, filter)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, levels)
+}
+
+// @Summary     List stock movements
+// @Description Page through the stock ledger, newest first by default. Every change of stock is a movement: receipts, sales and returns of orders, adjustments with their reason and the two legs of transfers.
+// @ID          list-stock-movements
+// @Security    BearerAuth
+// @Tags  	    stock
+// @Produce     json
+// @Param       warehouse_id query string false "Warehouse ID"
+// @Param       product_id   query string false "Product ID"
+// @Param       variant_id   query string false "Variant ID"
+// @Param       type         query string false "Movement type" Enums(receipt, sale, return, adjustment, transfer)
+// @Param       order_id     query string false "Order ID"
+// @Param       sort         query string false "Sort column, '-' prefix for descending" Enums(created_at, -created_at)
+// @Param       limit        query int    false "Page size, 100 at most"
+// @Param       cursor       query string false "next_cursor of the previous page"
+// @Success     200 {object} entity.Page[entity.StockMovement]
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /stock/movement [get]
+func (r *warehouseRoutes) listStockMovements(ctx *gin.Context) {
+	var filter entity.StockMovementFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	page, err := r.t.ListStockMovements(ctx, filter)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, page)
+}
+
+// @Summary     Receive stock
+// @Description Record goods received into a warehouse. Products with variants are received per variant.
+// @ID          receive-stock
+// @Security    BearerAuth
+// @Tags  	    stock
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.StockReceipt true "Receipt request"
+// @Success     201 {object} entity.StockMovement
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /stock/receipt [post]
+func (r *warehouseRoutes) receiveStock(ctx *gin.Context) {
+	var request entity.StockReceipt
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	movement, err := r.t.ReceiveStock(ctx, request, ctx.GetString(middleware.UserIDKey))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, movement)
+}
+
+// @Summary     Transfer stock
+// @Description Move items from one warehouse to another. The ledger gets a movement out of the source and one into the destination sharing a transfer_id.
+// @ID          transfer-stock
+// @Security    BearerAuth
+// @Tags  	    stock
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.StockTransfer true "Transfer request"
+// @Success     201 {array}  entity.StockMovement
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /stock/transfer [post]
+func (r *warehouseRoutes) transferStock(ctx *gin.Context) {
+	var request entity.StockTransfer
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	movements, err := r.t.TransferStock(ctx, request, ctx.GetString(middleware.UserIDKey))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, movements)
+}
+
+// @Summary     Adjust stock
+// @Description Correct the stock of a warehouse to the count found, after a stocktake or for damaged, lost or found items. The difference is recorded with the reason; nothing is recorded when the count was right.
+// @ID          adjust-stock
+// @Security    BearerAuth
+// @Tags  	    stock
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.StockAdjustment true "Adjustment request"
+// @Success     201 {object} entity.StockMovement
+// @Success     204
+// @Failure     400 {object} problem
+// @Failure     401 {object} problem
+// @Failure     403 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /stock/adjustment [post]
+func (r *warehouseRoutes) adjustStock(ctx *gin.Context) {
+	var request entity.StockAdjustment
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		bindErrorResponse(ctx, err)
+		return
+	}
+
+	movement, changed, err := r.t.AdjustStock(ctx, request, ctx.GetString(middleware.UserIDKey))
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	if !changed {
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, movement)
+}
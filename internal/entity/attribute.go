@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Attribute value types.
+const (
+	AttributeTypeString  = "string"
+	AttributeTypeNumber  = "number"
+	AttributeTypeEnum    = "enum"
+	AttributeTypeBoolean = "boolean"
+	// AttributeTypeUnit is a number measured in the attribute's Unit.
+	AttributeTypeUnit = "unit"
+)
+
+type (
+	// ProductAttribute is the value of an attribute for one product. Value
+	// holds a string, a float64 or a bool depending on Type.
+	ProductAttribute struct {
+		AttributeID string      `json:"attribute_id" validate:"required,uuid"`
+		Name        string      `json:"name"`
+		Type        string      `json:"type"`
+		Unit        string      `json:"unit,omitempty"`
+		Value       interface{} `json:"value"        swaggertype:"string" example:"red"`
+	}
+)
+
+// ValidateDefinition checks that the attribute describes a usable type.
+func (a Attribute) ValidateDefinition() error {
+	switch a.Type {
+	case AttributeTypeString, AttributeTypeNumber, AttributeTypeBoolean:
+	case AttributeTypeEnum:
+		if len(a.Options) == 0 {
+			return NewValidationError("options", "must not be empty for enum attributes")
+		}
+	case AttributeTypeUnit:
+		if a.Unit == "" {
+			return NewValidationError("unit", "must not be empty for unit attributes")
+		}
+	default:
+		return NewValidationError("type", "must be one of string, number, enum, boolean, unit")
+	}
+
+	return nil
+}
+
+// ValidateValue checks a decoded JSON value against the attribute's type.
+func (a Attribute) ValidateValue(v interface{}) error {
+	field := "attributes." + a.Name
+
+	switch a.Type {
+	case AttributeTypeString:
+		if _, ok := v.(string); !ok {
+			return NewValidationError(field, "must be a string")
+		}
+	case AttributeTypeNumber, AttributeTypeUnit:
+		if _, ok := v.(float64); !ok {
+			return NewValidationError(field, "must be a number")
+		}
+	case AttributeTypeBoolean:
+		if _, ok := v.(bool); !ok {
+			return NewValidationError(field, "must be a boolean")
+		}
+	case AttributeTypeEnum:
+		s, ok := v.(string)
+		if !ok || !slices.Contains(a.Options, s) {
+			return NewValidationError(field, fmt.Sprintf("must be one of %v", a.Options))
+		}
+	default:
+		return NewValidationError(field, "has unknown type "+a.Type)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttributeValidateDefinition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		attribute Attribute
+		field     string
+	}{
+		{name: "string", attribute: Attribute{Type: AttributeTypeString}},
+		{name: "number", attribute: Attribute{Type: AttributeTypeNumber}},
+		{name: "boolean", attribute: Attribute{Type: AttributeTypeBoolean}},
+		{name: "enum", attribute: Attribute{Type: AttributeTypeEnum, Options: []string{"red"}}},
+		{name: "enum without options", attribute: Attribute{Type: AttributeTypeEnum}, field: "options"},
+		{name: "unit", attribute: Attribute{Type: AttributeTypeUnit, Unit: "GB"}},
+		{name: "unit without unit", attribute: Attribute{Type: AttributeTypeUnit}, field: "unit"},
+		{name: "unknown type", attribute: Attribute{Type: "date"}, field: "type"},
+		{name: "no type", attribute: Attribute{}, field: "type"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.attribute.ValidateDefinition()
+			if tc.field == "" {
+				require.NoError(t, err)
+
+				return
+			}
+
+			var v *ValidationError
+			require.ErrorAs(t, err, &v)
+			require.Equal(t, tc.field, v.Field)
+		})
+	}
+}
+
+func TestAttributeValidateValue(t *testing.T) {
+	t.Parallel()
+
+	color := Attribute{Name: "color", Type: AttributeTypeEnum, Options: []string{"red", "blue"}}
+
+	tests := []struct {
+		name      string
+		attribute Attribute
+		value     interface{}
+		err       bool
+	}{
+		{name: "string", attribute: Attribute{Type: AttributeTypeString}, value: "cotton"},
+		{name: "string given a number", attribute: Attribute{Type: AttributeTypeString}, value: 1.5, err: true},
+		{name: "number", attribute: Attribute{Type: AttributeTypeNumber}, value: 1.5},
+		{name: "number given a string", attribute: Attribute{Type: AttributeTypeNumber}, value: "1.5", err: true},
+		{name: "number given an int", attribute: Attribute{Type: AttributeTypeNumber}, value: 1, err: true},
+		{name: "unit", attribute: Attribute{Type: AttributeTypeUnit, Unit: "GB"}, value: 256.0},
+		{name: "unit given a bool", attribute: Attribute{Type: AttributeTypeUnit, Unit: "GB"}, value: true, err: true},
+		{name: "boolean", attribute: Attribute{Type: AttributeTypeBoolean}, value: false},
+		{name: "boolean given a string", attribute: Attribute{Type: AttributeTypeBoolean}, value: "true", err: true},
+		{name: "enum option", attribute: color, value: "blue"},
+		{name: "enum other value", attribute: color, value: "green", err: true},
+		{name: "enum given a number", attribute: color, value: 1.0, err: true},
+		{name: "unknown type", attribute: Attribute{Type: "date"}, value: "2026-01-01", err: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.attribute.ValidateValue(tc.value)
+			if tc.err {
+				require.ErrorIs(t, err, ErrValidation)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -1,41 +1,113 @@
 package entity
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type (
 	// User -.
 	User struct {
-		ID         string `json:"id"`
-		Name       string `json:"name"`
-		Surname    string `json:"surname"`
-		Username   string `json:"username"`
-		Password   string `json:"password"`
-		BirthDate  string `json:"birth_date"`
-		TgUserName string `json:"tg_user_name"`
-		Phone      string `json:"phone"`
-		Instagram  string `json:"instagram"`
-		ClientFrom string `json:"client_from"`
-		RoleID     string `json:"role_id"`
-		CreatedAt  string `json:"created_at"`
-		UpdatedAt  string `json:"updated_at"`
+		ID         string     `json:"id"`
+		Name       string     `json:"name"`
+		Surname    string     `json:"surname"`
+		Username   string     `json:"username"`
+		Password   string     `json:"password,omitempty"`
+		BirthDate  *Date      `json:"birth_date" swaggertype:"string" format:"date" example:"1990-05-01"`
+		TgUserName string     `json:"tg_user_name"`
+		Phone      string     `json:"phone"`
+		Instagram  string     `json:"instagram"`
+		ClientFrom string     `json:"client_from"`
+		RoleID     string     `json:"role_id"`
+		CreatedAt  time.Time  `json:"created_at"`
+		UpdatedAt  time.Time  `json:"updated_at"`
+		Version    int        `json:"version"`
+		DeletedAt  *time.Time `json:"deleted_at,omitempty"`
 	}
 )
 
+type userJSON User
+
+// MarshalJSON never writes the password (or its hash) out of the API.
+func (u User) MarshalJSON() ([]byte, error) {
+	u.Password = ""
+
+	return json.Marshal(userJSON(u))
+}
+
 type (
 	// Role -.
 	Role struct {
-		ID           string `json:"id"`
-		Name         string `json:"name"`
-		ClientTypeId string `json:"client_type_id"`
-		CreatedAt    string `json:"created_at"`
-		UpdatedAt    string `json:"updated_at"`
+		ID           string    `json:"id"`
+		Name         string    `json:"name"`
+		ClientTypeId string    `json:"client_type_id"`
+		CreatedAt    time.Time `json:"created_at"`
+		UpdatedAt    time.Time `json:"updated_at"`
+	}
+)
+
+// Permission names checked by the HTTP layer.
+const (
+	PermissionProductCreate   = "product:create"
+	PermissionProductUpdate   = "product:update"
+	PermissionProductDelete   = "product:delete"
+	PermissionProductImport   = "product:import"
+	PermissionRoleManage      = "role:manage"
+	PermissionOrderRead       = "order:read"
+	PermissionOrderCreate     = "order:create"
+	PermissionOrderUpdate     = "order:update"
+	PermissionUserManage      = "user:manage"
+	PermissionPromotionManage = "promotion:manage"
+	PermissionCurrencyManage  = "currency:manage"
+	PermissionStockManage     = "stock:manage"
+)
+
+type (
+	// Permission -.
+	Permission struct {
+		ID          string    `json:"id"`
+		Name        string    `json:"name"`
+		Description string    `json:"description"`
+		CreatedAt   time.Time `json:"created_at"`
+		UpdatedAt   time.Time `json:"updated_at"`
 	}
 )
 
 type (
 	// ClientType -.
 	ClientType struct {
-		ID        string `json:"id"`
-		Name      string `json:"name"`
-		CreatedAt string `json:"created_at"`
-		UpdatedAt string `json:"updated_at"`
+		ID        string    `json:"id"`
+		Name      string    `json:"name"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+)
+
+type (
+	// Tokens -.
+	Tokens struct {
+		AccessToken           string    `json:"access_token"`
+		AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+		RefreshToken          string    `json:"refresh_token"`
+		RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+		TokenType             string    `json:"token_type" example:"Bearer"`
+	}
+
+	// RefreshToken is a persisted refresh token. Only the hash of the token
+	// is stored; tokens rotated from the same login share FamilyID.
+	RefreshToken struct {
+		ID        string
+		UserID    string
+		FamilyID  string
+		TokenHash string
+		ExpiresAt time.Time
+		RevokedAt *time.Time
+		CreatedAt time.Time
+	}
+
+	// AccessClaims -.
+	AccessClaims struct {
+		UserID string
+		RoleID string
 	}
 )
@@ -0,0 +1,87 @@
+package entity
+
+import "time"
+
+// Cart item problems. An unavailable item's product or variant is gone or
+// the product got variants since; an item with insufficient stock asks for
+// more than is available. Either keeps the item out of the totals.
+const (
+	CartItemUnavailable       = "unavailable"
+	CartItemInsufficientStock = "insufficient_stock"
+)
+
+type (
+	// Cart is the basket of a signed-in user or of an anonymous session,
+	// such as a web visitor or a chat. Prices, discounts and totals are
+	// computed when the cart is read, in Currency. SessionID is the hash of
+	// the session token; the token itself is only returned, as SessionToken,
+	// when the session is issued.
+	Cart struct {
+		ID           string          `json:"id,omitempty"`
+		UserID       string          `json:"user_id,omitempty"`
+		SessionID    string          `json:"-"`
+		SessionToken string          `json:"session_token,omitempty"`
+		Items        []CartItem      `json:"items"`
+		SubtotalCost int             `json:"subtotal_cost"`
+		DiscountCost int             `json:"discount_cost"`
+		TotalCost    int             `json:"total_cost"`
+		Currency     string          `json:"currency"      example:"USD"`
+		Discounts    []OrderDiscount `json:"discounts"`
+		CreatedAt    *time.Time      `json:"created_at,omitempty"`
+		UpdatedAt    *time.Time      `json:"updated_at,omitempty"`
+	}
+
+	// CartItem -. Cost is the current unit price in the cart currency.
+	// VariantID is required for products with variants.
+	CartItem struct {
+		ID        string    `json:"id"`
+		CartID    string    `json:"-"`
+		ProductID string    `json:"product_id"        validate:"required,uuid"`
+		VariantID string    `json:"variant_id"        validate:"omitempty,uuid"`
+		Count     int       `json:"count"             validate:"gt=0"`
+		Name      string    `json:"name"`
+		Cost      int       `json:"cost"`
+		Available int       `json:"available"`
+		Problem   string    `json:"problem,omitempty" enums:"unavailable,insufficient_stock"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+
+	// CartKey identifies a cart: the user's, or without a user the one of
+	// the session token.
+	CartKey struct {
+		UserID    string
+		SessionID string
+	}
+
+	// CartQuery selects the currency a cart is priced in and the coupons
+	// applied to it.
+	CartQuery struct {
+		Currency string   `form:"currency" validate:"omitempty,len=3,uppercase"`
+		Coupons  []string `form:"coupon"   validate:"max=5,dive,required,max=64"`
+	}
+
+	// CartCheckout turns a cart into an order, see NewOrder. UserID is the
+	// customer the order is placed for, the caller if omitted.
+	CartCheckout struct {
+		UserID        string   `json:"user_id"        validate:"omitempty,uuid"`
+		IntegrationID string   `json:"integration_id" validate:"omitempty,uuid"`
+		Coupons       []string `json:"coupons"        validate:"max=5,dive,required,max=64"`
+		Currency      string   `json:"currency"       validate:"omitempty,len=3,uppercase" example:"USD"`
+	}
+)
+
+// Subtotal is the price of the lines before discounts.
+func (c Cart) Subtotal() Money {
+	return Money{Amount: c.SubtotalCost, Currency: c.Currency}
+}
+
+// Discount is the total of the discounts.
+func (c Cart) Discount() Money {
+	return Money{Amount: c.DiscountCost, Currency: c.Currency}
+}
+
+// Total is what the customer pays.
+func (c Cart) Total() Money {
+	return Money{Amount: c.TotalCost, Currency: c.Currency}
+}
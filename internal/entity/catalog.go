@@ -0,0 +1,69 @@
+package entity
+
+type (
+	// CatalogQuery is a faceted search over the catalog. Attribute conditions
+	// are combined with AND; the values of one condition with OR.
+	CatalogQuery struct {
+		ListParams
+		CategoryID string               `form:"category_id"`
+		MinPrice   *int                 `form:"min_price"`
+		MaxPrice   *int                 `form:"max_price"`
+		Discounted *bool                `form:"discounted"`
+		InStock    *bool                `form:"in_stock"`
+		Buckets    int                  `form:"buckets"`
+		Attributes []AttributeCondition `form:"-"`
+	}
+
+	// AttributeCondition restricts one attribute to any of Values or, for
+	// number and unit attributes, to the range [Min, Max].
+	AttributeCondition struct {
+		AttributeID string
+		Type        string
+		Values      []string
+		Min         *float64
+		Max         *float64
+	}
+
+	// CatalogResult is a page of matching products with the facets of the
+	// whole result set.
+	CatalogResult struct {
+		Items      []Product  `json:"items"`
+		Total      int        `json:"total"`
+		NextCursor string     `json:"next_cursor,omitempty"`
+		Facets     []Facet    `json:"facets"`
+		Price      PriceFacet `json:"price"`
+	}
+
+	// Facet counts products per value of an attribute. Number and unit
+	// attributes also report their range.
+	Facet struct {
+		AttributeID string       `json:"attribute_id"`
+		Name        string       `json:"name"`
+		Type        string       `json:"type"`
+		Unit        string       `json:"unit,omitempty"`
+		Values      []FacetValue `json:"values,omitempty"`
+		Min         *float64     `json:"min,omitempty"`
+		Max         *float64     `json:"max,omitempty"`
+	}
+
+	// FacetValue -.
+	FacetValue struct {
+		Value interface{} `json:"value" swaggertype:"string"`
+		Count int         `json:"count"`
+	}
+
+	// PriceFacet describes the prices of the result set, ignoring the price
+	// filter itself. Buckets are half-open: From <= price < To.
+	PriceFacet struct {
+		Min     int           `json:"min"`
+		Max     int           `json:"max"`
+		Buckets []PriceBucket `json:"buckets"`
+	}
+
+	// PriceBucket -.
+	PriceBucket struct {
+		From  int `json:"from"`
+		To    int `json:"to"`
+		Count int `json:"count"`
+	}
+)
@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DateLayout is the RFC 3339 full-date layout.
+const DateLayout = time.DateOnly
+
+// Date is a calendar date without time of day, such as a birth date. It is
+// written to JSON as an RFC 3339 full-date ("2006-01-02") and maps to a
+// Postgres DATE column.
+type Date struct {
+	time.Time
+}
+
+// NewDate -.
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// String -.
+func (d Date) String() string {
+	return d.Format(DateLayout)
+}
+
+// MarshalJSON -.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON accepts a full-date or a full RFC 3339 timestamp, of which
+// only the date part is kept.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("date must be a string: %w", err)
+	}
+
+	t, err := time.Parse(DateLayout, s)
+	if err != nil {
+		t, err = time.Parse(time.RFC3339, s)
+		if err != nil {
+			return fmt.Errorf("date must be formatted as %s: %w", DateLayout, err)
+		}
+	}
+
+	*d = NewDate(t.Date())
+
+	return nil
+}
+
+// Scan implements sql.Scanner.
+func (d *Date) Scan(src any) error {
+	t, ok := src.(time.Time)
+	if !ok {
+		return fmt.Errorf("entity.Date - Scan: unsupported type %T", src)
+	}
+
+	*d = NewDate(t.Date())
+
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (d Date) Value() (driver.Value, error) {
+	return d.String(), nil
+}
@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(struct {
+		BirthDate Date `json:"birth_date"`
+	}{NewDate(1990, time.March, 7)})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"birth_date":"1990-03-07"}`, string(data))
+}
+
+func TestDateUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		in      string
+		want    Date
+		wantErr bool
+	}{
+		{name: "full date", in: `"1990-03-07"`, want: NewDate(1990, time.March, 7)},
+		{name: "timestamp", in: `"1990-03-07T15:04:05Z"`, want: NewDate(1990, time.March, 7)},
+		{name: "timestamp keeps its own day", in: `"1990-03-07T01:00:00+05:00"`, want: NewDate(1990, time.March, 7)},
+		{name: "leap day", in: `"2000-02-29"`, want: NewDate(2000, time.February, 29)},
+		{name: "no leap day", in: `"1999-02-29"`, wantErr: true},
+		{name: "other layout", in: `"07.03.1990"`, wantErr: true},
+		{name: "empty", in: `""`, wantErr: true},
+		{name: "number", in: `19900307`, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var d Date
+
+			err := json.Unmarshal([]byte(tc.in), &d)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, tc.want.Equal(d.Time), d)
+		})
+	}
+}
+
+func TestDateRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	in := NewDate(2024, time.December, 31)
+
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+
+	var out Date
+	require.NoError(t, json.Unmarshal(data, &out))
+	require.Equal(t, in, out)
+}
+
+func TestDateScan(t *testing.T) {
+	t.Parallel()
+
+	var d Date
+
+	require.NoError(t, d.Scan(time.Date(1990, time.March, 7, 0, 0, 0, 0, time.Local)))
+	require.Equal(t, NewDate(1990, time.March, 7), d)
+
+	v, err := d.Value()
+	require.NoError(t, err)
+	require.Equal(t, "1990-03-07", v)
+
+	require.Error(t, d.Scan("1990-03-07"))
+}
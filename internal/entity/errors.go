@@ -0,0 +1,42 @@
+package entity
+
+import "errors"
+
+// Domain errors. Repositories translate storage failures into these, use cases
+// propagate them wrapped and the HTTP layer maps them to status codes.
+var (
+	ErrNotFound             = errors.New("resource not found")
+	ErrConflict             = errors.New("resource conflicts with an existing one")
+	ErrForeignKey           = errors.New("referenced resource does not exist")
+	ErrInUse                = errors.New("resource is still referenced")
+	ErrValidation           = errors.New("validation failed")
+	ErrInvalidCredentials   = errors.New("invalid credentials")
+	ErrInvalidToken         = errors.New("invalid token")
+	ErrForbidden            = errors.New("permission denied")
+	ErrInsufficientStock    = errors.New("insufficient stock")
+	ErrInvalidTransition    = errors.New("status transition is not allowed")
+	ErrPreconditionFailed   = errors.New("resource was modified by someone else")
+	ErrPreconditionRequired = errors.New("missing If-Match precondition")
+	ErrTooLarge             = errors.New("file is too large")
+	ErrUnsupportedMedia     = errors.New("unsupported media type")
+)
+
+// ValidationError describes why a single field was rejected.
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+// NewValidationError -.
+func NewValidationError(field, reason string) *ValidationError {
+	return &ValidationError{Field: field, Reason: reason}
+}
+
+func (e *ValidationError) Error() string {
+	return e.Field + ": " + e.Reason
+}
+
+// Unwrap makes errors.Is(err, ErrValidation) hold.
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+// Marketplace feed formats.
+const (
+	FeedGoogle   = "google"
+	FeedFacebook = "facebook"
+	FeedYandex   = "yandex"
+)
+
+type (
+	// Feed is a generated marketplace feed. Version changes whenever the
+	// data it was generated from does.
+	Feed struct {
+		Format      string
+		Data        []byte
+		Version     string
+		GeneratedAt time.Time
+	}
+
+	// FeedSettings describe the shop in feeds. ProductURL is the link of a
+	// product page with {id} standing for the product ID.
+	FeedSettings struct {
+		ShopName   string
+		Company    string
+		ShopURL    string
+		ProductURL string
+	}
+
+	// FeedProduct is a product with the key of its primary image, if any.
+	FeedProduct struct {
+		Product
+		ImageKey string
+	}
+
+	// CategoryFeedMapping assigns marketplace taxonomy categories to a
+	// category, e.g. "Electronics > Communications > Telephony > Mobile
+	// Phones" in Google's taxonomy.
+	CategoryFeedMapping struct {
+		CategoryID       string    `json:"category_id"`
+		GoogleCategory   string    `json:"google_category"   validate:"max=255"`
+		FacebookCategory string    `json:"facebook_category" validate:"max=255"`
+		UpdatedAt        time.Time `json:"updated_at"`
+	}
+)
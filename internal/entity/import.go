@@ -0,0 +1,113 @@
+package entity
+
+import "time"
+
+// Import job statuses.
+const (
+	ImportStatusPending = "pending"
+	ImportStatusRunning = "running"
+	ImportStatusDone    = "done"
+	ImportStatusFailed  = "failed"
+)
+
+// Catalog file formats.
+const (
+	CatalogFormatCSV  = "csv"
+	CatalogFormatXLSX = "xlsx"
+)
+
+type (
+	// ImportJob tracks a catalog import running in the background. Rows
+	// that could not be imported are listed in Errors; the others are
+	// committed batch by batch.
+	ImportJob struct {
+		ID            string           `json:"id"`
+		Status        string           `json:"status"         example:"running"`
+		Format        string           `json:"format"         example:"xlsx"`
+		FileName      string           `json:"file_name"`
+		TotalRows     int              `json:"total_rows"`
+		ProcessedRows int              `json:"processed_rows"`
+		CreatedRows   int              `json:"created_rows"`
+		UpdatedRows   int              `json:"updated_rows"`
+		FailedRows    int              `json:"failed_rows"`
+		Errors        []ImportRowError `json:"errors"`
+		Error         string           `json:"error,omitempty"`
+		CreatedBy     string           `json:"created_by"`
+		CreatedAt     time.Time        `json:"created_at"`
+		UpdatedAt     time.Time        `json:"updated_at"`
+		FinishedAt    *time.Time       `json:"finished_at"`
+	}
+
+	// CatalogImport describes an uploaded catalog file. Mapping maps catalog
+	// columns, such as "cost" or "attribute:Color", to the headers of the
+	// file; when it is empty, headers named after catalog columns are used.
+	CatalogImport struct {
+		FileName  string
+		Mapping   map[string]string
+		CreatedBy string
+	}
+
+	// ImportRowError explains why a row was not imported. Row is the line
+	// number in the file, the header being line 1.
+	ImportRowError struct {
+		Row     int    `json:"row"`
+		Message string `json:"message"`
+	}
+
+	// ImportRow is a product read from a catalog file. Only the fields set
+	// in Columns were mapped and are written to the product.
+	ImportRow struct {
+		Line         int
+		Columns      map[string]bool
+		SKU          string
+		Name         string
+		Category     string
+		ShortInfo    string
+		Description  string
+		Cost         int
+		Count        int
+		DiscountCost int
+		Discount     int
+		Attributes   map[string]string
+	}
+
+	// CatalogExport selects the products to export and the file format.
+	CatalogExport struct {
+		Format             string `form:"format"              validate:"omitempty,oneof=csv xlsx"`
+		CategoryID         string `form:"category_id"         validate:"omitempty,uuid"`
+		IncludeDescendants bool   `form:"include_descendants"`
+	}
+)
+
+// Catalog file columns. Attribute values go in columns named
+// ImportAttributePrefix followed by the attribute name.
+const (
+	ImportColumnSKU          = "sku"
+	ImportColumnName         = "name"
+	ImportColumnCategory     = "category"
+	ImportColumnShortInfo    = "short_info"
+	ImportColumnDescription  = "description"
+	ImportColumnCost         = "cost"
+	ImportColumnCount        = "count"
+	ImportColumnDiscountCost = "discount_cost"
+	ImportColumnDiscount     = "discount"
+
+	ImportAttributePrefix = "attribute:"
+
+	// ImportCategorySeparator separates the levels of a category path such
+	// as "Electronics / Phones".
+	ImportCategorySeparator = " / "
+)
+
+// ImportColumns lists the product columns of a catalog file in export order.
+var ImportColumns = []string{
+	ImportColumnSKU,
+	ImportColumnName,
+	ImportColumnCategory,
+	ImportColumnShortInfo,
+	ImportColumnDescription,
+	ImportColumnCost,
+	ImportColumnCount,
+	ImportColumnDiscountCost,
+	ImportColumnDiscount,
+}
@@ -0,0 +1,49 @@
+package entity
+
+import "time"
+
+type (
+	// ProductMedia is an image of a product. Images are shown in position
+	// order; the primary one represents the product in lists and chats.
+	ProductMedia struct {
+		ID          string           `json:"id"`
+		ProductID   string           `json:"product_id"`
+		Key         string           `json:"-"`
+		URL         string           `json:"url"`
+		ContentType string           `json:"content_type"`
+		Size        int64            `json:"size"`
+		Width       int              `json:"width"`
+		Height      int              `json:"height"`
+		Position    int              `json:"position"`
+		Primary     bool             `json:"primary"`
+		Thumbnails  []MediaThumbnail `json:"thumbnails"`
+		CreatedAt   time.Time        `json:"created_at"`
+	}
+
+	// MediaThumbnail is a downscaled copy of an image whose longer side is
+	// Size pixels. Only sizes smaller than the image itself are generated.
+	MediaThumbnail struct {
+		Size   int    `json:"size"`
+		Key    string `json:"-"`
+		URL    string `json:"url"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
+	}
+
+	// MediaOrder lists all images of a product in their new order.
+	MediaOrder struct {
+		Media []string `json:"media" validate:"required,dive,uuid"`
+	}
+)
+
+// Keys returns the storage keys of the image and its thumbnails.
+func (m ProductMedia) Keys() []string {
+	keys := make([]string, 0, len(m.Thumbnails)+1)
+	keys = append(keys, m.Key)
+
+	for _, t := range m.Thumbnails {
+		keys = append(keys, t.Key)
+	}
+
+	return keys
+}
@@ -0,0 +1,182 @@
+package entity
+
+import (
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Exchange rate sources.
+const (
+	RateSourceManual = "manual"
+	RateSourceImport = "import"
+)
+
+// _decimal is a plain decimal number. big.Rat alone would also take
+// fractions, exponents, base prefixes and digit separators.
+var _decimal = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)$`)
+
+type (
+	// Money is an amount in the minor units of its currency: 1050 USD is
+	// $10.50. Prices are computed as Money. Entities store and serialize
+	// their amounts flat, as minor units next to one currency code, and
+	// hand them out as Money through methods such as Order.Total.
+	Money struct {
+		Amount   int    `json:"amount"   example:"1050"`
+		Currency string `json:"currency" example:"USD"`
+	}
+
+	// Currency -. MinorUnits is the number of decimal places of the minor
+	// unit. Product costs are in the Base currency.
+	Currency struct {
+		Code       string    `json:"code"        example:"USD"`
+		Name       string    `json:"name"        example:"US dollar"`
+		MinorUnits int       `json:"minor_units" example:"2"`
+		Base       bool      `json:"base"`
+		CreatedAt  time.Time `json:"created_at"`
+	}
+
+	// ExchangeRate is the price of one unit of Currency in the base
+	// currency from EffectiveAt on. Rate is a decimal.
+	ExchangeRate struct {
+		ID          string    `json:"id"`
+		Currency    string    `json:"currency"     example:"USD"`
+		Rate        string    `json:"rate"         example:"12650.5"`
+		Source      string    `json:"source"       example:"manual"`
+		EffectiveAt time.Time `json:"effective_at"`
+		CreatedBy   string    `json:"created_by,omitempty"`
+		CreatedAt   time.Time `json:"created_at"`
+	}
+
+	// NewExchangeRate -. EffectiveAt defaults to now.
+	NewExchangeRate struct {
+		Currency    string     `json:"currency"     validate:"required,len=3,uppercase" example:"USD"`
+		Rate        string     `json:"rate"         validate:"required,numeric"         example:"12650.5"`
+		EffectiveAt *time.Time `json:"effective_at"`
+	}
+
+	// ExchangeRateImport is a batch of rates taken from an outside source,
+	// such as a central bank feed.
+	ExchangeRateImport struct {
+		Rates []NewExchangeRate `json:"rates" validate:"required,min=1,max=200,dive"`
+	}
+
+	// ExchangeRateFilter -.
+	ExchangeRateFilter struct {
+		ListParams
+		Currency string `form:"currency"`
+	}
+
+	// ProductPrice is the price of a product in a currency other than the
+	// base one, in its minor units. Without VariantID it applies to every
+	// variant that has no price of its own.
+	ProductPrice struct {
+		ID           string    `json:"id"`
+		ProductID    string    `json:"product_id"`
+		VariantID    string    `json:"variant_id,omitempty" validate:"omitempty,uuid"`
+		Currency     string    `json:"currency"             validate:"required,len=3,uppercase" example:"USD"`
+		Cost         int       `json:"cost"                 validate:"min=0"                    example:"1050"`
+		DiscountCost int       `json:"discount_cost"        validate:"min=0"`
+		CreatedAt    time.Time `json:"created_at"`
+		UpdatedAt    time.Time `json:"updated_at"`
+	}
+)
+
+// UnitPrice is the price of one item, see Product.UnitPrice.
+func (p ProductPrice) UnitPrice() int {
+	return unitPrice(p.Cost, p.DiscountCost)
+}
+
+// Price is UnitPrice in the currency of the price.
+func (p ProductPrice) Price() Money {
+	return Money{Amount: p.UnitPrice(), Currency: p.Currency}
+}
+
+// Add returns m + o. Amounts of different currencies cannot be added; doing
+// so is a programming error and panics.
+func (m Money) Add(o Money) Money {
+	m.mustMatch(o)
+
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}
+}
+
+// Sub returns m - o, see Add.
+func (m Money) Sub(o Money) Money {
+	m.mustMatch(o)
+
+	return Money{Amount: m.Amount - o.Amount, Currency: m.Currency}
+}
+
+// Mul returns m times n, such as the price of n items.
+func (m Money) Mul(n int) Money {
+	return Money{Amount: m.Amount * n, Currency: m.Currency}
+}
+
+func (m Money) mustMatch(o Money) {
+	if m.Currency != o.Currency {
+		panic("entity - Money - currency mismatch: " + m.Currency + " and " + o.Currency)
+	}
+}
+
+// ParseRate parses a positive decimal exchange rate.
+func ParseRate(s string) (*big.Rat, error) {
+	s = strings.TrimSpace(s)
+
+	rate, ok := new(big.Rat).SetString(s)
+	if !ok || rate.Sign() <= 0 || !_decimal.MatchString(s) {
+		return nil, NewValidationError("rate", "must be a positive decimal")
+	}
+
+	return rate, nil
+}
+
+// Convert converts m from currency from to currency to at rate, the price
+// of one unit of from in to. The result is rounded half away from zero.
+func Convert(m Money, from, to Currency, rate *big.Rat) Money {
+	amount := new(big.Rat).SetInt64(int64(m.Amount))
+	amount.Mul(amount, rate)
+	amount.Mul(amount, new(big.Rat).SetFrac(pow10(to.MinorUnits), pow10(from.MinorUnits)))
+
+	return Money{Amount: round(amount), Currency: to.Code}
+}
+
+// Format writes amount, in minor units, as a decimal in major units:
+// 1050 is "10.50" for a currency with two minor units.
+func (c Currency) Format(amount int) string {
+	if c.MinorUnits <= 0 {
+		return big.NewInt(int64(amount)).String()
+	}
+
+	return new(big.Rat).SetFrac(big.NewInt(int64(amount)), pow10(c.MinorUnits)).FloatString(c.MinorUnits)
+}
+
+// ParseAmount reads a decimal in major units into minor units. More
+// decimal places than the currency has are rounded.
+func (c Currency) ParseAmount(s string) (int, error) {
+	s = strings.TrimSpace(s)
+
+	amount, ok := new(big.Rat).SetString(s)
+	if !ok || !_decimal.MatchString(s) {
+		return 0, NewValidationError("amount", "must be a decimal number")
+	}
+
+	amount.Mul(amount, new(big.Rat).SetInt(pow10(c.MinorUnits)))
+
+	return round(amount), nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// round rounds r half away from zero.
+func round(r *big.Rat) int {
+	q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+
+	if m.Abs(m).Lsh(m, 1).Cmp(r.Denom()) >= 0 {
+		q.Add(q, big.NewInt(int64(r.Sign())))
+	}
+
+	return int(q.Int64())
+}
@@ -0,0 +1,214 @@
+package entity
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_uzs = Currency{Code: "UZS", MinorUnits: 0}
+	_usd = Currency{Code: "USD", MinorUnits: 2}
+	_kwd = Currency{Code: "KWD", MinorUnits: 3}
+)
+
+func rat(t *testing.T, s string) *big.Rat {
+	t.Helper()
+
+	r, ok := new(big.Rat).SetString(s)
+	require.True(t, ok, s)
+
+	return r
+}
+
+func TestRound(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want int
+	}{
+		{name: "integer", in: "4", want: 4},
+		{name: "below half", in: "2.49", want: 2},
+		{name: "half up from even", in: "2.5", want: 3},
+		{name: "half up from odd", in: "3.5", want: 4},
+		{name: "above half", in: "2.51", want: 3},
+		{name: "negative below half", in: "-2.49", want: -2},
+		{name: "negative half from even", in: "-2.5", want: -3},
+		{name: "negative half from odd", in: "-3.5", want: -4},
+		{name: "negative integer", in: "-4", want: -4},
+		{name: "zero", in: "0", want: 0},
+		{name: "fraction", in: "7/3", want: 2},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, round(rat(t, tc.in)))
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		amount   int
+		from, to Currency
+		rate     string
+		want     int
+	}{
+		{name: "same minor units", amount: 1000, from: _usd, to: _usd, rate: "1.25", want: 1250},
+		{name: "half up from even", amount: 5, from: _usd, to: _usd, rate: "0.5", want: 3},
+		{name: "half up from odd", amount: 7, from: _usd, to: _usd, rate: "0.5", want: 4},
+		{name: "negative half", amount: -5, from: _usd, to: _usd, rate: "0.5", want: -3},
+		{name: "negative", amount: -1000, from: _usd, to: _usd, rate: "1.25", want: -1250},
+		{name: "to fewer minor units", amount: 100, from: _usd, to: _uzs, rate: "12650.5", want: 12651},
+		{name: "to fewer minor units exact", amount: 200, from: _usd, to: _uzs, rate: "12650.5", want: 25301},
+		{name: "to fewer minor units negative", amount: -100, from: _usd, to: _uzs, rate: "12650.5", want: -12651},
+		{name: "to more minor units", amount: 6250, from: _uzs, to: _usd, rate: "0.00008", want: 50},
+		{name: "up to one minor unit", amount: 63, from: _uzs, to: _usd, rate: "0.00008", want: 1},
+		{name: "down to zero", amount: 62, from: _uzs, to: _usd, rate: "0.00008", want: 0},
+		{name: "three minor units", amount: 1050, from: _usd, to: _kwd, rate: "0.3", want: 3150},
+		{name: "from three minor units", amount: 1005, from: _kwd, to: _usd, rate: "1", want: 101},
+		{name: "zero", amount: 0, from: _usd, to: _uzs, rate: "12650.5", want: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := Convert(Money{Amount: tc.amount, Currency: tc.from.Code}, tc.from, tc.to, rat(t, tc.rate))
+			require.Equal(t, Money{Amount: tc.want, Currency: tc.to.Code}, got)
+		})
+	}
+}
+
+func TestCurrencyParseAmount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		currency Currency
+		in       string
+		want     int
+		err      bool
+	}{
+		{name: "two minor units", currency: _usd, in: "10.50", want: 1050},
+		{name: "fewer decimals", currency: _usd, in: "10.5", want: 1050},
+		{name: "no decimals", currency: _usd, in: "10", want: 1000},
+		{name: "trailing point", currency: _usd, in: "10.", want: 1000},
+		{name: "leading point", currency: _usd, in: ".5", want: 50},
+		{name: "spaces", currency: _usd, in: " 12 ", want: 1200},
+		{name: "half up from even", currency: _usd, in: "0.005", want: 1},
+		{name: "half up from odd", currency: _usd, in: "0.015", want: 2},
+		{name: "below half", currency: _usd, in: "0.0049", want: 0},
+		{name: "negative", currency: _usd, in: "-1.25", want: -125},
+		{name: "negative half", currency: _usd, in: "-0.005", want: -1},
+		{name: "plus sign", currency: _usd, in: "+1", want: 100},
+		{name: "no minor units", currency: _uzs, in: "12650.5", want: 12651},
+		{name: "three minor units", currency: _kwd, in: "1.2345", want: 1235},
+		{name: "empty", currency: _usd, in: "", err: true},
+		{name: "blank", currency: _usd, in: "  ", err: true},
+		{name: "letters", currency: _usd, in: "abc", err: true},
+		{name: "fraction", currency: _usd, in: "1/2", err: true},
+		{name: "exponent", currency: _usd, in: "1e3", err: true},
+		{name: "hex", currency: _usd, in: "0x10", err: true},
+		{name: "separator", currency: _usd, in: "1_000", err: true},
+		{name: "two points", currency: _usd, in: "1.2.3", err: true},
+		{name: "comma", currency: _usd, in: "1,5", err: true},
+		{name: "sign only", currency: _usd, in: "-", err: true},
+		{name: "infinity", currency: _usd, in: "Inf", err: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tc.currency.ParseAmount(tc.in)
+			if tc.err {
+				require.ErrorIs(t, err, ErrValidation)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestCurrencyFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		currency Currency
+		amount   int
+		want     string
+	}{
+		{name: "two minor units", currency: _usd, amount: 1050, want: "10.50"},
+		{name: "below one", currency: _usd, amount: 5, want: "0.05"},
+		{name: "negative", currency: _usd, amount: -125, want: "-1.25"},
+		{name: "no minor units", currency: _uzs, amount: 12651, want: "12651"},
+		{name: "three minor units", currency: _kwd, amount: 1235, want: "1.235"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, tc.currency.Format(tc.amount))
+		})
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+		err  bool
+	}{
+		{name: "decimal", in: "12650.5", want: "25301/2"},
+		{name: "spaces", in: " 0.5 ", want: "1/2"},
+		{name: "zero", in: "0", err: true},
+		{name: "negative", in: "-1", err: true},
+		{name: "fraction", in: "1/2", err: true},
+		{name: "exponent", in: "1e3", err: true},
+		{name: "hex", in: "0x10", err: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseRate(tc.in)
+			if tc.err {
+				require.ErrorIs(t, err, ErrValidation)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got.String())
+		})
+	}
+}
+
+func TestMoney(t *testing.T) {
+	t.Parallel()
+
+	price := Money{Amount: 1050, Currency: "USD"}
+
+	require.Equal(t, Money{Amount: 3150, Currency: "USD"}, price.Mul(3))
+	require.Equal(t, Money{Amount: 1150, Currency: "USD"}, price.Add(Money{Amount: 100, Currency: "USD"}))
+	require.Equal(t, Money{Amount: -50, Currency: "USD"}, price.Sub(Money{Amount: 1100, Currency: "USD"}))
+	require.Panics(t, func() { price.Add(Money{Amount: 100, Currency: "UZS"}) })
+}
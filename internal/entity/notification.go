@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+type (
+	// LowStockThreshold is the count below which managers are alerted about
+	// a product. AlertedAt is set while the alert is outstanding.
+	LowStockThreshold struct {
+		ProductID string     `json:"product_id"`
+		Threshold int        `json:"threshold"            validate:"min=1" example:"5"`
+		AlertedAt *time.Time `json:"alerted_at,omitempty"`
+		CreatedAt time.Time  `json:"created_at"`
+		UpdatedAt time.Time  `json:"updated_at"`
+	}
+
+	// StockSubscription asks for a message to the customer in ChatID once
+	// a product is back in stock. ChatID is the numeric Telegram chat id of
+	// the customer's private chat with the bot; bots cannot message users by
+	// username.
+	StockSubscription struct {
+		ID        string    `json:"id"`
+		UserID    string    `json:"user_id"`
+		ProductID string    `json:"product_id"`
+		ChatID    string    `json:"chat_id"    validate:"required,max=20" example:"123456789"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+)
@@ -0,0 +1,58 @@
+package entity
+
+import "time"
+
+// Page -.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type (
+	// ListParams are common keyset pagination and sorting parameters.
+	// Sort is a column name, prefixed with "-" for descending order.
+	ListParams struct {
+		Cursor string `form:"cursor"`
+		Limit  int    `form:"limit"`
+		Sort   string `form:"sort"`
+	}
+
+	// ProductFilter -. IncludeDescendants also matches the products of all
+	// subcategories of CategoryID.
+	ProductFilter struct {
+		ListParams
+		CategoryID         string     `form:"category_id"`
+		IncludeDescendants bool       `form:"include_descendants"`
+		MinCost            *int       `form:"min_cost"`
+		MaxCost            *int       `form:"max_cost"`
+		InStock            *bool      `form:"in_stock"`
+		CreatedFrom        *time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+		CreatedTo          *time.Time `form:"created_to"   time_format:"2006-01-02T15:04:05Z07:00"`
+	}
+
+	// CategoryFilter -. Root selects top level categories only.
+	CategoryFilter struct {
+		ListParams
+		ParentID    string     `form:"parent_id"`
+		Root        bool       `form:"root"`
+		CreatedFrom *time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+		CreatedTo   *time.Time `form:"created_to"   time_format:"2006-01-02T15:04:05Z07:00"`
+	}
+
+	// AttributeFilter -.
+	AttributeFilter struct {
+		ListParams
+		CategoryID string `form:"category_id"`
+	}
+
+	// OrderFilter -.
+	OrderFilter struct {
+		ListParams
+		Status        string     `form:"status"`
+		UserID        string     `form:"user_id"`
+		IntegrationID string     `form:"integration_id"`
+		CreatedFrom   *time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+		CreatedTo     *time.Time `form:"created_to"   time_format:"2006-01-02T15:04:05Z07:00"`
+	}
+)
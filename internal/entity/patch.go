@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Optional is a field of a JSON Merge Patch (RFC 7396) document. A member
+// missing from the document leaves Set false, an explicit null sets Null,
+// and any other value is decoded into Value.
+type Optional[T any] struct {
+	Value T
+	Set   bool
+	Null  bool
+}
+
+// UnmarshalJSON is only called for members present in the document.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		o.Null = true
+		return nil
+	}
+
+	return json.Unmarshal(data, &o.Value)
+}
+
+// Some returns a set Optional holding v.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{Value: v, Set: true}
+}
+
+type (
+	// ProductPatch -.
+	ProductPatch struct {
+		Name         Optional[string] `json:"name"          swaggertype:"string"`
+		SKU          Optional[string] `json:"sku"           swaggertype:"string"`
+		CategoryID   Optional[string] `json:"category_id"   swaggertype:"string"`
+		ShortInfo    Optional[string] `json:"short_info"    swaggertype:"string"`
+		Description  Optional[string] `json:"description"   swaggertype:"string"`
+		Cost         Optional[int]    `json:"cost"          swaggertype:"integer"`
+		Count        Optional[int]    `json:"count"         swaggertype:"integer"`
+		DiscountCost Optional[int]    `json:"discount_cost" swaggertype:"integer"`
+		Discount     Optional[int]    `json:"discount"      swaggertype:"integer"`
+		Version      int              `json:"-"`
+	}
+
+	// CategoryPatch -.
+	CategoryPatch struct {
+		Name    Optional[string] `json:"name" swaggertype:"string"`
+		Version int              `json:"-"`
+	}
+
+	// AttributePatch -.
+	AttributePatch struct {
+		Name       Optional[string]   `json:"name"        swaggertype:"string"`
+		CategoryID Optional[string]   `json:"category_id" swaggertype:"string"`
+		Type       Optional[string]   `json:"type"        swaggertype:"string"`
+		Unit       Optional[string]   `json:"unit"        swaggertype:"string"`
+		Options    Optional[[]string] `json:"options"     swaggertype:"array,string"`
+		Required   Optional[bool]     `json:"required"    swaggertype:"boolean"`
+	}
+
+	// OrderPatch only covers the fields that may be edited directly; the
+	// status goes through the order state machine and the total is computed.
+	OrderPatch struct {
+		UserID        Optional[string] `json:"user_id"        swaggertype:"string"`
+		IntegrationID Optional[string] `json:"integration_id" swaggertype:"string"`
+		Version       int              `json:"-"`
+	}
+
+	// UserPatch -.
+	UserPatch struct {
+		Name       Optional[string] `json:"name"         swaggertype:"string"`
+		Surname    Optional[string] `json:"surname"      swaggertype:"string"`
+		Username   Optional[string] `json:"username"     swaggertype:"string"`
+		Password   Optional[string] `json:"password"     swaggertype:"string"`
+		BirthDate  Optional[Date]   `json:"birth_date"   swaggertype:"string" format:"date"`
+		TgUserName Optional[string] `json:"tg_user_name" swaggertype:"string"`
+		Phone      Optional[string] `json:"phone"        swaggertype:"string"`
+		Instagram  Optional[string] `json:"instagram"    swaggertype:"string"`
+		ClientFrom Optional[string] `json:"client_from"  swaggertype:"string"`
+		RoleID     Optional[string] `json:"role_id"      swaggertype:"string"`
+		Version    int              `json:"-"`
+	}
+)
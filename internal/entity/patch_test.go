@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionalUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		in      string
+		want    UserPatch
+		wantErr bool
+	}{
+		{name: "empty document", in: `{}`},
+		{name: "value", in: `{"name":"Ali"}`, want: UserPatch{Name: Some("Ali")}},
+		{name: "empty string is a value", in: `{"name":""}`, want: UserPatch{Name: Some("")}},
+		{name: "null", in: `{"phone":null}`, want: UserPatch{Phone: Optional[string]{Set: true, Null: true}}},
+		{name: "null with spaces", in: `{"phone": null }`, want: UserPatch{Phone: Optional[string]{Set: true, Null: true}}},
+		{
+			name: "date",
+			in:   `{"birth_date":"1990-03-07"}`,
+			want: UserPatch{BirthDate: Some(NewDate(1990, time.March, 7))},
+		},
+		{name: "null date", in: `{"birth_date":null}`, want: UserPatch{BirthDate: Optional[Date]{Set: true, Null: true}}},
+		{name: "unknown member", in: `{"nickname":"ali"}`},
+		{name: "wrong type", in: `{"name":1}`, wantErr: true},
+		{name: "bad date", in: `{"birth_date":"07.03.1990"}`, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got UserPatch
+
+			err := json.Unmarshal([]byte(tc.in), &got)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestOptionalUnmarshalJSONNumbers(t *testing.T) {
+	t.Parallel()
+
+	var got ProductPatch
+
+	require.NoError(t, json.Unmarshal([]byte(`{"cost":0,"discount":null,"sku":"A-1"}`), &got))
+	require.Equal(t, ProductPatch{
+		Cost:     Some(0),
+		Discount: Optional[int]{Set: true, Null: true},
+		SKU:      Some("A-1"),
+	}, got)
+	require.False(t, got.Count.Set)
+}
+
+func TestOptionalUnmarshalJSONSlice(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want Optional[[]string]
+	}{
+		{name: "absent", in: `{}`},
+		{name: "null", in: `{"options":null}`, want: Optional[[]string]{Set: true, Null: true}},
+		{name: "empty", in: `{"options":[]}`, want: Some([]string{})},
+		{name: "values", in: `{"options":["S","M"]}`, want: Some([]string{"S", "M"})},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got AttributePatch
+			require.NoError(t, json.Unmarshal([]byte(tc.in), &got))
+			require.Equal(t, tc.want, got.Options)
+		})
+	}
+}
@@ -1,29 +1,65 @@
 package entity
 
+import "time"
+
 type (
-	//Product
+	// Product -. Cost and DiscountCost are in minor units of the base
+	// currency. Count is the stock on hand over all warehouses, kept by the
+	// stock ledger, and Available what of it is not reserved by unpaid
+	// orders. Setting Count records a correction in the default warehouse.
+	// DeletedAt is only set on products in the trash.
 	Product struct {
-		ID           string `json:"id"`
-		Name         string `json:"name"`
-		CategoryID   string `json:"category_id"`
-		ShortInfo    string `json:"short_info"`
-		Description  string `json:"description"`
-		Cost         int    `json:"cost"`
-		Count        int    `json:"count"`
-		DiscountCost int    `json:"discount_cost"`
-		Discount     int    `json:"discount"`
-		CreatedAt    string `json:"created_at"`
-		UpdatedAt    string `json:"updated_at"`
+		ID           string             `json:"id"`
+		Name         string             `json:"name"`
+		SKU          string             `json:"sku"`
+		CategoryID   string             `json:"category_id"`
+		ShortInfo    string             `json:"short_info"`
+		Description  string             `json:"description"`
+		Cost         int                `json:"cost"`
+		Count        int                `json:"count"`
+		Available    int                `json:"available"`
+		DiscountCost int                `json:"discount_cost"`
+		Discount     int                `json:"discount"`
+		CreatedAt    time.Time          `json:"created_at"`
+		UpdatedAt    time.Time          `json:"updated_at"`
+		Version      int                `json:"version"`
+		DeletedAt    *time.Time         `json:"deleted_at,omitempty"`
+		Attributes   []ProductAttribute `json:"attributes,omitempty"`
+		Variants     []ProductVariant   `json:"variants,omitempty"`
+		Media        []ProductMedia     `json:"media,omitempty"`
 	}
 )
 
 type (
 	//Category
 	Category struct {
-		ID        string `json:"id"`
-		Name      string `json:"name"`
-		CreatedAt string `json:"created_at"`
-		UpdatedAt string `json:"updated_at"`
+		ID        string     `json:"id"`
+		Name      string     `json:"name"`
+		ParentID  string     `json:"parent_id,omitempty"`
+		Position  int        `json:"position"`
+		CreatedAt time.Time  `json:"created_at"`
+		UpdatedAt time.Time  `json:"updated_at"`
+		Version   int        `json:"version"`
+		DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	}
+
+	// CategoryNode is a category with its subcategories, ordered by position.
+	CategoryNode struct {
+		Category
+		Children []CategoryNode `json:"children"`
+	}
+
+	// CategoryMove places a category under ParentID, or at the root when it
+	// is empty, at Position among its new siblings.
+	CategoryMove struct {
+		ParentID string `json:"parent_id" validate:"omitempty,uuid"`
+		Position int    `json:"position"  validate:"min=0"`
+		Version  int    `json:"-"`
+	}
+
+	// CategoryOrder lists all children of a category in their new order.
+	CategoryOrder struct {
+		Children []string `json:"children" validate:"required,dive,uuid"`
 	}
 )
 
@@ -31,37 +67,159 @@ type (
 	// Attribute
 
 	Attribute struct {
-		ID        string `json:"id"`
-		Name      string `json:"name"`
-		CategoryID string `json:"category_id"`
-		CreatedAt string `json:"created_at"`
-		UpdatedAt string `json:"updated_at"`
+		ID         string    `json:"id"`
+		Name       string    `json:"name"`
+		CategoryID string    `json:"category_id"`
+		Type       string    `json:"type"     example:"enum"`
+		Unit       string    `json:"unit"     example:"GB"`
+		Options    []string  `json:"options"`
+		Required   bool      `json:"required"`
+		CreatedAt  time.Time `json:"created_at"`
+		UpdatedAt  time.Time `json:"updated_at"`
 	}
 )
 
 type (
-	//Order
+	// Order amounts are in minor units of Currency. ExchangeRate is the
+	// price of one unit of Currency in the base currency when the order was
+	// placed.
 	Order struct {
-		ID                string `json:"id"`
-		UserID            string `json:"user_id"`
-		IntegrationID     string `json:"integration_id"`
-		Status            string `json:"status"`
-		StatusChangedTime string `json:"status_chaged_time"`
-		TotalCost         int    `json:"total_cost"`
-		CreatedAt         string `json:"created_at"`
-		UpdatedAt         string `json:"updated_at"`
+		ID                string             `json:"id"`
+		UserID            string             `json:"user_id"`
+		IntegrationID     string             `json:"integration_id"`
+		Status            string             `json:"status"`
+		StatusChangedTime time.Time          `json:"status_changed_time"`
+		SubtotalCost      int                `json:"subtotal_cost"`
+		DiscountCost      int                `json:"discount_cost"`
+		TotalCost         int                `json:"total_cost"`
+		Currency          string             `json:"currency"      example:"USD"`
+		ExchangeRate      string             `json:"exchange_rate" example:"12650.5"`
+		CreatedAt         time.Time          `json:"created_at"`
+		UpdatedAt         time.Time          `json:"updated_at"`
+		Version           int                `json:"version"`
+		Products          []OrderProducts    `json:"products,omitempty"`
+		Discounts         []OrderDiscount    `json:"discounts,omitempty"`
+		Reservations      []StockReservation `json:"reservations,omitempty"`
 	}
 )
 
 type (
-	// OrderProducts
+	// NewOrder is a request to place an order. Prices, discounts and the
+	// total are computed server side; Coupons are promotion codes to apply.
+	// Currency defaults to the base currency.
+	NewOrder struct {
+		UserID        string         `json:"user_id"        validate:"required,uuid"`
+		IntegrationID string         `json:"integration_id" validate:"omitempty,uuid"`
+		Items         []NewOrderItem `json:"items"          validate:"required,min=1,dive"`
+		Coupons       []string       `json:"coupons"        validate:"max=5,dive,required,max=64"`
+		Currency      string         `json:"currency"       validate:"omitempty,len=3,uppercase" example:"USD"`
+		PlacedBy      string         `json:"-"`
+	}
+
+	// NewOrderItem -. VariantID is required for products with variants.
+	NewOrderItem struct {
+		ProductID string `json:"product_id" validate:"required,uuid"`
+		VariantID string `json:"variant_id" validate:"omitempty,uuid"`
+		Count     int    `json:"count"      validate:"gt=0"`
+	}
+)
+
+// Order lifecycle: new -> confirmed -> paid -> shipped -> delivered, with
+// cancelled reachable before shipping and returned after it.
+const (
+	OrderStatusNew       = "new"
+	OrderStatusConfirmed = "confirmed"
+	OrderStatusPaid      = "paid"
+	OrderStatusShipped   = "shipped"
+	OrderStatusDelivered = "delivered"
+	OrderStatusCancelled = "cancelled"
+	OrderStatusReturned  = "returned"
+)
+
+var _orderTransitions = map[string][]string{
+	OrderStatusNew:       {OrderStatusConfirmed, OrderStatusCancelled},
+	OrderStatusConfirmed: {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:      {OrderStatusShipped, OrderStatusCancelled},
+	OrderStatusShipped:   {OrderStatusDelivered, OrderStatusReturned},
+	OrderStatusDelivered: {OrderStatusReturned},
+}
+
+// CanTransitionOrder reports whether an order may move from one status to another.
+func CanTransitionOrder(from, to string) bool {
+	for _, next := range _orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RestocksOrder reports whether entering status puts the ordered items back on stock.
+func RestocksOrder(status string) bool {
+	return status == OrderStatusCancelled || status == OrderStatusReturned
+}
+
+type (
+	// OrderStatusChange is an entry of an order's timeline.
+	OrderStatusChange struct {
+		ID         string    `json:"id"`
+		OrderID    string    `json:"order_id"`
+		FromStatus string    `json:"from_status"`
+		ToStatus   string    `json:"to_status"`
+		ChangedBy  string    `json:"changed_by"`
+		Comment    string    `json:"comment"`
+		CreatedAt  time.Time `json:"created_at"`
+	}
+
+	// OrderStatusUpdate is a request to move an order to another status.
+	OrderStatusUpdate struct {
+		Status    string `json:"status"  validate:"required,oneof=new confirmed paid shipped delivered cancelled returned"`
+		Comment   string `json:"comment" validate:"max=1000"`
+		ChangedBy string `json:"-"`
+	}
+)
+
+// Subtotal is the price of the order lines before discounts.
+func (o Order) Subtotal() Money {
+	return Money{Amount: o.SubtotalCost, Currency: o.Currency}
+}
+
+// Discount is the total of the order's discounts.
+func (o Order) Discount() Money {
+	return Money{Amount: o.DiscountCost, Currency: o.Currency}
+}
+
+// Total is what the customer pays.
+func (o Order) Total() Money {
+	return Money{Amount: o.TotalCost, Currency: o.Currency}
+}
+
+// UnitPrice is the price a customer pays for one item right now.
+func (p Product) UnitPrice() int {
+	return unitPrice(p.Cost, p.DiscountCost)
+}
+
+func unitPrice(cost, discountCost int) int {
+	if discountCost > 0 && discountCost < cost {
+		return discountCost
+	}
+
+	return cost
+}
+
+type (
+	// OrderProducts is an order line. Cost is the unit price and Discount
+	// the promotions' total off the whole line.
 	OrderProducts struct {
-		ID        string `json:"id"`
-		OrderID   string `json:"order_id"`
-		ProductID string `json:"product_id"`
-		Count     int    `json:"count"`
-		Cost      int    `json:"cost"`
-		CreatedAt string `json:"created_at"`
-		UpdatedAt string `json:"updated_at"`
+		ID        string    `json:"id"`
+		OrderID   string    `json:"order_id"`
+		ProductID string    `json:"product_id"`
+		VariantID string    `json:"variant_id,omitempty"`
+		Count     int       `json:"count"`
+		Cost      int       `json:"cost"`
+		Discount  int       `json:"discount"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
 	}
 )
@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanTransitionOrder(t *testing.T) {
+	t.Parallel()
+
+	statuses := []string{
+		OrderStatusNew, OrderStatusConfirmed, OrderStatusPaid, OrderStatusShipped,
+		OrderStatusDelivered, OrderStatusCancelled, OrderStatusReturned,
+	}
+
+	allowed := map[[2]string]bool{
+		{OrderStatusNew, OrderStatusConfirmed}:       true,
+		{OrderStatusNew, OrderStatusCancelled}:       true,
+		{OrderStatusConfirmed, OrderStatusPaid}:      true,
+		{OrderStatusConfirmed, OrderStatusCancelled}: true,
+		{OrderStatusPaid, OrderStatusShipped}:        true,
+		{OrderStatusPaid, OrderStatusCancelled}:      true,
+		{OrderStatusShipped, OrderStatusDelivered}:   true,
+		{OrderStatusShipped, OrderStatusReturned}:    true,
+		{OrderStatusDelivered, OrderStatusReturned}:  true,
+	}
+
+	for _, from := range append(statuses, "", "unknown") {
+		for _, to := range append(statuses, "", "unknown") {
+			t.Run(from+"->"+to, func(t *testing.T) {
+				t.Parallel()
+
+				require.Equal(t, allowed[[2]string{from, to}], CanTransitionOrder(from, to))
+			})
+		}
+	}
+}
+
+func TestRestocksOrder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{status: OrderStatusNew, want: false},
+		{status: OrderStatusConfirmed, want: false},
+		{status: OrderStatusPaid, want: false},
+		{status: OrderStatusShipped, want: false},
+		{status: OrderStatusDelivered, want: false},
+		{status: OrderStatusCancelled, want: true},
+		{status: OrderStatusReturned, want: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.status, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, RestocksOrder(tc.status))
+		})
+	}
+}
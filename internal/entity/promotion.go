@@ -0,0 +1,146 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// Promotion types. Value is a percentage for PromotionPercentage, an amount
+// in minor units of the base currency off the matching items for
+// PromotionFixed, and the percentage off every
+// GetCount items after BuyCount items for PromotionBuyXGetY.
+const (
+	PromotionPercentage = "percentage"
+	PromotionFixed      = "fixed"
+	PromotionBuyXGetY   = "buy_x_get_y"
+)
+
+type (
+	// Promotion is a time-bounded discount campaign. It applies to the items
+	// whose product is in ProductIDs or whose category, or an ancestor of
+	// it, is in CategoryIDs, or to every item when both are empty. Empty
+	// ClientTypeIDs allow every customer. Promotions that are not Stackable
+	// never combine with other promotions. MinOrderCost, in minor units of
+	// the base currency, is the order subtotal the promotion needs; zero
+	// means any. Zero usage limits mean unlimited.
+	Promotion struct {
+		ID                string     `json:"id"`
+		Name              string     `json:"name"                 validate:"required,max=255"`
+		Description       string     `json:"description"`
+		Type              string     `json:"type"                 validate:"required,oneof=percentage fixed buy_x_get_y" example:"percentage"`
+		Value             int        `json:"value"                validate:"gt=0"                                       example:"10"`
+		BuyCount          int        `json:"buy_count"            validate:"gte=0"`
+		GetCount          int        `json:"get_count"            validate:"gte=0"`
+		MinOrderCost      int        `json:"min_order_cost"       validate:"gte=0"`
+		ProductIDs        []string   `json:"product_ids"          validate:"dive,uuid"`
+		CategoryIDs       []string   `json:"category_ids"         validate:"dive,uuid"`
+		ClientTypeIDs     []string   `json:"client_type_ids"      validate:"dive,uuid"`
+		StartsAt          *time.Time `json:"starts_at"`
+		EndsAt            *time.Time `json:"ends_at"`
+		Active            bool       `json:"active"`
+		Stackable         bool       `json:"stackable"`
+		Priority          int        `json:"priority"`
+		RequiresCoupon    bool       `json:"requires_coupon"`
+		UsageLimit        int        `json:"usage_limit"          validate:"gte=0"`
+		UsageLimitPerUser int        `json:"usage_limit_per_user" validate:"gte=0"`
+		UsedCount         int        `json:"used_count"`
+		CreatedAt         time.Time  `json:"created_at"`
+		UpdatedAt         time.Time  `json:"updated_at"`
+		Version           int        `json:"version"`
+	}
+
+	// Coupon is a code that unlocks a promotion requiring one. Its usage
+	// limits apply on top of those of the promotion.
+	Coupon struct {
+		ID                string    `json:"id"`
+		PromotionID       string    `json:"promotion_id"`
+		Code              string    `json:"code"                 validate:"required,max=64,printascii"`
+		UsageLimit        int       `json:"usage_limit"          validate:"gte=0"`
+		UsageLimitPerUser int       `json:"usage_limit_per_user" validate:"gte=0"`
+		UsedCount         int       `json:"used_count"`
+		CreatedAt         time.Time `json:"created_at"`
+		UpdatedAt         time.Time `json:"updated_at"`
+	}
+
+	// PromotionFilter -.
+	PromotionFilter struct {
+		ListParams
+		Active *bool `form:"active"`
+	}
+
+	// PromotionUsage is an application of a promotion to an order.
+	PromotionUsage struct {
+		PromotionID string
+		CouponID    string
+		OrderID     string
+		UserID      string
+	}
+
+	// OrderDiscount explains a discount applied to an order.
+	OrderDiscount struct {
+		ID          string    `json:"id,omitempty"`
+		OrderID     string    `json:"order_id,omitempty"`
+		PromotionID string    `json:"promotion_id"`
+		CouponID    string    `json:"-"`
+		CouponCode  string    `json:"coupon_code,omitempty"`
+		Name        string    `json:"name"`
+		Description string    `json:"description" example:"10% off 3 items"`
+		Amount      int       `json:"amount"`
+		CreatedAt   time.Time `json:"created_at"`
+	}
+
+	// OrderQuote is the price of an order before it is placed, see Order.
+	OrderQuote struct {
+		SubtotalCost int             `json:"subtotal_cost"`
+		DiscountCost int             `json:"discount_cost"`
+		TotalCost    int             `json:"total_cost"`
+		Currency     string          `json:"currency"      example:"USD"`
+		ExchangeRate string          `json:"exchange_rate" example:"12650.5"`
+		Products     []OrderProducts `json:"products"`
+		Discounts    []OrderDiscount `json:"discounts"`
+	}
+)
+
+// Subtotal is the price of the lines before discounts.
+func (q OrderQuote) Subtotal() Money {
+	return Money{Amount: q.SubtotalCost, Currency: q.Currency}
+}
+
+// Discount is the total of the discounts.
+func (q OrderQuote) Discount() Money {
+	return Money{Amount: q.DiscountCost, Currency: q.Currency}
+}
+
+// Total is what the customer pays.
+func (q OrderQuote) Total() Money {
+	return Money{Amount: q.TotalCost, Currency: q.Currency}
+}
+
+// Validate checks the type specific fields and the time window.
+func (p Promotion) Validate() error {
+	switch p.Type {
+	case PromotionPercentage:
+		if p.Value > 100 {
+			return NewValidationError("value", "must be at most 100 for percentage promotions")
+		}
+	case PromotionBuyXGetY:
+		if p.Value > 100 {
+			return NewValidationError("value", "must be at most 100 for buy_x_get_y promotions")
+		}
+
+		if p.BuyCount <= 0 || p.GetCount <= 0 {
+			return NewValidationError("buy_count", "buy_count and get_count must be positive for buy_x_get_y promotions")
+		}
+	}
+
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return NewValidationError("ends_at", "must be after starts_at")
+	}
+
+	return nil
+}
+
+// NormalizeCouponCode makes coupon codes case insensitive.
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// Stock reservation statuses. An active reservation holds stock for an
+// unpaid order; paying the order consumes it, cancelling releases it, and
+// the unpaid order is cancelled once it expires.
+const (
+	ReservationActive   = "active"
+	ReservationConsumed = "consumed"
+	ReservationReleased = "released"
+	ReservationExpired  = "expired"
+)
+
+type (
+	// StockReservation holds Count items of a product, or of one of its
+	// variants, for an order until ExpiresAt.
+	StockReservation struct {
+		ID        string    `json:"id"`
+		OrderID   string    `json:"order_id"`
+		ProductID string    `json:"product_id"`
+		VariantID string    `json:"variant_id,omitempty"`
+		Count     int       `json:"count"`
+		Status    string    `json:"status"     example:"active"`
+		ExpiresAt time.Time `json:"expires_at"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+)
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+type (
+	// ProductSearch is a free text query over product names and texts.
+	// Sort is fixed to relevance.
+	ProductSearch struct {
+		Query      string `form:"q" binding:"required"`
+		CategoryID string `form:"category_id"`
+		Cursor     string `form:"cursor"`
+		Limit      int    `form:"limit"`
+	}
+
+	// SearchHit is a matching product with its relevance and the matched
+	// words wrapped in <mark> tags.
+	SearchHit struct {
+		Product   Product         `json:"product"`
+		Rank      float64         `json:"rank"`
+		Highlight SearchHighlight `json:"highlight"`
+	}
+
+	// SearchHighlight -.
+	SearchHighlight struct {
+		Name        string `json:"name"`
+		ShortInfo   string `json:"short_info,omitempty"`
+		Description string `json:"description,omitempty"`
+	}
+
+	// SearchSynonym makes Term and Synonym match each other in searches.
+	SearchSynonym struct {
+		Term      string    `json:"term"    validate:"required,max=64"`
+		Synonym   string    `json:"synonym" validate:"required,max=64,nefield=Term"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+)
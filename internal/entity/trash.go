@@ -0,0 +1,13 @@
+package entity
+
+// TrashPurge counts the records a purge removed from the trash for good.
+type TrashPurge struct {
+	Products   int `json:"products"`
+	Categories int `json:"categories"`
+	Users      int `json:"users"`
+}
+
+// Total -.
+func (p TrashPurge) Total() int {
+	return p.Products + p.Categories + p.Users
+}
@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+)
+
+type (
+	// ProductVariant is a sellable version of a product with its own SKU,
+	// price and stock, told apart from its siblings by option values. Count
+	// and Available are as for Product.
+	ProductVariant struct {
+		ID           string          `json:"id"`
+		ProductID    string          `json:"product_id"`
+		SKU          string          `json:"sku"           validate:"required,max=64"`
+		Barcode      string          `json:"barcode"       validate:"omitempty,max=64"`
+		Options      []VariantOption `json:"options"       validate:"dive"`
+		Cost         int             `json:"cost"          validate:"gte=0"`
+		DiscountCost int             `json:"discount_cost" validate:"gte=0"`
+		Discount     int             `json:"discount"      validate:"gte=0"`
+		Count        int             `json:"count"         validate:"gte=0"`
+		Available    int             `json:"available"`
+		CreatedAt    time.Time       `json:"created_at"`
+		UpdatedAt    time.Time       `json:"updated_at"`
+		Version      int             `json:"version"`
+	}
+
+	// VariantOption is the value of a category attribute, such as size or
+	// color, that sets a variant apart.
+	VariantOption struct {
+		AttributeID string `json:"attribute_id" validate:"required,uuid"`
+		Name        string `json:"name"`
+		Value       string `json:"value"        validate:"required"`
+	}
+)
+
+// UnitPrice is the price of one item, see Product.UnitPrice.
+func (v ProductVariant) UnitPrice() int {
+	return unitPrice(v.Cost, v.DiscountCost)
+}
+
+// ValidateOption checks that value is a valid option value of the attribute.
+func (a Attribute) ValidateOption(value string) error {
+	field := "options." + a.Name
+
+	switch a.Type {
+	case AttributeTypeNumber, AttributeTypeUnit:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return NewValidationError(field, "must be a number")
+		}
+	case AttributeTypeBoolean:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return NewValidationError(field, "must be true or false")
+		}
+	case AttributeTypeEnum:
+		if !slices.Contains(a.Options, value) {
+			return NewValidationError(field, fmt.Sprintf("must be one of %v", a.Options))
+		}
+	}
+
+	return nil
+}
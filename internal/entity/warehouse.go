@@ -0,0 +1,114 @@
+package entity
+
+import "time"
+
+// Stock movement types. A transfer is recorded as two movements sharing a
+// TransferID, one out of a warehouse and one into another.
+const (
+	MovementReceipt    = "receipt"
+	MovementSale       = "sale"
+	MovementReturn     = "return"
+	MovementAdjustment = "adjustment"
+	MovementTransfer   = "transfer"
+)
+
+// Adjustment reasons. ReasonInitial is the opening balance of stock set
+// when a product or variant is created; ReasonCorrection is a count edited
+// on the product or variant, or imported.
+const (
+	ReasonStocktake  = "stocktake"
+	ReasonDamaged    = "damaged"
+	ReasonLost       = "lost"
+	ReasonFound      = "found"
+	ReasonCorrection = "correction"
+	ReasonInitial    = "initial"
+)
+
+type (
+	// Warehouse is a point stock is kept and shipped from. Stock edited
+	// without naming a warehouse goes to the Default one.
+	Warehouse struct {
+		ID        string    `json:"id"`
+		Code      string    `json:"code"       validate:"required,max=32"  example:"main"`
+		Name      string    `json:"name"       validate:"required,max=255" example:"Main warehouse"`
+		Address   string    `json:"address"    validate:"max=1000"`
+		Default   bool      `json:"default"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+
+	// StockLevel is the stock of a product, or of one of its variants, in a
+	// warehouse. It is the sum of the movements recorded there.
+	StockLevel struct {
+		WarehouseID string    `json:"warehouse_id"`
+		ProductID   string    `json:"product_id"`
+		VariantID   string    `json:"variant_id,omitempty"`
+		Count       int       `json:"count"`
+		UpdatedAt   time.Time `json:"updated_at"`
+	}
+
+	// StockLevelFilter -.
+	StockLevelFilter struct {
+		WarehouseID string `form:"warehouse_id"`
+		ProductID   string `form:"product_id"`
+		VariantID   string `form:"variant_id"`
+	}
+
+	// StockMovement is an entry of the stock ledger. Quantity is negative
+	// for items leaving the warehouse.
+	StockMovement struct {
+		ID          string    `json:"id"`
+		WarehouseID string    `json:"warehouse_id"`
+		ProductID   string    `json:"product_id"`
+		VariantID   string    `json:"variant_id,omitempty"`
+		Type        string    `json:"type"                  example:"receipt"`
+		Quantity    int       `json:"quantity"              example:"10"`
+		Reason      string    `json:"reason,omitempty"      example:"stocktake"`
+		OrderID     string    `json:"order_id,omitempty"`
+		TransferID  string    `json:"transfer_id,omitempty"`
+		Comment     string    `json:"comment,omitempty"`
+		CreatedBy   string    `json:"created_by,omitempty"`
+		CreatedAt   time.Time `json:"created_at"`
+	}
+
+	// StockMovementFilter -.
+	StockMovementFilter struct {
+		ListParams
+		WarehouseID string `form:"warehouse_id"`
+		ProductID   string `form:"product_id"`
+		VariantID   string `form:"variant_id"`
+		Type        string `form:"type"`
+		OrderID     string `form:"order_id"`
+	}
+
+	// StockReceipt puts Quantity items of goods received into a warehouse.
+	// VariantID is required for products with variants.
+	StockReceipt struct {
+		WarehouseID string `json:"warehouse_id" validate:"required,uuid"`
+		ProductID   string `json:"product_id"   validate:"required,uuid"`
+		VariantID   string `json:"variant_id"   validate:"omitempty,uuid"`
+		Quantity    int    `json:"quantity"     validate:"required,min=1" example:"10"`
+		Comment     string `json:"comment"      validate:"max=1000"`
+	}
+
+	// StockTransfer moves Quantity items from one warehouse to another.
+	StockTransfer struct {
+		FromWarehouseID string `json:"from_warehouse_id" validate:"required,uuid"`
+		ToWarehouseID   string `json:"to_warehouse_id"   validate:"required,uuid,nefield=FromWarehouseID"`
+		ProductID       string `json:"product_id"        validate:"required,uuid"`
+		VariantID       string `json:"variant_id"        validate:"omitempty,uuid"`
+		Quantity        int    `json:"quantity"          validate:"required,min=1" example:"5"`
+		Comment         string `json:"comment"           validate:"max=1000"`
+	}
+
+	// StockAdjustment corrects the stock in a warehouse to the Count found,
+	// for instance by a stocktake. The difference is recorded with Reason.
+	StockAdjustment struct {
+		WarehouseID string `json:"warehouse_id" validate:"required,uuid"`
+		ProductID   string `json:"product_id"   validate:"required,uuid"`
+		VariantID   string `json:"variant_id"   validate:"omitempty,uuid"`
+		Count       int    `json:"count"        validate:"min=0"                                               example:"8"`
+		Reason      string `json:"reason"       validate:"required,oneof=stocktake damaged lost found correction" example:"stocktake"`
+		Comment     string `json:"comment"      validate:"max=1000"`
+	}
+)
@@ -3,6 +3,8 @@ package repo
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"ai-seller/internal/entity"
 )
@@ -21,8 +23,14 @@ type (
 	AuthRepo interface {
 		CreateUser(context.Context, entity.User) error
 		GetUser(context.Context, string) (entity.User, error)
+		GetUserByUsername(context.Context, string) (entity.User, error)
 		UpdateUser(context.Context, entity.User) error
-		DeleteUser(context.Context, string) error
+		PatchUser(ctx context.Context, id string, p entity.UserPatch) error
+		UpdateUserPassword(ctx context.Context, id, hash string) error
+		DeleteUser(ctx context.Context, id string, version int) error
+		ListDeletedUsers(context.Context, entity.ListParams) (entity.Page[entity.User], error)
+		RestoreUser(context.Context, string) (entity.User, error)
+		PurgeUsers(ctx context.Context, before time.Time) (int, error)
 
 		CreateRole(context.Context, entity.Role) error
 		GetRole(context.Context, string) (entity.Role, error)
@@ -33,6 +41,18 @@ type (
 		GetClientType(context.Context, string) (entity.ClientType, error)
 		UpdateClientType(context.Context, entity.ClientType) error
 		DeleteClientType(context.Context, string) error
+
+		CreateRefreshToken(context.Context, entity.RefreshToken) error
+		GetRefreshToken(ctx context.Context, hash string) (entity.RefreshToken, error)
+		RevokeRefreshToken(ctx context.Context, id string) (bool, error)
+		RevokeRefreshTokenFamily(ctx context.Context, familyID string) error
+		RevokeUserRefreshTokens(ctx context.Context, userID string) error
+
+		GetPermissions(context.Context) ([]entity.Permission, error)
+		GetRolePermissions(ctx context.Context, roleID string) ([]entity.Permission, error)
+		AddRolePermission(ctx context.Context, roleID, permissionID string) error
+		DeleteRolePermission(ctx context.Context, roleID, permissionID string) error
+		HasPermission(ctx context.Context, roleID, permission string) (bool, error)
 	}
 
 	// IntegrationRepo -.
@@ -45,30 +65,173 @@ type (
 
 	// ProductRepo -.
 	ProductRepo interface {
-		CreateProduct(context.Context, entity.Product) error
+		CreateProduct(context.Context, entity.Product) (string, error)
 		GetProduct(context.Context, string) (entity.Product, error)
+		ListProducts(context.Context, entity.ProductFilter) (entity.Page[entity.Product], error)
+		SearchCatalog(ctx context.Context, q entity.CatalogQuery, attributes []entity.Attribute) (entity.CatalogResult, error)
+		SearchProducts(ctx context.Context, s entity.ProductSearch, words [][]string) (entity.Page[entity.SearchHit], error)
+		SuggestProducts(ctx context.Context, prefix string, limit int) ([]string, error)
 		UpdateProduct(context.Context, entity.Product) error
-		DeleteProduct(context.Context, string) error
+		PatchProduct(ctx context.Context, id string, p entity.ProductPatch) error
+		DeleteProduct(ctx context.Context, id string, version int) error
+		GetProducts(ctx context.Context, ids []string) ([]entity.Product, error)
+		LockProducts(ctx context.Context, ids []string) ([]entity.Product, error)
+		ListDeletedProducts(context.Context, entity.ListParams) (entity.Page[entity.Product], error)
+		RestoreProduct(context.Context, string) (entity.Product, error)
+		GetPurgeableProducts(ctx context.Context, before time.Time, limit int) ([]string, error)
+		PurgeProduct(context.Context, string) error
 
-		CreateCategory(context.Context, entity.Category) error
+		CreateCategory(context.Context, entity.Category) (string, error)
 		GetCategory(context.Context, string) (entity.Category, error)
+		ListCategories(context.Context, entity.CategoryFilter) (entity.Page[entity.Category], error)
 		UpdateCategory(context.Context, entity.Category) error
-		DeleteCategory(context.Context, string) error
+		PatchCategory(ctx context.Context, id string, p entity.CategoryPatch) error
+		DeleteCategory(ctx context.Context, id string, version int) error
+		GetCategorySubtree(ctx context.Context, rootID string) ([]entity.Category, error)
+		GetCategoryPath(ctx context.Context, id string) ([]entity.Category, error)
+		CountCategoryChildren(ctx context.Context, parentID string) (int, error)
+		MoveCategory(ctx context.Context, c entity.Category, m entity.CategoryMove) error
+		ReorderCategories(ctx context.Context, parentID string, ids []string) error
+		ListDeletedCategories(context.Context, entity.ListParams) (entity.Page[entity.Category], error)
+		RestoreCategory(context.Context, string) (entity.Category, error)
+		PurgeCategories(ctx context.Context, before time.Time) (int, error)
 
-		CreateAttribute(context.Context, entity.Attribute) error
+		CreateAttribute(context.Context, entity.Attribute) (string, error)
 		GetAttribute(context.Context, string) (entity.Attribute, error)
+		ListAttributes(context.Context, entity.AttributeFilter) (entity.Page[entity.Attribute], error)
 		UpdateAttribute(context.Context, entity.Attribute) error
+		PatchAttribute(ctx context.Context, id string, p entity.AttributePatch) error
+		GetAttributesByCategory(ctx context.Context, categoryID string) ([]entity.Attribute, error)
 		DeleteAttribute(context.Context, string) error
 
-		CreateOrder(context.Context, entity.Order) error
+		GetProductAttributes(ctx context.Context, productID string) ([]entity.ProductAttribute, error)
+		ReplaceProductAttributes(ctx context.Context, productID string, values []entity.ProductAttribute) error
+
+		CreateVariant(context.Context, entity.ProductVariant) (string, error)
+		GetVariant(ctx context.Context, productID, id string) (entity.ProductVariant, error)
+		GetVariantsByProduct(ctx context.Context, productID string) ([]entity.ProductVariant, error)
+		GetVariantsByProducts(ctx context.Context, productIDs []string) ([]entity.ProductVariant, error)
+		LockVariantsByProducts(ctx context.Context, productIDs []string) ([]entity.ProductVariant, error)
+		UpdateVariant(context.Context, entity.ProductVariant) error
+		ReplaceVariantOptions(ctx context.Context, variantID string, options []entity.VariantOption) error
+		DeleteVariant(ctx context.Context, productID, id string, version int) error
+
+		CreateReservation(context.Context, entity.StockReservation) error
+		GetReservationsByOrder(ctx context.Context, orderID string) ([]entity.StockReservation, error)
+		UpdateReservationStatus(ctx context.Context, orderID, from, to string) ([]entity.StockReservation, error)
+		GetExpiredReservationOrders(ctx context.Context, limit int) ([]string, error)
+		ReserveStock(ctx context.Context, productID, variantID string, count int) error
+		UnreserveStock(ctx context.Context, productID, variantID string, count int) error
+
+		CreateMedia(context.Context, entity.ProductMedia) (entity.ProductMedia, error)
+		GetMedia(ctx context.Context, productID, id string) (entity.ProductMedia, error)
+		GetMediaByProduct(ctx context.Context, productID string) ([]entity.ProductMedia, error)
+		SetPrimaryMedia(ctx context.Context, productID, id string) error
+		ReorderMedia(ctx context.Context, productID string, ids []string) error
+		DeleteMedia(context.Context, entity.ProductMedia) error
+
+		CreateImportJob(context.Context, entity.ImportJob) (entity.ImportJob, error)
+		GetImportJob(context.Context, string) (entity.ImportJob, error)
+		UpdateImportJob(context.Context, entity.ImportJob) error
+		FailImportJobs(ctx context.Context, statuses []string, message string) (int, error)
+		FindProducts(ctx context.Context, sku, name string) ([]entity.Product, error)
+		FindVariant(ctx context.Context, sku string) (entity.ProductVariant, error)
+		FindCategory(ctx context.Context, parentID, name string) (entity.Category, error)
+
+		FeedVersion(context.Context) (string, error)
+		ListFeedProducts(context.Context) ([]entity.FeedProduct, error)
+		GetFeedMappings(context.Context) ([]entity.CategoryFeedMapping, error)
+		GetFeedMapping(ctx context.Context, categoryID string) (entity.CategoryFeedMapping, error)
+		SetFeedMapping(context.Context, entity.CategoryFeedMapping) error
+
+		GetSynonyms(ctx context.Context, words []string) ([]entity.SearchSynonym, error)
+		ListSynonyms(context.Context) ([]entity.SearchSynonym, error)
+		CreateSynonym(context.Context, entity.SearchSynonym) (entity.SearchSynonym, error)
+		DeleteSynonym(ctx context.Context, term, synonym string) error
+
+		CreateOrder(context.Context, entity.Order) (string, error)
 		GetOrder(context.Context, string) (entity.Order, error)
+		ListOrders(context.Context, entity.OrderFilter) (entity.Page[entity.Order], error)
 		UpdateOrder(context.Context, entity.Order) error
-		DeleteOrder(context.Context, string) error
+		PatchOrder(ctx context.Context, id string, p entity.OrderPatch) error
+		DeleteOrder(ctx context.Context, id string, version int) error
+		LockOrder(ctx context.Context, id string) (entity.Order, error)
+		UpdateOrderStatus(ctx context.Context, id, from, to string) error
+
+		CreateOrderStatusChange(context.Context, entity.OrderStatusChange) error
+		GetOrderStatusHistory(ctx context.Context, orderID string) ([]entity.OrderStatusChange, error)
 
-		CreateOrderProducts(context.Context, entity.OrderProducts) error
+		CreateOrderProducts(context.Context, entity.OrderProducts) (string, error)
 		GetOrderProducts(context.Context, string) (entity.OrderProducts, error)
+		GetOrderProductsByOrder(ctx context.Context, orderID string) ([]entity.OrderProducts, error)
 		UpdateOrderProducts(context.Context, entity.OrderProducts) error
 		DeleteOrderProducts(context.Context, string) error
+
+		CreatePromotion(context.Context, entity.Promotion) (string, error)
+		GetPromotion(context.Context, string) (entity.Promotion, error)
+		ListPromotions(context.Context, entity.PromotionFilter) (entity.Page[entity.Promotion], error)
+		GetActivePromotions(context.Context) ([]entity.Promotion, error)
+		LockPromotions(ctx context.Context, ids []string) ([]entity.Promotion, error)
+		UpdatePromotion(context.Context, entity.Promotion) error
+		DeletePromotion(ctx context.Context, id string, version int) error
+		CountPromotionUsage(ctx context.Context, promotionID, userID string) (total, byUser int, err error)
+		CountCouponUsage(ctx context.Context, couponID, userID string) (total, byUser int, err error)
+		CreatePromotionUsage(context.Context, entity.PromotionUsage) error
+		DeletePromotionUsageByOrder(ctx context.Context, orderID string) error
+
+		CreateCoupon(context.Context, entity.Coupon) (string, error)
+		GetCoupon(ctx context.Context, promotionID, id string) (entity.Coupon, error)
+		GetCouponsByPromotion(ctx context.Context, promotionID string) ([]entity.Coupon, error)
+		GetCouponsByCodes(ctx context.Context, codes []string) ([]entity.Coupon, error)
+		DeleteCoupon(ctx context.Context, promotionID, id string) error
+
+		CreateOrderDiscount(context.Context, entity.OrderDiscount) error
+		GetOrderDiscounts(ctx context.Context, orderID string) ([]entity.OrderDiscount, error)
+
+		GetCurrencies(context.Context) ([]entity.Currency, error)
+		CreateExchangeRate(context.Context, entity.ExchangeRate) (entity.ExchangeRate, error)
+		GetExchangeRate(ctx context.Context, currency string) (entity.ExchangeRate, error)
+		ListExchangeRates(context.Context, entity.ExchangeRateFilter) (entity.Page[entity.ExchangeRate], error)
+
+		GetProductPrices(ctx context.Context, productID string) ([]entity.ProductPrice, error)
+		GetPricesByProducts(ctx context.Context, productIDs []string, currency string) ([]entity.ProductPrice, error)
+		SetProductPrice(context.Context, entity.ProductPrice) (entity.ProductPrice, error)
+		DeleteProductPrice(ctx context.Context, productID, id string) error
+
+		CreateWarehouse(context.Context, entity.Warehouse) (entity.Warehouse, error)
+		GetWarehouse(context.Context, string) (entity.Warehouse, error)
+		GetDefaultWarehouse(context.Context) (entity.Warehouse, error)
+		GetWarehouses(context.Context) ([]entity.Warehouse, error)
+		UpdateWarehouse(context.Context, entity.Warehouse) error
+		SetDefaultWarehouse(ctx context.Context, id string) error
+		DeleteWarehouse(ctx context.Context, id string) error
+
+		GetStockLevels(context.Context, entity.StockLevelFilter) ([]entity.StockLevel, error)
+		CreateStockMovement(context.Context, entity.StockMovement) (entity.StockMovement, error)
+		ListStockMovements(context.Context, entity.StockMovementFilter) (entity.Page[entity.StockMovement], error)
+		GetStockMovementsByOrder(ctx context.Context, orderID string) ([]entity.StockMovement, error)
+
+		GetLowStockThreshold(ctx context.Context, productID string) (entity.LowStockThreshold, error)
+		SetLowStockThreshold(context.Context, entity.LowStockThreshold) (entity.LowStockThreshold, error)
+		DeleteLowStockThreshold(ctx context.Context, productID string) error
+		ClaimLowStockAlert(ctx context.Context, productID string) (entity.LowStockThreshold, error)
+		ResetLowStockAlert(ctx context.Context, productID string) error
+		ReleaseLowStockAlert(ctx context.Context, productID string) error
+
+		CreateStockSubscription(context.Context, entity.StockSubscription) (entity.StockSubscription, error)
+		GetStockSubscriptions(ctx context.Context, productID string) ([]entity.StockSubscription, error)
+		DeleteStockSubscription(ctx context.Context, userID, productID string) error
+		ClaimStockSubscriptions(ctx context.Context, productID string) ([]entity.StockSubscription, error)
+		ReleaseStockSubscription(context.Context, entity.StockSubscription) error
+
+		GetCart(context.Context, entity.CartKey) (entity.Cart, error)
+		GetOrCreateCart(context.Context, entity.CartKey) (entity.Cart, error)
+		DeleteCart(ctx context.Context, id string) error
+		MergeCarts(ctx context.Context, from, to string) error
+		GetCartItems(ctx context.Context, cartID string) ([]entity.CartItem, error)
+		AddCartItem(context.Context, entity.CartItem) (entity.CartItem, error)
+		UpdateCartItem(ctx context.Context, cartID, id string, count int) (entity.CartItem, error)
+		DeleteCartItem(ctx context.Context, cartID, id string) error
 	}
 
 	// TranslationRepo -.
@@ -77,6 +240,41 @@ type (
 		GetHistory(context.Context) ([]entity.Translation, error)
 	}
 
+	// Transactor runs fn in a database transaction carried by ctx.
+	Transactor interface {
+		WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	}
+
+	// PasswordHasher -.
+	PasswordHasher interface {
+		Hash(password string) (string, error)
+		Verify(encoded, password string) (needsRehash bool, err error)
+	}
+
+	// TokenManager -.
+	TokenManager interface {
+		NewAccessToken(userID, roleID string) (string, time.Time, error)
+		ParseAccessToken(accessToken string) (userID, roleID string, err error)
+		NewRefreshToken() (refreshToken, hash string, expiresAt time.Time, err error)
+		HashRefreshToken(refreshToken string) string
+		NewCartSession() (session, hash string, err error)
+		HashCartSession(session string) string
+	}
+
+	// MediaStorage keeps uploaded files under slash-separated keys. Get
+	// reports a missing object as fs.ErrNotExist.
+	MediaStorage interface {
+		Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+		Get(ctx context.Context, key string) (io.ReadCloser, int64, error)
+		Delete(ctx context.Context, key string) error
+	}
+
+	// Notifier delivers text messages to a chat. An empty chatID addresses
+	// the managers' chat.
+	Notifier interface {
+		Notify(ctx context.Context, chatID, text string) error
+	}
+
 	// TranslationWebAPI -.
 	TranslationWebAPI interface {
 		Translate(entity.Translation) (entity.Translation, error)
@@ -3,11 +3,17 @@ package persistent
 import (
 	"context"
 	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
 
 	"ai-seller/internal/entity"
 	"ai-seller/pkg/postgres"
 )
 
+const _userColumns = "id, name, COALESCE(surname, ''), COALESCE(username, ''), birth_date, COALESCE(tg_user_name, ''), " +
+	"COALESCE(phone, ''), COALESCE(instagram, ''), COALESCE(client_from, ''), COALESCE(role_id::text, ''), created_at, updated_at, version"
+
 // AuthRepo -.
 type AuthRepo struct {
 	*postgres.Postgres
@@ -23,18 +29,18 @@ func NewAuthRepo(pg *postgres.Postgres) *AuthRepo {
 // CreateUser -.
 func (r *AuthRepo) CreateUser(ctx context.Context, u entity.User) error {
 	sql, args, err := r.Builder.
-		Insert("user").
+		Insert(`"user"`).
 		Columns("name, surname, username, password, birth_date, tg_user_name, phone, instagram, client_from, role_id").
-		Values(u.Name, u.Surname, u.Username, u.Password, u.BirthDate, u.TgUserName, u.Phone, u.Instagram, u.ClientFrom, u.RoleID).
+		Values(u.Name, u.Surname, u.Username, u.Password, u.BirthDate, u.TgUserName, u.Phone, u.Instagram, u.ClientFrom, nullIfEmpty(u.RoleID)).
 		Suffix("RETURNING id").
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("AuthRepo - CreateUser - r.Builder: %w", err)
 	}
 
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&u.ID)
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&u.ID)
 	if err != nil {
-		return fmt.Errorf("AuthRepo - CreateUser - r.Pool.QueryRow: %w", err)
+		return fmt.Errorf("AuthRepo - CreateUser - r.Querier.QueryRow: %w", mapError(err))
 	}
 
 	return nil
@@ -44,18 +50,41 @@ func (r *AuthRepo) CreateUser(ctx context.Context, u entity.User) error {
 func (r *AuthRepo) GetUser(ctx context.Context, id string) (entity.User, error) {
 	var user entity.User
 	sql, args, err := r.Builder.
-		Select("id, name, surname, username, password, birth_date, tg_user_name, phone, instagram, client_from, role_id, created_at, updated_at").
-		From("user").
+		Select(_userColumns).
+		From(`"user"`).
 		Where("id = ?", id).
+		Where(_notDeleted).
 		ToSql()
 	if err != nil {
 		return user, fmt.Errorf("AuthRepo - GetUser - r.Builder: %w", err)
 	}
 
-	row := r.Pool.QueryRow(ctx, sql, args...)
-	err = row.Scan(&user.ID, &user.Name, &user.Surname, &user.Username, &user.Password, &user.BirthDate, &user.TgUserName, &user.Phone, &user.Instagram, &user.ClientFrom, &user.RoleID, &user.CreatedAt, &user.UpdatedAt)
+	row := r.Querier(ctx).QueryRow(ctx, sql, args...)
+	err = row.Scan(&user.ID, &user.Name, &user.Surname, &user.Username, &user.BirthDate, &user.TgUserName, &user.Phone, &user.Instagram, &user.ClientFrom, &user.RoleID, &user.CreatedAt, &user.UpdatedAt, &user.Version)
 	if err != nil {
-		return user, fmt.Errorf("AuthRepo - GetUser - row.Scan: %w", err)
+		return user, fmt.Errorf("AuthRepo - GetUser - row.Scan: %w", mapError(err))
+	}
+
+	return user, nil
+}
+
+// GetUserByUsername returns the user together with its password hash.
+func (r *AuthRepo) GetUserByUsername(ctx context.Context, username string) (entity.User, error) {
+	var user entity.User
+	sql, args, err := r.Builder.
+		Select(_userColumns+", password").
+		From(`"user"`).
+		Where("username = ?", username).
+		Where(_notDeleted).
+		ToSql()
+	if err != nil {
+		return user, fmt.Errorf("AuthRepo - GetUserByUsername - r.Builder: %w", err)
+	}
+
+	row := r.Querier(ctx).QueryRow(ctx, sql, args...)
+	err = row.Scan(&user.ID, &user.Name, &user.Surname, &user.Username, &user.BirthDate, &user.TgUserName, &user.Phone, &user.Instagram, &user.ClientFrom, &user.RoleID, &user.CreatedAt, &user.UpdatedAt, &user.Version, &user.Password)
+	if err != nil {
+		return user, fmt.Errorf("AuthRepo - GetUserByUsername - row.Scan: %w", mapError(err))
 	}
 
 	return user, nil
@@ -63,51 +92,223 @@ func (r *AuthRepo) GetUser(ctx context.Context, id string) (entity.User, error)
 
 // UpdateUser -.
 func (r *AuthRepo) UpdateUser(ctx context.Context, u entity.User) error {
-	sql, args, err := r.Builder.
-		Update("user").
+	builder := r.Builder.
+		Update(`"user"`).
 		Set("name", u.Name).
 		Set("surname", u.Surname).
 		Set("username", u.Username).
-		Set("password", u.Password).
 		Set("birth_date", u.BirthDate).
 		Set("tg_user_name", u.TgUserName).
 		Set("phone", u.Phone).
 		Set("instagram", u.Instagram).
 		Set("client_from", u.ClientFrom).
-		Set("role_id", u.RoleID).
-		Set("updated_at", "CURRENT_TIMESTAMP").
+		Set("role_id", nullIfEmpty(u.RoleID)).
 		Where("id = ?", u.ID).
-		ToSql()
+		Where(_notDeleted).
+		Where(matchVersion(u.Version))
+
+	// Keep the stored hash when no new password is supplied.
+	if u.Password != "" {
+		builder = builder.Set("password", u.Password)
+	}
+
+	sql, args, err := builder.ToSql()
 	if err != nil {
 		return fmt.Errorf("AuthRepo - UpdateUser - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("AuthRepo - UpdateUser - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkVersion(ctx, r.Postgres, `"user"`, u.ID, u.Version, tag)
 	if err != nil {
-		return fmt.Errorf("AuthRepo - UpdateUser - r.Pool.Exec: %w", err)
+		return fmt.Errorf("AuthRepo - UpdateUser - checkVersion: %w", err)
 	}
 
 	return nil
 }
 
-// DeleteUser -.
-func (r *AuthRepo) DeleteUser(ctx context.Context, id string) error {
+// PatchUser updates only the fields supplied in the patch.
+func (r *AuthRepo) PatchUser(ctx context.Context, id string, p entity.UserPatch) error {
+	set := patchSet{}
+	setField(set, "name", p.Name)
+	setField(set, "surname", p.Surname)
+	setField(set, "username", p.Username)
+	setField(set, "password", p.Password)
+	setField(set, "birth_date", p.BirthDate)
+	setField(set, "tg_user_name", p.TgUserName)
+	setField(set, "phone", p.Phone)
+	setField(set, "instagram", p.Instagram)
+	setField(set, "client_from", p.ClientFrom)
+	setField(set, "role_id", p.RoleID)
+
+	err := execPatch(ctx, r.Postgres, `"user"`, id, p.Version, set)
+	if err != nil {
+		return fmt.Errorf("AuthRepo - PatchUser - %w", err)
+	}
+
+	return nil
+}
+
+// UpdateUserPassword -.
+func (r *AuthRepo) UpdateUserPassword(ctx context.Context, id, hash string) error {
 	sql, args, err := r.Builder.
-		Delete("user").
+		Update(`"user"`).
+		Set("password", hash).
 		Where("id = ?", id).
+		Where(_notDeleted).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("AuthRepo - UpdateUserPassword - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("AuthRepo - UpdateUserPassword - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("AuthRepo - UpdateUserPassword - checkAffected: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteUser moves a user to the trash.
+func (r *AuthRepo) DeleteUser(ctx context.Context, id string, version int) error {
+	sql, args, err := r.Builder.
+		Update(`"user"`).
+		Set("deleted_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where("id = ?", id).
+		Where(_notDeleted).
+		Where(matchVersion(version)).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("AuthRepo - DeleteUser - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
 	if err != nil {
-		return fmt.Errorf("AuthRepo - DeleteUser - r.Pool.Exec: %w", err)
+		return fmt.Errorf("AuthRepo - DeleteUser - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkVersion(ctx, r.Postgres, `"user"`, id, version, tag)
+	if err != nil {
+		return fmt.Errorf("AuthRepo - DeleteUser - checkVersion: %w", err)
 	}
 
 	return nil
 }
 
+// ListDeletedUsers returns the users in the trash, most recently deleted
+// first.
+func (r *AuthRepo) ListDeletedUsers(ctx context.Context, p entity.ListParams) (entity.Page[entity.User], error) {
+	var result entity.Page[entity.User]
+
+	pg, err := newPage(p, _trashSortColumns)
+	if err != nil {
+		return result, fmt.Errorf("AuthRepo - ListDeletedUsers - newPage: %w", err)
+	}
+
+	sql, args, err := r.Builder.
+		Select("COUNT(*)").
+		From(`"user"`).
+		Where("deleted_at IS NOT NULL").
+		ToSql()
+	if err != nil {
+		return result, fmt.Errorf("AuthRepo - ListDeletedUsers - r.Builder: %w", err)
+	}
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&result.Total)
+	if err != nil {
+		return result, fmt.Errorf("AuthRepo - ListDeletedUsers - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	sql, args, err = pg.apply(r.Builder.Select(_userColumns+", deleted_at").From(`"user"`).Where("deleted_at IS NOT NULL"), "").ToSql()
+	if err != nil {
+		return result, fmt.Errorf("AuthRepo - ListDeletedUsers - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return result, fmt.Errorf("AuthRepo - ListDeletedUsers - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	items := make([]entity.User, 0, pg.limit+1)
+
+	for rows.Next() {
+		var u entity.User
+
+		err = rows.Scan(&u.ID, &u.Name, &u.Surname, &u.Username, &u.BirthDate, &u.TgUserName, &u.Phone, &u.Instagram, &u.ClientFrom, &u.RoleID, &u.CreatedAt, &u.UpdatedAt, &u.Version, &u.DeletedAt)
+		if err != nil {
+			return result, fmt.Errorf("AuthRepo - ListDeletedUsers - rows.Scan: %w", err)
+		}
+
+		items = append(items, u)
+	}
+
+	result.Items, result.NextCursor, err = trim(pg, items, func(u entity.User, column string) (interface{}, string) {
+		if column == "name" {
+			return u.Name, u.ID
+		}
+
+		return *u.DeletedAt, u.ID
+	})
+	if err != nil {
+		return result, fmt.Errorf("AuthRepo - ListDeletedUsers - trim: %w", err)
+	}
+
+	return result, nil
+}
+
+// RestoreUser takes a user out of the trash. A user not in the trash is
+// ErrNotFound; one whose username was taken in the meantime is ErrConflict.
+func (r *AuthRepo) RestoreUser(ctx context.Context, id string) (entity.User, error) {
+	sql, args, err := r.Builder.
+		Update(`"user"`).
+		Set("deleted_at", nil).
+		Where("id = ?", id).
+		Where("deleted_at IS NOT NULL").
+		Suffix("RETURNING " + _userColumns).
+		ToSql()
+	if err != nil {
+		return entity.User{}, fmt.Errorf("AuthRepo - RestoreUser - r.Builder: %w", err)
+	}
+
+	var u entity.User
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&u.ID, &u.Name, &u.Surname, &u.Username, &u.BirthDate, &u.TgUserName, &u.Phone, &u.Instagram, &u.ClientFrom, &u.RoleID, &u.CreatedAt, &u.UpdatedAt, &u.Version)
+	if err != nil {
+		return entity.User{}, fmt.Errorf("AuthRepo - RestoreUser - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return u, nil
+}
+
+// PurgeUsers removes the users deleted before the given time that placed no
+// order, and returns how many were removed.
+func (r *AuthRepo) PurgeUsers(ctx context.Context, before time.Time) (int, error) {
+	sql, args, err := r.Builder.
+		Delete(`"user" u`).
+		Where("u.deleted_at < ?", before).
+		Where(`NOT EXISTS (SELECT 1 FROM "order" o WHERE o.user_id = u.id)`).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("AuthRepo - PurgeUsers - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("AuthRepo - PurgeUsers - r.Querier.Exec: %w", mapDeleteError(err))
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
 // -------------- Role --------------
 
 // CreateRole -.
@@ -122,9 +323,9 @@ func (r *AuthRepo) CreateRole(ctx context.Context, role entity.Role) error {
 		return fmt.Errorf("AuthRepo - CreateRole - r.Builder: %w", err)
 	}
 
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&role.ID)
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&role.ID)
 	if err != nil {
-		return fmt.Errorf("AuthRepo - CreateRole - r.Pool.QueryRow: %w", err)
+		return fmt.Errorf("AuthRepo - CreateRole - r.Querier.QueryRow: %w", mapError(err))
 	}
 
 	return nil
@@ -142,10 +343,10 @@ func (r *AuthRepo) GetRole(ctx context.Context, id string) (entity.Role, error)
 		return role, fmt.Errorf("AuthRepo - GetRole - r.Builder: %w", err)
 	}
 
-	row := r.Pool.QueryRow(ctx, sql, args...)
+	row := r.Querier(ctx).QueryRow(ctx, sql, args...)
 	err = row.Scan(&role.ID, &role.Name, &role.CreatedAt, &role.UpdatedAt)
 	if err != nil {
-		return role, fmt.Errorf("AuthRepo - GetRole - row.Scan: %w", err)
+		return role, fmt.Errorf("AuthRepo - GetRole - row.Scan: %w", mapError(err))
 	}
 
 	return role, nil
@@ -156,16 +357,20 @@ func (r *AuthRepo) UpdateRole(ctx context.Context, role entity.Role) error {
 	sql, args, err := r.Builder.
 		Update("role").
 		Set("name", role.Name).
-		Set("updated_at", "CURRENT_TIMESTAMP").
 		Where("id = ?", role.ID).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("AuthRepo - UpdateRole - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("AuthRepo - UpdateRole - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkAffected(tag)
 	if err != nil {
-		return fmt.Errorf("AuthRepo - UpdateRole - r.Pool.Exec: %w", err)
+		return fmt.Errorf("AuthRepo - UpdateRole - checkAffected: %w", err)
 	}
 
 	return nil
@@ -181,9 +386,14 @@ func (r *AuthRepo) DeleteRole(ctx context.Context, id string) error {
 		return fmt.Errorf("AuthRepo - DeleteRole - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("AuthRepo - DeleteRole - r.Querier.Exec: %w", mapDeleteError(err))
+	}
+
+	err = checkAffected(tag)
 	if err != nil {
-		return fmt.Errorf("AuthRepo - DeleteRole - r.Pool.Exec: %w", err)
+		return fmt.Errorf("AuthRepo - DeleteRole - checkAffected: %w", err)
 	}
 
 	return nil
@@ -203,9 +413,9 @@ func (r *AuthRepo) CreateClientType(ctx context.Context, clientType entity.Clien
 		return fmt.Errorf("AuthRepo - CreateClientType - r.Builder: %w", err)
 	}
 
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&clientType.ID)
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&clientType.ID)
 	if err != nil {
-		return fmt.Errorf("AuthRepo - CreateClientType - r.Pool.QueryRow: %w", err)
+		return fmt.Errorf("AuthRepo - CreateClientType - r.Querier.QueryRow: %w", mapError(err))
 	}
 
 	return nil
@@ -223,10 +433,10 @@ func (r *AuthRepo) GetClientType(ctx context.Context, id string) (entity.ClientT
 		return clientType, fmt.Errorf("AuthRepo - GetClientType - r.Builder: %w", err)
 	}
 
-	row := r.Pool.QueryRow(ctx, sql, args...)
+	row := r.Querier(ctx).QueryRow(ctx, sql, args...)
 	err = row.Scan(&clientType.ID, &clientType.Name, &clientType.CreatedAt, &clientType.UpdatedAt)
 	if err != nil {
-		return clientType, fmt.Errorf("AuthRepo - GetClientType - row.Scan: %w", err)
+		return clientType, fmt.Errorf("AuthRepo - GetClientType - row.Scan: %w", mapError(err))
 	}
 
 	return clientType, nil
@@ -237,16 +447,20 @@ func (r *AuthRepo) UpdateClientType(ctx context.Context, clientType entity.Clien
 	sql, args, err := r.Builder.
 		Update("client_type").
 		Set("name", clientType.Name).
-		Set("updated_at", "CURRENT_TIMESTAMP").
 		Where("id = ?", clientType.ID).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("AuthRepo - UpdateClientType - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("AuthRepo - UpdateClientType - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkAffected(tag)
 	if err != nil {
-		return fmt.Errorf("AuthRepo - UpdateClientType - r.Pool.Exec: %w", err)
+		return fmt.Errorf("AuthRepo - UpdateClientType - checkAffected: %w", err)
 	}
 
 	return nil
@@ -262,10 +476,242 @@ func (r *AuthRepo) DeleteClientType(ctx context.Context, id string) error {
 		return fmt.Errorf("AuthRepo - DeleteClientType - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
 	if err != nil {
-		return fmt.Errorf("AuthRepo - DeleteClientType - r.Pool.Exec: %w", err)
+		return fmt.Errorf("AuthRepo - DeleteClientType - r.Querier.Exec: %w", mapDeleteError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("AuthRepo - DeleteClientType - checkAffected: %w", err)
 	}
 
 	return nil
 }
+
+// -------------- RefreshToken --------------
+
+// CreateRefreshToken -.
+func (r *AuthRepo) CreateRefreshToken(ctx context.Context, t entity.RefreshToken) error {
+	sql, args, err := r.Builder.
+		Insert("refresh_token").
+		Columns("user_id, family_id, token_hash, expires_at").
+		Values(t.UserID, t.FamilyID, t.TokenHash, t.ExpiresAt).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("AuthRepo - CreateRefreshToken - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("AuthRepo - CreateRefreshToken - r.Querier.Exec: %w", mapError(err))
+	}
+
+	return nil
+}
+
+// GetRefreshToken -.
+func (r *AuthRepo) GetRefreshToken(ctx context.Context, hash string) (entity.RefreshToken, error) {
+	var t entity.RefreshToken
+	sql, args, err := r.Builder.
+		Select("id, user_id, family_id, token_hash, expires_at, revoked_at, created_at").
+		From("refresh_token").
+		Where("token_hash = ?", hash).
+		ToSql()
+	if err != nil {
+		return t, fmt.Errorf("AuthRepo - GetRefreshToken - r.Builder: %w", err)
+	}
+
+	row := r.Querier(ctx).QueryRow(ctx, sql, args...)
+	err = row.Scan(&t.ID, &t.UserID, &t.FamilyID, &t.TokenHash, &t.ExpiresAt, &t.RevokedAt, &t.CreatedAt)
+	if err != nil {
+		return t, fmt.Errorf("AuthRepo - GetRefreshToken - row.Scan: %w", mapError(err))
+	}
+
+	return t, nil
+}
+
+// RevokeRefreshToken revokes a still active token. It reports false when the
+// token had already been revoked, e.g. by a concurrent refresh.
+func (r *AuthRepo) RevokeRefreshToken(ctx context.Context, id string) (bool, error) {
+	sql, args, err := r.Builder.
+		Update("refresh_token").
+		Set("revoked_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("AuthRepo - RevokeRefreshToken - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return false, fmt.Errorf("AuthRepo - RevokeRefreshToken - r.Querier.Exec: %w", mapError(err))
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// RevokeRefreshTokenFamily -.
+func (r *AuthRepo) RevokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	sql, args, err := r.Builder.
+		Update("refresh_token").
+		Set("revoked_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where("family_id = ?", familyID).
+		Where("revoked_at IS NULL").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("AuthRepo - RevokeRefreshTokenFamily - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("AuthRepo - RevokeRefreshTokenFamily - r.Querier.Exec: %w", mapError(err))
+	}
+
+	return nil
+}
+
+// RevokeUserRefreshTokens revokes every active token of a user.
+func (r *AuthRepo) RevokeUserRefreshTokens(ctx context.Context, userID string) error {
+	sql, args, err := r.Builder.
+		Update("refresh_token").
+		Set("revoked_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("AuthRepo - RevokeUserRefreshTokens - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("AuthRepo - RevokeUserRefreshTokens - r.Querier.Exec: %w", mapError(err))
+	}
+
+	return nil
+}
+
+// -------------- Permission --------------
+
+// GetPermissions -.
+func (r *AuthRepo) GetPermissions(ctx context.Context) ([]entity.Permission, error) {
+	sql, args, err := r.Builder.
+		Select("id, name, COALESCE(description, ''), created_at, updated_at").
+		From("permission").
+		OrderBy("name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("AuthRepo - GetPermissions - r.Builder: %w", err)
+	}
+
+	return r.queryPermissions(ctx, "GetPermissions", sql, args)
+}
+
+// GetRolePermissions -.
+func (r *AuthRepo) GetRolePermissions(ctx context.Context, roleID string) ([]entity.Permission, error) {
+	sql, args, err := r.Builder.
+		Select("p.id, p.name, COALESCE(p.description, ''), p.created_at, p.updated_at").
+		From("permission p").
+		Join("role_permission rp ON rp.permission_id = p.id").
+		Where("rp.role_id = ?", roleID).
+		OrderBy("p.name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("AuthRepo - GetRolePermissions - r.Builder: %w", err)
+	}
+
+	return r.queryPermissions(ctx, "GetRolePermissions", sql, args)
+}
+
+func (r *AuthRepo) queryPermissions(ctx context.Context, method, sql string, args []interface{}) ([]entity.Permission, error) {
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("AuthRepo - %s - r.Querier.Query: %w", method, err)
+	}
+	defer rows.Close()
+
+	permissions := make([]entity.Permission, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var p entity.Permission
+
+		err = rows.Scan(&p.ID, &p.Name, &p.Description, &p.CreatedAt, &p.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("AuthRepo - %s - rows.Scan: %w", method, err)
+		}
+
+		permissions = append(permissions, p)
+	}
+
+	return permissions, nil
+}
+
+// AddRolePermission -.
+func (r *AuthRepo) AddRolePermission(ctx context.Context, roleID, permissionID string) error {
+	sql, args, err := r.Builder.
+		Insert("role_permission").
+		Columns("role_id, permission_id").
+		Values(roleID, permissionID).
+		Suffix("ON CONFLICT DO NOTHING").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("AuthRepo - AddRolePermission - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("AuthRepo - AddRolePermission - r.Querier.Exec: %w", mapError(err))
+	}
+
+	return nil
+}
+
+// DeleteRolePermission -.
+func (r *AuthRepo) DeleteRolePermission(ctx context.Context, roleID, permissionID string) error {
+	sql, args, err := r.Builder.
+		Delete("role_permission").
+		Where("role_id = ?", roleID).
+		Where("permission_id = ?", permissionID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("AuthRepo - DeleteRolePermission - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("AuthRepo - DeleteRolePermission - r.Querier.Exec: %w", mapDeleteError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("AuthRepo - DeleteRolePermission - checkAffected: %w", err)
+	}
+
+	return nil
+}
+
+// HasPermission -.
+func (r *AuthRepo) HasPermission(ctx context.Context, roleID, permission string) (bool, error) {
+	sql, args, err := r.Builder.
+		Select("1").
+		From("role_permission rp").
+		Join("permission p ON p.id = rp.permission_id").
+		Where("rp.role_id = ?", roleID).
+		Where("p.name = ?", permission).
+		Prefix("SELECT EXISTS (").
+		Suffix(")").
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("AuthRepo - HasPermission - r.Builder: %w", err)
+	}
+
+	var ok bool
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&ok)
+	if err != nil {
+		return false, fmt.Errorf("AuthRepo - HasPermission - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return ok, nil
+}
@@ -0,0 +1,237 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+
+	"ai-seller/internal/entity"
+)
+
+const (
+	_cartColumns     = "id, COALESCE(user_id::text, ''), COALESCE(session_id, ''), created_at, updated_at"
+	_cartItemColumns = "id, cart_id, product_id, COALESCE(variant_id::text, ''), count, created_at, updated_at"
+)
+
+// cartOwner matches the cart of k.
+func cartOwner(k entity.CartKey) squirrel.Eq {
+	if k.UserID != "" {
+		return squirrel.Eq{"user_id": k.UserID}
+	}
+
+	return squirrel.Eq{"session_id": k.SessionID}
+}
+
+// GetCart returns the cart of k without its items.
+func (r *ProductRepo) GetCart(ctx context.Context, k entity.CartKey) (entity.Cart, error) {
+	sql, args, err := r.Builder.
+		Select(_cartColumns).
+		From("cart").
+		Where(cartOwner(k)).
+		ToSql()
+	if err != nil {
+		return entity.Cart{}, fmt.Errorf("ProductRepo - GetCart - r.Builder: %w", err)
+	}
+
+	var c entity.Cart
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&c.ID, &c.UserID, &c.SessionID, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return entity.Cart{}, fmt.Errorf("ProductRepo - GetCart - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return c, nil
+}
+
+// GetOrCreateCart returns the cart of k, creating an empty one when there is
+// none yet.
+func (r *ProductRepo) GetOrCreateCart(ctx context.Context, k entity.CartKey) (entity.Cart, error) {
+	insert := r.Builder.
+		Insert("cart").
+		Columns("user_id, session_id")
+
+	if k.UserID != "" {
+		insert = insert.
+			Values(k.UserID, nil).
+			Suffix("ON CONFLICT (user_id) WHERE user_id IS NOT NULL")
+	} else {
+		insert = insert.
+			Values(nil, k.SessionID).
+			Suffix("ON CONFLICT (session_id) WHERE session_id IS NOT NULL")
+	}
+
+	// A no-op update, so that an existing cart is returned too.
+	sql, args, err := insert.
+		Suffix("DO UPDATE SET updated_at = CURRENT_TIMESTAMP RETURNING " + _cartColumns).
+		ToSql()
+	if err != nil {
+		return entity.Cart{}, fmt.Errorf("ProductRepo - GetOrCreateCart - r.Builder: %w", err)
+	}
+
+	var c entity.Cart
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&c.ID, &c.UserID, &c.SessionID, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return entity.Cart{}, fmt.Errorf("ProductRepo - GetOrCreateCart - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return c, nil
+}
+
+// DeleteCart deletes a cart with its items.
+func (r *ProductRepo) DeleteCart(ctx context.Context, id string) error {
+	sql, args, err := r.Builder.
+		Delete("cart").
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteCart - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteCart - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteCart - checkAffected: %w", err)
+	}
+
+	return nil
+}
+
+// MergeCarts moves the items of cart from into cart to, adding up the counts
+// of items in both, and deletes cart from. It must run in a transaction.
+func (r *ProductRepo) MergeCarts(ctx context.Context, from, to string) error {
+	sql, args, err := r.Builder.
+		Insert("cart_item").
+		Columns("cart_id, product_id, variant_id, count").
+		Select(r.Builder.
+			Select().
+			Column("?::uuid", to).
+			Columns("product_id, variant_id, count").
+			From("cart_item").
+			Where("cart_id = ?", from)).
+		Suffix("ON CONFLICT (cart_id, product_id, variant_id) DO UPDATE SET count = cart_item.count + EXCLUDED.count").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - MergeCarts - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - MergeCarts - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = r.DeleteCart(ctx, from)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - MergeCarts - %w", err)
+	}
+
+	return nil
+}
+
+// GetCartItems returns the items of a cart in the order they were added.
+func (r *ProductRepo) GetCartItems(ctx context.Context, cartID string) ([]entity.CartItem, error) {
+	sql, args, err := r.Builder.
+		Select(_cartItemColumns).
+		From("cart_item").
+		Where("cart_id = ?", cartID).
+		OrderBy("created_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetCartItems - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetCartItems - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	items := make([]entity.CartItem, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var i entity.CartItem
+
+		err = rows.Scan(&i.ID, &i.CartID, &i.ProductID, &i.VariantID, &i.Count, &i.CreatedAt, &i.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ProductRepo - GetCartItems - rows.Scan: %w", err)
+		}
+
+		items = append(items, i)
+	}
+
+	return items, nil
+}
+
+// AddCartItem puts an item in a cart. An item already in the cart has its
+// count increased instead; the item is returned with the resulting count.
+func (r *ProductRepo) AddCartItem(ctx context.Context, i entity.CartItem) (entity.CartItem, error) {
+	sql, args, err := r.Builder.
+		Insert("cart_item").
+		Columns("cart_id, product_id, variant_id, count").
+		Values(i.CartID, i.ProductID, nullIfEmpty(i.VariantID), i.Count).
+		Suffix("ON CONFLICT (cart_id, product_id, variant_id) DO UPDATE SET count = cart_item.count + EXCLUDED.count " +
+			"RETURNING " + _cartItemColumns).
+		ToSql()
+	if err != nil {
+		return entity.CartItem{}, fmt.Errorf("ProductRepo - AddCartItem - r.Builder: %w", err)
+	}
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&i.ID, &i.CartID, &i.ProductID, &i.VariantID, &i.Count, &i.CreatedAt, &i.UpdatedAt)
+	if err != nil {
+		return entity.CartItem{}, fmt.Errorf("ProductRepo - AddCartItem - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return i, nil
+}
+
+// UpdateCartItem sets the count of an item in a cart.
+func (r *ProductRepo) UpdateCartItem(ctx context.Context, cartID, id string, count int) (entity.CartItem, error) {
+	sql, args, err := r.Builder.
+		Update("cart_item").
+		Set("count", count).
+		Where("id = ?", id).
+		Where("cart_id = ?", cartID).
+		Suffix("RETURNING " + _cartItemColumns).
+		ToSql()
+	if err != nil {
+		return entity.CartItem{}, fmt.Errorf("ProductRepo - UpdateCartItem - r.Builder: %w", err)
+	}
+
+	var i entity.CartItem
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&i.ID, &i.CartID, &i.ProductID, &i.VariantID, &i.Count, &i.CreatedAt, &i.UpdatedAt)
+	if err != nil {
+		return entity.CartItem{}, fmt.Errorf("ProductRepo - UpdateCartItem - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return i, nil
+}
+
+// DeleteCartItem -.
+func (r *ProductRepo) DeleteCartItem(ctx context.Context, cartID, id string) error {
+	sql, args, err := r.Builder.
+		Delete("cart_item").
+		Where("id = ?", id).
+		Where("cart_id = ?", cartID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteCartItem - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteCartItem - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteCartItem - checkAffected: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,329 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+
+	"ai-seller/internal/entity"
+)
+
+const (
+	// _priceExpr is the price a customer pays, see entity.Product.UnitPrice.
+	_priceExpr = "CASE WHEN COALESCE(discount_cost, 0) > 0 AND discount_cost < cost THEN discount_cost ELSE cost END"
+
+	// _catalogSource exposes products with their effective price as p.
+	_catalogSource = "(SELECT product.*, " + _priceExpr + " AS price FROM product WHERE deleted_at IS NULL) p"
+
+	_defaultPriceBuckets = 5
+	_maxPriceBuckets     = 20
+)
+
+var _catalogSortColumns = []string{"created_at", "price", "name"}
+
+// SearchCatalog returns a page of products matching q together with facet
+// counts. Each facet is computed without its own condition, so that picking
+// a value does not hide the alternatives.
+func (r *ProductRepo) SearchCatalog(ctx context.Context, q entity.CatalogQuery, attributes []entity.Attribute) (entity.CatalogResult, error) {
+	var result entity.CatalogResult
+
+	pg, err := newPage(q.ListParams, _catalogSortColumns)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - SearchCatalog - newPage: %w", err)
+	}
+
+	where := catalogWhere(q, "", false)
+
+	result.Total, err = r.count(ctx, _catalogSource, where)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - SearchCatalog - r.count: %w", err)
+	}
+
+	sql, args, err := pg.apply(r.Builder.Select(_productColumns).From(_catalogSource).Where(where), "").ToSql()
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - SearchCatalog - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - SearchCatalog - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	items := make([]entity.Product, 0, pg.limit+1)
+
+	for rows.Next() {
+		var p entity.Product
+
+		err = rows.Scan(&p.ID, &p.Name, &p.SKU, &p.CategoryID, &p.ShortInfo, &p.Description, &p.Cost, &p.Count, &p.Available, &p.DiscountCost, &p.Discount, &p.CreatedAt, &p.UpdatedAt, &p.Version)
+		if err != nil {
+			return result, fmt.Errorf("ProductRepo - SearchCatalog - rows.Scan: %w", err)
+		}
+
+		items = append(items, p)
+	}
+
+	result.Items, result.NextCursor, err = trim(pg, items, func(p entity.Product, column string) (interface{}, string) {
+		if column == "price" {
+			return p.UnitPrice(), p.ID
+		}
+
+		return productSortKey(p, column)
+	})
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - SearchCatalog - trim: %w", err)
+	}
+
+	result.Facets = make([]entity.Facet, 0, len(attributes))
+
+	for _, a := range attributes {
+		facet, err := r.attributeFacet(ctx, a, catalogWhere(q, a.ID, false))
+		if err != nil {
+			return result, fmt.Errorf("ProductRepo - SearchCatalog - r.attributeFacet: %w", err)
+		}
+
+		result.Facets = append(result.Facets, facet)
+	}
+
+	result.Price, err = r.priceFacet(ctx, catalogWhere(q, "", true), q.Buckets)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - SearchCatalog - r.priceFacet: %w", err)
+	}
+
+	return result, nil
+}
+
+// catalogWhere builds the conditions of q over _catalogSource, leaving out
+// the condition on attribute skip and, if skipPrice is set, the price range.
+func catalogWhere(q entity.CatalogQuery, skip string, skipPrice bool) squirrel.And {
+	where := squirrel.And{}
+
+	if q.CategoryID != "" {
+		where = append(where, squirrel.Eq{"p.category_id": q.CategoryID})
+	}
+
+	if q.MinPrice != nil && !skipPrice {
+		where = append(where, squirrel.GtOrEq{"p.price": *q.MinPrice})
+	}
+
+	if q.MaxPrice != nil && !skipPrice {
+		where = append(where, squirrel.LtOrEq{"p.price": *q.MaxPrice})
+	}
+
+	if q.Discounted != nil {
+		where = append(where, squirrel.Expr("(p.price < p.cost) = ?", *q.Discounted))
+	}
+
+	if q.InStock != nil {
+		where = append(where, squirrel.Expr("(p.count > p.reserved) = ?", *q.InStock))
+	}
+
+	for _, c := range q.Attributes {
+		if c.AttributeID == skip {
+			continue
+		}
+
+		where = append(where, attributeCondition(c))
+	}
+
+	return where
+}
+
+// attributeCondition matches products having a value of the attribute that
+// satisfies c. Values arrive as text and are cast by Postgres.
+func attributeCondition(c entity.AttributeCondition) squirrel.Sqlizer {
+	match := squirrel.And{squirrel.Expr("c.product_id = p.id"), squirrel.Eq{"c.attribute_id": c.AttributeID}}
+
+	switch c.Type {
+	case entity.AttributeTypeNumber, entity.AttributeTypeUnit:
+		if len(c.Values) > 0 {
+			match = append(match, squirrel.Expr("c.value_number = ANY(?::numeric[])", c.Values))
+		}
+
+		if c.Min != nil {
+			match = append(match, squirrel.GtOrEq{"c.value_number": *c.Min})
+		}
+
+		if c.Max != nil {
+			match = append(match, squirrel.LtOrEq{"c.value_number": *c.Max})
+		}
+	case entity.AttributeTypeBoolean:
+		match = append(match, squirrel.Expr("c.value_boolean = ANY(?::boolean[])", c.Values))
+	default:
+		match = append(match, squirrel.Expr("c.value_string = ANY(?)", c.Values))
+	}
+
+	sql, args, _ := match.ToSql()
+
+	return squirrel.Expr("EXISTS (SELECT 1 FROM product_attribute_value c WHERE "+sql+")", args...)
+}
+
+// attributeFacet counts the products of where per value of a. Number and
+// unit attributes also report the range of their values.
+func (r *ProductRepo) attributeFacet(ctx context.Context, a entity.Attribute, where squirrel.And) (entity.Facet, error) {
+	facet := entity.Facet{AttributeID: a.ID, Name: a.Name, Type: a.Type, Unit: a.Unit}
+
+	base := r.Builder.
+		Select().
+		From("product_attribute_value v").
+		Join(_catalogSource + " ON p.id = v.product_id").
+		Where(squirrel.Eq{"v.attribute_id": a.ID}).
+		Where(where)
+
+	sql, args, err := base.
+		Columns("v.value_string", "v.value_number::float8", "v.value_boolean", "COUNT(*)").
+		GroupBy("v.value_string", "v.value_number", "v.value_boolean").
+		OrderBy("COUNT(*) DESC", "v.value_string", "v.value_number", "v.value_boolean").
+		ToSql()
+	if err != nil {
+		return facet, fmt.Errorf("r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return facet, fmt.Errorf("r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			value   entity.FacetValue
+			str     *string
+			number  *float64
+			boolean *bool
+		)
+
+		err = rows.Scan(&str, &number, &boolean, &value.Count)
+		if err != nil {
+			return facet, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		switch {
+		case number != nil:
+			value.Value = *number
+
+			if facet.Min == nil || *number < *facet.Min {
+				facet.Min = number
+			}
+
+			if facet.Max == nil || *number > *facet.Max {
+				facet.Max = number
+			}
+		case boolean != nil:
+			value.Value = *boolean
+		case str != nil:
+			value.Value = *str
+		}
+
+		facet.Values = append(facet.Values, value)
+	}
+
+	return facet, nil
+}
+
+// priceFacet finds the price range of where and splits it into buckets of
+// equal, rounded width.
+func (r *ProductRepo) priceFacet(ctx context.Context, where squirrel.And, buckets int) (entity.PriceFacet, error) {
+	var facet entity.PriceFacet
+
+	if buckets <= 0 {
+		buckets = _defaultPriceBuckets
+	}
+
+	if buckets > _maxPriceBuckets {
+		buckets = _maxPriceBuckets
+	}
+
+	sql, args, err := r.Builder.
+		Select("COALESCE(MIN(p.price), 0)", "COALESCE(MAX(p.price), 0)").
+		From(_catalogSource).
+		Where(where).
+		ToSql()
+	if err != nil {
+		return facet, fmt.Errorf("r.Builder: %w", err)
+	}
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&facet.Min, &facet.Max)
+	if err != nil {
+		return facet, fmt.Errorf("r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	start, step := priceStep(facet.Min, facet.Max, buckets)
+
+	sql, args, err = r.Builder.
+		Select(fmt.Sprintf("(p.price - %d) / %d AS bucket", start, step), "COUNT(*)").
+		From(_catalogSource).
+		Where(where).
+		GroupBy("bucket").
+		ToSql()
+	if err != nil {
+		return facet, fmt.Errorf("r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return facet, fmt.Errorf("r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	counts := make(map[int]int, buckets)
+
+	for rows.Next() {
+		var bucket, count int
+
+		err = rows.Scan(&bucket, &count)
+		if err != nil {
+			return facet, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		counts[bucket] = count
+	}
+
+	facet.Buckets = priceBuckets(start, step, facet.Max, counts)
+
+	return facet, nil
+}
+
+// priceStep picks the start and the rounded width of at most buckets
+// buckets covering min to max. Rounding start down to a multiple of the
+// width can leave max past the last bucket; the next wider step is taken
+// then.
+func priceStep(minPrice, maxPrice, buckets int) (start, step int) {
+	step = niceStep((maxPrice - minPrice + buckets) / buckets)
+	start = minPrice / step * step
+
+	for start+buckets*step <= maxPrice {
+		step = niceStep(step + 1)
+		start = minPrice / step * step
+	}
+
+	return start, step
+}
+
+// priceBuckets lists the buckets from start up to the one holding maxPrice
+// with the product counts per bucket index.
+func priceBuckets(start, step, maxPrice int, counts map[int]int) []entity.PriceBucket {
+	buckets := make([]entity.PriceBucket, 0, (maxPrice-start)/step+1)
+
+	for from := start; from <= maxPrice; from += step {
+		buckets = append(buckets, entity.PriceBucket{From: from, To: from + step, Count: counts[(from-start)/step]})
+	}
+
+	return buckets
+}
+
+// niceStep rounds n up to 1, 2 or 5 times a power of ten.
+func niceStep(n int) int {
+	step := 1
+
+	for {
+		for _, m := range []int{1, 2, 5} {
+			if step*m >= n {
+				return step * m
+			}
+		}
+
+		step *= 10
+	}
+}
@@ -0,0 +1,107 @@
+package persistent
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/internal/entity"
+)
+
+func TestNiceStep(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		n    int
+		want int
+	}{
+		{n: 0, want: 1},
+		{n: 1, want: 1},
+		{n: 2, want: 2},
+		{n: 3, want: 5},
+		{n: 5, want: 5},
+		{n: 6, want: 10},
+		{n: 10, want: 10},
+		{n: 11, want: 20},
+		{n: 21, want: 50},
+		{n: 51, want: 100},
+		{n: 101, want: 200},
+		{n: 250, want: 500},
+		{n: 1234567, want: 2000000},
+	}
+
+	for _, tc := range tests {
+		t.Run(strconv.Itoa(tc.n), func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, niceStep(tc.n))
+		})
+	}
+}
+
+func TestPriceStep(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name               string
+		minPrice, maxPrice int
+		buckets            int
+		start, step        int
+	}{
+		{name: "no products", minPrice: 0, maxPrice: 0, buckets: 5, start: 0, step: 1},
+		{name: "single price", minPrice: 1000, maxPrice: 1000, buckets: 5, start: 1000, step: 1},
+		{name: "fits", minPrice: 15, maxPrice: 95, buckets: 5, start: 0, step: 20},
+		{name: "exact", minPrice: 0, maxPrice: 99, buckets: 20, start: 0, step: 5},
+		{name: "fewer buckets", minPrice: 0, maxPrice: 100, buckets: 20, start: 0, step: 10},
+		{name: "rounded start overflows", minPrice: 19, maxPrice: 101, buckets: 5, start: 0, step: 50},
+		{name: "unaligned range", minPrice: 990, maxPrice: 1010, buckets: 2, start: 980, step: 20},
+		{name: "one bucket", minPrice: 95, maxPrice: 105, buckets: 1, start: 0, step: 200},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			start, step := priceStep(tc.minPrice, tc.maxPrice, tc.buckets)
+			require.Equal(t, tc.start, start)
+			require.Equal(t, tc.step, step)
+		})
+	}
+}
+
+func TestPriceStepBucketCount(t *testing.T) {
+	t.Parallel()
+
+	for buckets := 1; buckets <= _maxPriceBuckets; buckets++ {
+		for minPrice := 0; minPrice < 300; minPrice += 7 {
+			for maxPrice := minPrice; maxPrice < 1500; maxPrice += 13 {
+				start, step := priceStep(minPrice, maxPrice, buckets)
+				got := priceBuckets(start, step, maxPrice, nil)
+
+				require.LessOrEqual(t, len(got), buckets, "min %d max %d buckets %d", minPrice, maxPrice, buckets)
+				require.LessOrEqual(t, got[0].From, minPrice)
+				require.Greater(t, got[len(got)-1].To, maxPrice)
+			}
+		}
+	}
+}
+
+func TestPriceBuckets(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []entity.PriceBucket{
+		{From: 0, To: 20, Count: 1},
+		{From: 20, To: 40, Count: 0},
+		{From: 40, To: 60, Count: 2},
+		{From: 60, To: 80, Count: 0},
+		{From: 80, To: 100, Count: 3},
+	}, priceBuckets(0, 20, 95, map[int]int{0: 1, 2: 2, 4: 3}))
+
+	require.Equal(t, []entity.PriceBucket{
+		{From: 980, To: 1000, Count: 4},
+		{From: 1000, To: 1020, Count: 1},
+	}, priceBuckets(980, 20, 1010, map[int]int{0: 4, 1: 1}))
+
+	require.Equal(t, []entity.PriceBucket{{From: 0, To: 1, Count: 0}}, priceBuckets(0, 1, 0, nil))
+}
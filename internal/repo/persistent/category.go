@@ -0,0 +1,193 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+
+	"ai-seller/internal/entity"
+)
+
+const (
+	// _subtreeQuery selects the IDs of a category and all its descendants.
+	_subtreeQuery = "WITH RECURSIVE subtree AS (" +
+		"SELECT id FROM category WHERE id = ? AND deleted_at IS NULL " +
+		"UNION SELECT c.id FROM category c JOIN subtree s ON c.parent_id = s.id WHERE c.deleted_at IS NULL" +
+		") SELECT id FROM subtree"
+
+	// _ancestorsQuery selects a category and its ancestors with their depth
+	// counted from the category, which has depth 0. The ancestors of a
+	// category outside the trash are never in it.
+	_ancestorsQuery = "WITH RECURSIVE ancestors AS (" +
+		"SELECT category.*, 0 AS depth FROM category WHERE id = ? AND deleted_at IS NULL " +
+		"UNION ALL SELECT c.*, a.depth + 1 FROM category c JOIN ancestors a ON c.id = a.parent_id" +
+		") "
+)
+
+// GetCategorySubtree returns rootID and all its descendants, or every
+// category when rootID is empty, ordered by parent and position.
+func (r *ProductRepo) GetCategorySubtree(ctx context.Context, rootID string) ([]entity.Category, error) {
+	builder := r.Builder.
+		Select(_categoryColumns).
+		From("category").
+		Where(_notDeleted).
+		OrderBy("parent_id NULLS FIRST", "position", "name")
+
+	if rootID != "" {
+		builder = builder.Where("id IN ("+_subtreeQuery+")", rootID)
+	}
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetCategorySubtree - r.Builder: %w", err)
+	}
+
+	categories, err := r.queryCategories(ctx, sql, args)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetCategorySubtree - %w", err)
+	}
+
+	return categories, nil
+}
+
+// GetCategoryPath returns the ancestors of a category from the root down to
+// the category itself.
+func (r *ProductRepo) GetCategoryPath(ctx context.Context, id string) ([]entity.Category, error) {
+	sql, args, err := r.Builder.
+		Select(_categoryColumns).
+		Prefix(_ancestorsQuery, id).
+		From("ancestors").
+		OrderBy("depth DESC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetCategoryPath - r.Builder: %w", err)
+	}
+
+	path, err := r.queryCategories(ctx, sql, args)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetCategoryPath - %w", err)
+	}
+
+	if len(path) == 0 {
+		return nil, fmt.Errorf("ProductRepo - GetCategoryPath: %w", entity.ErrNotFound)
+	}
+
+	return path, nil
+}
+
+func (r *ProductRepo) queryCategories(ctx context.Context, sql string, args []interface{}) ([]entity.Category, error) {
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	categories := make([]entity.Category, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var c entity.Category
+
+		err = rows.Scan(&c.ID, &c.Name, &c.ParentID, &c.Position, &c.CreatedAt, &c.UpdatedAt, &c.Version)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		categories = append(categories, c)
+	}
+
+	return categories, nil
+}
+
+// CountCategoryChildren -.
+func (r *ProductRepo) CountCategoryChildren(ctx context.Context, parentID string) (int, error) {
+	total, err := r.count(ctx, "category", squirrel.And{squirrel.Eq{"parent_id": nullIfEmpty(parentID)}, squirrel.Expr(_notDeleted)})
+	if err != nil {
+		return 0, fmt.Errorf("ProductRepo - CountCategoryChildren - r.count: %w", err)
+	}
+
+	return total, nil
+}
+
+// MoveCategory detaches c from its current siblings, closing the gap it
+// leaves, and inserts it under m.ParentID at m.Position, shifting the
+// siblings after it. It must run in a transaction.
+func (r *ProductRepo) MoveCategory(ctx context.Context, c entity.Category, m entity.CategoryMove) error {
+	err := r.shiftSiblings(ctx, c.ParentID, c.Position+1, -1, c.ID)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - MoveCategory - r.shiftSiblings: %w", err)
+	}
+
+	err = r.shiftSiblings(ctx, m.ParentID, m.Position, 1, c.ID)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - MoveCategory - r.shiftSiblings: %w", err)
+	}
+
+	sql, args, err := r.Builder.
+		Update("category").
+		Set("parent_id", nullIfEmpty(m.ParentID)).
+		Set("position", m.Position).
+		Where("id = ?", c.ID).
+		Where(_notDeleted).
+		Where(matchVersion(m.Version)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - MoveCategory - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - MoveCategory - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkVersion(ctx, r.Postgres, "category", c.ID, m.Version, tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - MoveCategory - checkVersion: %w", err)
+	}
+
+	return nil
+}
+
+// shiftSiblings moves the children of parentID from position from onwards
+// by delta, leaving category except in place.
+func (r *ProductRepo) shiftSiblings(ctx context.Context, parentID string, from, delta int, except string) error {
+	sql, args, err := r.Builder.
+		Update("category").
+		Set("position", squirrel.Expr("position + ?", delta)).
+		Where(squirrel.Eq{"parent_id": nullIfEmpty(parentID)}).
+		Where(squirrel.GtOrEq{"position": from}).
+		Where(squirrel.NotEq{"id": except}).
+		Where(_notDeleted).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("r.Querier.Exec: %w", mapError(err))
+	}
+
+	return nil
+}
+
+// ReorderCategories sets the position of every child of parentID to its
+// index in ids.
+func (r *ProductRepo) ReorderCategories(ctx context.Context, parentID string, ids []string) error {
+	sql, args, err := r.Builder.
+		Update("category").
+		Set("position", squirrel.Expr("array_position(?::uuid[], id) - 1", ids)).
+		Where(squirrel.Eq{"parent_id": nullIfEmpty(parentID)}).
+		Where(_notDeleted).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - ReorderCategories - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - ReorderCategories - r.Querier.Exec: %w", mapError(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,271 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+
+	"ai-seller/internal/entity"
+)
+
+const (
+	_exchangeRateColumns = "id, currency, rate::text, source, effective_at, COALESCE(created_by::text, ''), created_at"
+
+	_productPriceColumns = "id, product_id, COALESCE(variant_id::text, ''), currency, cost, COALESCE(discount_cost, 0), created_at, updated_at"
+)
+
+var _exchangeRateSortColumns = []string{"effective_at", "created_at"}
+
+// ---------------- Currency ----------------
+
+// GetCurrencies returns the known currencies, the base one first.
+func (r *ProductRepo) GetCurrencies(ctx context.Context) ([]entity.Currency, error) {
+	sql, args, err := r.Builder.
+		Select("code, name, minor_units, base, created_at").
+		From("currency").
+		OrderBy("base DESC", "code").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetCurrencies - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetCurrencies - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	currencies := make([]entity.Currency, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var c entity.Currency
+
+		err = rows.Scan(&c.Code, &c.Name, &c.MinorUnits, &c.Base, &c.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ProductRepo - GetCurrencies - rows.Scan: %w", err)
+		}
+
+		currencies = append(currencies, c)
+	}
+
+	return currencies, nil
+}
+
+// ---------------- ExchangeRate ----------------
+
+// CreateExchangeRate -.
+func (r *ProductRepo) CreateExchangeRate(ctx context.Context, e entity.ExchangeRate) (entity.ExchangeRate, error) {
+	effectiveAt := interface{}(squirrel.Expr("CURRENT_TIMESTAMP"))
+	if !e.EffectiveAt.IsZero() {
+		effectiveAt = e.EffectiveAt
+	}
+
+	sql, args, err := r.Builder.
+		Insert("exchange_rate").
+		Columns("currency, rate, source, effective_at, created_by").
+		Values(e.Currency, squirrel.Expr("?::text::numeric", e.Rate), e.Source, effectiveAt, nullIfEmpty(e.CreatedBy)).
+		Suffix("RETURNING " + _exchangeRateColumns).
+		ToSql()
+	if err != nil {
+		return entity.ExchangeRate{}, fmt.Errorf("ProductRepo - CreateExchangeRate - r.Builder: %w", err)
+	}
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).
+		Scan(&e.ID, &e.Currency, &e.Rate, &e.Source, &e.EffectiveAt, &e.CreatedBy, &e.CreatedAt)
+	if err != nil {
+		return entity.ExchangeRate{}, fmt.Errorf("ProductRepo - CreateExchangeRate - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return e, nil
+}
+
+// GetExchangeRate returns the rate of a currency in effect right now.
+func (r *ProductRepo) GetExchangeRate(ctx context.Context, currency string) (entity.ExchangeRate, error) {
+	rates, err := r.queryExchangeRates(ctx, r.Builder.
+		Select(_exchangeRateColumns).
+		From("exchange_rate").
+		Where("currency = ?", currency).
+		Where("effective_at <= CURRENT_TIMESTAMP").
+		OrderBy("effective_at DESC", "created_at DESC").
+		Limit(1))
+	if err != nil {
+		return entity.ExchangeRate{}, fmt.Errorf("ProductRepo - GetExchangeRate - %w", err)
+	}
+
+	if len(rates) == 0 {
+		return entity.ExchangeRate{}, fmt.Errorf("ProductRepo - GetExchangeRate: %w", entity.ErrNotFound)
+	}
+
+	return rates[0], nil
+}
+
+// ListExchangeRates returns the rate history, including rates that take
+// effect later.
+func (r *ProductRepo) ListExchangeRates(ctx context.Context, f entity.ExchangeRateFilter) (entity.Page[entity.ExchangeRate], error) {
+	var result entity.Page[entity.ExchangeRate]
+
+	pg, err := newPage(f.ListParams, _exchangeRateSortColumns)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListExchangeRates - newPage: %w", err)
+	}
+
+	where := squirrel.And{}
+	if f.Currency != "" {
+		where = append(where, squirrel.Eq{"currency": f.Currency})
+	}
+
+	result.Total, err = r.count(ctx, "exchange_rate", where)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListExchangeRates - r.count: %w", err)
+	}
+
+	items, err := r.queryExchangeRates(ctx, pg.apply(r.Builder.Select(_exchangeRateColumns).From("exchange_rate").Where(where), ""))
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListExchangeRates - %w", err)
+	}
+
+	result.Items, result.NextCursor, err = trim(pg, items, func(e entity.ExchangeRate, column string) (interface{}, string) {
+		if column == "created_at" {
+			return e.CreatedAt, e.ID
+		}
+
+		return e.EffectiveAt, e.ID
+	})
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListExchangeRates - trim: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *ProductRepo) queryExchangeRates(ctx context.Context, builder squirrel.SelectBuilder) ([]entity.ExchangeRate, error) {
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	rates := make([]entity.ExchangeRate, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var e entity.ExchangeRate
+
+		err = rows.Scan(&e.ID, &e.Currency, &e.Rate, &e.Source, &e.EffectiveAt, &e.CreatedBy, &e.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		rates = append(rates, e)
+	}
+
+	return rates, nil
+}
+
+// ---------------- ProductPrice ----------------
+
+// GetProductPrices returns the prices of a product and its variants.
+func (r *ProductRepo) GetProductPrices(ctx context.Context, productID string) ([]entity.ProductPrice, error) {
+	prices, err := r.queryProductPrices(ctx, squirrel.Eq{"product_id": productID})
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetProductPrices - %w", err)
+	}
+
+	return prices, nil
+}
+
+// GetPricesByProducts returns the prices of the products and their variants
+// in a currency.
+func (r *ProductRepo) GetPricesByProducts(ctx context.Context, productIDs []string, currency string) ([]entity.ProductPrice, error) {
+	prices, err := r.queryProductPrices(ctx, squirrel.Eq{"product_id": productIDs, "currency": currency})
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetPricesByProducts - %w", err)
+	}
+
+	return prices, nil
+}
+
+func (r *ProductRepo) queryProductPrices(ctx context.Context, where squirrel.Sqlizer) ([]entity.ProductPrice, error) {
+	sql, args, err := r.Builder.
+		Select(_productPriceColumns).
+		From("product_price").
+		Where(where).
+		OrderBy("currency", "variant_id NULLS FIRST").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	prices := make([]entity.ProductPrice, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var p entity.ProductPrice
+
+		err = rows.Scan(&p.ID, &p.ProductID, &p.VariantID, &p.Currency, &p.Cost, &p.DiscountCost, &p.CreatedAt, &p.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		prices = append(prices, p)
+	}
+
+	return prices, nil
+}
+
+// SetProductPrice creates or replaces the price of a product, or of one of
+// its variants, in a currency.
+func (r *ProductRepo) SetProductPrice(ctx context.Context, p entity.ProductPrice) (entity.ProductPrice, error) {
+	sql, args, err := r.Builder.
+		Insert("product_price").
+		Columns("product_id, variant_id, currency, cost, discount_cost").
+		Values(p.ProductID, nullIfEmpty(p.VariantID), p.Currency, p.Cost, nullIfZero(p.DiscountCost)).
+		Suffix("ON CONFLICT (product_id, variant_id, currency) DO UPDATE SET cost = EXCLUDED.cost, discount_cost = EXCLUDED.discount_cost " +
+			"RETURNING " + _productPriceColumns).
+		ToSql()
+	if err != nil {
+		return entity.ProductPrice{}, fmt.Errorf("ProductRepo - SetProductPrice - r.Builder: %w", err)
+	}
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).
+		Scan(&p.ID, &p.ProductID, &p.VariantID, &p.Currency, &p.Cost, &p.DiscountCost, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return entity.ProductPrice{}, fmt.Errorf("ProductRepo - SetProductPrice - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return p, nil
+}
+
+// DeleteProductPrice -.
+func (r *ProductRepo) DeleteProductPrice(ctx context.Context, productID, id string) error {
+	sql, args, err := r.Builder.
+		Delete("product_price").
+		Where("id = ?", id).
+		Where("product_id = ?", productID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteProductPrice - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteProductPrice - r.Querier.Exec: %w", mapDeleteError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteProductPrice - checkAffected: %w", err)
+	}
+
+	return nil
+}
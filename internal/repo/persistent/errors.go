@@ -0,0 +1,65 @@
+package persistent
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"ai-seller/internal/entity"
+)
+
+// Postgres error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	_pgUniqueViolation        = "23505"
+	_pgForeignKeyViolation    = "23503"
+	_pgNotNullViolation       = "23502"
+	_pgCheckViolation         = "23514"
+	_pgInvalidTextRepr        = "22P02"
+	_pgStringDataRightTrunc   = "22001"
+	_pgNumericValueOutOfRange = "22003"
+)
+
+// mapError wraps err with the matching domain error, if any.
+func mapError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: %w", entity.ErrNotFound, err)
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case _pgUniqueViolation:
+		return fmt.Errorf("%w: %w", entity.ErrConflict, err)
+	case _pgForeignKeyViolation:
+		return fmt.Errorf("%w: %w", entity.ErrForeignKey, err)
+	case _pgNotNullViolation, _pgCheckViolation, _pgInvalidTextRepr, _pgStringDataRightTrunc, _pgNumericValueOutOfRange:
+		return fmt.Errorf("%w: %w", entity.ErrValidation, err)
+	}
+
+	return err
+}
+
+// mapDeleteError is mapError for DELETE statements, where a foreign key
+// violation means the row is still referenced rather than missing a parent.
+func mapDeleteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == _pgForeignKeyViolation {
+		return fmt.Errorf("%w: %w", entity.ErrInUse, err)
+	}
+
+	return mapError(err)
+}
+
+// checkAffected reports entity.ErrNotFound when a statement touched no rows.
+func checkAffected(tag pgconn.CommandTag) error {
+	if tag.RowsAffected() == 0 {
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"ai-seller/internal/entity"
+)
+
+// _feedVersionQuery summarises everything a feed shows. Any insert, update
+// or delete changes one of the row counts or latest update times.
+const _feedVersionQuery = "SELECT concat_ws('/'," +
+	"(SELECT COUNT(*) || ':' || COALESCE(MAX(updated_at)::text, '') FROM product)," +
+	"(SELECT COUNT(*) || ':' || COALESCE(MAX(updated_at)::text, '') FROM category)," +
+	"(SELECT COUNT(*) || ':' || COALESCE(MAX(updated_at)::text, '') FROM product_media WHERE is_primary)," +
+	"(SELECT COUNT(*) || ':' || COALESCE(MAX(updated_at)::text, '') FROM category_feed_mapping))"
+
+// FeedVersion returns a fingerprint of the data feeds are generated from.
+func (r *ProductRepo) FeedVersion(ctx context.Context) (string, error) {
+	var version string
+
+	err := r.Querier(ctx).QueryRow(ctx, _feedVersionQuery).Scan(&version)
+	if err != nil {
+		return "", fmt.Errorf("ProductRepo - FeedVersion - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return version, nil
+}
+
+// ListFeedProducts returns all products with their primary image.
+func (r *ProductRepo) ListFeedProducts(ctx context.Context) ([]entity.FeedProduct, error) {
+	products := r.Builder.
+		Select("product.*", "m.key AS image_key").
+		From("product").
+		LeftJoin("product_media m ON m.product_id = product.id AND m.is_primary").
+		Where("product.deleted_at IS NULL")
+
+	sql, args, err := r.Builder.
+		Select(_productColumns, "COALESCE(image_key, '')").
+		FromSelect(products, "p").
+		OrderBy("created_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - ListFeedProducts - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - ListFeedProducts - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	items := make([]entity.FeedProduct, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var (
+			item entity.FeedProduct
+			p    = &item.Product
+		)
+
+		err = rows.Scan(&p.ID, &p.Name, &p.SKU, &p.CategoryID, &p.ShortInfo, &p.Description, &p.Cost, &p.Count, &p.Available, &p.DiscountCost, &p.Discount, &p.CreatedAt, &p.UpdatedAt, &p.Version,
+			&item.ImageKey)
+		if err != nil {
+			return nil, fmt.Errorf("ProductRepo - ListFeedProducts - rows.Scan: %w", err)
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// GetFeedMappings returns the feed mappings of all categories.
+func (r *ProductRepo) GetFeedMappings(ctx context.Context) ([]entity.CategoryFeedMapping, error) {
+	sql, args, err := r.Builder.
+		Select("category_id", "google_category", "facebook_category", "updated_at").
+		From("category_feed_mapping").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetFeedMappings - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetFeedMappings - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	mappings := make([]entity.CategoryFeedMapping, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var m entity.CategoryFeedMapping
+
+		err = rows.Scan(&m.CategoryID, &m.GoogleCategory, &m.FacebookCategory, &m.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ProductRepo - GetFeedMappings - rows.Scan: %w", err)
+		}
+
+		mappings = append(mappings, m)
+	}
+
+	return mappings, nil
+}
+
+// GetFeedMapping returns the feed mapping of a category. A category without
+// one gets an empty mapping.
+func (r *ProductRepo) GetFeedMapping(ctx context.Context, categoryID string) (entity.CategoryFeedMapping, error) {
+	m := entity.CategoryFeedMapping{CategoryID: categoryID}
+
+	sql, args, err := r.Builder.
+		Select("google_category", "facebook_category", "updated_at").
+		From("category_feed_mapping").
+		Where("category_id = ?", categoryID).
+		ToSql()
+	if err != nil {
+		return m, fmt.Errorf("ProductRepo - GetFeedMapping - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return m, fmt.Errorf("ProductRepo - GetFeedMapping - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		err = rows.Scan(&m.GoogleCategory, &m.FacebookCategory, &m.UpdatedAt)
+		if err != nil {
+			return m, fmt.Errorf("ProductRepo - GetFeedMapping - rows.Scan: %w", err)
+		}
+	}
+
+	return m, nil
+}
+
+// SetFeedMapping stores the feed mapping of a category.
+func (r *ProductRepo) SetFeedMapping(ctx context.Context, m entity.CategoryFeedMapping) error {
+	sql, args, err := r.Builder.
+		Insert("category_feed_mapping").
+		Columns("category_id", "google_category", "facebook_category").
+		Values(m.CategoryID, m.GoogleCategory, m.FacebookCategory).
+		Suffix("ON CONFLICT (category_id) DO UPDATE SET google_category = EXCLUDED.google_category, facebook_category = EXCLUDED.facebook_category").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - SetFeedMapping - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - SetFeedMapping - r.Querier.Exec: %w", mapError(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+
+	"ai-seller/internal/entity"
+)
+
+const _importJobColumns = "id, status, format, file_name, total_rows, processed_rows, created_rows, updated_rows, failed_rows, errors, error, COALESCE(created_by::text, ''), created_at, updated_at, finished_at"
+
+// CreateImportJob -.
+func (r *ProductRepo) CreateImportJob(ctx context.Context, j entity.ImportJob) (entity.ImportJob, error) {
+	sql, args, err := r.Builder.
+		Insert("import_job").
+		Columns("status, format, file_name, total_rows, created_by").
+		Values(j.Status, j.Format, j.FileName, j.TotalRows, nullIfEmpty(j.CreatedBy)).
+		Suffix("RETURNING id, created_at, updated_at").
+		ToSql()
+	if err != nil {
+		return entity.ImportJob{}, fmt.Errorf("ProductRepo - CreateImportJob - r.Builder: %w", err)
+	}
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&j.ID, &j.CreatedAt, &j.UpdatedAt)
+	if err != nil {
+		return entity.ImportJob{}, fmt.Errorf("ProductRepo - CreateImportJob - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return j, nil
+}
+
+// GetImportJob -.
+func (r *ProductRepo) GetImportJob(ctx context.Context, id string) (entity.ImportJob, error) {
+	var j entity.ImportJob
+
+	sql, args, err := r.Builder.
+		Select(_importJobColumns).
+		From("import_job").
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return j, fmt.Errorf("ProductRepo - GetImportJob - r.Builder: %w", err)
+	}
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&j.ID, &j.Status, &j.Format, &j.FileName, &j.TotalRows, &j.ProcessedRows,
+		&j.CreatedRows, &j.UpdatedRows, &j.FailedRows, &j.Errors, &j.Error, &j.CreatedBy, &j.CreatedAt, &j.UpdatedAt, &j.FinishedAt)
+	if err != nil {
+		return j, fmt.Errorf("ProductRepo - GetImportJob - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return j, nil
+}
+
+// UpdateImportJob stores the progress of a job.
+func (r *ProductRepo) UpdateImportJob(ctx context.Context, j entity.ImportJob) error {
+	sql, args, err := r.Builder.
+		Update("import_job").
+		Set("status", j.Status).
+		Set("processed_rows", j.ProcessedRows).
+		Set("created_rows", j.CreatedRows).
+		Set("updated_rows", j.UpdatedRows).
+		Set("failed_rows", j.FailedRows).
+		Set("errors", j.Errors).
+		Set("error", j.Error).
+		Set("finished_at", j.FinishedAt).
+		Where("id = ?", j.ID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdateImportJob - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdateImportJob - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdateImportJob - checkAffected: %w", err)
+	}
+
+	return nil
+}
+
+// FailImportJobs marks the jobs in one of statuses failed with message and
+// returns how many there were.
+func (r *ProductRepo) FailImportJobs(ctx context.Context, statuses []string, message string) (int, error) {
+	sql, args, err := r.Builder.
+		Update("import_job").
+		Set("status", entity.ImportStatusFailed).
+		Set("error", message).
+		Set("finished_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"status": statuses}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("ProductRepo - FailImportJobs - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("ProductRepo - FailImportJobs - r.Querier.Exec: %w", mapError(err))
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// FindProducts returns the products with the given SKU, or with the given
+// name when sku is empty.
+func (r *ProductRepo) FindProducts(ctx context.Context, sku, name string) ([]entity.Product, error) {
+	where := squirrel.Eq{"sku": sku}
+	if sku == "" {
+		where = squirrel.Eq{"name": name}
+	}
+
+	sql, args, err := r.Builder.
+		Select(_productColumns).
+		From("product").
+		Where(where).
+		Where(_notDeleted).
+		OrderBy("created_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - FindProducts - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - FindProducts - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	products := make([]entity.Product, 0, 1)
+
+	for rows.Next() {
+		var p entity.Product
+
+		err = rows.Scan(&p.ID, &p.Name, &p.SKU, &p.CategoryID, &p.ShortInfo, &p.Description, &p.Cost, &p.Count, &p.Available, &p.DiscountCost, &p.Discount, &p.CreatedAt, &p.UpdatedAt, &p.Version)
+		if err != nil {
+			return nil, fmt.Errorf("ProductRepo - FindProducts - rows.Scan: %w", err)
+		}
+
+		products = append(products, p)
+	}
+
+	return products, nil
+}
+
+// FindVariant returns the variant with the given SKU, unless its product is
+// in the trash.
+func (r *ProductRepo) FindVariant(ctx context.Context, sku string) (entity.ProductVariant, error) {
+	variants, err := r.queryVariants(ctx, squirrel.And{
+		squirrel.Eq{"sku": sku},
+		squirrel.Expr("product_id IN (SELECT id FROM product WHERE " + _notDeleted + ")"),
+	}, false)
+	if err != nil {
+		return entity.ProductVariant{}, fmt.Errorf("ProductRepo - FindVariant - %w", err)
+	}
+
+	if len(variants) == 0 {
+		return entity.ProductVariant{}, fmt.Errorf("ProductRepo - FindVariant: %w", entity.ErrNotFound)
+	}
+
+	return variants[0], nil
+}
+
+// FindCategory returns the child of parentID, or the root category when it
+// is empty, with the given name.
+func (r *ProductRepo) FindCategory(ctx context.Context, parentID, name string) (entity.Category, error) {
+	sql, args, err := r.Builder.
+		Select(_categoryColumns).
+		From("category").
+		Where(squirrel.Eq{"parent_id": nullIfEmpty(parentID), "name": name}).
+		Where(_notDeleted).
+		ToSql()
+	if err != nil {
+		return entity.Category{}, fmt.Errorf("ProductRepo - FindCategory - r.Builder: %w", err)
+	}
+
+	categories, err := r.queryCategories(ctx, sql, args)
+	if err != nil {
+		return entity.Category{}, fmt.Errorf("ProductRepo - FindCategory - %w", err)
+	}
+
+	if len(categories) == 0 {
+		return entity.Category{}, fmt.Errorf("ProductRepo - FindCategory: %w", entity.ErrNotFound)
+	}
+
+	return categories[0], nil
+}
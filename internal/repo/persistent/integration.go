@@ -23,17 +23,17 @@ func NewIntegrationRepo(pg *postgres.Postgres) *IntegrationRepo {
 func (r *IntegrationRepo) CreateIntegration(ctx context.Context, i entity.Integration) error {
 	sql, args, err := r.Builder.
 		Insert("integration").
-		Columns("name, created_at, updated_at").
-		Values(i.Name, i.CreatedAt, i.UpdatedAt).
+		Columns("name").
+		Values(i.Name).
 		Suffix("RETURNING id").
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("IntegrationRepo - CreateIntegration - r.Builder: %w", err)
 	}
 
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&i.ID)
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&i.ID)
 	if err != nil {
-		return fmt.Errorf("IntegrationRepo - CreateIntegration - r.Pool.QueryRow: %w", err)
+		return fmt.Errorf("IntegrationRepo - CreateIntegration - r.Querier.QueryRow: %w", mapError(err))
 	}
 
 	return nil
@@ -52,9 +52,9 @@ func (r *IntegrationRepo) GetIntegration(ctx context.Context, id string) (entity
 		return i, fmt.Errorf("IntegrationRepo - GetIntegration - r.Builder: %w", err)
 	}
 
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&i.ID, &i.Name, &i.CreatedAt, &i.UpdatedAt)
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&i.ID, &i.Name, &i.CreatedAt, &i.UpdatedAt)
 	if err != nil {
-		return i, fmt.Errorf("IntegrationRepo - GetIntegration - r.Pool.QueryRow: %w", err)
+		return i, fmt.Errorf("IntegrationRepo - GetIntegration - r.Querier.QueryRow: %w", mapError(err))
 	}
 
 	return i, nil
@@ -65,17 +65,20 @@ func (r *IntegrationRepo) UpdateIntegration(ctx context.Context, i entity.Integr
 	sql, args, err := r.Builder.
 		Update("integration").
 		Set("name", i.Name).
-		Set("created_at", i.CreatedAt).
-		Set("updated_at", i.UpdatedAt).
 		Where("id = ?", i.ID).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("IntegrationRepo - UpdateIntegration - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
 	if err != nil {
-		return fmt.Errorf("IntegrationRepo - UpdateIntegration - r.Pool.Exec: %w", err)
+		return fmt.Errorf("IntegrationRepo - UpdateIntegration - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("IntegrationRepo - UpdateIntegration - checkAffected: %w", err)
 	}
 
 	return nil
@@ -91,9 +94,14 @@ func (r *IntegrationRepo) DeleteIntegration(ctx context.Context, id string) erro
 		return fmt.Errorf("IntegrationRepo - DeleteIntegration - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("IntegrationRepo - DeleteIntegration - r.Querier.Exec: %w", mapDeleteError(err))
+	}
+
+	err = checkAffected(tag)
 	if err != nil {
-		return fmt.Errorf("IntegrationRepo - DeleteIntegration - r.Pool.Exec: %w", err)
+		return fmt.Errorf("IntegrationRepo - DeleteIntegration - checkAffected: %w", err)
 	}
 
 	return nil
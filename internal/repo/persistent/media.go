@@ -0,0 +1,290 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+
+	"ai-seller/internal/entity"
+)
+
+const _mediaColumns = "id, product_id, key, content_type, size, width, height, position, is_primary, created_at"
+
+// CreateMedia appends an image and its thumbnails to the images of a product.
+// The first image of a product becomes its primary one. It must run in a
+// transaction.
+func (r *ProductRepo) CreateMedia(ctx context.Context, m entity.ProductMedia) (entity.ProductMedia, error) {
+	sql, args, err := r.Builder.
+		Insert("product_media").
+		Columns("product_id, key, content_type, size, width, height, position, is_primary").
+		Values(m.ProductID, m.Key, m.ContentType, m.Size, m.Width, m.Height,
+			squirrel.Expr("(SELECT COUNT(*) FROM product_media WHERE product_id = ?)", m.ProductID),
+			squirrel.Expr("NOT EXISTS (SELECT 1 FROM product_media WHERE product_id = ? AND is_primary)", m.ProductID)).
+		Suffix("RETURNING id, position, is_primary, created_at").
+		ToSql()
+	if err != nil {
+		return entity.ProductMedia{}, fmt.Errorf("ProductRepo - CreateMedia - r.Builder: %w", err)
+	}
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&m.ID, &m.Position, &m.Primary, &m.CreatedAt)
+	if err != nil {
+		return entity.ProductMedia{}, fmt.Errorf("ProductRepo - CreateMedia - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	if len(m.Thumbnails) == 0 {
+		return m, nil
+	}
+
+	insert := r.Builder.
+		Insert("product_media_thumbnail").
+		Columns("media_id", "size", "key", "width", "height")
+
+	for _, t := range m.Thumbnails {
+		insert = insert.Values(m.ID, t.Size, t.Key, t.Width, t.Height)
+	}
+
+	sql, args, err = insert.ToSql()
+	if err != nil {
+		return entity.ProductMedia{}, fmt.Errorf("ProductRepo - CreateMedia - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return entity.ProductMedia{}, fmt.Errorf("ProductRepo - CreateMedia - r.Querier.Exec: %w", mapError(err))
+	}
+
+	return m, nil
+}
+
+// GetMedia returns an image of a product with its thumbnails.
+func (r *ProductRepo) GetMedia(ctx context.Context, productID, id string) (entity.ProductMedia, error) {
+	media, err := r.queryMedia(ctx, squirrel.Eq{"id": id, "product_id": productID})
+	if err != nil {
+		return entity.ProductMedia{}, fmt.Errorf("ProductRepo - GetMedia - %w", err)
+	}
+
+	if len(media) == 0 {
+		return entity.ProductMedia{}, fmt.Errorf("ProductRepo - GetMedia: %w", entity.ErrNotFound)
+	}
+
+	return media[0], nil
+}
+
+// GetMediaByProduct returns the images of a product by position.
+func (r *ProductRepo) GetMediaByProduct(ctx context.Context, productID string) ([]entity.ProductMedia, error) {
+	media, err := r.queryMedia(ctx, squirrel.Eq{"product_id": productID})
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetMediaByProduct - %w", err)
+	}
+
+	return media, nil
+}
+
+func (r *ProductRepo) queryMedia(ctx context.Context, where squirrel.Sqlizer) ([]entity.ProductMedia, error) {
+	sql, args, err := r.Builder.
+		Select(_mediaColumns).
+		From("product_media").
+		Where(where).
+		OrderBy("position", "created_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	media := make([]entity.ProductMedia, 0, _defaultEntityCap)
+	ids := make([]string, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var m entity.ProductMedia
+
+		err = rows.Scan(&m.ID, &m.ProductID, &m.Key, &m.ContentType, &m.Size, &m.Width, &m.Height, &m.Position, &m.Primary, &m.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		m.Thumbnails = []entity.MediaThumbnail{}
+		media = append(media, m)
+		ids = append(ids, m.ID)
+	}
+
+	rows.Close()
+
+	if len(ids) == 0 {
+		return media, nil
+	}
+
+	thumbnails, err := r.mediaThumbnails(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range media {
+		media[i].Thumbnails = append(media[i].Thumbnails, thumbnails[media[i].ID]...)
+	}
+
+	return media, nil
+}
+
+// mediaThumbnails returns the thumbnails of the images keyed by image id.
+func (r *ProductRepo) mediaThumbnails(ctx context.Context, mediaIDs []string) (map[string][]entity.MediaThumbnail, error) {
+	sql, args, err := r.Builder.
+		Select("media_id", "size", "key", "width", "height").
+		From("product_media_thumbnail").
+		Where(squirrel.Eq{"media_id": mediaIDs}).
+		OrderBy("size").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	thumbnails := make(map[string][]entity.MediaThumbnail, len(mediaIDs))
+
+	for rows.Next() {
+		var (
+			mediaID string
+			t       entity.MediaThumbnail
+		)
+
+		err = rows.Scan(&mediaID, &t.Size, &t.Key, &t.Width, &t.Height)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		thumbnails[mediaID] = append(thumbnails[mediaID], t)
+	}
+
+	return thumbnails, nil
+}
+
+// SetPrimaryMedia makes an image the primary one of its product. It must run
+// in a transaction.
+func (r *ProductRepo) SetPrimaryMedia(ctx context.Context, productID, id string) error {
+	sql, args, err := r.Builder.
+		Update("product_media").
+		Set("is_primary", false).
+		Where("product_id = ?", productID).
+		Where("is_primary").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - SetPrimaryMedia - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - SetPrimaryMedia - r.Querier.Exec: %w", mapError(err))
+	}
+
+	sql, args, err = r.Builder.
+		Update("product_media").
+		Set("is_primary", true).
+		Where("id = ?", id).
+		Where("product_id = ?", productID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - SetPrimaryMedia - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - SetPrimaryMedia - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - SetPrimaryMedia - checkAffected: %w", err)
+	}
+
+	return nil
+}
+
+// ReorderMedia sets the position of every image of a product to its index
+// in ids.
+func (r *ProductRepo) ReorderMedia(ctx context.Context, productID string, ids []string) error {
+	sql, args, err := r.Builder.
+		Update("product_media").
+		Set("position", squirrel.Expr("array_position(?::uuid[], id) - 1", ids)).
+		Where("product_id = ?", productID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - ReorderMedia - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - ReorderMedia - r.Querier.Exec: %w", mapError(err))
+	}
+
+	return nil
+}
+
+// DeleteMedia removes an image, closing the gap it leaves in the order. When
+// it was the primary image the first remaining one takes over. It must run
+// in a transaction.
+func (r *ProductRepo) DeleteMedia(ctx context.Context, m entity.ProductMedia) error {
+	sql, args, err := r.Builder.
+		Delete("product_media").
+		Where("id = ?", m.ID).
+		Where("product_id = ?", m.ProductID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteMedia - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteMedia - r.Querier.Exec: %w", mapDeleteError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteMedia - checkAffected: %w", err)
+	}
+
+	sql, args, err = r.Builder.
+		Update("product_media").
+		Set("position", squirrel.Expr("position - 1")).
+		Where("product_id = ?", m.ProductID).
+		Where(squirrel.Gt{"position": m.Position}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteMedia - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteMedia - r.Querier.Exec: %w", mapError(err))
+	}
+
+	if !m.Primary {
+		return nil
+	}
+
+	sql, args, err = r.Builder.
+		Update("product_media").
+		Set("is_primary", true).
+		Where("id = (SELECT id FROM product_media WHERE product_id = ? ORDER BY position, created_at LIMIT 1)", m.ProductID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteMedia - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteMedia - r.Querier.Exec: %w", mapError(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,289 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+
+	"ai-seller/internal/entity"
+)
+
+const (
+	_lowStockThresholdColumns = "product_id, threshold, alerted_at, created_at, updated_at"
+	_stockSubscriptionColumns = "id, user_id, product_id, chat_id, created_at"
+)
+
+// ---------------- LowStockThreshold ----------------
+
+// GetLowStockThreshold -.
+func (r *ProductRepo) GetLowStockThreshold(ctx context.Context, productID string) (entity.LowStockThreshold, error) {
+	sql, args, err := r.Builder.
+		Select(_lowStockThresholdColumns).
+		From("low_stock_threshold").
+		Where("product_id = ?", productID).
+		ToSql()
+	if err != nil {
+		return entity.LowStockThreshold{}, fmt.Errorf("ProductRepo - GetLowStockThreshold - r.Builder: %w", err)
+	}
+
+	var t entity.LowStockThreshold
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&t.ProductID, &t.Threshold, &t.AlertedAt, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return entity.LowStockThreshold{}, fmt.Errorf("ProductRepo - GetLowStockThreshold - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return t, nil
+}
+
+// SetLowStockThreshold creates or replaces the threshold of a product. A
+// changed threshold is evaluated afresh, so an outstanding alert is cleared.
+func (r *ProductRepo) SetLowStockThreshold(ctx context.Context, t entity.LowStockThreshold) (entity.LowStockThreshold, error) {
+	sql, args, err := r.Builder.
+		Insert("low_stock_threshold").
+		Columns("product_id, threshold").
+		Values(t.ProductID, t.Threshold).
+		Suffix("ON CONFLICT (product_id) DO UPDATE SET threshold = EXCLUDED.threshold, alerted_at = NULL " +
+			"RETURNING " + _lowStockThresholdColumns).
+		ToSql()
+	if err != nil {
+		return entity.LowStockThreshold{}, fmt.Errorf("ProductRepo - SetLowStockThreshold - r.Builder: %w", err)
+	}
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&t.ProductID, &t.Threshold, &t.AlertedAt, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return entity.LowStockThreshold{}, fmt.Errorf("ProductRepo - SetLowStockThreshold - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return t, nil
+}
+
+// DeleteLowStockThreshold -.
+func (r *ProductRepo) DeleteLowStockThreshold(ctx context.Context, productID string) error {
+	sql, args, err := r.Builder.
+		Delete("low_stock_threshold").
+		Where("product_id = ?", productID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteLowStockThreshold - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteLowStockThreshold - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteLowStockThreshold - checkAffected: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimLowStockAlert marks the threshold of a product alerted when its
+// count dropped below it and no alert is outstanding, and returns it.
+// Concurrent callers cannot both claim the alert. Nothing to alert is
+// ErrNotFound.
+func (r *ProductRepo) ClaimLowStockAlert(ctx context.Context, productID string) (entity.LowStockThreshold, error) {
+	sql, args, err := r.Builder.
+		Update("low_stock_threshold t").
+		Set("alerted_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		From("product p").
+		Where("p.id = t.product_id").
+		Where("t.product_id = ?", productID).
+		Where("t.alerted_at IS NULL").
+		Where("p.count < t.threshold").
+		Where("p.deleted_at IS NULL").
+		Suffix("RETURNING t.product_id, t.threshold, t.alerted_at, t.created_at, t.updated_at").
+		ToSql()
+	if err != nil {
+		return entity.LowStockThreshold{}, fmt.Errorf("ProductRepo - ClaimLowStockAlert - r.Builder: %w", err)
+	}
+
+	var t entity.LowStockThreshold
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&t.ProductID, &t.Threshold, &t.AlertedAt, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return entity.LowStockThreshold{}, fmt.Errorf("ProductRepo - ClaimLowStockAlert - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return t, nil
+}
+
+// ResetLowStockAlert clears the alert of a product whose count is back at or
+// above its threshold, so that the next drop alerts again.
+func (r *ProductRepo) ResetLowStockAlert(ctx context.Context, productID string) error {
+	sql, args, err := r.Builder.
+		Update("low_stock_threshold t").
+		Set("alerted_at", nil).
+		From("product p").
+		Where("p.id = t.product_id").
+		Where("t.product_id = ?", productID).
+		Where("t.alerted_at IS NOT NULL").
+		Where("p.count >= t.threshold").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - ResetLowStockAlert - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - ResetLowStockAlert - r.Querier.Exec: %w", mapError(err))
+	}
+
+	return nil
+}
+
+// ReleaseLowStockAlert clears a claimed alert that could not be sent, so
+// that it is claimed again.
+func (r *ProductRepo) ReleaseLowStockAlert(ctx context.Context, productID string) error {
+	sql, args, err := r.Builder.
+		Update("low_stock_threshold").
+		Set("alerted_at", nil).
+		Where("product_id = ?", productID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - ReleaseLowStockAlert - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - ReleaseLowStockAlert - r.Querier.Exec: %w", mapError(err))
+	}
+
+	return nil
+}
+
+// ---------------- StockSubscription ----------------
+
+// CreateStockSubscription subscribes a user to a product, replacing the chat
+// of an existing subscription.
+func (r *ProductRepo) CreateStockSubscription(ctx context.Context, s entity.StockSubscription) (entity.StockSubscription, error) {
+	sql, args, err := r.Builder.
+		Insert("stock_subscription").
+		Columns("user_id, product_id, chat_id").
+		Values(s.UserID, s.ProductID, s.ChatID).
+		Suffix("ON CONFLICT (user_id, product_id) DO UPDATE SET chat_id = EXCLUDED.chat_id " +
+			"RETURNING " + _stockSubscriptionColumns).
+		ToSql()
+	if err != nil {
+		return entity.StockSubscription{}, fmt.Errorf("ProductRepo - CreateStockSubscription - r.Builder: %w", err)
+	}
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&s.ID, &s.UserID, &s.ProductID, &s.ChatID, &s.CreatedAt)
+	if err != nil {
+		return entity.StockSubscription{}, fmt.Errorf("ProductRepo - CreateStockSubscription - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return s, nil
+}
+
+// GetStockSubscriptions returns the subscriptions to a product, oldest first.
+func (r *ProductRepo) GetStockSubscriptions(ctx context.Context, productID string) ([]entity.StockSubscription, error) {
+	sql, args, err := r.Builder.
+		Select(_stockSubscriptionColumns).
+		From("stock_subscription").
+		Where("product_id = ?", productID).
+		OrderBy("created_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetStockSubscriptions - r.Builder: %w", err)
+	}
+
+	subscriptions, err := r.queryStockSubscriptions(ctx, sql, args)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetStockSubscriptions - %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// DeleteStockSubscription -.
+func (r *ProductRepo) DeleteStockSubscription(ctx context.Context, userID, productID string) error {
+	sql, args, err := r.Builder.
+		Delete("stock_subscription").
+		Where("user_id = ?", userID).
+		Where("product_id = ?", productID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteStockSubscription - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteStockSubscription - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteStockSubscription - checkAffected: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimStockSubscriptions removes and returns the subscriptions to a product
+// that is available again. Concurrent callers cannot both claim one.
+func (r *ProductRepo) ClaimStockSubscriptions(ctx context.Context, productID string) ([]entity.StockSubscription, error) {
+	sql, args, err := r.Builder.
+		Delete("stock_subscription s").
+		Suffix("USING product p WHERE p.id = s.product_id AND s.product_id = ? AND p.count > p.reserved AND p.deleted_at IS NULL "+
+			"RETURNING s.id, s.user_id, s.product_id, s.chat_id, s.created_at", productID).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - ClaimStockSubscriptions - r.Builder: %w", err)
+	}
+
+	subscriptions, err := r.queryStockSubscriptions(ctx, sql, args)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - ClaimStockSubscriptions - %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// ReleaseStockSubscription puts back a claimed subscription whose message
+// could not be sent. A subscription made again in the meantime is kept.
+func (r *ProductRepo) ReleaseStockSubscription(ctx context.Context, s entity.StockSubscription) error {
+	sql, args, err := r.Builder.
+		Insert("stock_subscription").
+		Columns("id, user_id, product_id, chat_id, created_at").
+		Values(s.ID, s.UserID, s.ProductID, s.ChatID, s.CreatedAt).
+		Suffix("ON CONFLICT DO NOTHING").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - ReleaseStockSubscription - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - ReleaseStockSubscription - r.Querier.Exec: %w", mapError(err))
+	}
+
+	return nil
+}
+
+func (r *ProductRepo) queryStockSubscriptions(ctx context.Context, sql string, args []interface{}) ([]entity.StockSubscription, error) {
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	subscriptions := make([]entity.StockSubscription, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var s entity.StockSubscription
+
+		err = rows.Scan(&s.ID, &s.UserID, &s.ProductID, &s.ChatID, &s.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		subscriptions = append(subscriptions, s)
+	}
+
+	return subscriptions, nil
+}
@@ -0,0 +1,119 @@
+package persistent
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+
+	"ai-seller/internal/entity"
+)
+
+const (
+	_defaultPageLimit = 20
+	_maxPageLimit     = 100
+)
+
+// cursor points at the last row of a page: its sort value and id.
+type cursor struct {
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// page is a validated keyset pagination request for a single list query.
+type page struct {
+	column string
+	desc   bool
+	limit  int
+	after  *cursor
+}
+
+// newPage validates p against the sortable columns of a table. The first
+// entry of columns is used when no sort is requested.
+func newPage(p entity.ListParams, columns []string) (page, error) {
+	pg := page{
+		column: columns[0],
+		desc:   true,
+		limit:  p.Limit,
+	}
+
+	if pg.limit <= 0 {
+		pg.limit = _defaultPageLimit
+	}
+
+	if pg.limit > _maxPageLimit {
+		pg.limit = _maxPageLimit
+	}
+
+	if p.Sort != "" {
+		pg.desc = strings.HasPrefix(p.Sort, "-")
+		pg.column = strings.TrimPrefix(p.Sort, "-")
+
+		if !slices.Contains(columns, pg.column) {
+			return page{}, entity.NewValidationError("sort", "unsupported sort column "+pg.column)
+		}
+	}
+
+	if p.Cursor != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(p.Cursor)
+		if err != nil {
+			return page{}, entity.NewValidationError("cursor", "malformed cursor")
+		}
+
+		pg.after = &cursor{}
+
+		err = json.Unmarshal(raw, pg.after)
+		if err != nil {
+			return page{}, entity.NewValidationError("cursor", "malformed cursor")
+		}
+	}
+
+	return pg, nil
+}
+
+// apply adds the keyset condition, ordering and limit to b. One extra row is
+// requested to learn whether a next page exists.
+func (pg page) apply(b squirrel.SelectBuilder, prefix string) squirrel.SelectBuilder {
+	column, id := prefix+pg.column, prefix+"id"
+
+	op, dir := ">", "ASC"
+	if pg.desc {
+		op, dir = "<", "DESC"
+	}
+
+	if pg.after != nil {
+		b = b.Where(fmt.Sprintf("(%s, %s) %s (?, ?)", column, id, op), pg.after.Value, pg.after.ID)
+	}
+
+	return b.OrderBy(column+" "+dir, id+" "+dir).Limit(uint64(pg.limit) + 1) //nolint:gosec // limit is bounded by _maxPageLimit
+}
+
+// trim drops the extra row fetched by apply and builds the cursor of the next
+// page from the last remaining item.
+func trim[T any](pg page, items []T, key func(T, string) (interface{}, string)) ([]T, string, error) {
+	if len(items) <= pg.limit {
+		return items, "", nil
+	}
+
+	items = items[:pg.limit]
+	value, id := key(items[len(items)-1], pg.column)
+
+	raw, err := json.Marshal(cursor{Value: cursorValue(value), ID: id})
+	if err != nil {
+		return nil, "", fmt.Errorf("trim - json.Marshal: %w", err)
+	}
+
+	return items, base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func cursorValue(v interface{}) string {
+	if t, ok := v.(time.Time); ok {
+		return t.Format(time.RFC3339Nano)
+	}
+
+	return fmt.Sprint(v)
+}
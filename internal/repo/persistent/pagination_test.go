@@ -0,0 +1,131 @@
+package persistent
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/internal/entity"
+)
+
+func TestNewPage(t *testing.T) {
+	t.Parallel()
+
+	columns := []string{"created_at", "name"}
+	token := base64.RawURLEncoding.EncodeToString([]byte(`{"v":"Shirt","id":"p1"}`))
+
+	tests := []struct {
+		name   string
+		params entity.ListParams
+		want   page
+		field  string
+	}{
+		{name: "defaults", want: page{column: "created_at", desc: true, limit: _defaultPageLimit}},
+		{name: "limit", params: entity.ListParams{Limit: 5}, want: page{column: "created_at", desc: true, limit: 5}},
+		{name: "limit capped", params: entity.ListParams{Limit: 1000}, want: page{column: "created_at", desc: true, limit: _maxPageLimit}},
+		{name: "negative limit", params: entity.ListParams{Limit: -1}, want: page{column: "created_at", desc: true, limit: _defaultPageLimit}},
+		{name: "ascending", params: entity.ListParams{Sort: "name"}, want: page{column: "name", limit: _defaultPageLimit}},
+		{name: "descending", params: entity.ListParams{Sort: "-name"}, want: page{column: "name", desc: true, limit: _defaultPageLimit}},
+		{name: "unsupported sort", params: entity.ListParams{Sort: "cost"}, field: "sort"},
+		{name: "sort by a column name injection", params: entity.ListParams{Sort: "name; DROP TABLE product"}, field: "sort"},
+		{
+			name:   "cursor",
+			params: entity.ListParams{Sort: "name", Cursor: token},
+			want:   page{column: "name", limit: _defaultPageLimit, after: &cursor{Value: "Shirt", ID: "p1"}},
+		},
+		{name: "cursor not base64", params: entity.ListParams{Cursor: "%%%"}, field: "cursor"},
+		{name: "cursor padded", params: entity.ListParams{Cursor: token + "=="}, field: "cursor"},
+		{name: "cursor not json", params: entity.ListParams{Cursor: base64.RawURLEncoding.EncodeToString([]byte("v=1"))}, field: "cursor"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := newPage(tc.params, columns)
+			if tc.field != "" {
+				var v *entity.ValidationError
+				require.ErrorAs(t, err, &v)
+				require.Equal(t, tc.field, v.Field)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestTrim(t *testing.T) {
+	t.Parallel()
+
+	type row struct {
+		id        string
+		createdAt time.Time
+	}
+
+	key := func(r row, _ string) (interface{}, string) { return r.createdAt, r.id }
+	at := time.Date(2026, 10, 18, 7, 0, 0, 123456789, time.UTC)
+	rows := []row{{"r1", at}, {"r2", at.Add(-time.Second)}, {"r3", at.Add(-2 * time.Second)}}
+
+	t.Run("last page", func(t *testing.T) {
+		t.Parallel()
+
+		items, next, err := trim(page{column: "created_at", limit: 3}, rows, key)
+		require.NoError(t, err)
+		require.Equal(t, rows, items)
+		require.Empty(t, next)
+	})
+
+	t.Run("next page round trip", func(t *testing.T) {
+		t.Parallel()
+
+		items, next, err := trim(page{column: "created_at", desc: true, limit: 2}, rows, key)
+		require.NoError(t, err)
+		require.Equal(t, rows[:2], items)
+
+		pg, err := newPage(entity.ListParams{Limit: 2, Cursor: next}, []string{"created_at"})
+		require.NoError(t, err)
+		require.Equal(t, &cursor{Value: "2026-10-18T06:59:59.123456789Z", ID: "r2"}, pg.after)
+	})
+}
+
+func TestPageApply(t *testing.T) {
+	t.Parallel()
+
+	builder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).Select("id").From("product p")
+
+	tests := []struct {
+		name string
+		page page
+		sql  string
+		args []interface{}
+	}{
+		{
+			name: "first page",
+			page: page{column: "name", limit: 10},
+			sql:  "SELECT id FROM product p ORDER BY p.name ASC, p.id ASC LIMIT 11",
+		},
+		{
+			name: "after a cursor descending",
+			page: page{column: "name", desc: true, limit: 10, after: &cursor{Value: "Shirt", ID: "p1"}},
+			sql:  "SELECT id FROM product p WHERE (p.name, p.id) < ($1, $2) ORDER BY p.name DESC, p.id DESC LIMIT 11",
+			args: []interface{}{"Shirt", "p1"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			sql, args, err := tc.page.apply(builder, "p.").ToSql()
+			require.NoError(t, err)
+			require.Equal(t, tc.sql, sql)
+			require.Equal(t, tc.args, args)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"ai-seller/internal/entity"
+	"ai-seller/pkg/postgres"
+)
+
+// patchSet collects the SET clauses of a partial update, keyed by column.
+type patchSet map[string]interface{}
+
+// setField adds column to the update when the patch supplied it; an explicit
+// null writes NULL.
+func setField[T any](s patchSet, column string, f entity.Optional[T]) {
+	if !f.Set {
+		return
+	}
+
+	if f.Null {
+		s[column] = nil
+		return
+	}
+
+	s[column] = f.Value
+}
+
+// execPatch updates the supplied columns of one row, guarded by version. An
+// empty patch is a no-op; callers re-read the row, which reports a missing one.
+func execPatch(ctx context.Context, pg *postgres.Postgres, table, id string, version int, s patchSet) error {
+	if len(s) == 0 {
+		return nil
+	}
+
+	sql, args, err := pg.Builder.
+		Update(table).
+		SetMap(s).
+		Where("id = ?", id).
+		Where(live(table)).
+		Where(matchVersion(version)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("r.Builder: %w", err)
+	}
+
+	tag, err := pg.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkVersion(ctx, pg, table, id, version, tag)
+	if err != nil {
+		return fmt.Errorf("checkVersion: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package persistent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/internal/entity"
+)
+
+func TestSetField(t *testing.T) {
+	t.Parallel()
+
+	s := patchSet{}
+
+	setField(s, "name", entity.Optional[string]{})
+	setField(s, "phone", entity.Optional[string]{Set: true, Null: true})
+	setField(s, "surname", entity.Some(""))
+	setField(s, "cost", entity.Some(0))
+
+	require.Equal(t, patchSet{"phone": nil, "surname": "", "cost": 0}, s)
+}
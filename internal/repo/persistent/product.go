@@ -4,7 +4,28 @@ import (
 	"ai-seller/internal/entity"
 	"ai-seller/pkg/postgres"
 	"context"
+	"errors"
 	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	_productColumns   = "id, name, COALESCE(sku, ''), COALESCE(category_id::text, ''), COALESCE(short_info, ''), COALESCE(description, ''), cost, count, GREATEST(count - reserved, 0), COALESCE(discount_cost, 0), COALESCE(discount, 0), created_at, updated_at, version"
+	_categoryColumns  = "id, name, COALESCE(parent_id::text, ''), position, created_at, updated_at, version"
+	_attributeColumns = "id, name, COALESCE(category_id::text, ''), type, COALESCE(unit, ''), options, required, created_at, updated_at"
+	_orderColumns     = "id, COALESCE(user_id::text, ''), COALESCE(integration_id::text, ''), COALESCE(status, ''), status_changed_time, subtotal_cost, discount_cost, total_cost, currency, exchange_rate::text, created_at, updated_at, version"
+)
+
+// Sortable columns per table; the first one is the default sort.
+var (
+	_productSortColumns   = []string{"created_at", "updated_at", "name", "cost", "count"}
+	_categorySortColumns  = []string{"created_at", "updated_at", "name", "position"}
+	_attributeSortColumns = []string{"created_at", "updated_at", "name"}
+	_orderSortColumns     = []string{"created_at", "updated_at", "status_changed_time", "total_cost"}
 )
 
 // ProductRepo -.
@@ -20,23 +41,23 @@ func NewProductRepo(pg *postgres.Postgres) *ProductRepo {
 // ---------------- Product ----------------
 
 // CreateProduct -.
-func (r *ProductRepo) CreateProduct(ctx context.Context, p entity.Product) error {
+func (r *ProductRepo) CreateProduct(ctx context.Context, p entity.Product) (string, error) {
 	sql, args, err := r.Builder.
 		Insert("product").
-		Columns("name, category_id, short_info, description, cost, count, discount_cost, discount, created_at, updated_at").
-		Values(p.Name, p.CategoryID, p.ShortInfo, p.Description, p.Cost, p.Count, p.DiscountCost, p.Discount, p.CreatedAt, p.UpdatedAt).
+		Columns("name, sku, category_id, short_info, description, cost, discount_cost, discount").
+		Values(p.Name, nullIfEmpty(p.SKU), nullIfEmpty(p.CategoryID), p.ShortInfo, p.Description, p.Cost, p.DiscountCost, p.Discount).
 		Suffix("RETURNING id").
 		ToSql()
 	if err != nil {
-		return fmt.Errorf("ProductRepo - CreateProduct - r.Builder: %w", err)
+		return "", fmt.Errorf("ProductRepo - CreateProduct - r.Builder: %w", err)
 	}
 
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&p.ID)
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&p.ID)
 	if err != nil {
-		return fmt.Errorf("ProductRepo - CreateProduct - r.Pool.QueryRow: %w", err)
+		return "", fmt.Errorf("ProductRepo - CreateProduct - r.Querier.QueryRow: %w", mapError(err))
 	}
 
-	return nil
+	return p.ID, nil
 }
 
 // GetProductByID -.
@@ -44,88 +65,270 @@ func (r *ProductRepo) GetProduct(ctx context.Context, id string) (entity.Product
 	var p entity.Product
 
 	sql, args, err := r.Builder.
-		Select("id, name, category_id, short_info, description, cost, count, discount_cost, discount, created_at, updated_at").
+		Select(_productColumns).
 		From("product").
 		Where("id = ?", id).
+		Where(_notDeleted).
 		ToSql()
 	if err != nil {
 		return p, fmt.Errorf("ProductRepo - GetProductByID - r.Builder: %w", err)
 	}
 
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&p.ID, &p.Name, &p.CategoryID, &p.ShortInfo, &p.Description, &p.Cost, &p.Count, &p.DiscountCost, &p.Discount, &p.CreatedAt, &p.UpdatedAt)
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&p.ID, &p.Name, &p.SKU, &p.CategoryID, &p.ShortInfo, &p.Description, &p.Cost, &p.Count, &p.Available, &p.DiscountCost, &p.Discount, &p.CreatedAt, &p.UpdatedAt, &p.Version)
 	if err != nil {
-		return p, fmt.Errorf("ProductRepo - GetProductByID - r.Pool.QueryRow: %w", err)
+		return p, fmt.Errorf("ProductRepo - GetProductByID - r.Querier.QueryRow: %w", mapError(err))
 	}
 
 	return p, nil
 }
 
+// ListProducts -.
+func (r *ProductRepo) ListProducts(ctx context.Context, f entity.ProductFilter) (entity.Page[entity.Product], error) {
+	var result entity.Page[entity.Product]
+
+	pg, err := newPage(f.ListParams, _productSortColumns)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListProducts - newPage: %w", err)
+	}
+
+	where := squirrel.And{squirrel.Expr(_notDeleted)}
+	if f.CategoryID != "" && f.IncludeDescendants {
+		where = append(where, squirrel.Expr("category_id IN ("+_subtreeQuery+")", f.CategoryID))
+	} else if f.CategoryID != "" {
+		where = append(where, squirrel.Eq{"category_id": f.CategoryID})
+	}
+
+	if f.MinCost != nil {
+		where = append(where, squirrel.GtOrEq{"cost": *f.MinCost})
+	}
+
+	if f.MaxCost != nil {
+		where = append(where, squirrel.LtOrEq{"cost": *f.MaxCost})
+	}
+
+	if f.InStock != nil {
+		where = append(where, squirrel.Expr("(count > reserved) = ?", *f.InStock))
+	}
+
+	where = appendCreatedRange(where, f.CreatedFrom, f.CreatedTo)
+
+	result.Total, err = r.count(ctx, "product", where)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListProducts - r.count: %w", err)
+	}
+
+	sql, args, err := pg.apply(r.Builder.Select(_productColumns).From("product").Where(where), "").ToSql()
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListProducts - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListProducts - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	items := make([]entity.Product, 0, pg.limit+1)
+
+	for rows.Next() {
+		var p entity.Product
+
+		err = rows.Scan(&p.ID, &p.Name, &p.SKU, &p.CategoryID, &p.ShortInfo, &p.Description, &p.Cost, &p.Count, &p.Available, &p.DiscountCost, &p.Discount, &p.CreatedAt, &p.UpdatedAt, &p.Version)
+		if err != nil {
+			return result, fmt.Errorf("ProductRepo - ListProducts - rows.Scan: %w", err)
+		}
+
+		items = append(items, p)
+	}
+
+	result.Items, result.NextCursor, err = trim(pg, items, productSortKey)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListProducts - trim: %w", err)
+	}
+
+	return result, nil
+}
+
+func productSortKey(p entity.Product, column string) (interface{}, string) {
+	switch column {
+	case "updated_at":
+		return p.UpdatedAt, p.ID
+	case "name":
+		return p.Name, p.ID
+	case "cost":
+		return p.Cost, p.ID
+	case "count":
+		return p.Count, p.ID
+	default:
+		return p.CreatedAt, p.ID
+	}
+}
+
 // UpdateProduct -.
 func (r *ProductRepo) UpdateProduct(ctx context.Context, p entity.Product) error {
 	sql, args, err := r.Builder.
 		Update("product").
 		Set("name", p.Name).
-		Set("category_id", p.CategoryID).
+		Set("sku", nullIfEmpty(p.SKU)).
+		Set("category_id", nullIfEmpty(p.CategoryID)).
 		Set("short_info", p.ShortInfo).
 		Set("description", p.Description).
 		Set("cost", p.Cost).
-		Set("count", p.Count).
 		Set("discount_cost", p.DiscountCost).
 		Set("discount", p.Discount).
-		Set("created_at", p.CreatedAt).
-		Set("updated_at", p.UpdatedAt).
 		Where("id = ?", p.ID).
+		Where(_notDeleted).
+		Where(matchVersion(p.Version)).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("ProductRepo - UpdateProduct - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdateProduct - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkVersion(ctx, r.Postgres, "product", p.ID, p.Version, tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdateProduct - checkVersion: %w", err)
+	}
+
+	return nil
+}
+
+// PatchProduct updates only the fields supplied in the patch.
+func (r *ProductRepo) PatchProduct(ctx context.Context, id string, p entity.ProductPatch) error {
+	set := patchSet{}
+	setField(set, "name", p.Name)
+	setField(set, "sku", p.SKU)
+	setField(set, "category_id", p.CategoryID)
+	setField(set, "short_info", p.ShortInfo)
+	setField(set, "description", p.Description)
+	setField(set, "cost", p.Cost)
+	setField(set, "discount_cost", p.DiscountCost)
+	setField(set, "discount", p.Discount)
+
+	if p.SKU.Set && p.SKU.Value == "" {
+		set["sku"] = nil
+	}
+
+	err := execPatch(ctx, r.Postgres, "product", id, p.Version, set)
 	if err != nil {
-		return fmt.Errorf("ProductRepo - UpdateProduct - r.Pool.Exec: %w", err)
+		return fmt.Errorf("ProductRepo - PatchProduct - %w", err)
 	}
 
 	return nil
 }
 
-// DeleteProduct -.
-func (r *ProductRepo) DeleteProduct(ctx context.Context, id string) error {
+// DeleteProduct moves a product to the trash.
+func (r *ProductRepo) DeleteProduct(ctx context.Context, id string, version int) error {
 	sql, args, err := r.Builder.
-		Delete("product").
+		Update("product").
+		Set("deleted_at", squirrel.Expr("CURRENT_TIMESTAMP")).
 		Where("id = ?", id).
+		Where(_notDeleted).
+		Where(matchVersion(version)).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("ProductRepo - DeleteProduct - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteProduct - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkVersion(ctx, r.Postgres, "product", id, version, tag)
 	if err != nil {
-		return fmt.Errorf("ProductRepo - DeleteProduct - r.Pool.Exec: %w", err)
+		return fmt.Errorf("ProductRepo - DeleteProduct - checkVersion: %w", err)
 	}
 
 	return nil
 }
 
+// GetProducts returns the products with the given ids, without locking them.
+func (r *ProductRepo) GetProducts(ctx context.Context, ids []string) ([]entity.Product, error) {
+	products, err := r.queryProducts(ctx, ids, false)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetProducts - %w", err)
+	}
+
+	return products, nil
+}
+
+// LockProducts selects the products FOR UPDATE. Rows are locked in id order
+// so that concurrent callers cannot deadlock. Must run in a transaction.
+func (r *ProductRepo) LockProducts(ctx context.Context, ids []string) ([]entity.Product, error) {
+	products, err := r.queryProducts(ctx, ids, true)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - LockProducts - %w", err)
+	}
+
+	return products, nil
+}
+
+func (r *ProductRepo) queryProducts(ctx context.Context, ids []string, lock bool) ([]entity.Product, error) {
+	builder := r.Builder.
+		Select(_productColumns).
+		From("product").
+		Where(squirrel.Eq{"id": ids}).
+		Where(_notDeleted).
+		OrderBy("id")
+
+	if lock {
+		builder = builder.Suffix("FOR UPDATE")
+	}
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	products := make([]entity.Product, 0, len(ids))
+
+	for rows.Next() {
+		var p entity.Product
+
+		err = rows.Scan(&p.ID, &p.Name, &p.SKU, &p.CategoryID, &p.ShortInfo, &p.Description, &p.Cost, &p.Count, &p.Available, &p.DiscountCost, &p.Discount, &p.CreatedAt, &p.UpdatedAt, &p.Version)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		products = append(products, p)
+	}
+
+	return products, nil
+}
+
 // ---------------- Category ----------------
 
 // CreateCategory -.
-func (r *ProductRepo) CreateCategory(ctx context.Context, c entity.Category) error {
+func (r *ProductRepo) CreateCategory(ctx context.Context, c entity.Category) (string, error) {
+	// New categories go after their last sibling.
 	sql, args, err := r.Builder.
 		Insert("category").
-		Columns("name, created_at, updated_at").
-		Values(c.Name, c.CreatedAt, c.UpdatedAt).
+		Columns("name", "parent_id", "position").
+		Values(c.Name, nullIfEmpty(c.ParentID), squirrel.Expr(
+			"(SELECT COALESCE(MAX(position) + 1, 0) FROM category WHERE parent_id IS NOT DISTINCT FROM ?::uuid AND deleted_at IS NULL)", nullIfEmpty(c.ParentID))).
 		Suffix("RETURNING id").
 		ToSql()
 	if err != nil {
-		return fmt.Errorf("ProductRepo - CreateCategory - r.Builder: %w", err)
+		return "", fmt.Errorf("ProductRepo - CreateCategory - r.Builder: %w", err)
 	}
 
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&c.ID)
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&c.ID)
 	if err != nil {
-		return fmt.Errorf("ProductRepo - CreateCategory - r.Pool.QueryRow: %w", err)
+		return "", fmt.Errorf("ProductRepo - CreateCategory - r.Querier.QueryRow: %w", mapError(err))
 	}
 
-	return nil
+	return c.ID, nil
 }
 
 // GetCategoryByID -.
@@ -133,56 +336,161 @@ func (r *ProductRepo) GetCategory(ctx context.Context, id string) (entity.Catego
 	var c entity.Category
 
 	sql, args, err := r.Builder.
-		Select("id, name, created_at, updated_at").
+		Select(_categoryColumns).
 		From("category").
 		Where("id = ?", id).
+		Where(_notDeleted).
 		ToSql()
 	if err != nil {
 		return c, fmt.Errorf("ProductRepo - GetCategoryByID - r.Builder: %w", err)
 	}
 
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&c.ID, &c.Name, &c.CreatedAt, &c.UpdatedAt)
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&c.ID, &c.Name, &c.ParentID, &c.Position, &c.CreatedAt, &c.UpdatedAt, &c.Version)
 	if err != nil {
-		return c, fmt.Errorf("ProductRepo - GetCategoryByID - r.Pool.QueryRow: %w", err)
+		return c, fmt.Errorf("ProductRepo - GetCategoryByID - r.Querier.QueryRow: %w", mapError(err))
 	}
 
 	return c, nil
 }
 
+// ListCategories -.
+func (r *ProductRepo) ListCategories(ctx context.Context, f entity.CategoryFilter) (entity.Page[entity.Category], error) {
+	var result entity.Page[entity.Category]
+
+	pg, err := newPage(f.ListParams, _categorySortColumns)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListCategories - newPage: %w", err)
+	}
+
+	where := appendCreatedRange(squirrel.And{squirrel.Expr(_notDeleted)}, f.CreatedFrom, f.CreatedTo)
+
+	if f.ParentID != "" {
+		where = append(where, squirrel.Eq{"parent_id": f.ParentID})
+	}
+
+	if f.Root {
+		where = append(where, squirrel.Eq{"parent_id": nil})
+	}
+
+	result.Total, err = r.count(ctx, "category", where)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListCategories - r.count: %w", err)
+	}
+
+	sql, args, err := pg.apply(r.Builder.Select(_categoryColumns).From("category").Where(where), "").ToSql()
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListCategories - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListCategories - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	items := make([]entity.Category, 0, pg.limit+1)
+
+	for rows.Next() {
+		var c entity.Category
+
+		err = rows.Scan(&c.ID, &c.Name, &c.ParentID, &c.Position, &c.CreatedAt, &c.UpdatedAt, &c.Version)
+		if err != nil {
+			return result, fmt.Errorf("ProductRepo - ListCategories - rows.Scan: %w", err)
+		}
+
+		items = append(items, c)
+	}
+
+	result.Items, result.NextCursor, err = trim(pg, items, func(c entity.Category, column string) (interface{}, string) {
+		switch column {
+		case "updated_at":
+			return c.UpdatedAt, c.ID
+		case "name":
+			return c.Name, c.ID
+		case "position":
+			return c.Position, c.ID
+		default:
+			return c.CreatedAt, c.ID
+		}
+	})
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListCategories - trim: %w", err)
+	}
+
+	return result, nil
+}
+
 // UpdateCategory -.
 func (r *ProductRepo) UpdateCategory(ctx context.Context, c entity.Category) error {
 	sql, args, err := r.Builder.
 		Update("category").
 		Set("name", c.Name).
-		Set("created_at", c.CreatedAt).
-		Set("updated_at", c.UpdatedAt).
 		Where("id = ?", c.ID).
+		Where(_notDeleted).
+		Where(matchVersion(c.Version)).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("ProductRepo - UpdateCategory - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdateCategory - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkVersion(ctx, r.Postgres, "category", c.ID, c.Version, tag)
 	if err != nil {
-		return fmt.Errorf("ProductRepo - UpdateCategory - r.Pool.Exec: %w", err)
+		return fmt.Errorf("ProductRepo - UpdateCategory - checkVersion: %w", err)
 	}
 
 	return nil
 }
 
-// DeleteCategory -.
-func (r *ProductRepo) DeleteCategory(ctx context.Context, id string) error {
+// PatchCategory updates only the fields supplied in the patch.
+func (r *ProductRepo) PatchCategory(ctx context.Context, id string, p entity.CategoryPatch) error {
+	set := patchSet{}
+	setField(set, "name", p.Name)
+
+	err := execPatch(ctx, r.Postgres, "category", id, p.Version, set)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - PatchCategory - %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCategory moves a category to the trash and closes the gap it leaves
+// among its siblings. It must run in a transaction.
+func (r *ProductRepo) DeleteCategory(ctx context.Context, id string, version int) error {
 	sql, args, err := r.Builder.
-		Delete("category").
+		Update("category").
+		Set("deleted_at", squirrel.Expr("CURRENT_TIMESTAMP")).
 		Where("id = ?", id).
+		Where(_notDeleted).
+		Where(matchVersion(version)).
+		Suffix("RETURNING COALESCE(parent_id::text, ''), position").
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("ProductRepo - DeleteCategory - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	var c entity.Category
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&c.ParentID, &c.Position)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// No row was returned: tell a stale version from a missing category.
+		err = checkVersion(ctx, r.Postgres, "category", id, version, pgconn.CommandTag{})
+
+		return fmt.Errorf("ProductRepo - DeleteCategory - checkVersion: %w", err)
+	}
+
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteCategory - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	err = r.shiftSiblings(ctx, c.ParentID, c.Position+1, -1, id)
 	if err != nil {
-		return fmt.Errorf("ProductRepo - DeleteCategory - r.Pool.Exec: %w", err)
+		return fmt.Errorf("ProductRepo - DeleteCategory - r.shiftSiblings: %w", err)
 	}
 
 	return nil
@@ -191,23 +499,23 @@ func (r *ProductRepo) DeleteCategory(ctx context.Context, id string) error {
 // ---------------- Attribute ----------------
 
 // CreateAttribute -.
-func (r *ProductRepo) CreateAttribute(ctx context.Context, a entity.Attribute) error {
+func (r *ProductRepo) CreateAttribute(ctx context.Context, a entity.Attribute) (string, error) {
 	sql, args, err := r.Builder.
 		Insert("attribute").
-		Columns("name, category_id, created_at, updated_at").
-		Values(a.Name, a.CategoryID, a.CreatedAt, a.UpdatedAt).
+		Columns("name, category_id, type, unit, options, required").
+		Values(a.Name, a.CategoryID, a.Type, nullIfEmpty(a.Unit), attributeOptions(a.Options), a.Required).
 		Suffix("RETURNING id").
 		ToSql()
 	if err != nil {
-		return fmt.Errorf("ProductRepo - CreateAttribute - r.Builder: %w", err)
+		return "", fmt.Errorf("ProductRepo - CreateAttribute - r.Builder: %w", err)
 	}
 
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&a.ID)
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&a.ID)
 	if err != nil {
-		return fmt.Errorf("ProductRepo - CreateAttribute - r.Pool.QueryRow: %w", err)
+		return "", fmt.Errorf("ProductRepo - CreateAttribute - r.Querier.QueryRow: %w", mapError(err))
 	}
 
-	return nil
+	return a.ID, nil
 }
 
 // GetAttributeByID -.
@@ -215,7 +523,7 @@ func (r *ProductRepo) GetAttribute(ctx context.Context, id string) (entity.Attri
 	var a entity.Attribute
 
 	sql, args, err := r.Builder.
-		Select("id, name, category_id, created_at, updated_at").
+		Select(_attributeColumns).
 		From("attribute").
 		Where("id = ?", id).
 		ToSql()
@@ -223,31 +531,120 @@ func (r *ProductRepo) GetAttribute(ctx context.Context, id string) (entity.Attri
 		return a, fmt.Errorf("ProductRepo - GetAttributeByID - r.Builder: %w", err)
 	}
 
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&a.ID, &a.Name, &a.CategoryID, &a.CreatedAt, &a.UpdatedAt)
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&a.ID, &a.Name, &a.CategoryID, &a.Type, &a.Unit, &a.Options, &a.Required, &a.CreatedAt, &a.UpdatedAt)
 	if err != nil {
-		return a, fmt.Errorf("ProductRepo - GetAttributeByID - r.Pool.QueryRow: %w", err)
+		return a, fmt.Errorf("ProductRepo - GetAttributeByID - r.Querier.QueryRow: %w", mapError(err))
 	}
 
 	return a, nil
 }
 
+// ListAttributes -.
+func (r *ProductRepo) ListAttributes(ctx context.Context, f entity.AttributeFilter) (entity.Page[entity.Attribute], error) {
+	var result entity.Page[entity.Attribute]
+
+	pg, err := newPage(f.ListParams, _attributeSortColumns)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListAttributes - newPage: %w", err)
+	}
+
+	where := squirrel.And{}
+	if f.CategoryID != "" {
+		where = append(where, squirrel.Eq{"category_id": f.CategoryID})
+	}
+
+	result.Total, err = r.count(ctx, "attribute", where)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListAttributes - r.count: %w", err)
+	}
+
+	sql, args, err := pg.apply(r.Builder.Select(_attributeColumns).From("attribute").Where(where), "").ToSql()
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListAttributes - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListAttributes - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	items := make([]entity.Attribute, 0, pg.limit+1)
+
+	for rows.Next() {
+		var a entity.Attribute
+
+		err = rows.Scan(&a.ID, &a.Name, &a.CategoryID, &a.Type, &a.Unit, &a.Options, &a.Required, &a.CreatedAt, &a.UpdatedAt)
+		if err != nil {
+			return result, fmt.Errorf("ProductRepo - ListAttributes - rows.Scan: %w", err)
+		}
+
+		items = append(items, a)
+	}
+
+	result.Items, result.NextCursor, err = trim(pg, items, func(a entity.Attribute, column string) (interface{}, string) {
+		switch column {
+		case "updated_at":
+			return a.UpdatedAt, a.ID
+		case "name":
+			return a.Name, a.ID
+		default:
+			return a.CreatedAt, a.ID
+		}
+	})
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListAttributes - trim: %w", err)
+	}
+
+	return result, nil
+}
+
 // UpdateAttribute -.
 func (r *ProductRepo) UpdateAttribute(ctx context.Context, a entity.Attribute) error {
 	sql, args, err := r.Builder.
 		Update("attribute").
 		Set("name", a.Name).
 		Set("category_id", a.CategoryID).
-		Set("created_at", a.CreatedAt).
-		Set("updated_at", a.UpdatedAt).
+		Set("type", a.Type).
+		Set("unit", nullIfEmpty(a.Unit)).
+		Set("options", attributeOptions(a.Options)).
+		Set("required", a.Required).
 		Where("id = ?", a.ID).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("ProductRepo - UpdateAttribute - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdateAttribute - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdateAttribute - checkAffected: %w", err)
+	}
+
+	return nil
+}
+
+// PatchAttribute updates only the fields supplied in the patch.
+func (r *ProductRepo) PatchAttribute(ctx context.Context, id string, p entity.AttributePatch) error {
+	set := patchSet{}
+	setField(set, "name", p.Name)
+	setField(set, "category_id", p.CategoryID)
+	setField(set, "type", p.Type)
+	setField(set, "unit", p.Unit)
+	setField(set, "required", p.Required)
+
+	// options is NOT NULL: an explicit null clears the list.
+	if p.Options.Set {
+		set["options"] = attributeOptions(p.Options.Value)
+	}
+
+	err := execPatch(ctx, r.Postgres, "attribute", id, 0, set)
 	if err != nil {
-		return fmt.Errorf("ProductRepo - UpdateAttribute - r.Pool.Exec: %w", err)
+		return fmt.Errorf("ProductRepo - PatchAttribute - %w", err)
 	}
 
 	return nil
@@ -263,9 +660,165 @@ func (r *ProductRepo) DeleteAttribute(ctx context.Context, id string) error {
 		return fmt.Errorf("ProductRepo - DeleteAttribute - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteAttribute - r.Querier.Exec: %w", mapDeleteError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteAttribute - checkAffected: %w", err)
+	}
+
+	return nil
+}
+
+// GetAttributesByCategory returns the attributes defined for a category.
+func (r *ProductRepo) GetAttributesByCategory(ctx context.Context, categoryID string) ([]entity.Attribute, error) {
+	sql, args, err := r.Builder.
+		Select(_attributeColumns).
+		From("attribute").
+		Where("category_id = ?", categoryID).
+		OrderBy("name", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetAttributesByCategory - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetAttributesByCategory - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	attributes := make([]entity.Attribute, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var a entity.Attribute
+
+		err = rows.Scan(&a.ID, &a.Name, &a.CategoryID, &a.Type, &a.Unit, &a.Options, &a.Required, &a.CreatedAt, &a.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ProductRepo - GetAttributesByCategory - rows.Scan: %w", err)
+		}
+
+		attributes = append(attributes, a)
+	}
+
+	return attributes, nil
+}
+
+// attributeOptions keeps NOT NULL options as an empty array instead of NULL.
+func attributeOptions(options []string) []string {
+	if options == nil {
+		return []string{}
+	}
+
+	return options
+}
+
+// ---------------- ProductAttributeValue ----------------
+
+// GetProductAttributes returns the attribute values of a product resolved
+// against their definitions.
+func (r *ProductRepo) GetProductAttributes(ctx context.Context, productID string) ([]entity.ProductAttribute, error) {
+	sql, args, err := r.Builder.
+		Select("a.id, a.name, a.type, COALESCE(a.unit, ''), v.value_string, v.value_number, v.value_boolean").
+		From("product_attribute_value v").
+		Join("attribute a ON a.id = v.attribute_id").
+		Where("v.product_id = ?", productID).
+		OrderBy("a.name", "a.id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetProductAttributes - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetProductAttributes - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	values := make([]entity.ProductAttribute, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var (
+			v       entity.ProductAttribute
+			str     *string
+			number  *float64
+			boolean *bool
+		)
+
+		err = rows.Scan(&v.AttributeID, &v.Name, &v.Type, &v.Unit, &str, &number, &boolean)
+		if err != nil {
+			return nil, fmt.Errorf("ProductRepo - GetProductAttributes - rows.Scan: %w", err)
+		}
+
+		switch {
+		case str != nil:
+			v.Value = *str
+		case number != nil:
+			v.Value = *number
+		case boolean != nil:
+			v.Value = *boolean
+		}
+
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+// ReplaceProductAttributes drops the stored attribute values of a product
+// and writes values instead. Must run in a transaction.
+func (r *ProductRepo) ReplaceProductAttributes(ctx context.Context, productID string, values []entity.ProductAttribute) error {
+	sql, args, err := r.Builder.
+		Delete("product_attribute_value").
+		Where("product_id = ?", productID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - ReplaceProductAttributes - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
 	if err != nil {
-		return fmt.Errorf("ProductRepo - DeleteAttribute - r.Pool.Exec: %w", err)
+		return fmt.Errorf("ProductRepo - ReplaceProductAttributes - r.Querier.Exec: %w", mapError(err))
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	insert := r.Builder.
+		Insert("product_attribute_value").
+		Columns("product_id, attribute_id, value_string, value_number, value_boolean")
+
+	for _, v := range values {
+		var (
+			str     *string
+			number  *float64
+			boolean *bool
+		)
+
+		switch value := v.Value.(type) {
+		case string:
+			str = &value
+		case float64:
+			number = &value
+		case bool:
+			boolean = &value
+		}
+
+		insert = insert.Values(productID, v.AttributeID, str, number, boolean)
+	}
+
+	sql, args, err = insert.ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - ReplaceProductAttributes - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - ReplaceProductAttributes - r.Querier.Exec: %w", mapError(err))
 	}
 
 	return nil
@@ -274,23 +827,23 @@ func (r *ProductRepo) DeleteAttribute(ctx context.Context, id string) error {
 // ---------------- Order ----------------
 
 // CreateOrder -.
-func (r *ProductRepo) CreateOrder(ctx context.Context, o entity.Order) error {
+func (r *ProductRepo) CreateOrder(ctx context.Context, o entity.Order) (string, error) {
 	sql, args, err := r.Builder.
-		Insert("order").
-		Columns("user_id, integration_id, status, status_changed_time, total_cost, created_at, updated_at").
-		Values(o.UserID, o.IntegrationID, o.Status, o.StatusChangedTime, o.TotalCost, o.CreatedAt, o.UpdatedAt).
+		Insert(`"order"`).
+		Columns("user_id, integration_id, status, status_changed_time, subtotal_cost, discount_cost, total_cost, currency, exchange_rate").
+		Values(nullIfEmpty(o.UserID), nullIfEmpty(o.IntegrationID), o.Status, squirrel.Expr("CURRENT_TIMESTAMP"), o.SubtotalCost, o.DiscountCost, o.TotalCost, o.Currency, squirrel.Expr("?::text::numeric", o.ExchangeRate)).
 		Suffix("RETURNING id").
 		ToSql()
 	if err != nil {
-		return fmt.Errorf("ProductRepo - CreateOrder - r.Builder: %w", err)
+		return "", fmt.Errorf("ProductRepo - CreateOrder - r.Builder: %w", err)
 	}
 
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&o.ID)
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&o.ID)
 	if err != nil {
-		return fmt.Errorf("ProductRepo - CreateOrder - r.Pool.QueryRow: %w", err)
+		return "", fmt.Errorf("ProductRepo - CreateOrder - r.Querier.QueryRow: %w", mapError(err))
 	}
 
-	return nil
+	return o.ID, nil
 }
 
 // GetOrderByID -.
@@ -298,85 +851,315 @@ func (r *ProductRepo) GetOrder(ctx context.Context, id string) (entity.Order, er
 	var o entity.Order
 
 	sql, args, err := r.Builder.
-		Select("id, user_id, integration_id, status, status_changed_time, total_cost, created_at, updated_at").
-		From("order").
+		Select(_orderColumns).
+		From(`"order"`).
 		Where("id = ?", id).
 		ToSql()
 	if err != nil {
 		return o, fmt.Errorf("ProductRepo - GetOrderByID - r.Builder: %w", err)
 	}
 
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&o.ID, &o.UserID, &o.IntegrationID, &o.Status, &o.StatusChangedTime, &o.TotalCost, &o.CreatedAt, &o.UpdatedAt)
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&o.ID, &o.UserID, &o.IntegrationID, &o.Status, &o.StatusChangedTime, &o.SubtotalCost, &o.DiscountCost, &o.TotalCost, &o.Currency, &o.ExchangeRate, &o.CreatedAt, &o.UpdatedAt, &o.Version)
 	if err != nil {
-		return o, fmt.Errorf("ProductRepo - GetOrderByID - r.Pool.QueryRow: %w", err)
+		return o, fmt.Errorf("ProductRepo - GetOrderByID - r.Querier.QueryRow: %w", mapError(err))
 	}
 
 	return o, nil
 }
 
+// ListOrders -.
+func (r *ProductRepo) ListOrders(ctx context.Context, f entity.OrderFilter) (entity.Page[entity.Order], error) {
+	var result entity.Page[entity.Order]
+
+	pg, err := newPage(f.ListParams, _orderSortColumns)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListOrders - newPage: %w", err)
+	}
+
+	where := squirrel.And{}
+	if f.Status != "" {
+		where = append(where, squirrel.Eq{"status": f.Status})
+	}
+
+	if f.UserID != "" {
+		where = append(where, squirrel.Eq{"user_id": f.UserID})
+	}
+
+	if f.IntegrationID != "" {
+		where = append(where, squirrel.Eq{"integration_id": f.IntegrationID})
+	}
+
+	where = appendCreatedRange(where, f.CreatedFrom, f.CreatedTo)
+
+	result.Total, err = r.count(ctx, `"order"`, where)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListOrders - r.count: %w", err)
+	}
+
+	sql, args, err := pg.apply(r.Builder.Select(_orderColumns).From(`"order"`).Where(where), "").ToSql()
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListOrders - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListOrders - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	items := make([]entity.Order, 0, pg.limit+1)
+
+	for rows.Next() {
+		var o entity.Order
+
+		err = rows.Scan(&o.ID, &o.UserID, &o.IntegrationID, &o.Status, &o.StatusChangedTime, &o.SubtotalCost, &o.DiscountCost, &o.TotalCost, &o.Currency, &o.ExchangeRate, &o.CreatedAt, &o.UpdatedAt, &o.Version)
+		if err != nil {
+			return result, fmt.Errorf("ProductRepo - ListOrders - rows.Scan: %w", err)
+		}
+
+		items = append(items, o)
+	}
+
+	result.Items, result.NextCursor, err = trim(pg, items, func(o entity.Order, column string) (interface{}, string) {
+		switch column {
+		case "updated_at":
+			return o.UpdatedAt, o.ID
+		case "status_changed_time":
+			return o.StatusChangedTime, o.ID
+		case "total_cost":
+			return o.TotalCost, o.ID
+		default:
+			return o.CreatedAt, o.ID
+		}
+	})
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListOrders - trim: %w", err)
+	}
+
+	return result, nil
+}
+
 // UpdateOrder -.
 func (r *ProductRepo) UpdateOrder(ctx context.Context, o entity.Order) error {
 	sql, args, err := r.Builder.
-		Update("order").
-		Set("user_id", o.UserID).
-		Set("integration_id", o.IntegrationID).
-		Set("status", o.Status).
-		Set("status_changed_time", o.StatusChangedTime).
-		Set("total_cost", o.TotalCost).
-		Set("created_at", o.CreatedAt).
-		Set("updated_at", o.UpdatedAt).
+		Update(`"order"`).
+		Set("user_id", nullIfEmpty(o.UserID)).
+		Set("integration_id", nullIfEmpty(o.IntegrationID)).
 		Where("id = ?", o.ID).
+		Where(matchVersion(o.Version)).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("ProductRepo - UpdateOrder - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdateOrder - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkVersion(ctx, r.Postgres, `"order"`, o.ID, o.Version, tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdateOrder - checkVersion: %w", err)
+	}
+
+	return nil
+}
+
+// PatchOrder updates only the fields supplied in the patch.
+func (r *ProductRepo) PatchOrder(ctx context.Context, id string, p entity.OrderPatch) error {
+	set := patchSet{}
+	setField(set, "user_id", p.UserID)
+	setField(set, "integration_id", p.IntegrationID)
+
+	err := execPatch(ctx, r.Postgres, `"order"`, id, p.Version, set)
 	if err != nil {
-		return fmt.Errorf("ProductRepo - UpdateOrder - r.Pool.Exec: %w", err)
+		return fmt.Errorf("ProductRepo - PatchOrder - %w", err)
 	}
 
 	return nil
 }
 
 // DeleteOrder -.
-func (r *ProductRepo) DeleteOrder(ctx context.Context, id string) error {
+func (r *ProductRepo) DeleteOrder(ctx context.Context, id string, version int) error {
 	sql, args, err := r.Builder.
-		Delete("order").
+		Delete(`"order"`).
 		Where("id = ?", id).
+		Where(matchVersion(version)).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("ProductRepo - DeleteOrder - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteOrder - r.Querier.Exec: %w", mapDeleteError(err))
+	}
+
+	err = checkVersion(ctx, r.Postgres, `"order"`, id, version, tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteOrder - checkVersion: %w", err)
+	}
+
+	return nil
+}
+
+// LockOrder selects the order FOR UPDATE. Must run in a transaction.
+func (r *ProductRepo) LockOrder(ctx context.Context, id string) (entity.Order, error) {
+	var o entity.Order
+
+	sql, args, err := r.Builder.
+		Select(_orderColumns).
+		From(`"order"`).
+		Where("id = ?", id).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return o, fmt.Errorf("ProductRepo - LockOrder - r.Builder: %w", err)
+	}
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&o.ID, &o.UserID, &o.IntegrationID, &o.Status, &o.StatusChangedTime, &o.SubtotalCost, &o.DiscountCost, &o.TotalCost, &o.Currency, &o.ExchangeRate, &o.CreatedAt, &o.UpdatedAt, &o.Version)
+	if err != nil {
+		return o, fmt.Errorf("ProductRepo - LockOrder - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return o, nil
+}
+
+// UpdateOrderStatus moves an order from one status to another. The current
+// status is part of the WHERE clause, so a concurrent change yields ErrConflict.
+func (r *ProductRepo) UpdateOrderStatus(ctx context.Context, id, from, to string) error {
+	sql, args, err := r.Builder.
+		Update(`"order"`).
+		Set("status", to).
+		Set("status_changed_time", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where("id = ?", id).
+		Where("status = ?", from).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdateOrderStatus - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdateOrderStatus - r.Querier.Exec: %w", mapError(err))
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("ProductRepo - UpdateOrderStatus: %w", entity.ErrConflict)
+	}
+
+	return nil
+}
+
+// ---------------- OrderStatusHistory ----------------
+
+// CreateOrderStatusChange -.
+func (r *ProductRepo) CreateOrderStatusChange(ctx context.Context, c entity.OrderStatusChange) error {
+	sql, args, err := r.Builder.
+		Insert("order_status_history").
+		Columns("order_id, from_status, to_status, changed_by, comment").
+		Values(c.OrderID, nullIfEmpty(c.FromStatus), c.ToStatus, nullIfEmpty(c.ChangedBy), nullIfEmpty(c.Comment)).
+		ToSql()
 	if err != nil {
-		return fmt.Errorf("ProductRepo - DeleteOrder - r.Pool.Exec: %w", err)
+		return fmt.Errorf("ProductRepo - CreateOrderStatusChange - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - CreateOrderStatusChange - r.Querier.Exec: %w", mapError(err))
 	}
 
 	return nil
 }
 
+// GetOrderStatusHistory returns the status changes of an order, oldest first.
+func (r *ProductRepo) GetOrderStatusHistory(ctx context.Context, orderID string) ([]entity.OrderStatusChange, error) {
+	sql, args, err := r.Builder.
+		Select("id, order_id, COALESCE(from_status, ''), to_status, COALESCE(changed_by::text, ''), COALESCE(comment, ''), created_at").
+		From("order_status_history").
+		Where("order_id = ?", orderID).
+		OrderBy("created_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetOrderStatusHistory - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetOrderStatusHistory - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	history := make([]entity.OrderStatusChange, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var c entity.OrderStatusChange
+
+		err = rows.Scan(&c.ID, &c.OrderID, &c.FromStatus, &c.ToStatus, &c.ChangedBy, &c.Comment, &c.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ProductRepo - GetOrderStatusHistory - rows.Scan: %w", err)
+		}
+
+		history = append(history, c)
+	}
+
+	return history, nil
+}
+
 // ---------------- OrderProducts ----------------
 
 // CreateOrderProduct -.
-func (r *ProductRepo) CreateOrderProducts(ctx context.Context, op entity.OrderProducts) error {
+func (r *ProductRepo) CreateOrderProducts(ctx context.Context, op entity.OrderProducts) (string, error) {
 	sql, args, err := r.Builder.
 		Insert("order_products").
-		Columns("order_id, product_id, count, cost, created_at, updated_at").
-		Values(op.OrderID, op.ProductID, op.Count, op.Cost, op.CreatedAt, op.UpdatedAt).
+		Columns("order_id, product_id, variant_id, count, cost, discount").
+		Values(op.OrderID, op.ProductID, nullIfEmpty(op.VariantID), op.Count, op.Cost, op.Discount).
 		Suffix("RETURNING id").
 		ToSql()
 	if err != nil {
-		return fmt.Errorf("ProductRepo - CreateOrderProduct - r.Builder: %w", err)
+		return "", fmt.Errorf("ProductRepo - CreateOrderProduct - r.Builder: %w", err)
 	}
 
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&op.ID)
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&op.ID)
 	if err != nil {
-		return fmt.Errorf("ProductRepo - CreateOrderProduct - r.Pool.QueryRow: %w", err)
+		return "", fmt.Errorf("ProductRepo - CreateOrderProduct - r.Querier.QueryRow: %w", mapError(err))
 	}
 
-	return nil
+	return op.ID, nil
+}
+
+// GetOrderProductsByOrder returns the lines of an order.
+func (r *ProductRepo) GetOrderProductsByOrder(ctx context.Context, orderID string) ([]entity.OrderProducts, error) {
+	sql, args, err := r.Builder.
+		Select("id, order_id, product_id, COALESCE(variant_id::text, ''), count, cost, discount, created_at, updated_at").
+		From("order_products").
+		Where("order_id = ?", orderID).
+		OrderBy("created_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetOrderProductsByOrder - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetOrderProductsByOrder - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	lines := make([]entity.OrderProducts, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var op entity.OrderProducts
+
+		err = rows.Scan(&op.ID, &op.OrderID, &op.ProductID, &op.VariantID, &op.Count, &op.Cost, &op.Discount, &op.CreatedAt, &op.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ProductRepo - GetOrderProductsByOrder - rows.Scan: %w", err)
+		}
+
+		lines = append(lines, op)
+	}
+
+	return lines, nil
 }
 
 // GetOrderProductByID -.
@@ -384,7 +1167,7 @@ func (r *ProductRepo) GetOrderProducts(ctx context.Context, id string) (entity.O
 	var op entity.OrderProducts
 
 	sql, args, err := r.Builder.
-		Select("id, order_id, product_id, count, cost, created_at, updated_at").
+		Select("id, order_id, product_id, COALESCE(variant_id::text, ''), count, cost, discount, created_at, updated_at").
 		From("order_products").
 		Where("id = ?", id).
 		ToSql()
@@ -392,9 +1175,9 @@ func (r *ProductRepo) GetOrderProducts(ctx context.Context, id string) (entity.O
 		return op, fmt.Errorf("ProductRepo - GetOrderProductByID - r.Builder: %w", err)
 	}
 
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&op.ID, &op.OrderID, &op.ProductID, &op.Count, &op.Cost, &op.CreatedAt, &op.UpdatedAt)
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&op.ID, &op.OrderID, &op.ProductID, &op.VariantID, &op.Count, &op.Cost, &op.Discount, &op.CreatedAt, &op.UpdatedAt)
 	if err != nil {
-		return op, fmt.Errorf("ProductRepo - GetOrderProductByID - r.Pool.QueryRow: %w", err)
+		return op, fmt.Errorf("ProductRepo - GetOrderProductByID - r.Querier.QueryRow: %w", mapError(err))
 	}
 
 	return op, nil
@@ -406,19 +1189,23 @@ func (r *ProductRepo) UpdateOrderProducts(ctx context.Context, op entity.OrderPr
 		Update("order_products").
 		Set("order_id", op.OrderID).
 		Set("product_id", op.ProductID).
+		Set("variant_id", nullIfEmpty(op.VariantID)).
 		Set("count", op.Count).
 		Set("cost", op.Cost).
-		Set("created_at", op.CreatedAt).
-		Set("updated_at", op.UpdatedAt).
 		Where("id = ?", op.ID).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("ProductRepo - UpdateOrderProduct - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
 	if err != nil {
-		return fmt.Errorf("ProductRepo - UpdateOrderProduct - r.Pool.Exec: %w", err)
+		return fmt.Errorf("ProductRepo - UpdateOrderProduct - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdateOrderProduct - checkAffected: %w", err)
 	}
 
 	return nil
@@ -434,10 +1221,57 @@ func (r *ProductRepo) DeleteOrderProducts(ctx context.Context, id string) error
 		return fmt.Errorf("ProductRepo - DeleteOrderProduct - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteOrderProduct - r.Querier.Exec: %w", mapDeleteError(err))
+	}
+
+	err = checkAffected(tag)
 	if err != nil {
-		return fmt.Errorf("ProductRepo - DeleteOrderProduct - r.Pool.Exec: %w", err)
+		return fmt.Errorf("ProductRepo - DeleteOrderProduct - checkAffected: %w", err)
 	}
 
 	return nil
 }
+
+// ---------------- Helpers ----------------
+
+func (r *ProductRepo) count(ctx context.Context, table string, where squirrel.Sqlizer) (int, error) {
+	sql, args, err := r.Builder.
+		Select("COUNT(*)").
+		From(table).
+		Where(where).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("ProductRepo - count - r.Builder: %w", err)
+	}
+
+	var total int
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("ProductRepo - count - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return total, nil
+}
+
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+
+	return s
+}
+
+func appendCreatedRange(where squirrel.And, from, to *time.Time) squirrel.And {
+	if from != nil {
+		where = append(where, squirrel.GtOrEq{"created_at": *from})
+	}
+
+	if to != nil {
+		where = append(where, squirrel.Lt{"created_at": *to})
+	}
+
+	return where
+}
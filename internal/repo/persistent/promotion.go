@@ -0,0 +1,486 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+
+	"ai-seller/internal/entity"
+)
+
+const (
+	_promotionColumns = "id, name, description, type, value, buy_count, get_count, min_order_cost, " +
+		"product_ids::text[], category_ids::text[], client_type_ids::text[], starts_at, ends_at, active, stackable, priority, requires_coupon, " +
+		"COALESCE(usage_limit, 0), COALESCE(usage_limit_per_user, 0), " +
+		"(SELECT COUNT(*) FROM promotion_usage u WHERE u.promotion_id = promotion.id)::int, created_at, updated_at, version"
+
+	_couponColumns = "id, promotion_id, code, COALESCE(usage_limit, 0), COALESCE(usage_limit_per_user, 0), " +
+		"(SELECT COUNT(*) FROM promotion_usage u WHERE u.coupon_id = coupon.id)::int, created_at, updated_at"
+
+	// _activePromotion matches promotions running right now.
+	_activePromotion = "active AND (starts_at IS NULL OR starts_at <= CURRENT_TIMESTAMP) AND (ends_at IS NULL OR ends_at > CURRENT_TIMESTAMP)"
+)
+
+var _promotionSortColumns = []string{"created_at", "updated_at", "priority"}
+
+// CreatePromotion -.
+func (r *ProductRepo) CreatePromotion(ctx context.Context, p entity.Promotion) (string, error) {
+	sql, args, err := r.Builder.
+		Insert("promotion").
+		Columns("name, description, type, value, buy_count, get_count, min_order_cost, product_ids, category_ids, client_type_ids",
+			"starts_at, ends_at, active, stackable, priority, requires_coupon, usage_limit, usage_limit_per_user").
+		Values(p.Name, p.Description, p.Type, p.Value, p.BuyCount, p.GetCount, p.MinOrderCost, uuidArray(p.ProductIDs), uuidArray(p.CategoryIDs), uuidArray(p.ClientTypeIDs),
+			p.StartsAt, p.EndsAt, p.Active, p.Stackable, p.Priority, p.RequiresCoupon, nullIfZero(p.UsageLimit), nullIfZero(p.UsageLimitPerUser)).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("ProductRepo - CreatePromotion - r.Builder: %w", err)
+	}
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&p.ID)
+	if err != nil {
+		return "", fmt.Errorf("ProductRepo - CreatePromotion - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return p.ID, nil
+}
+
+// GetPromotion -.
+func (r *ProductRepo) GetPromotion(ctx context.Context, id string) (entity.Promotion, error) {
+	promotions, err := r.queryPromotions(ctx, r.Builder.Select(_promotionColumns).From("promotion").Where("id = ?", id))
+	if err != nil {
+		return entity.Promotion{}, fmt.Errorf("ProductRepo - GetPromotion - %w", err)
+	}
+
+	if len(promotions) == 0 {
+		return entity.Promotion{}, fmt.Errorf("ProductRepo - GetPromotion: %w", entity.ErrNotFound)
+	}
+
+	return promotions[0], nil
+}
+
+// ListPromotions -.
+func (r *ProductRepo) ListPromotions(ctx context.Context, f entity.PromotionFilter) (entity.Page[entity.Promotion], error) {
+	var result entity.Page[entity.Promotion]
+
+	pg, err := newPage(f.ListParams, _promotionSortColumns)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListPromotions - newPage: %w", err)
+	}
+
+	where := squirrel.And{}
+	if f.Active != nil {
+		where = append(where, squirrel.Eq{"active": *f.Active})
+	}
+
+	result.Total, err = r.count(ctx, "promotion", where)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListPromotions - r.count: %w", err)
+	}
+
+	items, err := r.queryPromotions(ctx, pg.apply(r.Builder.Select(_promotionColumns).From("promotion").Where(where), ""))
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListPromotions - %w", err)
+	}
+
+	result.Items, result.NextCursor, err = trim(pg, items, func(p entity.Promotion, column string) (interface{}, string) {
+		switch column {
+		case "updated_at":
+			return p.UpdatedAt, p.ID
+		case "priority":
+			return p.Priority, p.ID
+		default:
+			return p.CreatedAt, p.ID
+		}
+	})
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListPromotions - trim: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetActivePromotions returns the promotions running right now, highest
+// priority first.
+func (r *ProductRepo) GetActivePromotions(ctx context.Context) ([]entity.Promotion, error) {
+	promotions, err := r.queryPromotions(ctx, r.Builder.
+		Select(_promotionColumns).
+		From("promotion").
+		Where(_activePromotion).
+		OrderBy("priority DESC", "id"))
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetActivePromotions - %w", err)
+	}
+
+	return promotions, nil
+}
+
+// LockPromotions selects the promotions FOR UPDATE in id order, so that
+// concurrent orders check their usage limits one after another. Must run in
+// a transaction.
+func (r *ProductRepo) LockPromotions(ctx context.Context, ids []string) ([]entity.Promotion, error) {
+	promotions, err := r.queryPromotions(ctx, r.Builder.
+		Select(_promotionColumns).
+		From("promotion").
+		Where(squirrel.Eq{"id": ids}).
+		OrderBy("id").
+		Suffix("FOR UPDATE"))
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - LockPromotions - %w", err)
+	}
+
+	return promotions, nil
+}
+
+func (r *ProductRepo) queryPromotions(ctx context.Context, builder squirrel.SelectBuilder) ([]entity.Promotion, error) {
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	promotions := make([]entity.Promotion, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var p entity.Promotion
+
+		err = rows.Scan(&p.ID, &p.Name, &p.Description, &p.Type, &p.Value, &p.BuyCount, &p.GetCount, &p.MinOrderCost,
+			&p.ProductIDs, &p.CategoryIDs, &p.ClientTypeIDs, &p.StartsAt, &p.EndsAt, &p.Active, &p.Stackable, &p.Priority, &p.RequiresCoupon,
+			&p.UsageLimit, &p.UsageLimitPerUser, &p.UsedCount, &p.CreatedAt, &p.UpdatedAt, &p.Version)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		promotions = append(promotions, p)
+	}
+
+	return promotions, nil
+}
+
+// UpdatePromotion -.
+func (r *ProductRepo) UpdatePromotion(ctx context.Context, p entity.Promotion) error {
+	sql, args, err := r.Builder.
+		Update("promotion").
+		Set("name", p.Name).
+		Set("description", p.Description).
+		Set("type", p.Type).
+		Set("value", p.Value).
+		Set("buy_count", p.BuyCount).
+		Set("get_count", p.GetCount).
+		Set("min_order_cost", p.MinOrderCost).
+		Set("product_ids", uuidArray(p.ProductIDs)).
+		Set("category_ids", uuidArray(p.CategoryIDs)).
+		Set("client_type_ids", uuidArray(p.ClientTypeIDs)).
+		Set("starts_at", p.StartsAt).
+		Set("ends_at", p.EndsAt).
+		Set("active", p.Active).
+		Set("stackable", p.Stackable).
+		Set("priority", p.Priority).
+		Set("requires_coupon", p.RequiresCoupon).
+		Set("usage_limit", nullIfZero(p.UsageLimit)).
+		Set("usage_limit_per_user", nullIfZero(p.UsageLimitPerUser)).
+		Where("id = ?", p.ID).
+		Where(matchVersion(p.Version)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdatePromotion - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdatePromotion - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkVersion(ctx, r.Postgres, "promotion", p.ID, p.Version, tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdatePromotion - checkVersion: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePromotion deletes a promotion with its coupons. Orders keep the
+// explanation of discounts it gave.
+func (r *ProductRepo) DeletePromotion(ctx context.Context, id string, version int) error {
+	sql, args, err := r.Builder.
+		Delete("promotion").
+		Where("id = ?", id).
+		Where(matchVersion(version)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeletePromotion - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeletePromotion - r.Querier.Exec: %w", mapDeleteError(err))
+	}
+
+	err = checkVersion(ctx, r.Postgres, "promotion", id, version, tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeletePromotion - checkVersion: %w", err)
+	}
+
+	return nil
+}
+
+// CountPromotionUsage returns how many times a promotion was used in total
+// and by the user.
+func (r *ProductRepo) CountPromotionUsage(ctx context.Context, promotionID, userID string) (total, byUser int, err error) {
+	return r.countUsage(ctx, "CountPromotionUsage", "promotion_id", promotionID, userID)
+}
+
+// CountCouponUsage returns how many times a coupon was used in total and by
+// the user.
+func (r *ProductRepo) CountCouponUsage(ctx context.Context, couponID, userID string) (total, byUser int, err error) {
+	return r.countUsage(ctx, "CountCouponUsage", "coupon_id", couponID, userID)
+}
+
+func (r *ProductRepo) countUsage(ctx context.Context, method, column, id, userID string) (total, byUser int, err error) {
+	sql, args, err := r.Builder.
+		Select("COUNT(*)").
+		Column("COUNT(*) FILTER (WHERE user_id = ?)", nullIfEmpty(userID)).
+		From("promotion_usage").
+		Where(squirrel.Eq{column: id}).
+		ToSql()
+	if err != nil {
+		return 0, 0, fmt.Errorf("ProductRepo - %s - r.Builder: %w", method, err)
+	}
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&total, &byUser)
+	if err != nil {
+		return 0, 0, fmt.Errorf("ProductRepo - %s - r.Querier.QueryRow: %w", method, mapError(err))
+	}
+
+	return total, byUser, nil
+}
+
+// CreatePromotionUsage -.
+func (r *ProductRepo) CreatePromotionUsage(ctx context.Context, u entity.PromotionUsage) error {
+	sql, args, err := r.Builder.
+		Insert("promotion_usage").
+		Columns("promotion_id, coupon_id, order_id, user_id").
+		Values(u.PromotionID, nullIfEmpty(u.CouponID), u.OrderID, nullIfEmpty(u.UserID)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - CreatePromotionUsage - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - CreatePromotionUsage - r.Querier.Exec: %w", mapError(err))
+	}
+
+	return nil
+}
+
+// DeletePromotionUsageByOrder gives the promotions and coupons used by an
+// order back to their usage limits.
+func (r *ProductRepo) DeletePromotionUsageByOrder(ctx context.Context, orderID string) error {
+	sql, args, err := r.Builder.
+		Delete("promotion_usage").
+		Where("order_id = ?", orderID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeletePromotionUsageByOrder - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeletePromotionUsageByOrder - r.Querier.Exec: %w", mapError(err))
+	}
+
+	return nil
+}
+
+// ---------------- Coupon ----------------
+
+// CreateCoupon -.
+func (r *ProductRepo) CreateCoupon(ctx context.Context, c entity.Coupon) (string, error) {
+	sql, args, err := r.Builder.
+		Insert("coupon").
+		Columns("promotion_id, code, usage_limit, usage_limit_per_user").
+		Values(c.PromotionID, c.Code, nullIfZero(c.UsageLimit), nullIfZero(c.UsageLimitPerUser)).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("ProductRepo - CreateCoupon - r.Builder: %w", err)
+	}
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&c.ID)
+	if err != nil {
+		return "", fmt.Errorf("ProductRepo - CreateCoupon - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return c.ID, nil
+}
+
+// GetCoupon returns a coupon of a promotion.
+func (r *ProductRepo) GetCoupon(ctx context.Context, promotionID, id string) (entity.Coupon, error) {
+	coupons, err := r.queryCoupons(ctx, squirrel.Eq{"id": id, "promotion_id": promotionID})
+	if err != nil {
+		return entity.Coupon{}, fmt.Errorf("ProductRepo - GetCoupon - %w", err)
+	}
+
+	if len(coupons) == 0 {
+		return entity.Coupon{}, fmt.Errorf("ProductRepo - GetCoupon: %w", entity.ErrNotFound)
+	}
+
+	return coupons[0], nil
+}
+
+// GetCouponsByPromotion -.
+func (r *ProductRepo) GetCouponsByPromotion(ctx context.Context, promotionID string) ([]entity.Coupon, error) {
+	coupons, err := r.queryCoupons(ctx, squirrel.Eq{"promotion_id": promotionID})
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetCouponsByPromotion - %w", err)
+	}
+
+	return coupons, nil
+}
+
+// GetCouponsByCodes returns the coupons with the given normalized codes.
+func (r *ProductRepo) GetCouponsByCodes(ctx context.Context, codes []string) ([]entity.Coupon, error) {
+	coupons, err := r.queryCoupons(ctx, squirrel.Eq{"code": codes})
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetCouponsByCodes - %w", err)
+	}
+
+	return coupons, nil
+}
+
+func (r *ProductRepo) queryCoupons(ctx context.Context, where squirrel.Sqlizer) ([]entity.Coupon, error) {
+	sql, args, err := r.Builder.
+		Select(_couponColumns).
+		From("coupon").
+		Where(where).
+		OrderBy("created_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	coupons := make([]entity.Coupon, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var c entity.Coupon
+
+		err = rows.Scan(&c.ID, &c.PromotionID, &c.Code, &c.UsageLimit, &c.UsageLimitPerUser, &c.UsedCount, &c.CreatedAt, &c.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		coupons = append(coupons, c)
+	}
+
+	return coupons, nil
+}
+
+// DeleteCoupon -.
+func (r *ProductRepo) DeleteCoupon(ctx context.Context, promotionID, id string) error {
+	sql, args, err := r.Builder.
+		Delete("coupon").
+		Where("id = ?", id).
+		Where("promotion_id = ?", promotionID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteCoupon - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteCoupon - r.Querier.Exec: %w", mapDeleteError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteCoupon - checkAffected: %w", err)
+	}
+
+	return nil
+}
+
+// ---------------- OrderDiscount ----------------
+
+// CreateOrderDiscount -.
+func (r *ProductRepo) CreateOrderDiscount(ctx context.Context, d entity.OrderDiscount) error {
+	sql, args, err := r.Builder.
+		Insert("order_discount").
+		Columns("order_id, promotion_id, coupon_code, name, description, amount").
+		Values(d.OrderID, nullIfEmpty(d.PromotionID), d.CouponCode, d.Name, d.Description, d.Amount).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - CreateOrderDiscount - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - CreateOrderDiscount - r.Querier.Exec: %w", mapError(err))
+	}
+
+	return nil
+}
+
+// GetOrderDiscounts returns the discounts applied to an order in the order
+// they were applied.
+func (r *ProductRepo) GetOrderDiscounts(ctx context.Context, orderID string) ([]entity.OrderDiscount, error) {
+	sql, args, err := r.Builder.
+		Select("id, order_id, COALESCE(promotion_id::text, ''), coupon_code, name, description, amount, created_at").
+		From("order_discount").
+		Where("order_id = ?", orderID).
+		OrderBy("created_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetOrderDiscounts - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetOrderDiscounts - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	discounts := make([]entity.OrderDiscount, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var d entity.OrderDiscount
+
+		err = rows.Scan(&d.ID, &d.OrderID, &d.PromotionID, &d.CouponCode, &d.Name, &d.Description, &d.Amount, &d.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ProductRepo - GetOrderDiscounts - rows.Scan: %w", err)
+		}
+
+		discounts = append(discounts, d)
+	}
+
+	return discounts, nil
+}
+
+// uuidArray passes ids as a UUID[] parameter; nil becomes an empty array.
+func uuidArray(ids []string) squirrel.Sqlizer {
+	if ids == nil {
+		ids = []string{}
+	}
+
+	return squirrel.Expr("?::text[]::uuid[]", ids)
+}
+
+func nullIfZero(n int) interface{} {
+	if n == 0 {
+		return nil
+	}
+
+	return n
+}
@@ -0,0 +1,201 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+
+	"ai-seller/internal/entity"
+)
+
+const _reservationColumns = "id, order_id, product_id, COALESCE(variant_id::text, ''), count, status, expires_at, created_at, updated_at"
+
+// CreateReservation -.
+func (r *ProductRepo) CreateReservation(ctx context.Context, s entity.StockReservation) error {
+	sql, args, err := r.Builder.
+		Insert("stock_reservation").
+		Columns("order_id, product_id, variant_id, count, expires_at").
+		Values(s.OrderID, s.ProductID, nullIfEmpty(s.VariantID), s.Count, s.ExpiresAt).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - CreateReservation - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - CreateReservation - r.Querier.Exec: %w", mapError(err))
+	}
+
+	return nil
+}
+
+// GetReservationsByOrder -.
+func (r *ProductRepo) GetReservationsByOrder(ctx context.Context, orderID string) ([]entity.StockReservation, error) {
+	sql, args, err := r.Builder.
+		Select(_reservationColumns).
+		From("stock_reservation").
+		Where("order_id = ?", orderID).
+		OrderBy("created_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetReservationsByOrder - r.Builder: %w", err)
+	}
+
+	reservations, err := r.queryReservations(ctx, sql, args)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetReservationsByOrder - %w", err)
+	}
+
+	return reservations, nil
+}
+
+// UpdateReservationStatus moves the reservations of an order in status from
+// to status to and returns them.
+func (r *ProductRepo) UpdateReservationStatus(ctx context.Context, orderID, from, to string) ([]entity.StockReservation, error) {
+	sql, args, err := r.Builder.
+		Update("stock_reservation").
+		Set("status", to).
+		Where("order_id = ?", orderID).
+		Where("status = ?", from).
+		Suffix("RETURNING " + _reservationColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - UpdateReservationStatus - r.Builder: %w", err)
+	}
+
+	reservations, err := r.queryReservations(ctx, sql, args)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - UpdateReservationStatus - %w", err)
+	}
+
+	return reservations, nil
+}
+
+// GetExpiredReservationOrders returns up to limit orders holding active
+// reservations that have expired.
+func (r *ProductRepo) GetExpiredReservationOrders(ctx context.Context, limit int) ([]string, error) {
+	sql, args, err := r.Builder.
+		Select("DISTINCT order_id").
+		From("stock_reservation").
+		Where("status = ?", entity.ReservationActive).
+		Where("expires_at <= CURRENT_TIMESTAMP").
+		Limit(uint64(limit)). //nolint:gosec // limit is a small constant
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetExpiredReservationOrders - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetExpiredReservationOrders - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0, limit)
+
+	for rows.Next() {
+		var id string
+
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, fmt.Errorf("ProductRepo - GetExpiredReservationOrders - rows.Scan: %w", err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (r *ProductRepo) queryReservations(ctx context.Context, sql string, args []interface{}) ([]entity.StockReservation, error) {
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	reservations := make([]entity.StockReservation, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var s entity.StockReservation
+
+		err = rows.Scan(&s.ID, &s.OrderID, &s.ProductID, &s.VariantID, &s.Count, &s.Status, &s.ExpiresAt, &s.CreatedAt, &s.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		reservations = append(reservations, s)
+	}
+
+	return reservations, nil
+}
+
+// ReserveStock sets count items of a product, or of one of its variants,
+// aside. A variant's reservations also count against its product.
+func (r *ProductRepo) ReserveStock(ctx context.Context, productID, variantID string, count int) error {
+	if variantID != "" {
+		err := r.addReserved(ctx, "product_variant", variantID, count)
+		if err != nil {
+			return fmt.Errorf("ProductRepo - ReserveStock - %w", err)
+		}
+	}
+
+	err := r.addReserved(ctx, "product", productID, count)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - ReserveStock - %w", err)
+	}
+
+	return nil
+}
+
+// UnreserveStock gives back what ReserveStock set aside.
+func (r *ProductRepo) UnreserveStock(ctx context.Context, productID, variantID string, count int) error {
+	if variantID != "" {
+		err := r.addReserved(ctx, "product_variant", variantID, -count)
+		if err != nil {
+			return fmt.Errorf("ProductRepo - UnreserveStock - %w", err)
+		}
+	}
+
+	err := r.addReserved(ctx, "product", productID, -count)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UnreserveStock - %w", err)
+	}
+
+	return nil
+}
+
+// addReserved changes the reserved count of a row. Reserving more than is
+// available is ErrInsufficientStock.
+func (r *ProductRepo) addReserved(ctx context.Context, table, id string, delta int) error {
+	update := r.Builder.
+		Update(table).
+		Set("reserved", squirrel.Expr("GREATEST(reserved + ?, 0)", delta)).
+		Where("id = ?", id)
+
+	if delta > 0 {
+		update = update.Where("count - reserved >= ?", delta)
+	}
+
+	sql, args, err := update.ToSql()
+	if err != nil {
+		return fmt.Errorf("r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("r.Querier.Exec: %w", mapError(err))
+	}
+
+	if tag.RowsAffected() == 0 && delta > 0 {
+		return fmt.Errorf("%s %s: %w", table, id, entity.ErrInsufficientStock)
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("checkAffected: %w", err)
+	}
+
+	return nil
+}
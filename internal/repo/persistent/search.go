@@ -0,0 +1,281 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+
+	"ai-seller/internal/entity"
+)
+
+const (
+	// _searchQuery parses the query with every configuration of
+	// product.search_vector, so that stemmed and plain words both match.
+	_searchQuery = "CROSS JOIN (SELECT to_tsquery('simple', ?) || to_tsquery('russian', ?) || to_tsquery('english', ?) AS tsq) q"
+
+	// _highlightOptions wrap matches in <mark> tags; long texts are cut to
+	// the fragments around them.
+	_highlightOptions = "StartSel=<mark>, StopSel=</mark>, HighlightAll=true"
+	_fragmentOptions  = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5"
+
+	_defaultSuggestLimit = 10
+)
+
+var _searchSortColumns = []string{"rank"}
+
+// SearchProducts ranks products by full text match of words and by trigram
+// similarity of the raw query to the name, which tolerates typos. words
+// holds one group of alternatives (a word and its synonyms) per query word.
+func (r *ProductRepo) SearchProducts(ctx context.Context, s entity.ProductSearch, words [][]string) (entity.Page[entity.SearchHit], error) {
+	var result entity.Page[entity.SearchHit]
+
+	pg, err := newPage(entity.ListParams{Cursor: s.Cursor, Limit: s.Limit}, _searchSortColumns)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - SearchProducts - newPage: %w", err)
+	}
+
+	tsq := tsQuery(words)
+
+	where := squirrel.And{squirrel.Expr(_notDeleted), squirrel.Or{
+		squirrel.Expr("search_vector @@ q.tsq"),
+		squirrel.Expr("name %> ?", s.Query),
+	}}
+
+	if s.CategoryID != "" {
+		where = append(where, squirrel.Eq{"category_id": s.CategoryID})
+	}
+
+	matches := r.Builder.
+		Select("product.*", "q.tsq").
+		Column("(ts_rank_cd(search_vector, q.tsq) + word_similarity(?, name))::float8 AS rank", s.Query).
+		From("product").
+		JoinClause(_searchQuery, tsq, tsq, tsq).
+		Where(where)
+
+	sql, args, err := r.Builder.Select("COUNT(*)").FromSelect(matches, "m").ToSql()
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - SearchProducts - r.Builder: %w", err)
+	}
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&result.Total)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - SearchProducts - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	// Headlines are costly, so they are only built for the rows of the page.
+	page := pg.apply(r.Builder.Select("*").FromSelect(matches, "m"), "")
+
+	sql, args, err = r.Builder.
+		Select(_productColumns, "rank").
+		Column(fmt.Sprintf("ts_headline('simple', name, tsq, '%s')", _highlightOptions)).
+		Column(fmt.Sprintf("ts_headline('simple', COALESCE(short_info, ''), tsq, '%s')", _highlightOptions)).
+		Column(fmt.Sprintf("ts_headline('simple', COALESCE(description, ''), tsq, '%s')", _fragmentOptions)).
+		FromSelect(page, "p").
+		OrderBy("rank DESC", "id DESC").
+		ToSql()
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - SearchProducts - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - SearchProducts - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	hits := make([]entity.SearchHit, 0, pg.limit+1)
+
+	for rows.Next() {
+		var (
+			h entity.SearchHit
+			p = &h.Product
+		)
+
+		err = rows.Scan(&p.ID, &p.Name, &p.SKU, &p.CategoryID, &p.ShortInfo, &p.Description, &p.Cost, &p.Count, &p.Available, &p.DiscountCost, &p.Discount, &p.CreatedAt, &p.UpdatedAt, &p.Version,
+			&h.Rank, &h.Highlight.Name, &h.Highlight.ShortInfo, &h.Highlight.Description)
+		if err != nil {
+			return result, fmt.Errorf("ProductRepo - SearchProducts - rows.Scan: %w", err)
+		}
+
+		hits = append(hits, h)
+	}
+
+	result.Items, result.NextCursor, err = trim(pg, hits, func(h entity.SearchHit, _ string) (interface{}, string) {
+		return h.Rank, h.Product.ID
+	})
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - SearchProducts - trim: %w", err)
+	}
+
+	return result, nil
+}
+
+// tsQuery joins the alternatives of a word with | and the words with &.
+// Every alternative matches as a prefix, so the last, unfinished word of a
+// query still finds results. Words must be letters, digits and plain
+// apostrophes. The text search parser splits words at apostrophes, so
+// "ko'ylak" matches as the phrase ko <-> ylak.
+func tsQuery(words [][]string) string {
+	groups := make([]string, 0, len(words))
+
+	for _, alternatives := range words {
+		terms := make([]string, 0, len(alternatives))
+
+		for _, a := range alternatives {
+			parts := strings.FieldsFunc(a, func(r rune) bool { return r == '\'' })
+			if len(parts) > 0 {
+				terms = append(terms, strings.Join(parts, " <-> ")+":*")
+			}
+		}
+
+		groups = append(groups, "("+strings.Join(terms, " | ")+")")
+	}
+
+	return strings.Join(groups, " & ")
+}
+
+// SuggestProducts returns product names starting with prefix, then names
+// containing a word similar to it.
+func (r *ProductRepo) SuggestProducts(ctx context.Context, prefix string, limit int) ([]string, error) {
+	if limit <= 0 || limit > _maxPageLimit {
+		limit = _defaultSuggestLimit
+	}
+
+	sql, args, err := r.Builder.
+		Select("name").
+		From("product").
+		Where(squirrel.Or{
+			squirrel.ILike{"name": escapeLike(prefix) + "%"},
+			squirrel.Expr("name %> ?", prefix),
+		}).
+		Where(_notDeleted).
+		GroupBy("name").
+		OrderByClause("name ILIKE ? DESC, word_similarity(?, name) DESC, name", escapeLike(prefix)+"%", prefix).
+		Limit(uint64(limit)). //nolint:gosec // limit is bounded by _maxPageLimit
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - SuggestProducts - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - SuggestProducts - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	names := make([]string, 0, limit)
+
+	for rows.Next() {
+		var name string
+
+		err = rows.Scan(&name)
+		if err != nil {
+			return nil, fmt.Errorf("ProductRepo - SuggestProducts - rows.Scan: %w", err)
+		}
+
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// GetSynonyms returns the synonym pairs that contain any of words on either
+// side.
+func (r *ProductRepo) GetSynonyms(ctx context.Context, words []string) ([]entity.SearchSynonym, error) {
+	sql, args, err := r.Builder.
+		Select("term", "synonym", "created_at").
+		From("search_synonym").
+		Where(squirrel.Or{squirrel.Eq{"term": words}, squirrel.Eq{"synonym": words}}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetSynonyms - r.Builder: %w", err)
+	}
+
+	return r.querySynonyms(ctx, "GetSynonyms", sql, args)
+}
+
+// ListSynonyms -.
+func (r *ProductRepo) ListSynonyms(ctx context.Context) ([]entity.SearchSynonym, error) {
+	sql, args, err := r.Builder.
+		Select("term", "synonym", "created_at").
+		From("search_synonym").
+		OrderBy("term", "synonym").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - ListSynonyms - r.Builder: %w", err)
+	}
+
+	return r.querySynonyms(ctx, "ListSynonyms", sql, args)
+}
+
+func (r *ProductRepo) querySynonyms(ctx context.Context, method, sql string, args []interface{}) ([]entity.SearchSynonym, error) {
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - %s - r.Querier.Query: %w", method, mapError(err))
+	}
+	defer rows.Close()
+
+	synonyms := make([]entity.SearchSynonym, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var s entity.SearchSynonym
+
+		err = rows.Scan(&s.Term, &s.Synonym, &s.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ProductRepo - %s - rows.Scan: %w", method, err)
+		}
+
+		synonyms = append(synonyms, s)
+	}
+
+	return synonyms, nil
+}
+
+// CreateSynonym -.
+func (r *ProductRepo) CreateSynonym(ctx context.Context, s entity.SearchSynonym) (entity.SearchSynonym, error) {
+	sql, args, err := r.Builder.
+		Insert("search_synonym").
+		Columns("term", "synonym").
+		Values(s.Term, s.Synonym).
+		Suffix("RETURNING created_at").
+		ToSql()
+	if err != nil {
+		return s, fmt.Errorf("ProductRepo - CreateSynonym - r.Builder: %w", err)
+	}
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&s.CreatedAt)
+	if err != nil {
+		return s, fmt.Errorf("ProductRepo - CreateSynonym - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return s, nil
+}
+
+// DeleteSynonym -.
+func (r *ProductRepo) DeleteSynonym(ctx context.Context, term, synonym string) error {
+	sql, args, err := r.Builder.
+		Delete("search_synonym").
+		Where(squirrel.Eq{"term": term, "synonym": synonym}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteSynonym - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteSynonym - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteSynonym - checkAffected: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package persistent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTSQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		words [][]string
+		want  string
+	}{
+		{name: "no words", words: nil, want: ""},
+		{name: "one word", words: [][]string{{"shirt"}}, want: "(shirt:*)"},
+		{name: "words", words: [][]string{{"red"}, {"shirt"}}, want: "(red:*) & (shirt:*)"},
+		{name: "apostrophe", words: [][]string{{"ko'ylak"}}, want: "(ko <-> ylak:*)"},
+		{name: "doubled apostrophe", words: [][]string{{"ko''ylak"}}, want: "(ko <-> ylak:*)"},
+		{
+			name:  "synonyms",
+			words: [][]string{{"red", "crimson"}, {"shirt", "tee", "футболка"}},
+			want:  "(red:* | crimson:*) & (shirt:* | tee:* | футболка:*)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, tsQuery(tc.words))
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+
+	"ai-seller/internal/entity"
+)
+
+// _notDeleted keeps the rows of a soft-deleted table that are not in the trash.
+const _notDeleted = "deleted_at IS NULL"
+
+// _trashSortColumns lists the columns a trash listing can be sorted by.
+var _trashSortColumns = []string{"deleted_at", "name"}
+
+// _softDeleted lists the tables whose deleted rows are kept in the trash.
+var _softDeleted = map[string]bool{"product": true, "category": true, `"user"`: true}
+
+// live hides the rows of table that are in the trash.
+func live(table string) squirrel.Sqlizer {
+	if !_softDeleted[table] {
+		return squirrel.And{}
+	}
+
+	return squirrel.Expr(_notDeleted)
+}
+
+// ---------------- Product ----------------
+
+// ListDeletedProducts returns the products in the trash, most recently
+// deleted first.
+func (r *ProductRepo) ListDeletedProducts(ctx context.Context, p entity.ListParams) (entity.Page[entity.Product], error) {
+	var result entity.Page[entity.Product]
+
+	pg, err := newPage(p, _trashSortColumns)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListDeletedProducts - newPage: %w", err)
+	}
+
+	where := squirrel.Expr("deleted_at IS NOT NULL")
+
+	result.Total, err = r.count(ctx, "product", where)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListDeletedProducts - r.count: %w", err)
+	}
+
+	sql, args, err := pg.apply(r.Builder.Select(_productColumns+", deleted_at").From("product").Where(where), "").ToSql()
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListDeletedProducts - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListDeletedProducts - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	items := make([]entity.Product, 0, pg.limit+1)
+
+	for rows.Next() {
+		var p entity.Product
+
+		err = rows.Scan(&p.ID, &p.Name, &p.SKU, &p.CategoryID, &p.ShortInfo, &p.Description, &p.Cost, &p.Count, &p.Available, &p.DiscountCost, &p.Discount, &p.CreatedAt, &p.UpdatedAt, &p.Version, &p.DeletedAt)
+		if err != nil {
+			return result, fmt.Errorf("ProductRepo - ListDeletedProducts - rows.Scan: %w", err)
+		}
+
+		items = append(items, p)
+	}
+
+	result.Items, result.NextCursor, err = trim(pg, items, func(p entity.Product, column string) (interface{}, string) {
+		if column == "name" {
+			return p.Name, p.ID
+		}
+
+		return *p.DeletedAt, p.ID
+	})
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListDeletedProducts - trim: %w", err)
+	}
+
+	return result, nil
+}
+
+// RestoreProduct takes a product out of the trash. A product not in the trash
+// is ErrNotFound; one whose SKU was taken in the meantime is ErrConflict.
+func (r *ProductRepo) RestoreProduct(ctx context.Context, id string) (entity.Product, error) {
+	sql, args, err := r.Builder.
+		Update("product").
+		Set("deleted_at", nil).
+		Where("id = ?", id).
+		Where("deleted_at IS NOT NULL").
+		Suffix("RETURNING " + _productColumns).
+		ToSql()
+	if err != nil {
+		return entity.Product{}, fmt.Errorf("ProductRepo - RestoreProduct - r.Builder: %w", err)
+	}
+
+	var p entity.Product
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&p.ID, &p.Name, &p.SKU, &p.CategoryID, &p.ShortInfo, &p.Description, &p.Cost, &p.Count, &p.Available, &p.DiscountCost, &p.Discount, &p.CreatedAt, &p.UpdatedAt, &p.Version)
+	if err != nil {
+		return entity.Product{}, fmt.Errorf("ProductRepo - RestoreProduct - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return p, nil
+}
+
+// GetPurgeableProducts returns up to limit products deleted before the given
+// time that no order refers to, oldest first.
+func (r *ProductRepo) GetPurgeableProducts(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	sql, args, err := r.Builder.
+		Select("id").
+		From("product p").
+		Where("p.deleted_at < ?", before).
+		Where("NOT EXISTS (SELECT 1 FROM order_products op WHERE op.product_id = p.id)").
+		OrderBy("p.deleted_at", "p.id").
+		Limit(uint64(limit)). //nolint:gosec // limit is a positive constant of the caller
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetPurgeableProducts - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetPurgeableProducts - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var id string
+
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, fmt.Errorf("ProductRepo - GetPurgeableProducts - rows.Scan: %w", err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// PurgeProduct removes a product in the trash for good, together with its
+// variants, media records, prices and stock. A product still referenced by an
+// order is ErrInUse.
+func (r *ProductRepo) PurgeProduct(ctx context.Context, id string) error {
+	sql, args, err := r.Builder.
+		Delete("product").
+		Where("id = ?", id).
+		Where("deleted_at IS NOT NULL").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - PurgeProduct - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - PurgeProduct - r.Querier.Exec: %w", mapDeleteError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - PurgeProduct - checkAffected: %w", err)
+	}
+
+	return nil
+}
+
+// ---------------- Category ----------------
+
+// ListDeletedCategories returns the categories in the trash, most recently
+// deleted first.
+func (r *ProductRepo) ListDeletedCategories(ctx context.Context, p entity.ListParams) (entity.Page[entity.Category], error) {
+	var result entity.Page[entity.Category]
+
+	pg, err := newPage(p, _trashSortColumns)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListDeletedCategories - newPage: %w", err)
+	}
+
+	where := squirrel.Expr("deleted_at IS NOT NULL")
+
+	result.Total, err = r.count(ctx, "category", where)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListDeletedCategories - r.count: %w", err)
+	}
+
+	sql, args, err := pg.apply(r.Builder.Select(_categoryColumns+", deleted_at").From("category").Where(where), "").ToSql()
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListDeletedCategories - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListDeletedCategories - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	items := make([]entity.Category, 0, pg.limit+1)
+
+	for rows.Next() {
+		var c entity.Category
+
+		err = rows.Scan(&c.ID, &c.Name, &c.ParentID, &c.Position, &c.CreatedAt, &c.UpdatedAt, &c.Version, &c.DeletedAt)
+		if err != nil {
+			return result, fmt.Errorf("ProductRepo - ListDeletedCategories - rows.Scan: %w", err)
+		}
+
+		items = append(items, c)
+	}
+
+	result.Items, result.NextCursor, err = trim(pg, items, func(c entity.Category, column string) (interface{}, string) {
+		if column == "name" {
+			return c.Name, c.ID
+		}
+
+		return *c.DeletedAt, c.ID
+	})
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListDeletedCategories - trim: %w", err)
+	}
+
+	return result, nil
+}
+
+// RestoreCategory takes a category out of the trash and places it after its
+// siblings. A category not in the trash is ErrNotFound; one whose name was
+// taken among its siblings in the meantime is ErrConflict.
+func (r *ProductRepo) RestoreCategory(ctx context.Context, id string) (entity.Category, error) {
+	sql, args, err := r.Builder.
+		Update("category").
+		Set("deleted_at", nil).
+		Set("position", squirrel.Expr("(SELECT COALESCE(MAX(s.position) + 1, 0) FROM category s "+
+			"WHERE s.parent_id IS NOT DISTINCT FROM category.parent_id AND s.deleted_at IS NULL)")).
+		Where("id = ?", id).
+		Where("deleted_at IS NOT NULL").
+		Suffix("RETURNING " + _categoryColumns).
+		ToSql()
+	if err != nil {
+		return entity.Category{}, fmt.Errorf("ProductRepo - RestoreCategory - r.Builder: %w", err)
+	}
+
+	var c entity.Category
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&c.ID, &c.Name, &c.ParentID, &c.Position, &c.CreatedAt, &c.UpdatedAt, &c.Version)
+	if err != nil {
+		return entity.Category{}, fmt.Errorf("ProductRepo - RestoreCategory - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return c, nil
+}
+
+// PurgeCategories removes the categories deleted before the given time that
+// no product, attribute or other category refers to any more, and returns
+// how many were removed.
+func (r *ProductRepo) PurgeCategories(ctx context.Context, before time.Time) (int, error) {
+	sql, args, err := r.Builder.
+		Delete("category c").
+		Where("c.deleted_at < ?", before).
+		Where("NOT EXISTS (SELECT 1 FROM product p WHERE p.category_id = c.id)").
+		Where("NOT EXISTS (SELECT 1 FROM attribute a WHERE a.category_id = c.id)").
+		Where("NOT EXISTS (SELECT 1 FROM category s WHERE s.parent_id = c.id)").
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("ProductRepo - PurgeCategories - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("ProductRepo - PurgeCategories - r.Querier.Exec: %w", mapDeleteError(err))
+	}
+
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,268 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+
+	"ai-seller/internal/entity"
+)
+
+const _variantColumns = "id, product_id, sku, COALESCE(barcode, ''), cost, COALESCE(discount_cost, 0), COALESCE(discount, 0), count, GREATEST(count - reserved, 0), created_at, updated_at, version"
+
+// CreateVariant -.
+func (r *ProductRepo) CreateVariant(ctx context.Context, v entity.ProductVariant) (string, error) {
+	sql, args, err := r.Builder.
+		Insert("product_variant").
+		Columns("product_id, sku, barcode, cost, discount_cost, discount").
+		Values(v.ProductID, v.SKU, nullIfEmpty(v.Barcode), v.Cost, v.DiscountCost, v.Discount).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("ProductRepo - CreateVariant - r.Builder: %w", err)
+	}
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&v.ID)
+	if err != nil {
+		return "", fmt.Errorf("ProductRepo - CreateVariant - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return v.ID, nil
+}
+
+// GetVariant returns a variant of a product with its options.
+func (r *ProductRepo) GetVariant(ctx context.Context, productID, id string) (entity.ProductVariant, error) {
+	variants, err := r.queryVariants(ctx, squirrel.Eq{"id": id, "product_id": productID}, false)
+	if err != nil {
+		return entity.ProductVariant{}, fmt.Errorf("ProductRepo - GetVariant - %w", err)
+	}
+
+	if len(variants) == 0 {
+		return entity.ProductVariant{}, fmt.Errorf("ProductRepo - GetVariant: %w", entity.ErrNotFound)
+	}
+
+	return variants[0], nil
+}
+
+// GetVariantsByProduct returns the variants of a product with their options.
+func (r *ProductRepo) GetVariantsByProduct(ctx context.Context, productID string) ([]entity.ProductVariant, error) {
+	variants, err := r.queryVariants(ctx, squirrel.Eq{"product_id": productID}, false)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetVariantsByProduct - %w", err)
+	}
+
+	return variants, nil
+}
+
+// GetVariantsByProducts returns the variants of the given products, without
+// locking them.
+func (r *ProductRepo) GetVariantsByProducts(ctx context.Context, productIDs []string) ([]entity.ProductVariant, error) {
+	variants, err := r.queryVariants(ctx, squirrel.Eq{"product_id": productIDs}, false)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetVariantsByProducts - %w", err)
+	}
+
+	return variants, nil
+}
+
+// LockVariantsByProducts selects the variants of the products FOR UPDATE, in
+// id order like LockProducts. Must run in a transaction.
+func (r *ProductRepo) LockVariantsByProducts(ctx context.Context, productIDs []string) ([]entity.ProductVariant, error) {
+	variants, err := r.queryVariants(ctx, squirrel.Eq{"product_id": productIDs}, true)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - LockVariantsByProducts - %w", err)
+	}
+
+	return variants, nil
+}
+
+func (r *ProductRepo) queryVariants(ctx context.Context, where squirrel.Sqlizer, lock bool) ([]entity.ProductVariant, error) {
+	builder := r.Builder.
+		Select(_variantColumns).
+		From("product_variant").
+		Where(where)
+
+	if lock {
+		builder = builder.OrderBy("id").Suffix("FOR UPDATE")
+	} else {
+		builder = builder.OrderBy("created_at", "id")
+	}
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	variants := make([]entity.ProductVariant, 0, _defaultEntityCap)
+	ids := make([]string, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var v entity.ProductVariant
+
+		err = rows.Scan(&v.ID, &v.ProductID, &v.SKU, &v.Barcode, &v.Cost, &v.DiscountCost, &v.Discount, &v.Count, &v.Available, &v.CreatedAt, &v.UpdatedAt, &v.Version)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		v.Options = []entity.VariantOption{}
+		variants = append(variants, v)
+		ids = append(ids, v.ID)
+	}
+
+	rows.Close()
+
+	if len(ids) == 0 {
+		return variants, nil
+	}
+
+	options, err := r.variantOptions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range variants {
+		variants[i].Options = append(variants[i].Options, options[variants[i].ID]...)
+	}
+
+	return variants, nil
+}
+
+// variantOptions returns the options of the variants keyed by variant id.
+func (r *ProductRepo) variantOptions(ctx context.Context, variantIDs []string) (map[string][]entity.VariantOption, error) {
+	sql, args, err := r.Builder.
+		Select("o.variant_id", "o.attribute_id", "a.name", "o.value").
+		From("product_variant_option o").
+		Join("attribute a ON a.id = o.attribute_id").
+		Where(squirrel.Eq{"o.variant_id": variantIDs}).
+		OrderBy("a.name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	options := make(map[string][]entity.VariantOption, len(variantIDs))
+
+	for rows.Next() {
+		var (
+			variantID string
+			o         entity.VariantOption
+		)
+
+		err = rows.Scan(&variantID, &o.AttributeID, &o.Name, &o.Value)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		options[variantID] = append(options[variantID], o)
+	}
+
+	return options, nil
+}
+
+// UpdateVariant -.
+func (r *ProductRepo) UpdateVariant(ctx context.Context, v entity.ProductVariant) error {
+	sql, args, err := r.Builder.
+		Update("product_variant").
+		Set("sku", v.SKU).
+		Set("barcode", nullIfEmpty(v.Barcode)).
+		Set("cost", v.Cost).
+		Set("discount_cost", v.DiscountCost).
+		Set("discount", v.Discount).
+		Where("id = ?", v.ID).
+		Where("product_id = ?", v.ProductID).
+		Where(matchVersion(v.Version)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdateVariant - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdateVariant - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkVersion(ctx, r.Postgres, "product_variant", v.ID, v.Version, tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdateVariant - checkVersion: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceVariantOptions sets the options of a variant.
+func (r *ProductRepo) ReplaceVariantOptions(ctx context.Context, variantID string, options []entity.VariantOption) error {
+	sql, args, err := r.Builder.
+		Delete("product_variant_option").
+		Where("variant_id = ?", variantID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - ReplaceVariantOptions - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - ReplaceVariantOptions - r.Querier.Exec: %w", mapError(err))
+	}
+
+	if len(options) == 0 {
+		return nil
+	}
+
+	insert := r.Builder.
+		Insert("product_variant_option").
+		Columns("variant_id", "attribute_id", "value")
+
+	for _, o := range options {
+		insert = insert.Values(variantID, o.AttributeID, o.Value)
+	}
+
+	sql, args, err = insert.ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - ReplaceVariantOptions - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - ReplaceVariantOptions - r.Querier.Exec: %w", mapError(err))
+	}
+
+	return nil
+}
+
+// DeleteVariant -.
+func (r *ProductRepo) DeleteVariant(ctx context.Context, productID, id string, version int) error {
+	sql, args, err := r.Builder.
+		Delete("product_variant").
+		Where("id = ?", id).
+		Where("product_id = ?", productID).
+		Where(matchVersion(version)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteVariant - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteVariant - r.Querier.Exec: %w", mapDeleteError(err))
+	}
+
+	err = checkVersion(ctx, r.Postgres, "product_variant", id, version, tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteVariant - checkVersion: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"ai-seller/internal/entity"
+	"ai-seller/pkg/postgres"
+)
+
+// matchVersion guards a statement with the row version the caller last saw.
+// Version 0 means the caller does not care.
+func matchVersion(version int) squirrel.Sqlizer {
+	if version == 0 {
+		return squirrel.And{}
+	}
+
+	return squirrel.Eq{"version": version}
+}
+
+// checkVersion is checkAffected for statements guarded by matchVersion: when
+// no row was touched it tells a stale version from a missing row. A row in
+// the trash is missing.
+func checkVersion(ctx context.Context, pg *postgres.Postgres, table, id string, version int, tag pgconn.CommandTag) error {
+	if tag.RowsAffected() > 0 {
+		return nil
+	}
+
+	if version == 0 {
+		return entity.ErrNotFound
+	}
+
+	sql, args, err := pg.Builder.
+		Select("1").
+		Prefix("SELECT EXISTS (").
+		From(table).
+		Where("id = ?", id).
+		Where(live(table)).
+		Suffix(")").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("r.Builder: %w", err)
+	}
+
+	var exists bool
+
+	err = pg.Querier(ctx).QueryRow(ctx, sql, args...).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	if exists {
+		return entity.ErrPreconditionFailed
+	}
+
+	return entity.ErrNotFound
+}
@@ -0,0 +1,72 @@
+package persistent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/internal/entity"
+	"ai-seller/pkg/postgres"
+)
+
+func TestMatchVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		version int
+		sql     string
+		args    []interface{}
+	}{
+		{name: "any", version: 0, sql: "(1=1)", args: []interface{}{}},
+		{name: "version", version: 3, sql: "version = ?", args: []interface{}{3}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			sql, args, err := matchVersion(tc.version).ToSql()
+			require.NoError(t, err)
+			require.Equal(t, tc.sql, sql)
+			require.Equal(t, tc.args, args)
+		})
+	}
+}
+
+// TestCheckVersion covers the outcomes that need no lookup; telling a stale
+// version from a missing row queries the table.
+func TestCheckVersion(t *testing.T) {
+	t.Parallel()
+
+	pg := &postgres.Postgres{Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)}
+
+	tests := []struct {
+		name    string
+		version int
+		tag     pgconn.CommandTag
+		err     error
+	}{
+		{name: "updated", version: 3, tag: pgconn.NewCommandTag("UPDATE 1"), err: nil},
+		{name: "updated any version", version: 0, tag: pgconn.NewCommandTag("UPDATE 1"), err: nil},
+		{name: "missing without version", version: 0, tag: pgconn.NewCommandTag("UPDATE 0"), err: entity.ErrNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkVersion(context.Background(), pg, "product", "id", tc.version, tc.tag)
+			if tc.err == nil {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
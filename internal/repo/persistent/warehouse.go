@@ -0,0 +1,383 @@
+package persistent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"ai-seller/internal/entity"
+)
+
+const (
+	_warehouseColumns = "id, code, name, COALESCE(address, ''), is_default, created_at, updated_at"
+
+	_stockMovementColumns = "id, warehouse_id, product_id, COALESCE(variant_id::text, ''), type, quantity, COALESCE(reason, ''), " +
+		"COALESCE(order_id::text, ''), COALESCE(transfer_id::text, ''), COALESCE(comment, ''), COALESCE(created_by::text, ''), created_at"
+
+	// _stockLevelCheck is the constraint keeping stock levels from going
+	// negative.
+	_stockLevelCheck = "stock_level_count_check"
+)
+
+var _stockMovementSortColumns = []string{"created_at"}
+
+// ---------------- Warehouse ----------------
+
+// CreateWarehouse -.
+func (r *ProductRepo) CreateWarehouse(ctx context.Context, w entity.Warehouse) (entity.Warehouse, error) {
+	sql, args, err := r.Builder.
+		Insert("warehouse").
+		Columns("code, name, address").
+		Values(w.Code, w.Name, nullIfEmpty(w.Address)).
+		Suffix("RETURNING " + _warehouseColumns).
+		ToSql()
+	if err != nil {
+		return entity.Warehouse{}, fmt.Errorf("ProductRepo - CreateWarehouse - r.Builder: %w", err)
+	}
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).
+		Scan(&w.ID, &w.Code, &w.Name, &w.Address, &w.Default, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		return entity.Warehouse{}, fmt.Errorf("ProductRepo - CreateWarehouse - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return w, nil
+}
+
+// GetWarehouse -.
+func (r *ProductRepo) GetWarehouse(ctx context.Context, id string) (entity.Warehouse, error) {
+	warehouses, err := r.queryWarehouses(ctx, squirrel.Eq{"id": id})
+	if err != nil {
+		return entity.Warehouse{}, fmt.Errorf("ProductRepo - GetWarehouse - %w", err)
+	}
+
+	if len(warehouses) == 0 {
+		return entity.Warehouse{}, fmt.Errorf("ProductRepo - GetWarehouse: %w", entity.ErrNotFound)
+	}
+
+	return warehouses[0], nil
+}
+
+// GetDefaultWarehouse -.
+func (r *ProductRepo) GetDefaultWarehouse(ctx context.Context) (entity.Warehouse, error) {
+	warehouses, err := r.queryWarehouses(ctx, squirrel.Eq{"is_default": true})
+	if err != nil {
+		return entity.Warehouse{}, fmt.Errorf("ProductRepo - GetDefaultWarehouse - %w", err)
+	}
+
+	if len(warehouses) == 0 {
+		return entity.Warehouse{}, fmt.Errorf("ProductRepo - GetDefaultWarehouse: %w", entity.ErrNotFound)
+	}
+
+	return warehouses[0], nil
+}
+
+// GetWarehouses returns all warehouses, the default one first.
+func (r *ProductRepo) GetWarehouses(ctx context.Context) ([]entity.Warehouse, error) {
+	warehouses, err := r.queryWarehouses(ctx, squirrel.And{})
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetWarehouses - %w", err)
+	}
+
+	return warehouses, nil
+}
+
+func (r *ProductRepo) queryWarehouses(ctx context.Context, where squirrel.Sqlizer) ([]entity.Warehouse, error) {
+	sql, args, err := r.Builder.
+		Select(_warehouseColumns).
+		From("warehouse").
+		Where(where).
+		OrderBy("is_default DESC", "code").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	warehouses := make([]entity.Warehouse, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var w entity.Warehouse
+
+		err = rows.Scan(&w.ID, &w.Code, &w.Name, &w.Address, &w.Default, &w.CreatedAt, &w.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		warehouses = append(warehouses, w)
+	}
+
+	return warehouses, nil
+}
+
+// UpdateWarehouse -.
+func (r *ProductRepo) UpdateWarehouse(ctx context.Context, w entity.Warehouse) error {
+	sql, args, err := r.Builder.
+		Update("warehouse").
+		Set("code", w.Code).
+		Set("name", w.Name).
+		Set("address", nullIfEmpty(w.Address)).
+		Where("id = ?", w.ID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdateWarehouse - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdateWarehouse - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - UpdateWarehouse - checkAffected: %w", err)
+	}
+
+	return nil
+}
+
+// SetDefaultWarehouse makes a warehouse the default one in place of the
+// current default. Must run in a transaction.
+func (r *ProductRepo) SetDefaultWarehouse(ctx context.Context, id string) error {
+	sql, args, err := r.Builder.
+		Update("warehouse").
+		Set("is_default", false).
+		Where("is_default").
+		Where("id <> ?", id).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - SetDefaultWarehouse - r.Builder: %w", err)
+	}
+
+	_, err = r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - SetDefaultWarehouse - r.Querier.Exec: %w", mapError(err))
+	}
+
+	sql, args, err = r.Builder.
+		Update("warehouse").
+		Set("is_default", true).
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - SetDefaultWarehouse - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - SetDefaultWarehouse - r.Querier.Exec: %w", mapError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - SetDefaultWarehouse - checkAffected: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteWarehouse deletes a warehouse that never held stock.
+func (r *ProductRepo) DeleteWarehouse(ctx context.Context, id string) error {
+	sql, args, err := r.Builder.
+		Delete("warehouse").
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteWarehouse - r.Builder: %w", err)
+	}
+
+	tag, err := r.Querier(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteWarehouse - r.Querier.Exec: %w", mapDeleteError(err))
+	}
+
+	err = checkAffected(tag)
+	if err != nil {
+		return fmt.Errorf("ProductRepo - DeleteWarehouse - checkAffected: %w", err)
+	}
+
+	return nil
+}
+
+// ---------------- Stock ----------------
+
+// GetStockLevels returns the stock levels matching the filter, those of the
+// default warehouse first and then the fullest.
+func (r *ProductRepo) GetStockLevels(ctx context.Context, f entity.StockLevelFilter) ([]entity.StockLevel, error) {
+	where := squirrel.And{}
+	if f.WarehouseID != "" {
+		where = append(where, squirrel.Eq{"l.warehouse_id": f.WarehouseID})
+	}
+
+	if f.ProductID != "" {
+		where = append(where, squirrel.Eq{"l.product_id": f.ProductID})
+	}
+
+	if f.VariantID != "" {
+		where = append(where, squirrel.Eq{"l.variant_id": f.VariantID})
+	}
+
+	sql, args, err := r.Builder.
+		Select("l.warehouse_id, l.product_id, COALESCE(l.variant_id::text, ''), l.count, l.updated_at").
+		From("stock_level l").
+		Join("warehouse w ON w.id = l.warehouse_id").
+		Where(where).
+		OrderBy("w.is_default DESC", "l.count DESC", "w.code").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetStockLevels - r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetStockLevels - r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	levels := make([]entity.StockLevel, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var l entity.StockLevel
+
+		err = rows.Scan(&l.WarehouseID, &l.ProductID, &l.VariantID, &l.Count, &l.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ProductRepo - GetStockLevels - rows.Scan: %w", err)
+		}
+
+		levels = append(levels, l)
+	}
+
+	return levels, nil
+}
+
+// CreateStockMovement records a movement, which the database applies to the
+// stock level and the product or variant count. Taking out more than the
+// warehouse holds is ErrInsufficientStock.
+func (r *ProductRepo) CreateStockMovement(ctx context.Context, m entity.StockMovement) (entity.StockMovement, error) {
+	sql, args, err := r.Builder.
+		Insert("stock_movement").
+		Columns("warehouse_id, product_id, variant_id, type, quantity, reason, order_id, transfer_id, comment, created_by").
+		Values(m.WarehouseID, m.ProductID, nullIfEmpty(m.VariantID), m.Type, m.Quantity, nullIfEmpty(m.Reason),
+			nullIfEmpty(m.OrderID), nullIfEmpty(m.TransferID), nullIfEmpty(m.Comment), nullIfEmpty(m.CreatedBy)).
+		Suffix("RETURNING " + _stockMovementColumns).
+		ToSql()
+	if err != nil {
+		return entity.StockMovement{}, fmt.Errorf("ProductRepo - CreateStockMovement - r.Builder: %w", err)
+	}
+
+	err = r.Querier(ctx).QueryRow(ctx, sql, args...).
+		Scan(&m.ID, &m.WarehouseID, &m.ProductID, &m.VariantID, &m.Type, &m.Quantity, &m.Reason,
+			&m.OrderID, &m.TransferID, &m.Comment, &m.CreatedBy, &m.CreatedAt)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName == _stockLevelCheck {
+		return entity.StockMovement{}, fmt.Errorf("ProductRepo - CreateStockMovement: %w", entity.ErrInsufficientStock)
+	}
+
+	if err != nil {
+		return entity.StockMovement{}, fmt.Errorf("ProductRepo - CreateStockMovement - r.Querier.QueryRow: %w", mapError(err))
+	}
+
+	return m, nil
+}
+
+// ListStockMovements pages through the ledger.
+func (r *ProductRepo) ListStockMovements(ctx context.Context, f entity.StockMovementFilter) (entity.Page[entity.StockMovement], error) {
+	var result entity.Page[entity.StockMovement]
+
+	pg, err := newPage(f.ListParams, _stockMovementSortColumns)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListStockMovements - newPage: %w", err)
+	}
+
+	where := squirrel.And{}
+	if f.WarehouseID != "" {
+		where = append(where, squirrel.Eq{"warehouse_id": f.WarehouseID})
+	}
+
+	if f.ProductID != "" {
+		where = append(where, squirrel.Eq{"product_id": f.ProductID})
+	}
+
+	if f.VariantID != "" {
+		where = append(where, squirrel.Eq{"variant_id": f.VariantID})
+	}
+
+	if f.Type != "" {
+		where = append(where, squirrel.Eq{"type": f.Type})
+	}
+
+	if f.OrderID != "" {
+		where = append(where, squirrel.Eq{"order_id": f.OrderID})
+	}
+
+	result.Total, err = r.count(ctx, "stock_movement", where)
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListStockMovements - r.count: %w", err)
+	}
+
+	items, err := r.queryStockMovements(ctx, pg.apply(r.Builder.Select(_stockMovementColumns).From("stock_movement").Where(where), ""))
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListStockMovements - %w", err)
+	}
+
+	result.Items, result.NextCursor, err = trim(pg, items, func(m entity.StockMovement, _ string) (interface{}, string) {
+		return m.CreatedAt, m.ID
+	})
+	if err != nil {
+		return result, fmt.Errorf("ProductRepo - ListStockMovements - trim: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetStockMovementsByOrder returns the movements of an order, oldest first.
+func (r *ProductRepo) GetStockMovementsByOrder(ctx context.Context, orderID string) ([]entity.StockMovement, error) {
+	movements, err := r.queryStockMovements(ctx, r.Builder.
+		Select(_stockMovementColumns).
+		From("stock_movement").
+		Where("order_id = ?", orderID).
+		OrderBy("created_at", "id"))
+	if err != nil {
+		return nil, fmt.Errorf("ProductRepo - GetStockMovementsByOrder - %w", err)
+	}
+
+	return movements, nil
+}
+
+func (r *ProductRepo) queryStockMovements(ctx context.Context, builder squirrel.SelectBuilder) ([]entity.StockMovement, error) {
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("r.Builder: %w", err)
+	}
+
+	rows, err := r.Querier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("r.Querier.Query: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	movements := make([]entity.StockMovement, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		var m entity.StockMovement
+
+		err = rows.Scan(&m.ID, &m.WarehouseID, &m.ProductID, &m.VariantID, &m.Type, &m.Quantity, &m.Reason,
+			&m.OrderID, &m.TransferID, &m.Comment, &m.CreatedBy, &m.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		movements = append(movements, m)
+	}
+
+	return movements, nil
+}
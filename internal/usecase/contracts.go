@@ -3,6 +3,7 @@ package usecase
 
 import (
 	"context"
+	"io"
 
 	"ai-seller/internal/entity"
 )
@@ -20,7 +21,22 @@ type (
 		CreateUser(context.Context, entity.User) error
 		GetUser(context.Context, string) (entity.User, error)
 		UpdateUser(context.Context, entity.User) error
-		DeleteUser(context.Context, string) error
+		PatchUser(ctx context.Context, id string, p entity.UserPatch) (entity.User, error)
+		DeleteUser(ctx context.Context, id string, version int) error
+		ListDeletedUsers(context.Context, entity.ListParams) (entity.Page[entity.User], error)
+		RestoreUser(context.Context, string) (entity.User, error)
+		Authenticate(ctx context.Context, username, password string) (entity.User, error)
+
+		Login(ctx context.Context, username, password, cartSession string) (entity.Tokens, error)
+		Refresh(ctx context.Context, refreshToken string) (entity.Tokens, error)
+		Logout(ctx context.Context, refreshToken string) error
+		VerifyAccessToken(accessToken string) (entity.AccessClaims, error)
+
+		GetPermissions(context.Context) ([]entity.Permission, error)
+		GetRolePermissions(ctx context.Context, roleID string) ([]entity.Permission, error)
+		AddRolePermission(ctx context.Context, roleID, permissionID string) error
+		DeleteRolePermission(ctx context.Context, roleID, permissionID string) error
+		HasPermission(ctx context.Context, roleID, permission string) (bool, error)
 
 		CreateRole(context.Context, entity.Role) error
 		GetRole(context.Context, string) (entity.Role, error)
@@ -45,27 +61,121 @@ type (
 	Product interface {
 		CreateProduct(context.Context, entity.Product) error
 		GetProduct(context.Context, string) (entity.Product, error)
+		ListProducts(context.Context, entity.ProductFilter) (entity.Page[entity.Product], error)
+		SearchCatalog(context.Context, entity.CatalogQuery) (entity.CatalogResult, error)
+		SearchProducts(context.Context, entity.ProductSearch) (entity.Page[entity.SearchHit], error)
+		SuggestProducts(ctx context.Context, prefix string, limit int) ([]string, error)
 		UpdateProduct(context.Context, entity.Product) error
-		DeleteProduct(context.Context, string) error
+		PatchProduct(ctx context.Context, id string, p entity.ProductPatch) (entity.Product, error)
+		DeleteProduct(ctx context.Context, id string, version int) error
+		ListDeletedProducts(context.Context, entity.ListParams) (entity.Page[entity.Product], error)
+		RestoreProduct(context.Context, string) (entity.Product, error)
 
 		CreateCategory(context.Context, entity.Category) error
 		GetCategory(context.Context, string) (entity.Category, error)
+		ListCategories(context.Context, entity.CategoryFilter) (entity.Page[entity.Category], error)
 		UpdateCategory(context.Context, entity.Category) error
-		DeleteCategory(context.Context, string) error
+		PatchCategory(ctx context.Context, id string, p entity.CategoryPatch) (entity.Category, error)
+		DeleteCategory(ctx context.Context, id string, version int) error
+		ListDeletedCategories(context.Context, entity.ListParams) (entity.Page[entity.Category], error)
+		RestoreCategory(context.Context, string) (entity.Category, error)
+		GetCategoryTree(ctx context.Context, rootID string) ([]entity.CategoryNode, error)
+		MoveCategory(ctx context.Context, id string, m entity.CategoryMove) (entity.Category, error)
+		ReorderCategories(ctx context.Context, parentID string, o entity.CategoryOrder) ([]entity.Category, error)
+		GetProductBreadcrumbs(ctx context.Context, productID string) ([]entity.Category, error)
 
 		CreateAttribute(context.Context, entity.Attribute) error
 		GetAttribute(context.Context, string) (entity.Attribute, error)
+		ListAttributes(context.Context, entity.AttributeFilter) (entity.Page[entity.Attribute], error)
 		UpdateAttribute(context.Context, entity.Attribute) error
+		PatchAttribute(ctx context.Context, id string, p entity.AttributePatch) (entity.Attribute, error)
 		DeleteAttribute(context.Context, string) error
 
+		GetProductAttributes(ctx context.Context, productID string) ([]entity.ProductAttribute, error)
+		SetProductAttributes(ctx context.Context, productID string, values []entity.ProductAttribute) ([]entity.ProductAttribute, error)
+
+		ListVariants(ctx context.Context, productID string) ([]entity.ProductVariant, error)
+		GetVariant(ctx context.Context, productID, id string) (entity.ProductVariant, error)
+		CreateVariant(context.Context, entity.ProductVariant) (entity.ProductVariant, error)
+		UpdateVariant(context.Context, entity.ProductVariant) (entity.ProductVariant, error)
+		DeleteVariant(ctx context.Context, productID, id string, version int) error
+
+		ListMedia(ctx context.Context, productID string) ([]entity.ProductMedia, error)
+		UploadMedia(ctx context.Context, productID string, r io.Reader) (entity.ProductMedia, error)
+		SetPrimaryMedia(ctx context.Context, productID, id string) (entity.ProductMedia, error)
+		ReorderMedia(ctx context.Context, productID string, o entity.MediaOrder) ([]entity.ProductMedia, error)
+		DeleteMedia(ctx context.Context, productID, id string) error
+		OpenMedia(ctx context.Context, key string) (io.ReadCloser, int64, error)
+
+		ImportProducts(ctx context.Context, i entity.CatalogImport, r io.Reader) (entity.ImportJob, error)
+		GetImportJob(ctx context.Context, id string) (entity.ImportJob, error)
+		ExportProducts(ctx context.Context, e entity.CatalogExport, w io.Writer) error
+
+		GetFeed(ctx context.Context, format string) (entity.Feed, error)
+		GetCategoryFeedMapping(ctx context.Context, categoryID string) (entity.CategoryFeedMapping, error)
+		SetCategoryFeedMapping(ctx context.Context, m entity.CategoryFeedMapping) (entity.CategoryFeedMapping, error)
+
+		ListSynonyms(context.Context) ([]entity.SearchSynonym, error)
+		CreateSynonym(context.Context, entity.SearchSynonym) (entity.SearchSynonym, error)
+		DeleteSynonym(ctx context.Context, term, synonym string) error
+
 		CreateOrder(context.Context, entity.Order) error
+		PlaceOrder(context.Context, entity.NewOrder) (entity.Order, error)
+		QuoteOrder(context.Context, entity.NewOrder) (entity.OrderQuote, error)
+		ChangeOrderStatus(ctx context.Context, orderID string, u entity.OrderStatusUpdate) (entity.Order, error)
+		GetOrderTimeline(ctx context.Context, orderID string) ([]entity.OrderStatusChange, error)
 		GetOrder(context.Context, string) (entity.Order, error)
+		ListOrders(context.Context, entity.OrderFilter) (entity.Page[entity.Order], error)
 		UpdateOrder(context.Context, entity.Order) error
-		DeleteOrder(context.Context, string) error
+		PatchOrder(ctx context.Context, id string, p entity.OrderPatch) (entity.Order, error)
+		DeleteOrder(ctx context.Context, id string, version int) error
 
 		CreateOrderProducts(context.Context, entity.OrderProducts) error
 		GetOrderProducts(context.Context, string) (entity.OrderProducts, error)
 		UpdateOrderProducts(context.Context, entity.OrderProducts) error
 		DeleteOrderProducts(context.Context, string) error
+
+		GetCart(context.Context, entity.CartKey, entity.CartQuery) (entity.Cart, error)
+		AddCartItem(context.Context, entity.CartKey, entity.CartItem) (entity.Cart, error)
+		UpdateCartItem(ctx context.Context, k entity.CartKey, id string, count int) (entity.Cart, error)
+		RemoveCartItem(ctx context.Context, k entity.CartKey, id string) (entity.Cart, error)
+		ClearCart(context.Context, entity.CartKey) error
+		MergeCart(ctx context.Context, userID, session string) error
+		CheckoutCart(ctx context.Context, k entity.CartKey, c entity.CartCheckout, placedBy string) (entity.Order, error)
+
+		CreatePromotion(context.Context, entity.Promotion) (entity.Promotion, error)
+		GetPromotion(context.Context, string) (entity.Promotion, error)
+		ListPromotions(context.Context, entity.PromotionFilter) (entity.Page[entity.Promotion], error)
+		UpdatePromotion(context.Context, entity.Promotion) (entity.Promotion, error)
+		DeletePromotion(ctx context.Context, id string, version int) error
+		ListCoupons(ctx context.Context, promotionID string) ([]entity.Coupon, error)
+		CreateCoupon(context.Context, entity.Coupon) (entity.Coupon, error)
+		DeleteCoupon(ctx context.Context, promotionID, id string) error
+
+		ListCurrencies(context.Context) ([]entity.Currency, error)
+		ListExchangeRates(context.Context, entity.ExchangeRateFilter) (entity.Page[entity.ExchangeRate], error)
+		SetExchangeRate(ctx context.Context, n entity.NewExchangeRate, createdBy string) (entity.ExchangeRate, error)
+		ImportExchangeRates(ctx context.Context, imp entity.ExchangeRateImport, createdBy string) ([]entity.ExchangeRate, error)
+		GetProductPrices(ctx context.Context, productID string) ([]entity.ProductPrice, error)
+		SetProductPrice(context.Context, entity.ProductPrice) (entity.ProductPrice, error)
+		DeleteProductPrice(ctx context.Context, productID, id string) error
+
+		ListWarehouses(context.Context) ([]entity.Warehouse, error)
+		GetWarehouse(context.Context, string) (entity.Warehouse, error)
+		CreateWarehouse(context.Context, entity.Warehouse) (entity.Warehouse, error)
+		UpdateWarehouse(context.Context, entity.Warehouse) (entity.Warehouse, error)
+		DeleteWarehouse(context.Context, string) error
+		GetStockLevels(context.Context, entity.StockLevelFilter) ([]entity.StockLevel, error)
+		ListStockMovements(context.Context, entity.StockMovementFilter) (entity.Page[entity.StockMovement], error)
+		ReceiveStock(ctx context.Context, r entity.StockReceipt, createdBy string) (entity.StockMovement, error)
+		TransferStock(ctx context.Context, t entity.StockTransfer, createdBy string) ([]entity.StockMovement, error)
+		AdjustStock(ctx context.Context, a entity.StockAdjustment, createdBy string) (entity.StockMovement, bool, error)
+
+		GetLowStockThreshold(ctx context.Context, productID string) (entity.LowStockThreshold, error)
+		SetLowStockThreshold(context.Context, entity.LowStockThreshold) (entity.LowStockThreshold, error)
+		DeleteLowStockThreshold(ctx context.Context, productID string) error
+		SubscribeToStock(context.Context, entity.StockSubscription) (entity.StockSubscription, error)
+		UnsubscribeFromStock(ctx context.Context, userID, productID string) error
+		ListStockSubscriptions(ctx context.Context, productID string) ([]entity.StockSubscription, error)
 	}
 )
@@ -0,0 +1,119 @@
+package product
+
+import (
+	"context"
+	"fmt"
+
+	"ai-seller/internal/entity"
+)
+
+// GetProductAttributes -.
+func (uc *UseCase) GetProductAttributes(ctx context.Context, productID string) ([]entity.ProductAttribute, error) {
+	_, err := uc.product.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - GetProductAttributes - s.product.GetProduct: %w", err)
+	}
+
+	values, err := uc.product.GetProductAttributes(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - GetProductAttributes - s.product.GetProductAttributes: %w", err)
+	}
+
+	return values, nil
+}
+
+// SetProductAttributes replaces the attribute values of a product. Every
+// value must belong to an attribute of the product's category and match its
+// type, and every required attribute must be given.
+func (uc *UseCase) SetProductAttributes(ctx context.Context, productID string, values []entity.ProductAttribute) ([]entity.ProductAttribute, error) {
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		product, err := uc.product.GetProduct(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("s.product.GetProduct: %w", err)
+		}
+
+		var definitions []entity.Attribute
+		if product.CategoryID != "" {
+			definitions, err = uc.product.GetAttributesByCategory(ctx, product.CategoryID)
+			if err != nil {
+				return fmt.Errorf("s.product.GetAttributesByCategory: %w", err)
+			}
+		}
+
+		checked, err := checkProductAttributes(definitions, values)
+		if err != nil {
+			return err
+		}
+
+		err = uc.product.ReplaceProductAttributes(ctx, productID, checked)
+		if err != nil {
+			return fmt.Errorf("s.product.ReplaceProductAttributes: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - SetProductAttributes - s.tx.WithinTransaction: %w", err)
+	}
+
+	stored, err := uc.product.GetProductAttributes(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - SetProductAttributes - s.product.GetProductAttributes: %w", err)
+	}
+
+	return stored, nil
+}
+
+// checkProductAttributes validates values against the category's attribute
+// definitions. Null values are dropped.
+func checkProductAttributes(definitions []entity.Attribute, values []entity.ProductAttribute) ([]entity.ProductAttribute, error) {
+	byID := make(map[string]entity.Attribute, len(definitions))
+	for _, a := range definitions {
+		byID[a.ID] = a
+	}
+
+	seen := make(map[string]bool, len(values))
+	checked := make([]entity.ProductAttribute, 0, len(values))
+
+	for _, v := range values {
+		a, ok := byID[v.AttributeID]
+		if !ok {
+			return nil, entity.NewValidationError("attributes", "attribute "+v.AttributeID+" is not defined for the product's category")
+		}
+
+		if seen[a.ID] {
+			return nil, entity.NewValidationError("attributes."+a.Name, "is given more than once")
+		}
+
+		seen[a.ID] = true
+
+		if v.Value == nil {
+			continue
+		}
+
+		if err := a.ValidateValue(v.Value); err != nil {
+			return nil, err
+		}
+
+		checked = append(checked, entity.ProductAttribute{AttributeID: a.ID, Name: a.Name, Type: a.Type, Unit: a.Unit, Value: v.Value})
+	}
+
+	for _, a := range definitions {
+		if a.Required && !hasAttribute(checked, a.ID) {
+			return nil, entity.NewValidationError("attributes."+a.Name, "is required")
+		}
+	}
+
+	return checked, nil
+}
+
+// hasAttribute -.
+func hasAttribute(values []entity.ProductAttribute, attributeID string) bool {
+	for _, v := range values {
+		if v.AttributeID == attributeID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,89 @@
+package product
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/internal/entity"
+)
+
+func TestCheckProductAttributes(t *testing.T) {
+	t.Parallel()
+
+	color := entity.Attribute{ID: "a1", Name: "color", Type: entity.AttributeTypeEnum, Options: []string{"red", "blue"}, Required: true}
+	ram := entity.Attribute{ID: "a2", Name: "ram", Type: entity.AttributeTypeUnit, Unit: "GB"}
+	definitions := []entity.Attribute{color, ram}
+
+	tests := []struct {
+		name   string
+		values []entity.ProductAttribute
+		want   []entity.ProductAttribute
+		field  string
+	}{
+		{
+			name: "resolved from the definitions",
+			values: []entity.ProductAttribute{
+				{AttributeID: "a2", Name: "ignored", Value: 16.0},
+				{AttributeID: "a1", Value: "red"},
+			},
+			want: []entity.ProductAttribute{
+				{AttributeID: "a2", Name: "ram", Type: entity.AttributeTypeUnit, Unit: "GB", Value: 16.0},
+				{AttributeID: "a1", Name: "color", Type: entity.AttributeTypeEnum, Value: "red"},
+			},
+		},
+		{
+			name:   "null values are dropped",
+			values: []entity.ProductAttribute{{AttributeID: "a1", Value: "blue"}, {AttributeID: "a2"}},
+			want:   []entity.ProductAttribute{{AttributeID: "a1", Name: "color", Type: entity.AttributeTypeEnum, Value: "blue"}},
+		},
+		{
+			name:   "undefined attribute",
+			values: []entity.ProductAttribute{{AttributeID: "a1", Value: "red"}, {AttributeID: "a3", Value: "x"}},
+			field:  "attributes",
+		},
+		{
+			name:   "given twice",
+			values: []entity.ProductAttribute{{AttributeID: "a1", Value: "red"}, {AttributeID: "a1", Value: "blue"}},
+			field:  "attributes.color",
+		},
+		{
+			name:   "wrong type",
+			values: []entity.ProductAttribute{{AttributeID: "a1", Value: "red"}, {AttributeID: "a2", Value: "16"}},
+			field:  "attributes.ram",
+		},
+		{
+			name:   "not an option",
+			values: []entity.ProductAttribute{{AttributeID: "a1", Value: "green"}},
+			field:  "attributes.color",
+		},
+		{
+			name:   "required missing",
+			values: []entity.ProductAttribute{{AttributeID: "a2", Value: 8.0}},
+			field:  "attributes.color",
+		},
+		{
+			name:   "required null",
+			values: []entity.ProductAttribute{{AttributeID: "a1"}},
+			field:  "attributes.color",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := checkProductAttributes(definitions, tc.values)
+			if tc.field != "" {
+				var v *entity.ValidationError
+				require.ErrorAs(t, err, &v)
+				require.Equal(t, tc.field, v.Field)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
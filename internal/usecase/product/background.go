@@ -0,0 +1,58 @@
+package product
+
+import (
+	"context"
+	"fmt"
+)
+
+// background runs work that outlives the request that started it, such as
+// notifications and imports, in a goroutine Shutdown waits for. Its error is
+// logged. Work started once Shutdown began is dropped.
+func (uc *UseCase) background(name string, fn func(ctx context.Context) error) {
+	uc.bgMu.Lock()
+	defer uc.bgMu.Unlock()
+
+	if uc.bgClosed {
+		uc.l.Warn("ProductUseCase - background - %s: dropped after shutdown", name)
+
+		return
+	}
+
+	uc.bgWG.Add(1)
+
+	go func() {
+		defer uc.bgWG.Done()
+
+		err := fn(uc.bgCtx)
+		if err != nil {
+			uc.l.Error(fmt.Errorf("ProductUseCase - %s: %w", name, err))
+		}
+	}()
+}
+
+// Shutdown waits for the background work to finish. Once ctx is done the
+// work still running is cancelled and waited for.
+func (uc *UseCase) Shutdown(ctx context.Context) error {
+	uc.bgMu.Lock()
+	uc.bgClosed = true
+	uc.bgMu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		uc.bgWG.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		uc.bgCancel()
+
+		return nil
+	case <-ctx.Done():
+		uc.bgCancel()
+		<-done
+
+		return fmt.Errorf("ProductUseCase - Shutdown: %w", ctx.Err())
+	}
+}
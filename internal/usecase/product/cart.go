@@ -0,0 +1,428 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"ai-seller/internal/entity"
+)
+
+// GetCart returns the cart of k priced in the currency of q with its
+// coupons and the current promotions applied. Items that cannot be checked
+// out as they are carry a problem and are left out of the totals. Without a
+// cart an empty one is returned.
+func (uc *UseCase) GetCart(ctx context.Context, k entity.CartKey, q entity.CartQuery) (entity.Cart, error) {
+	cart, err := uc.getCart(ctx, uc.storedCartKey(k), q)
+	if err != nil {
+		return entity.Cart{}, fmt.Errorf("ProductUseCase - GetCart - %w", err)
+	}
+
+	return cart, nil
+}
+
+// AddCartItem puts an item in the cart of k, creating the cart first. An
+// item already in the cart has its count increased. The resulting count is
+// checked against the available stock. An anonymous caller without a known
+// session gets a new one, returned as the SessionToken of the cart.
+func (uc *UseCase) AddCartItem(ctx context.Context, k entity.CartKey, item entity.CartItem) (entity.Cart, error) {
+	var (
+		cart    entity.Cart
+		session string
+	)
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+
+		cart, session, err = uc.writableCart(ctx, k)
+		if err != nil {
+			return err
+		}
+
+		item.CartID = cart.ID
+
+		item, err = uc.product.AddCartItem(ctx, item)
+		if err != nil {
+			return fmt.Errorf("s.product.AddCartItem: %w", err)
+		}
+
+		return uc.checkCartItem(ctx, item)
+	})
+	if err != nil {
+		return entity.Cart{}, fmt.Errorf("ProductUseCase - AddCartItem - s.tx.WithinTransaction: %w", err)
+	}
+
+	cart, err = uc.getCart(ctx, entity.CartKey{UserID: cart.UserID, SessionID: cart.SessionID}, entity.CartQuery{})
+	if err != nil {
+		return entity.Cart{}, fmt.Errorf("ProductUseCase - AddCartItem - %w", err)
+	}
+
+	cart.SessionToken = session
+
+	return cart, nil
+}
+
+// UpdateCartItem sets the count of an item in the cart of k, checked against
+// the available stock.
+func (uc *UseCase) UpdateCartItem(ctx context.Context, k entity.CartKey, id string, count int) (entity.Cart, error) {
+	k = uc.storedCartKey(k)
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		cart, err := uc.product.GetCart(ctx, k)
+		if err != nil {
+			return fmt.Errorf("s.product.GetCart: %w", err)
+		}
+
+		item, err := uc.product.UpdateCartItem(ctx, cart.ID, id, count)
+		if err != nil {
+			return fmt.Errorf("s.product.UpdateCartItem: %w", err)
+		}
+
+		return uc.checkCartItem(ctx, item)
+	})
+	if err != nil {
+		return entity.Cart{}, fmt.Errorf("ProductUseCase - UpdateCartItem - s.tx.WithinTransaction: %w", err)
+	}
+
+	cart, err := uc.getCart(ctx, k, entity.CartQuery{})
+	if err != nil {
+		return entity.Cart{}, fmt.Errorf("ProductUseCase - UpdateCartItem - %w", err)
+	}
+
+	return cart, nil
+}
+
+// RemoveCartItem takes an item out of the cart of k.
+func (uc *UseCase) RemoveCartItem(ctx context.Context, k entity.CartKey, id string) (entity.Cart, error) {
+	k = uc.storedCartKey(k)
+
+	cart, err := uc.product.GetCart(ctx, k)
+	if err != nil {
+		return entity.Cart{}, fmt.Errorf("ProductUseCase - RemoveCartItem - s.product.GetCart: %w", err)
+	}
+
+	err = uc.product.DeleteCartItem(ctx, cart.ID, id)
+	if err != nil {
+		return entity.Cart{}, fmt.Errorf("ProductUseCase - RemoveCartItem - s.product.DeleteCartItem: %w", err)
+	}
+
+	cart, err = uc.getCart(ctx, k, entity.CartQuery{})
+	if err != nil {
+		return entity.Cart{}, fmt.Errorf("ProductUseCase - RemoveCartItem - %w", err)
+	}
+
+	return cart, nil
+}
+
+// ClearCart empties the cart of k. Clearing no cart is a no-op.
+func (uc *UseCase) ClearCart(ctx context.Context, k entity.CartKey) error {
+	cart, err := uc.product.GetCart(ctx, uc.storedCartKey(k))
+	if errors.Is(err, entity.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - ClearCart - s.product.GetCart: %w", err)
+	}
+
+	err = uc.product.DeleteCart(ctx, cart.ID)
+	if err != nil && !errors.Is(err, entity.ErrNotFound) {
+		return fmt.Errorf("ProductUseCase - ClearCart - s.product.DeleteCart: %w", err)
+	}
+
+	return nil
+}
+
+// MergeCart moves the items of the cart of an anonymous session token into
+// the user's cart once the user signed in. Counts of items in both carts are
+// added up; stock is checked when the cart is read or checked out.
+func (uc *UseCase) MergeCart(ctx context.Context, userID, session string) error {
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		from, err := uc.product.GetCart(ctx, uc.storedCartKey(entity.CartKey{SessionID: session}))
+		if errors.Is(err, entity.ErrNotFound) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("s.product.GetCart: %w", err)
+		}
+
+		to, err := uc.product.GetOrCreateCart(ctx, entity.CartKey{UserID: userID})
+		if err != nil {
+			return fmt.Errorf("s.product.GetOrCreateCart: %w", err)
+		}
+
+		err = uc.product.MergeCarts(ctx, from.ID, to.ID)
+		if err != nil {
+			return fmt.Errorf("s.product.MergeCarts: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - MergeCart - s.tx.WithinTransaction: %w", err)
+	}
+
+	return nil
+}
+
+// CheckoutCart places the order of the cart of k for c.UserID through
+// PlaceOrder, so its stock is reserved and it is priced the same way, and
+// empties the cart in the same transaction.
+func (uc *UseCase) CheckoutCart(ctx context.Context, k entity.CartKey, c entity.CartCheckout, placedBy string) (entity.Order, error) {
+	if c.UserID == "" {
+		return entity.Order{}, fmt.Errorf("ProductUseCase - CheckoutCart: %w", entity.NewValidationError("user_id", "is required"))
+	}
+
+	var order entity.Order
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		cart, err := uc.product.GetCart(ctx, uc.storedCartKey(k))
+		if err != nil {
+			return fmt.Errorf("s.product.GetCart: %w", err)
+		}
+
+		items, err := uc.product.GetCartItems(ctx, cart.ID)
+		if err != nil {
+			return fmt.Errorf("s.product.GetCartItems: %w", err)
+		}
+
+		if len(items) == 0 {
+			return entity.NewValidationError("items", "the cart is empty")
+		}
+
+		order, err = uc.PlaceOrder(ctx, entity.NewOrder{
+			UserID:        c.UserID,
+			IntegrationID: c.IntegrationID,
+			Items:         cartOrderItems(items),
+			Coupons:       c.Coupons,
+			Currency:      c.Currency,
+			PlacedBy:      placedBy,
+		})
+		if err != nil {
+			return fmt.Errorf("uc.PlaceOrder: %w", err)
+		}
+
+		// A concurrent checkout of the same cart finds it gone and rolls back.
+		err = uc.product.DeleteCart(ctx, cart.ID)
+		if err != nil {
+			return fmt.Errorf("s.product.DeleteCart: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.Order{}, fmt.Errorf("ProductUseCase - CheckoutCart - s.tx.WithinTransaction: %w", err)
+	}
+
+	return order, nil
+}
+
+// storedCartKey swaps the session token of k for its stored hash.
+func (uc *UseCase) storedCartKey(k entity.CartKey) entity.CartKey {
+	if k.UserID != "" || k.SessionID == "" {
+		return entity.CartKey{UserID: k.UserID}
+	}
+
+	return entity.CartKey{SessionID: uc.tokens.HashCartSession(k.SessionID)}
+}
+
+// getCart is GetCart for a stored key.
+func (uc *UseCase) getCart(ctx context.Context, k entity.CartKey, q entity.CartQuery) (entity.Cart, error) {
+	cart, err := uc.product.GetCart(ctx, k)
+	if errors.Is(err, entity.ErrNotFound) {
+		cart = entity.Cart{UserID: k.UserID, Items: []entity.CartItem{}}
+	} else if err != nil {
+		return entity.Cart{}, fmt.Errorf("s.product.GetCart: %w", err)
+	}
+
+	if cart.ID != "" {
+		cart.Items, err = uc.product.GetCartItems(ctx, cart.ID)
+		if err != nil {
+			return entity.Cart{}, fmt.Errorf("s.product.GetCartItems: %w", err)
+		}
+	}
+
+	err = uc.priceCart(ctx, &cart, q)
+	if err != nil {
+		return entity.Cart{}, fmt.Errorf("uc.priceCart: %w", err)
+	}
+
+	return cart, nil
+}
+
+// writableCart returns the cart of k, creating it first. Sessions are only
+// ever issued here: an anonymous caller whose token matches no cart gets a
+// new token, returned alongside, rather than a cart under a token of its
+// own choosing.
+func (uc *UseCase) writableCart(ctx context.Context, k entity.CartKey) (entity.Cart, string, error) {
+	if k.UserID != "" {
+		cart, err := uc.product.GetOrCreateCart(ctx, k)
+		if err != nil {
+			return entity.Cart{}, "", fmt.Errorf("s.product.GetOrCreateCart: %w", err)
+		}
+
+		return cart, "", nil
+	}
+
+	if k.SessionID != "" {
+		cart, err := uc.product.GetCart(ctx, uc.storedCartKey(k))
+		if err == nil {
+			return cart, "", nil
+		}
+
+		if !errors.Is(err, entity.ErrNotFound) {
+			return entity.Cart{}, "", fmt.Errorf("s.product.GetCart: %w", err)
+		}
+	}
+
+	session, hash, err := uc.tokens.NewCartSession()
+	if err != nil {
+		return entity.Cart{}, "", fmt.Errorf("s.tokens.NewCartSession: %w", err)
+	}
+
+	cart, err := uc.product.GetOrCreateCart(ctx, entity.CartKey{SessionID: hash})
+	if err != nil {
+		return entity.Cart{}, "", fmt.Errorf("s.product.GetOrCreateCart: %w", err)
+	}
+
+	return cart, session, nil
+}
+
+// checkCartItem rejects an item that cannot be ordered or asks for more than
+// is available.
+func (uc *UseCase) checkCartItem(ctx context.Context, item entity.CartItem) error {
+	_, available, err := uc.cartItemStock(ctx, item)
+	if err != nil {
+		return err
+	}
+
+	if available < item.Count {
+		return fmt.Errorf("product %s has %d available, requested %d: %w", item.ProductID, available, item.Count, entity.ErrInsufficientStock)
+	}
+
+	return nil
+}
+
+// cartItemStock returns the product of an item and how many of the item are
+// available. A product or variant no longer in the catalog is ErrForeignKey,
+// like PlaceOrder reports it.
+func (uc *UseCase) cartItemStock(ctx context.Context, item entity.CartItem) (entity.Product, int, error) {
+	product, err := uc.product.GetProduct(ctx, item.ProductID)
+	if errors.Is(err, entity.ErrNotFound) {
+		return entity.Product{}, 0, fmt.Errorf("product %s: %w", item.ProductID, entity.ErrForeignKey)
+	}
+
+	if err != nil {
+		return entity.Product{}, 0, fmt.Errorf("s.product.GetProduct: %w", err)
+	}
+
+	if item.VariantID != "" {
+		variant, err := uc.product.GetVariant(ctx, item.ProductID, item.VariantID)
+		if errors.Is(err, entity.ErrNotFound) {
+			return entity.Product{}, 0, fmt.Errorf("variant %s of product %s: %w", item.VariantID, item.ProductID, entity.ErrForeignKey)
+		}
+
+		if err != nil {
+			return entity.Product{}, 0, fmt.Errorf("s.product.GetVariant: %w", err)
+		}
+
+		return product, variant.Available, nil
+	}
+
+	variants, err := uc.product.GetVariantsByProduct(ctx, item.ProductID)
+	if err != nil {
+		return entity.Product{}, 0, fmt.Errorf("s.product.GetVariantsByProduct: %w", err)
+	}
+
+	if len(variants) > 0 {
+		return entity.Product{}, 0, entity.NewValidationError("variant_id", "is required for product "+item.ProductID)
+	}
+
+	return product, product.Available, nil
+}
+
+// priceCart fills in the live name, stock and price of the items of a cart
+// and its totals, the way QuoteOrder prices an order of its items.
+func (uc *UseCase) priceCart(ctx context.Context, cart *entity.Cart, q entity.CartQuery) error {
+	items := make([]entity.CartItem, 0, len(cart.Items))
+
+	for i := range cart.Items {
+		item := &cart.Items[i]
+
+		product, available, err := uc.cartItemStock(ctx, *item)
+
+		switch {
+		case errors.Is(err, entity.ErrForeignKey), errors.Is(err, entity.ErrValidation):
+			item.Problem = entity.CartItemUnavailable
+
+			continue
+		case err != nil:
+			return err
+		}
+
+		item.Name, item.Available = product.Name, available
+
+		if available < item.Count {
+			item.Problem = entity.CartItemInsufficientStock
+
+			continue
+		}
+
+		items = append(items, *item)
+	}
+
+	if len(items) == 0 {
+		pr, err := uc.newPricing(ctx, q.Currency, nil)
+		if err != nil {
+			return err
+		}
+
+		cart.Currency, cart.Discounts = pr.currency.Code, []entity.OrderDiscount{}
+
+		return nil
+	}
+
+	var quote entity.OrderQuote
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+
+		orderItems := cartOrderItems(items)
+		quote, err = uc.priceOrder(ctx, entity.NewOrder{
+			UserID:   cart.UserID,
+			Items:    orderItems,
+			Coupons:  q.Coupons,
+			Currency: q.Currency,
+		}, orderItems, false)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("s.tx.WithinTransaction: %w", err)
+	}
+
+	for i := range cart.Items {
+		item := &cart.Items[i]
+
+		for _, line := range quote.Products {
+			if line.ProductID == item.ProductID && line.VariantID == item.VariantID {
+				item.Cost = line.Cost
+			}
+		}
+	}
+
+	cart.SubtotalCost, cart.DiscountCost, cart.TotalCost = quote.SubtotalCost, quote.DiscountCost, quote.TotalCost
+	cart.Currency, cart.Discounts = quote.Currency, quote.Discounts
+
+	return nil
+}
+
+func cartOrderItems(items []entity.CartItem) []entity.NewOrderItem {
+	orderItems := make([]entity.NewOrderItem, 0, len(items))
+	for _, item := range items {
+		orderItems = append(orderItems, entity.NewOrderItem{ProductID: item.ProductID, VariantID: item.VariantID, Count: item.Count})
+	}
+
+	return orderItems
+}
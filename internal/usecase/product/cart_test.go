@@ -0,0 +1,191 @@
+package product
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/internal/entity"
+)
+
+func TestGetCartPricing(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		items    []entity.CartItem
+		problems []string
+		costs    []int
+		total    int
+	}{
+		{
+			name:     "priced",
+			items:    []entity.CartItem{{ProductID: "plain", Count: 2}, {ProductID: "shirt", VariantID: "shirt-m", Count: 1}},
+			problems: []string{"", ""},
+			costs:    []int{1000, 2200},
+			total:    4200,
+		},
+		{
+			name:     "insufficient stock left out of the totals",
+			items:    []entity.CartItem{{ProductID: "plain", Count: 6}, {ProductID: "shirt", VariantID: "shirt-s", Count: 1}},
+			problems: []string{entity.CartItemInsufficientStock, ""},
+			costs:    []int{0, 2000},
+			total:    2000,
+		},
+		{
+			name: "unavailable",
+			items: []entity.CartItem{
+				{ProductID: "gone", Count: 1},
+				{ProductID: "shirt", Count: 1},
+				{ProductID: "plain", VariantID: "shirt-s", Count: 1},
+				{ProductID: "plain", Count: 1},
+			},
+			problems: []string{entity.CartItemUnavailable, entity.CartItemUnavailable, entity.CartItemUnavailable, ""},
+			costs:    []int{0, 0, 0, 1000},
+			total:    1000,
+		},
+		{
+			name:     "nothing to price",
+			items:    []entity.CartItem{{ProductID: "gone", Count: 1}},
+			problems: []string{entity.CartItemUnavailable},
+			costs:    []int{0},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := orderRepo()
+			r.addCart(entity.Cart{ID: "c1", UserID: "u1"}, tc.items...)
+
+			cart, err := newTestUseCase(t, r).GetCart(context.Background(), entity.CartKey{UserID: "u1"}, entity.CartQuery{})
+			require.NoError(t, err)
+
+			problems := make([]string, 0, len(cart.Items))
+			costs := make([]int, 0, len(cart.Items))
+
+			for _, item := range cart.Items {
+				problems = append(problems, item.Problem)
+				costs = append(costs, item.Cost)
+			}
+
+			require.Equal(t, tc.problems, problems)
+			require.Equal(t, tc.costs, costs)
+			require.Equal(t, tc.total, cart.TotalCost)
+			require.Equal(t, "USD", cart.Currency)
+		})
+	}
+}
+
+func TestMergeCart(t *testing.T) {
+	t.Parallel()
+
+	t.Run("into the user's cart", func(t *testing.T) {
+		t.Parallel()
+
+		r := orderRepo()
+		r.addCart(entity.Cart{ID: "anonymous", SessionID: "hash-s1"},
+			entity.CartItem{ProductID: "plain", Count: 1}, entity.CartItem{ProductID: "shirt", VariantID: "shirt-m", Count: 1})
+		r.addCart(entity.Cart{ID: "user", UserID: "u1"}, entity.CartItem{ProductID: "plain", Count: 2})
+
+		require.NoError(t, newTestUseCase(t, r).MergeCart(context.Background(), "u1", "s1"))
+
+		items, err := r.GetCartItems(context.Background(), "user")
+		require.NoError(t, err)
+		require.Equal(t, []entity.CartItem{
+			{CartID: "user", ProductID: "plain", Count: 3},
+			{CartID: "user", ProductID: "shirt", VariantID: "shirt-m", Count: 1},
+		}, items)
+		require.NotContains(t, r.carts, "anonymous")
+	})
+
+	t.Run("creates the user's cart", func(t *testing.T) {
+		t.Parallel()
+
+		r := orderRepo()
+		r.addCart(entity.Cart{ID: "anonymous", SessionID: "hash-s1"}, entity.CartItem{ProductID: "plain", Count: 1})
+
+		require.NoError(t, newTestUseCase(t, r).MergeCart(context.Background(), "u1", "s1"))
+
+		cart, err := r.GetCart(context.Background(), entity.CartKey{UserID: "u1"})
+		require.NoError(t, err)
+
+		items, err := r.GetCartItems(context.Background(), cart.ID)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+	})
+
+	t.Run("unknown session", func(t *testing.T) {
+		t.Parallel()
+
+		r := orderRepo()
+
+		require.NoError(t, newTestUseCase(t, r).MergeCart(context.Background(), "u1", "s1"))
+		require.Empty(t, r.carts)
+	})
+}
+
+func TestCheckoutCart(t *testing.T) {
+	t.Parallel()
+
+	t.Run("places the order and empties the cart", func(t *testing.T) {
+		t.Parallel()
+
+		r := orderRepo()
+		r.addCart(entity.Cart{ID: "c1", SessionID: "hash-s1"},
+			entity.CartItem{ProductID: "plain", Count: 2}, entity.CartItem{ProductID: "shirt", VariantID: "shirt-s", Count: 1})
+
+		order, err := newTestUseCase(t, r).CheckoutCart(context.Background(), entity.CartKey{SessionID: "s1"},
+			entity.CartCheckout{UserID: "u1"}, "manager")
+		require.NoError(t, err)
+
+		require.Equal(t, "u1", order.UserID)
+		require.Equal(t, 4000, order.TotalCost)
+		require.Len(t, order.Reservations, 2)
+		require.Empty(t, r.carts)
+		require.Empty(t, r.cartItems)
+	})
+
+	t.Run("no customer", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newTestUseCase(t, orderRepo()).CheckoutCart(context.Background(), entity.CartKey{SessionID: "s1"},
+			entity.CartCheckout{}, "")
+		require.ErrorIs(t, err, entity.ErrValidation)
+	})
+
+	t.Run("no cart", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newTestUseCase(t, orderRepo()).CheckoutCart(context.Background(), entity.CartKey{UserID: "u1"},
+			entity.CartCheckout{UserID: "u1"}, "u1")
+		require.ErrorIs(t, err, entity.ErrNotFound)
+	})
+
+	t.Run("empty cart", func(t *testing.T) {
+		t.Parallel()
+
+		r := orderRepo()
+		r.addCart(entity.Cart{ID: "c1", UserID: "u1"})
+
+		_, err := newTestUseCase(t, r).CheckoutCart(context.Background(), entity.CartKey{UserID: "u1"},
+			entity.CartCheckout{UserID: "u1"}, "u1")
+		require.ErrorIs(t, err, entity.ErrValidation)
+		require.Contains(t, r.carts, "c1")
+	})
+
+	t.Run("insufficient stock keeps the cart", func(t *testing.T) {
+		t.Parallel()
+
+		r := orderRepo()
+		r.addCart(entity.Cart{ID: "c1", UserID: "u1"}, entity.CartItem{ProductID: "plain", Count: 6})
+
+		_, err := newTestUseCase(t, r).CheckoutCart(context.Background(), entity.CartKey{UserID: "u1"},
+			entity.CartCheckout{UserID: "u1"}, "u1")
+		require.ErrorIs(t, err, entity.ErrInsufficientStock)
+		require.Contains(t, r.carts, "c1")
+		require.Empty(t, r.orders)
+	})
+}
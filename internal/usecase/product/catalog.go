@@ -0,0 +1,129 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"ai-seller/internal/entity"
+)
+
+// SearchCatalog runs a faceted search. Attribute conditions are resolved
+// against the attributes of the requested category, which is required as
+// soon as any attribute is filtered on.
+func (uc *UseCase) SearchCatalog(ctx context.Context, q entity.CatalogQuery) (entity.CatalogResult, error) {
+	if len(q.Attributes) > 0 && q.CategoryID == "" {
+		return entity.CatalogResult{}, entity.NewValidationError("category_id", "is required to filter on attributes")
+	}
+
+	if q.MinPrice != nil && q.MaxPrice != nil && *q.MinPrice > *q.MaxPrice {
+		return entity.CatalogResult{}, entity.NewValidationError("min_price", "must not exceed max_price")
+	}
+
+	var definitions []entity.Attribute
+
+	if q.CategoryID != "" {
+		var err error
+
+		definitions, err = uc.product.GetAttributesByCategory(ctx, q.CategoryID)
+		if err != nil {
+			return entity.CatalogResult{}, fmt.Errorf("ProductUseCase - SearchCatalog - s.product.GetAttributesByCategory: %w", err)
+		}
+	}
+
+	for i, c := range q.Attributes {
+		idx := slices.IndexFunc(definitions, func(a entity.Attribute) bool { return a.ID == c.AttributeID })
+		if idx < 0 {
+			return entity.CatalogResult{}, entity.NewValidationError("attr."+c.AttributeID, "is not an attribute of the category")
+		}
+
+		resolved, err := resolveCondition(definitions[idx], c)
+		if err != nil {
+			return entity.CatalogResult{}, err
+		}
+
+		q.Attributes[i] = resolved
+	}
+
+	result, err := uc.product.SearchCatalog(ctx, q, definitions)
+	if err != nil {
+		return entity.CatalogResult{}, fmt.Errorf("ProductUseCase - SearchCatalog - s.product.SearchCatalog: %w", err)
+	}
+
+	return result, nil
+}
+
+// resolveCondition checks the raw values of c against a. Number and unit
+// attributes accept "min..max" ranges with either end left open.
+func resolveCondition(a entity.Attribute, c entity.AttributeCondition) (entity.AttributeCondition, error) {
+	field := "attr." + a.ID
+	resolved := entity.AttributeCondition{AttributeID: a.ID, Type: a.Type}
+
+	for _, raw := range c.Values {
+		switch a.Type {
+		case entity.AttributeTypeNumber, entity.AttributeTypeUnit:
+			lower, upper, isRange := strings.Cut(raw, "..")
+			if !isRange {
+				if _, err := strconv.ParseFloat(raw, 64); err != nil {
+					return resolved, entity.NewValidationError(field, "must be a number or a min..max range")
+				}
+
+				resolved.Values = append(resolved.Values, raw)
+
+				continue
+			}
+
+			if resolved.Min != nil || resolved.Max != nil {
+				return resolved, entity.NewValidationError(field, "accepts a single range")
+			}
+
+			var err error
+
+			resolved.Min, err = parseBound(lower)
+			if err != nil {
+				return resolved, entity.NewValidationError(field, "has a malformed lower bound")
+			}
+
+			resolved.Max, err = parseBound(upper)
+			if err != nil {
+				return resolved, entity.NewValidationError(field, "has a malformed upper bound")
+			}
+		case entity.AttributeTypeBoolean:
+			if _, err := strconv.ParseBool(raw); err != nil {
+				return resolved, entity.NewValidationError(field, "must be true or false")
+			}
+
+			resolved.Values = append(resolved.Values, raw)
+		case entity.AttributeTypeEnum:
+			if !slices.Contains(a.Options, raw) {
+				return resolved, entity.NewValidationError(field, fmt.Sprintf("must be one of %v", a.Options))
+			}
+
+			resolved.Values = append(resolved.Values, raw)
+		default:
+			resolved.Values = append(resolved.Values, raw)
+		}
+	}
+
+	// A range and exact values cannot be OR'd in one condition.
+	if len(resolved.Values) > 0 && (resolved.Min != nil || resolved.Max != nil) {
+		return resolved, entity.NewValidationError(field, "mixes a range with exact values")
+	}
+
+	return resolved, nil
+}
+
+func parseBound(s string) (*float64, error) {
+	if s == "" {
+		return nil, nil //nolint:nilnil // an open bound
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
@@ -0,0 +1,179 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"ai-seller/internal/entity"
+)
+
+// GetCategoryTree returns the subtree under rootID, or the whole forest of
+// root categories when rootID is empty.
+func (uc *UseCase) GetCategoryTree(ctx context.Context, rootID string) ([]entity.CategoryNode, error) {
+	categories, err := uc.product.GetCategorySubtree(ctx, rootID)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - GetCategoryTree - s.product.GetCategorySubtree: %w", err)
+	}
+
+	children := make(map[string][]entity.Category, len(categories))
+	for _, c := range categories {
+		if c.ID != rootID {
+			children[c.ParentID] = append(children[c.ParentID], c)
+		}
+	}
+
+	if rootID == "" {
+		return categoryNodes(children, ""), nil
+	}
+
+	idx := slices.IndexFunc(categories, func(c entity.Category) bool { return c.ID == rootID })
+	if idx < 0 {
+		return nil, fmt.Errorf("ProductUseCase - GetCategoryTree: %w", entity.ErrNotFound)
+	}
+
+	return []entity.CategoryNode{{Category: categories[idx], Children: categoryNodes(children, rootID)}}, nil
+}
+
+func categoryNodes(children map[string][]entity.Category, parentID string) []entity.CategoryNode {
+	nodes := make([]entity.CategoryNode, 0, len(children[parentID]))
+	for _, c := range children[parentID] {
+		nodes = append(nodes, entity.CategoryNode{Category: c, Children: categoryNodes(children, c.ID)})
+	}
+
+	return nodes
+}
+
+// MoveCategory puts a category under a new parent, or reorders it among its
+// siblings when the parent stays the same. A category cannot be moved under
+// itself or its descendants. Positions past the last sibling are clamped.
+func (uc *UseCase) MoveCategory(ctx context.Context, id string, m entity.CategoryMove) (entity.Category, error) {
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		category, err := uc.product.GetCategory(ctx, id)
+		if err != nil {
+			return fmt.Errorf("s.product.GetCategory: %w", err)
+		}
+
+		if m.ParentID != "" {
+			path, err := uc.product.GetCategoryPath(ctx, m.ParentID)
+			if errors.Is(err, entity.ErrNotFound) {
+				return entity.NewValidationError("parent_id", "category "+m.ParentID+" does not exist")
+			}
+
+			if err != nil {
+				return fmt.Errorf("s.product.GetCategoryPath: %w", err)
+			}
+
+			if slices.ContainsFunc(path, func(c entity.Category) bool { return c.ID == id }) {
+				return entity.NewValidationError("parent_id", "cannot move a category under itself or its descendant")
+			}
+		}
+
+		siblings, err := uc.product.CountCategoryChildren(ctx, m.ParentID)
+		if err != nil {
+			return fmt.Errorf("s.product.CountCategoryChildren: %w", err)
+		}
+
+		if m.ParentID == category.ParentID {
+			siblings--
+		}
+
+		m.Position = min(m.Position, siblings)
+
+		err = uc.product.MoveCategory(ctx, category, m)
+		if err != nil {
+			return fmt.Errorf("s.product.MoveCategory: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.Category{}, fmt.Errorf("ProductUseCase - MoveCategory - s.tx.WithinTransaction: %w", err)
+	}
+
+	category, err := uc.product.GetCategory(ctx, id)
+	if err != nil {
+		return entity.Category{}, fmt.Errorf("ProductUseCase - MoveCategory - s.product.GetCategory: %w", err)
+	}
+
+	return category, nil
+}
+
+// ReorderCategories sets the order of the children of parentID, or of the
+// root categories when it is empty. o must list every child exactly once.
+func (uc *UseCase) ReorderCategories(ctx context.Context, parentID string, o entity.CategoryOrder) ([]entity.Category, error) {
+	var children []entity.Category
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, err := uc.childCategories(ctx, parentID)
+		if err != nil {
+			return err
+		}
+
+		ids := slices.Sorted(slices.Values(o.Children))
+		if len(ids) != len(current) || len(slices.Compact(ids)) != len(current) {
+			return entity.NewValidationError("children", "must list every child category exactly once")
+		}
+
+		for _, c := range current {
+			if _, found := slices.BinarySearch(ids, c.ID); !found {
+				return entity.NewValidationError("children", "must list every child category exactly once")
+			}
+		}
+
+		err = uc.product.ReorderCategories(ctx, parentID, o.Children)
+		if err != nil {
+			return fmt.Errorf("s.product.ReorderCategories: %w", err)
+		}
+
+		children, err = uc.childCategories(ctx, parentID)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - ReorderCategories - s.tx.WithinTransaction: %w", err)
+	}
+
+	return children, nil
+}
+
+// childCategories returns the direct children of parentID by position.
+func (uc *UseCase) childCategories(ctx context.Context, parentID string) ([]entity.Category, error) {
+	if parentID != "" {
+		_, err := uc.product.GetCategory(ctx, parentID)
+		if err != nil {
+			return nil, fmt.Errorf("s.product.GetCategory: %w", err)
+		}
+	}
+
+	subtree, err := uc.product.GetCategorySubtree(ctx, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("s.product.GetCategorySubtree: %w", err)
+	}
+
+	children := slices.DeleteFunc(subtree, func(c entity.Category) bool { return c.ID == parentID || c.ParentID != parentID })
+	slices.SortStableFunc(children, func(a, b entity.Category) int { return a.Position - b.Position })
+
+	return children, nil
+}
+
+// GetProductBreadcrumbs returns the category path of a product from the root
+// down to its own category.
+func (uc *UseCase) GetProductBreadcrumbs(ctx context.Context, productID string) ([]entity.Category, error) {
+	product, err := uc.product.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - GetProductBreadcrumbs - s.product.GetProduct: %w", err)
+	}
+
+	if product.CategoryID == "" {
+		return []entity.Category{}, nil
+	}
+
+	path, err := uc.product.GetCategoryPath(ctx, product.CategoryID)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - GetProductBreadcrumbs - s.product.GetCategoryPath: %w", err)
+	}
+
+	return path, nil
+}
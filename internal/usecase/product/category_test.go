@@ -0,0 +1,226 @@
+package product
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/internal/entity"
+)
+
+// categoryRepo holds two root categories, electronics and clothes, with
+// phones and laptops under electronics and android under phones.
+func categoryRepo() *fakeRepo {
+	r := newFakeRepo()
+	r.addCategory(entity.Category{ID: "electronics", Position: 0})
+	r.addCategory(entity.Category{ID: "clothes", Position: 1})
+	r.addCategory(entity.Category{ID: "phones", ParentID: "electronics", Position: 0})
+	r.addCategory(entity.Category{ID: "laptops", ParentID: "electronics", Position: 1})
+	r.addCategory(entity.Category{ID: "android", ParentID: "phones", Position: 0})
+
+	return r
+}
+
+// placesOf maps every category to its parent and position.
+func placesOf(r *fakeRepo) map[string]entity.CategoryMove {
+	places := make(map[string]entity.CategoryMove, len(r.categories))
+	for id, c := range r.categories {
+		places[id] = entity.CategoryMove{ParentID: c.ParentID, Position: c.Position}
+	}
+
+	return places
+}
+
+func TestMoveCategory(t *testing.T) {
+	t.Parallel()
+
+	unchanged := placesOf(categoryRepo())
+
+	tests := []struct {
+		name    string
+		id      string
+		move    entity.CategoryMove
+		changed map[string]entity.CategoryMove
+		field   string
+		err     error
+	}{
+		{
+			name:    "under another parent",
+			id:      "laptops",
+			move:    entity.CategoryMove{ParentID: "clothes"},
+			changed: map[string]entity.CategoryMove{"laptops": {ParentID: "clothes"}},
+		},
+		{
+			name: "to the root",
+			id:   "android",
+			move: entity.CategoryMove{Position: 1},
+			changed: map[string]entity.CategoryMove{
+				"android": {Position: 1},
+				"clothes": {Position: 2},
+			},
+		},
+		{
+			name: "among its siblings",
+			id:   "phones",
+			move: entity.CategoryMove{ParentID: "electronics", Position: 1},
+			changed: map[string]entity.CategoryMove{
+				"phones":  {ParentID: "electronics", Position: 1},
+				"laptops": {ParentID: "electronics", Position: 0},
+			},
+		},
+		{
+			name: "position past the last sibling",
+			id:   "phones",
+			move: entity.CategoryMove{ParentID: "electronics", Position: 9},
+			changed: map[string]entity.CategoryMove{
+				"phones":  {ParentID: "electronics", Position: 1},
+				"laptops": {ParentID: "electronics", Position: 0},
+			},
+		},
+		{
+			name: "position past the last sibling of a new parent",
+			id:   "android",
+			move: entity.CategoryMove{ParentID: "electronics", Position: 9},
+			changed: map[string]entity.CategoryMove{
+				"android": {ParentID: "electronics", Position: 2},
+			},
+		},
+		{
+			name:  "under itself",
+			id:    "phones",
+			move:  entity.CategoryMove{ParentID: "phones"},
+			field: "parent_id",
+		},
+		{
+			name:  "under its child",
+			id:    "phones",
+			move:  entity.CategoryMove{ParentID: "android"},
+			field: "parent_id",
+		},
+		{
+			name:  "under its grandchild",
+			id:    "electronics",
+			move:  entity.CategoryMove{ParentID: "android"},
+			field: "parent_id",
+		},
+		{
+			name:  "under a missing parent",
+			id:    "phones",
+			move:  entity.CategoryMove{ParentID: "books"},
+			field: "parent_id",
+		},
+		{
+			name: "missing category",
+			id:   "books",
+			err:  entity.ErrNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := categoryRepo()
+			uc := newTestUseCase(t, r)
+
+			got, err := uc.MoveCategory(context.Background(), tc.id, tc.move)
+
+			switch {
+			case tc.field != "":
+				var v *entity.ValidationError
+				require.ErrorAs(t, err, &v)
+				require.Equal(t, tc.field, v.Field)
+				require.Equal(t, unchanged, placesOf(r))
+			case tc.err != nil:
+				require.ErrorIs(t, err, tc.err)
+			default:
+				require.NoError(t, err)
+				require.Equal(t, tc.changed[tc.id].ParentID, got.ParentID)
+				require.Equal(t, tc.changed[tc.id].Position, got.Position)
+
+				want := make(map[string]entity.CategoryMove, len(unchanged))
+				for id, m := range unchanged {
+					want[id] = m
+				}
+
+				for id, m := range tc.changed {
+					want[id] = m
+				}
+
+				require.Equal(t, want, placesOf(r))
+			}
+		})
+	}
+}
+
+func TestGetCategoryTree(t *testing.T) {
+	t.Parallel()
+
+	ids := func(nodes []entity.CategoryNode) []string {
+		var out []string
+		for _, n := range nodes {
+			out = append(out, n.ID)
+		}
+
+		return out
+	}
+
+	uc := newTestUseCase(t, categoryRepo())
+
+	forest, err := uc.GetCategoryTree(context.Background(), "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"electronics", "clothes"}, ids(forest))
+	require.Equal(t, []string{"phones", "laptops"}, ids(forest[0].Children))
+	require.Equal(t, []string{"android"}, ids(forest[0].Children[0].Children))
+
+	tree, err := uc.GetCategoryTree(context.Background(), "phones")
+	require.NoError(t, err)
+	require.Equal(t, []string{"phones"}, ids(tree))
+	require.Equal(t, []string{"android"}, ids(tree[0].Children))
+
+	_, err = uc.GetCategoryTree(context.Background(), "books")
+	require.ErrorIs(t, err, entity.ErrNotFound)
+}
+
+func TestReorderCategories(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		children []string
+		want     []string
+		field    string
+	}{
+		{name: "reversed", children: []string{"laptops", "phones"}, want: []string{"laptops", "phones"}},
+		{name: "missing child", children: []string{"laptops"}, field: "children"},
+		{name: "duplicate child", children: []string{"laptops", "laptops"}, field: "children"},
+		{name: "foreign child", children: []string{"laptops", "android"}, field: "children"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			uc := newTestUseCase(t, categoryRepo())
+
+			got, err := uc.ReorderCategories(context.Background(), "electronics", entity.CategoryOrder{Children: tc.children})
+			if tc.field != "" {
+				var v *entity.ValidationError
+				require.ErrorAs(t, err, &v)
+				require.Equal(t, tc.field, v.Field)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			var ids []string
+			for _, c := range got {
+				ids = append(ids, c.ID)
+			}
+
+			require.Equal(t, tc.want, ids)
+		})
+	}
+}
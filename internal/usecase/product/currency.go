@@ -0,0 +1,302 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"ai-seller/internal/entity"
+)
+
+// ListCurrencies returns the known currencies, the base one first.
+func (uc *UseCase) ListCurrencies(ctx context.Context) ([]entity.Currency, error) {
+	currencies, err := uc.product.GetCurrencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - ListCurrencies - s.product.GetCurrencies: %w", err)
+	}
+
+	return currencies, nil
+}
+
+// ListExchangeRates -.
+func (uc *UseCase) ListExchangeRates(ctx context.Context, f entity.ExchangeRateFilter) (entity.Page[entity.ExchangeRate], error) {
+	page, err := uc.product.ListExchangeRates(ctx, f)
+	if err != nil {
+		return entity.Page[entity.ExchangeRate]{}, fmt.Errorf("ProductUseCase - ListExchangeRates - s.product.ListExchangeRates: %w", err)
+	}
+
+	return page, nil
+}
+
+// SetExchangeRate records a manually entered rate. Earlier rates are kept
+// for the history; orders record the rate they were placed at.
+func (uc *UseCase) SetExchangeRate(ctx context.Context, n entity.NewExchangeRate, createdBy string) (entity.ExchangeRate, error) {
+	currencies, err := uc.product.GetCurrencies(ctx)
+	if err != nil {
+		return entity.ExchangeRate{}, fmt.Errorf("ProductUseCase - SetExchangeRate - s.product.GetCurrencies: %w", err)
+	}
+
+	rate, err := newExchangeRate(currencies, n, entity.RateSourceManual, createdBy)
+	if err != nil {
+		return entity.ExchangeRate{}, fmt.Errorf("ProductUseCase - SetExchangeRate - newExchangeRate: %w", err)
+	}
+
+	rate, err = uc.product.CreateExchangeRate(ctx, rate)
+	if err != nil {
+		return entity.ExchangeRate{}, fmt.Errorf("ProductUseCase - SetExchangeRate - s.product.CreateExchangeRate: %w", err)
+	}
+
+	return rate, nil
+}
+
+// ImportExchangeRates records a batch of rates from an outside source. The
+// batch is stored as a whole or not at all.
+func (uc *UseCase) ImportExchangeRates(ctx context.Context, imp entity.ExchangeRateImport, createdBy string) ([]entity.ExchangeRate, error) {
+	currencies, err := uc.product.GetCurrencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - ImportExchangeRates - s.product.GetCurrencies: %w", err)
+	}
+
+	rates := make([]entity.ExchangeRate, 0, len(imp.Rates))
+
+	for _, n := range imp.Rates {
+		rate, err := newExchangeRate(currencies, n, entity.RateSourceImport, createdBy)
+		if err != nil {
+			return nil, fmt.Errorf("ProductUseCase - ImportExchangeRates - newExchangeRate: %w", err)
+		}
+
+		rates = append(rates, rate)
+	}
+
+	err = uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		for i, rate := range rates {
+			rates[i], err = uc.product.CreateExchangeRate(ctx, rate)
+			if err != nil {
+				return fmt.Errorf("s.product.CreateExchangeRate: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - ImportExchangeRates - s.tx.WithinTransaction: %w", err)
+	}
+
+	return rates, nil
+}
+
+func newExchangeRate(currencies []entity.Currency, n entity.NewExchangeRate, source, createdBy string) (entity.ExchangeRate, error) {
+	currency, ok := findCurrency(currencies, n.Currency)
+	if !ok {
+		return entity.ExchangeRate{}, entity.NewValidationError("currency", n.Currency+" is not supported")
+	}
+
+	if currency.Base {
+		return entity.ExchangeRate{}, entity.NewValidationError("currency", n.Currency+" is the base currency")
+	}
+
+	_, err := entity.ParseRate(n.Rate)
+	if err != nil {
+		return entity.ExchangeRate{}, err
+	}
+
+	rate := entity.ExchangeRate{Currency: n.Currency, Rate: n.Rate, Source: source, CreatedBy: createdBy}
+	if n.EffectiveAt != nil {
+		rate.EffectiveAt = *n.EffectiveAt
+	}
+
+	return rate, nil
+}
+
+// GetProductPrices returns the prices of a product and its variants in
+// currencies other than the base one.
+func (uc *UseCase) GetProductPrices(ctx context.Context, productID string) ([]entity.ProductPrice, error) {
+	_, err := uc.product.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - GetProductPrices - s.product.GetProduct: %w", err)
+	}
+
+	prices, err := uc.product.GetProductPrices(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - GetProductPrices - s.product.GetProductPrices: %w", err)
+	}
+
+	return prices, nil
+}
+
+// SetProductPrice sets the price of a product, or of one of its variants,
+// in a currency other than the base one, overriding the converted price.
+func (uc *UseCase) SetProductPrice(ctx context.Context, p entity.ProductPrice) (entity.ProductPrice, error) {
+	_, err := uc.product.GetProduct(ctx, p.ProductID)
+	if err != nil {
+		return entity.ProductPrice{}, fmt.Errorf("ProductUseCase - SetProductPrice - s.product.GetProduct: %w", err)
+	}
+
+	if p.VariantID != "" {
+		_, err = uc.product.GetVariant(ctx, p.ProductID, p.VariantID)
+		if errors.Is(err, entity.ErrNotFound) {
+			return entity.ProductPrice{}, fmt.Errorf("ProductUseCase - SetProductPrice: %w",
+				entity.NewValidationError("variant_id", "variant "+p.VariantID+" does not belong to the product"))
+		}
+
+		if err != nil {
+			return entity.ProductPrice{}, fmt.Errorf("ProductUseCase - SetProductPrice - s.product.GetVariant: %w", err)
+		}
+	}
+
+	currencies, err := uc.product.GetCurrencies(ctx)
+	if err != nil {
+		return entity.ProductPrice{}, fmt.Errorf("ProductUseCase - SetProductPrice - s.product.GetCurrencies: %w", err)
+	}
+
+	currency, ok := findCurrency(currencies, p.Currency)
+	switch {
+	case !ok:
+		return entity.ProductPrice{}, fmt.Errorf("ProductUseCase - SetProductPrice: %w", entity.NewValidationError("currency", p.Currency+" is not supported"))
+	case currency.Base:
+		return entity.ProductPrice{}, fmt.Errorf("ProductUseCase - SetProductPrice: %w",
+			entity.NewValidationError("currency", "prices in the base currency are the product cost"))
+	}
+
+	price, err := uc.product.SetProductPrice(ctx, p)
+	if err != nil {
+		return entity.ProductPrice{}, fmt.Errorf("ProductUseCase - SetProductPrice - s.product.SetProductPrice: %w", err)
+	}
+
+	return price, nil
+}
+
+// DeleteProductPrice -. The product falls back to its converted price.
+func (uc *UseCase) DeleteProductPrice(ctx context.Context, productID, id string) error {
+	err := uc.product.DeleteProductPrice(ctx, productID, id)
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - DeleteProductPrice - s.product.DeleteProductPrice: %w", err)
+	}
+
+	return nil
+}
+
+func findCurrency(currencies []entity.Currency, code string) (entity.Currency, bool) {
+	for _, c := range currencies {
+		if c.Code == code {
+			return c, true
+		}
+	}
+
+	return entity.Currency{}, false
+}
+
+// baseCurrency returns the currency product costs are in.
+func (uc *UseCase) baseCurrency(ctx context.Context) (entity.Currency, error) {
+	currencies, err := uc.product.GetCurrencies(ctx)
+	if err != nil {
+		return entity.Currency{}, fmt.Errorf("s.product.GetCurrencies: %w", err)
+	}
+
+	for _, c := range currencies {
+		if c.Base {
+			return c, nil
+		}
+	}
+
+	return entity.Currency{}, errors.New("no base currency")
+}
+
+// pricing prices the lines of an order in its currency: from the price list
+// of the currency when a product has an entry there, or by converting the
+// base price at the current exchange rate.
+type pricing struct {
+	base     entity.Currency
+	currency entity.Currency
+	rate     string
+	fromBase *big.Rat
+	prices   map[[2]string]entity.ProductPrice
+}
+
+// newPricing loads the currency, its current rate and the price list
+// entries of the products. An empty code means the base currency.
+func (uc *UseCase) newPricing(ctx context.Context, code string, productIDs []string) (pricing, error) {
+	currencies, err := uc.product.GetCurrencies(ctx)
+	if err != nil {
+		return pricing{}, fmt.Errorf("s.product.GetCurrencies: %w", err)
+	}
+
+	var pr pricing
+
+	for _, c := range currencies {
+		if c.Base {
+			pr.base = c
+		}
+	}
+
+	if code == "" || code == pr.base.Code {
+		pr.currency, pr.rate = pr.base, "1"
+
+		return pr, nil
+	}
+
+	var ok bool
+
+	pr.currency, ok = findCurrency(currencies, code)
+	if !ok {
+		return pricing{}, entity.NewValidationError("currency", code+" is not supported")
+	}
+
+	rate, err := uc.product.GetExchangeRate(ctx, code)
+	if errors.Is(err, entity.ErrNotFound) {
+		return pricing{}, entity.NewValidationError("currency", code+" has no exchange rate")
+	}
+
+	if err != nil {
+		return pricing{}, fmt.Errorf("s.product.GetExchangeRate: %w", err)
+	}
+
+	r, err := entity.ParseRate(rate.Rate)
+	if err != nil {
+		return pricing{}, fmt.Errorf("entity.ParseRate: %w", err)
+	}
+
+	pr.rate, pr.fromBase = rate.Rate, r.Inv(r)
+
+	prices, err := uc.product.GetPricesByProducts(ctx, productIDs, code)
+	if err != nil {
+		return pricing{}, fmt.Errorf("s.product.GetPricesByProducts: %w", err)
+	}
+
+	pr.prices = make(map[[2]string]entity.ProductPrice, len(prices))
+	for _, p := range prices {
+		pr.prices[[2]string{p.ProductID, p.VariantID}] = p
+	}
+
+	return pr, nil
+}
+
+// zero is no money in the order currency.
+func (pr pricing) zero() entity.Money {
+	return entity.Money{Currency: pr.currency.Code}
+}
+
+// convert converts an amount in the base currency into the order currency.
+func (pr pricing) convert(amount int) entity.Money {
+	m := entity.Money{Amount: amount, Currency: pr.base.Code}
+	if pr.fromBase == nil {
+		return m
+	}
+
+	return entity.Convert(m, pr.base, pr.currency, pr.fromBase)
+}
+
+// unitPrice is the price of one item of a product or variant whose base
+// price is basePrice. A variant price wins over a product one.
+func (pr pricing) unitPrice(productID, variantID string, basePrice int) entity.Money {
+	if p, ok := pr.prices[[2]string{productID, variantID}]; ok {
+		return p.Price()
+	}
+
+	if p, ok := pr.prices[[2]string{productID, ""}]; ok {
+		return p.Price()
+	}
+
+	return pr.convert(basePrice)
+}
@@ -0,0 +1,236 @@
+package product
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"ai-seller/internal/entity"
+)
+
+const (
+	_exportPageLimit = 100
+	_exportSheet     = "Products"
+	// _formulaPrefixes start a formula in spreadsheet applications.
+	_formulaPrefixes = "=+-@\t\r"
+)
+
+// ExportProducts writes the selected products to w as a catalog file that
+// ImportProducts reads back with the default mapping: one row per product
+// with its category path and an attribute column per attribute in use. Costs
+// are written in major units of the base currency. Text that would start a
+// formula is escaped with an apostrophe, which ImportProducts drops again.
+func (uc *UseCase) ExportProducts(ctx context.Context, e entity.CatalogExport, w io.Writer) error {
+	categories, err := uc.product.GetCategorySubtree(ctx, "")
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - ExportProducts - s.product.GetCategorySubtree: %w", err)
+	}
+
+	paths := categoryPaths(categories, entity.ImportCategorySeparator)
+
+	currency, err := uc.baseCurrency(ctx)
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - ExportProducts - uc.baseCurrency: %w", err)
+	}
+
+	var (
+		products   []entity.Product
+		attributes []string
+	)
+
+	f := entity.ProductFilter{
+		ListParams:         entity.ListParams{Limit: _exportPageLimit},
+		CategoryID:         e.CategoryID,
+		IncludeDescendants: e.IncludeDescendants,
+	}
+
+	for {
+		page, err := uc.product.ListProducts(ctx, f)
+		if err != nil {
+			return fmt.Errorf("ProductUseCase - ExportProducts - s.product.ListProducts: %w", err)
+		}
+
+		for _, p := range page.Items {
+			p.Attributes, err = uc.product.GetProductAttributes(ctx, p.ID)
+			if err != nil {
+				return fmt.Errorf("ProductUseCase - ExportProducts - s.product.GetProductAttributes: %w", err)
+			}
+
+			for _, a := range p.Attributes {
+				if !slices.Contains(attributes, a.Name) {
+					attributes = append(attributes, a.Name)
+				}
+			}
+
+			products = append(products, p)
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+
+		f.Cursor = page.NextCursor
+	}
+
+	slices.Sort(attributes)
+
+	header := slices.Clone(entity.ImportColumns)
+	for _, name := range attributes {
+		header = append(header, entity.ImportAttributePrefix+name)
+	}
+
+	records := make([][]interface{}, 0, len(products)+1)
+	records = append(records, toCells(header))
+
+	for _, p := range products {
+		record := []interface{}{p.SKU, p.Name, paths[p.CategoryID], p.ShortInfo, p.Description,
+			currency.Format(p.Cost), p.Count, currency.Format(p.DiscountCost), p.Discount}
+
+		for _, name := range attributes {
+			idx := slices.IndexFunc(p.Attributes, func(a entity.ProductAttribute) bool { return a.Name == name })
+			if idx < 0 {
+				record = append(record, "")
+				continue
+			}
+
+			record = append(record, formatAttributeValue(p.Attributes[idx].Value))
+		}
+
+		records = append(records, record)
+	}
+
+	if e.Format == entity.CatalogFormatXLSX {
+		err = writeXLSX(w, records)
+	} else {
+		err = writeCSV(w, records)
+	}
+
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - ExportProducts - %w", err)
+	}
+
+	return nil
+}
+
+// categoryPaths maps category IDs to paths such as "Electronics / Phones".
+func categoryPaths(categories []entity.Category, separator string) map[string]string {
+	byID := make(map[string]entity.Category, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+
+	paths := make(map[string]string, len(categories))
+
+	for _, c := range categories {
+		names := []string{c.Name}
+		for parent, ok := byID[c.ParentID]; ok; parent, ok = byID[parent.ParentID] {
+			names = append(names, parent.Name)
+		}
+
+		slices.Reverse(names)
+		paths[c.ID] = strings.Join(names, separator)
+	}
+
+	return paths
+}
+
+func formatAttributeValue(v interface{}) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// escapeFormula prefixes text that starts like a formula with an apostrophe,
+// so that spreadsheet applications show it instead of running it.
+func escapeFormula(v interface{}) interface{} {
+	if s, ok := v.(string); ok && s != "" && strings.ContainsRune(_formulaPrefixes, rune(s[0])) {
+		return "'" + s
+	}
+
+	return v
+}
+
+func toCells(values []string) []interface{} {
+	cells := make([]interface{}, len(values))
+	for i, v := range values {
+		cells[i] = v
+	}
+
+	return cells
+}
+
+// writeCSV writes a comma separated file with a byte order mark, which
+// spreadsheet applications need to recognise UTF-8.
+func writeCSV(w io.Writer, records [][]interface{}) error {
+	_, err := io.WriteString(w, _utf8BOM)
+	if err != nil {
+		return fmt.Errorf("io.WriteString: %w", err)
+	}
+
+	cw := csv.NewWriter(w)
+
+	for _, record := range records {
+		cells := make([]string, len(record))
+		for i, v := range record {
+			cells[i] = fmt.Sprint(escapeFormula(v))
+		}
+
+		err = cw.Write(cells)
+		if err != nil {
+			return fmt.Errorf("cw.Write: %w", err)
+		}
+	}
+
+	cw.Flush()
+
+	if err = cw.Error(); err != nil {
+		return fmt.Errorf("cw.Flush: %w", err)
+	}
+
+	return nil
+}
+
+func writeXLSX(w io.Writer, records [][]interface{}) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	err := f.SetSheetName(f.GetSheetName(0), _exportSheet)
+	if err != nil {
+		return fmt.Errorf("f.SetSheetName: %w", err)
+	}
+
+	for i, record := range records {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return fmt.Errorf("excelize.CoordinatesToCellName: %w", err)
+		}
+
+		record = slices.Clone(record)
+		for j, v := range record {
+			record[j] = escapeFormula(v)
+		}
+
+		err = f.SetSheetRow(_exportSheet, cell, &record)
+		if err != nil {
+			return fmt.Errorf("f.SetSheetRow: %w", err)
+		}
+	}
+
+	err = f.Write(w)
+	if err != nil {
+		return fmt.Errorf("f.Write: %w", err)
+	}
+
+	return nil
+}
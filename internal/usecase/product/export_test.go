@@ -0,0 +1,55 @@
+package product
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/internal/entity"
+)
+
+func TestEscapeFormula(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   interface{}
+		want interface{}
+	}{
+		{name: "text", in: "Shirt", want: "Shirt"},
+		{name: "empty", in: "", want: ""},
+		{name: "equals", in: "=1+1", want: "'=1+1"},
+		{name: "plus", in: "+998", want: "'+998"},
+		{name: "minus", in: "-5", want: "'-5"},
+		{name: "at", in: "@SUM(A1)", want: "'@SUM(A1)"},
+		{name: "tab", in: "\t=1", want: "'\t=1"},
+		{name: "inside", in: "a=b", want: "a=b"},
+		{name: "number", in: -5, want: -5},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, escapeFormula(tc.in))
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	err := writeCSV(&buf, [][]interface{}{{"sku", "name", "count"}, {"A-1", "=cmd|' /C calc'!A0", 3}})
+	require.NoError(t, err)
+	require.Equal(t, _utf8BOM+"sku,name,count\nA-1,'=cmd|' /C calc'!A0,3\n", buf.String())
+
+	records, err := readCatalog(entity.CatalogFormatCSV, buf.Bytes())
+	require.NoError(t, err)
+
+	row, err := readImportRow(2, records[1], map[string]int{"sku": 0, "name": 1}, _usd)
+	require.NoError(t, err)
+	require.Equal(t, "=cmd|' /C calc'!A0", row.Name)
+}
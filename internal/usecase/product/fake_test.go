@@ -0,0 +1,719 @@
+package product
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"ai-seller/internal/entity"
+	"ai-seller/internal/repo"
+)
+
+// fakeRepo is an in-memory ProductRepo holding just what the tests reach.
+// Methods it does not override panic on the nil embedded interface.
+type fakeRepo struct {
+	repo.ProductRepo
+
+	mu           sync.Mutex
+	seq          int
+	currencies   []entity.Currency
+	products     map[string]entity.Product
+	variants     map[string]entity.ProductVariant
+	orders       map[string]entity.Order
+	lines        []entity.OrderProducts
+	reservations []entity.StockReservation
+	warehouses   []entity.Warehouse
+	levels       []entity.StockLevel
+	movements    []entity.StockMovement
+	carts        map[string]entity.Cart
+	cartItems    []entity.CartItem
+	categories   map[string]entity.Category
+	feedVersion  string
+	feedProducts []entity.FeedProduct
+	feedMappings []entity.CategoryFeedMapping
+	feedReads    int
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		currencies: []entity.Currency{{Code: "USD", MinorUnits: 2, Base: true}},
+		products:   map[string]entity.Product{},
+		variants:   map[string]entity.ProductVariant{},
+		orders:     map[string]entity.Order{},
+		carts:      map[string]entity.Cart{},
+		categories: map[string]entity.Category{},
+	}
+}
+
+// fakeTx runs fn right away; the fake has nothing to roll back.
+type fakeTx struct{}
+
+func (fakeTx) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// newTestUseCase returns a use case over r whose background work is waited
+// for when the test ends.
+func newTestUseCase(t *testing.T, r *fakeRepo, opts ...Option) *UseCase {
+	t.Helper()
+
+	uc := New(nil, r, nil, nil, fakeTokens{}, fakeTx{}, nil, nil, opts...)
+	t.Cleanup(func() { _ = uc.Shutdown(context.Background()) })
+
+	return uc
+}
+
+// fakeTokens hashes cart sessions by prefixing them.
+type fakeTokens struct {
+	repo.TokenManager
+}
+
+func (fakeTokens) HashCartSession(session string) string {
+	return "hash-" + session
+}
+
+func (r *fakeRepo) nextID(prefix string) string {
+	r.seq++
+
+	return prefix + strconv.Itoa(r.seq)
+}
+
+func (r *fakeRepo) addProduct(p entity.Product) {
+	r.products[p.ID] = p
+}
+
+func (r *fakeRepo) addVariant(v entity.ProductVariant) {
+	r.variants[v.ID] = v
+}
+
+func (r *fakeRepo) GetCurrencies(context.Context) ([]entity.Currency, error) {
+	return r.currencies, nil
+}
+
+func (r *fakeRepo) GetActivePromotions(context.Context) ([]entity.Promotion, error) {
+	return nil, nil
+}
+
+func (r *fakeRepo) GetProducts(_ context.Context, ids []string) ([]entity.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	products := make([]entity.Product, 0, len(ids))
+
+	for _, id := range ids {
+		if p, ok := r.products[id]; ok {
+			products = append(products, p)
+		}
+	}
+
+	return products, nil
+}
+
+func (r *fakeRepo) LockProducts(ctx context.Context, ids []string) ([]entity.Product, error) {
+	return r.GetProducts(ctx, ids)
+}
+
+func (r *fakeRepo) GetVariantsByProducts(_ context.Context, productIDs []string) ([]entity.ProductVariant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	variants := make([]entity.ProductVariant, 0, len(r.variants))
+
+	for _, v := range r.variants {
+		if slices.Contains(productIDs, v.ProductID) {
+			variants = append(variants, v)
+		}
+	}
+
+	return variants, nil
+}
+
+func (r *fakeRepo) LockVariantsByProducts(ctx context.Context, productIDs []string) ([]entity.ProductVariant, error) {
+	return r.GetVariantsByProducts(ctx, productIDs)
+}
+
+func (r *fakeRepo) GetVariantsByProduct(ctx context.Context, productID string) ([]entity.ProductVariant, error) {
+	return r.GetVariantsByProducts(ctx, []string{productID})
+}
+
+func (r *fakeRepo) ReserveStock(_ context.Context, productID, variantID string, count int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if variantID != "" {
+		v := r.variants[variantID]
+		if v.Available < count {
+			return fmt.Errorf("variant %s: %w", variantID, entity.ErrInsufficientStock)
+		}
+
+		v.Available -= count
+		r.variants[variantID] = v
+
+		return nil
+	}
+
+	p := r.products[productID]
+	if p.Available < count {
+		return fmt.Errorf("product %s: %w", productID, entity.ErrInsufficientStock)
+	}
+
+	p.Available -= count
+	r.products[productID] = p
+
+	return nil
+}
+
+func (r *fakeRepo) UnreserveStock(_ context.Context, productID, variantID string, count int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if variantID != "" {
+		v := r.variants[variantID]
+		v.Available += count
+		r.variants[variantID] = v
+
+		return nil
+	}
+
+	p := r.products[productID]
+	p.Available += count
+	r.products[productID] = p
+
+	return nil
+}
+
+func (r *fakeRepo) CreateReservation(_ context.Context, s entity.StockReservation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s.ID = r.nextID("reservation-")
+	s.Status = entity.ReservationActive
+	r.reservations = append(r.reservations, s)
+
+	return nil
+}
+
+func (r *fakeRepo) GetReservationsByOrder(_ context.Context, orderID string) ([]entity.StockReservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservations := make([]entity.StockReservation, 0, len(r.reservations))
+
+	for _, s := range r.reservations {
+		if s.OrderID == orderID {
+			reservations = append(reservations, s)
+		}
+	}
+
+	return reservations, nil
+}
+
+func (r *fakeRepo) UpdateReservationStatus(_ context.Context, orderID, from, to string) ([]entity.StockReservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var updated []entity.StockReservation
+
+	for i, s := range r.reservations {
+		if s.OrderID == orderID && s.Status == from {
+			r.reservations[i].Status = to
+			updated = append(updated, r.reservations[i])
+		}
+	}
+
+	return updated, nil
+}
+
+func (r *fakeRepo) GetExpiredReservationOrders(_ context.Context, limit int) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []string
+
+	for _, s := range r.reservations {
+		if len(ids) < limit && s.Status == entity.ReservationActive && !s.ExpiresAt.After(time.Now()) && !slices.Contains(ids, s.OrderID) {
+			ids = append(ids, s.OrderID)
+		}
+	}
+
+	return ids, nil
+}
+
+func (r *fakeRepo) CreateOrder(_ context.Context, o entity.Order) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	o.ID = r.nextID("order-")
+	r.orders[o.ID] = o
+
+	return o.ID, nil
+}
+
+func (r *fakeRepo) GetOrder(_ context.Context, id string) (entity.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	o, ok := r.orders[id]
+	if !ok {
+		return entity.Order{}, fmt.Errorf("order %s: %w", id, entity.ErrNotFound)
+	}
+
+	return o, nil
+}
+
+func (r *fakeRepo) LockOrder(ctx context.Context, id string) (entity.Order, error) {
+	return r.GetOrder(ctx, id)
+}
+
+func (r *fakeRepo) UpdateOrderStatus(_ context.Context, id, from, to string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	o, ok := r.orders[id]
+	if !ok || o.Status != from {
+		return entity.ErrConflict
+	}
+
+	o.Status = to
+	r.orders[id] = o
+
+	return nil
+}
+
+func (r *fakeRepo) CreateOrderStatusChange(context.Context, entity.OrderStatusChange) error {
+	return nil
+}
+
+func (r *fakeRepo) CreateOrderProducts(_ context.Context, op entity.OrderProducts) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	op.ID = r.nextID("line-")
+	r.lines = append(r.lines, op)
+
+	return op.ID, nil
+}
+
+func (r *fakeRepo) GetOrderProductsByOrder(_ context.Context, orderID string) ([]entity.OrderProducts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lines := make([]entity.OrderProducts, 0, len(r.lines))
+
+	for _, l := range r.lines {
+		if l.OrderID == orderID {
+			lines = append(lines, l)
+		}
+	}
+
+	return lines, nil
+}
+
+func (r *fakeRepo) GetOrderDiscounts(context.Context, string) ([]entity.OrderDiscount, error) {
+	return []entity.OrderDiscount{}, nil
+}
+
+func (r *fakeRepo) GetProduct(_ context.Context, id string) (entity.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.products[id]
+	if !ok {
+		return entity.Product{}, fmt.Errorf("product %s: %w", id, entity.ErrNotFound)
+	}
+
+	return p, nil
+}
+
+func (r *fakeRepo) GetVariant(_ context.Context, productID, id string) (entity.ProductVariant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, ok := r.variants[id]
+	if !ok || v.ProductID != productID {
+		return entity.ProductVariant{}, fmt.Errorf("variant %s: %w", id, entity.ErrNotFound)
+	}
+
+	return v, nil
+}
+
+func (r *fakeRepo) GetWarehouse(_ context.Context, id string) (entity.Warehouse, error) {
+	for _, w := range r.warehouses {
+		if w.ID == id {
+			return w, nil
+		}
+	}
+
+	return entity.Warehouse{}, fmt.Errorf("warehouse %s: %w", id, entity.ErrNotFound)
+}
+
+func (r *fakeRepo) GetDefaultWarehouse(context.Context) (entity.Warehouse, error) {
+	for _, w := range r.warehouses {
+		if w.Default {
+			return w, nil
+		}
+	}
+
+	return entity.Warehouse{}, fmt.Errorf("default warehouse: %w", entity.ErrNotFound)
+}
+
+// GetStockLevels orders the levels like the database: the default warehouse
+// first, then the fullest.
+func (r *fakeRepo) GetStockLevels(_ context.Context, f entity.StockLevelFilter) ([]entity.StockLevel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	levels := make([]entity.StockLevel, 0, len(r.levels))
+
+	for _, l := range r.levels {
+		if (f.WarehouseID == "" || l.WarehouseID == f.WarehouseID) && (f.ProductID == "" || l.ProductID == f.ProductID) &&
+			(f.VariantID == "" || l.VariantID == f.VariantID) {
+			levels = append(levels, l)
+		}
+	}
+
+	isDefault := func(id string) bool {
+		return slices.ContainsFunc(r.warehouses, func(w entity.Warehouse) bool { return w.ID == id && w.Default })
+	}
+
+	slices.SortStableFunc(levels, func(a, b entity.StockLevel) int {
+		switch {
+		case isDefault(a.WarehouseID) != isDefault(b.WarehouseID):
+			if isDefault(a.WarehouseID) {
+				return -1
+			}
+
+			return 1
+		default:
+			return b.Count - a.Count
+		}
+	})
+
+	return levels, nil
+}
+
+// CreateStockMovement moves the level of the warehouse and the count of the
+// product or variant, as the database triggers do.
+func (r *fakeRepo) CreateStockMovement(_ context.Context, m entity.StockMovement) (entity.StockMovement, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m.ID = r.nextID("movement-")
+	r.movements = append(r.movements, m)
+
+	idx := slices.IndexFunc(r.levels, func(l entity.StockLevel) bool {
+		return l.WarehouseID == m.WarehouseID && l.ProductID == m.ProductID && l.VariantID == m.VariantID
+	})
+	if idx < 0 {
+		r.levels = append(r.levels, entity.StockLevel{WarehouseID: m.WarehouseID, ProductID: m.ProductID, VariantID: m.VariantID})
+		idx = len(r.levels) - 1
+	}
+
+	r.levels[idx].Count += m.Quantity
+
+	if m.VariantID != "" {
+		v := r.variants[m.VariantID]
+		v.Count += m.Quantity
+		v.Available += m.Quantity
+		r.variants[m.VariantID] = v
+	}
+
+	p := r.products[m.ProductID]
+	p.Count += m.Quantity
+	p.Available += m.Quantity
+	r.products[m.ProductID] = p
+
+	return m, nil
+}
+
+func (r *fakeRepo) ClaimLowStockAlert(_ context.Context, productID string) (entity.LowStockThreshold, error) {
+	return entity.LowStockThreshold{}, fmt.Errorf("threshold of %s: %w", productID, entity.ErrNotFound)
+}
+
+func (r *fakeRepo) ResetLowStockAlert(context.Context, string) error {
+	return nil
+}
+
+func (r *fakeRepo) ClaimStockSubscriptions(context.Context, string) ([]entity.StockSubscription, error) {
+	return nil, nil
+}
+
+func (r *fakeRepo) addCart(c entity.Cart, items ...entity.CartItem) {
+	r.carts[c.ID] = c
+
+	for _, item := range items {
+		item.CartID = c.ID
+		r.cartItems = append(r.cartItems, item)
+	}
+}
+
+func (r *fakeRepo) GetCart(_ context.Context, k entity.CartKey) (entity.Cart, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range r.carts {
+		if c.UserID == k.UserID && c.SessionID == k.SessionID {
+			return c, nil
+		}
+	}
+
+	return entity.Cart{}, fmt.Errorf("cart: %w", entity.ErrNotFound)
+}
+
+func (r *fakeRepo) GetOrCreateCart(ctx context.Context, k entity.CartKey) (entity.Cart, error) {
+	c, err := r.GetCart(ctx, k)
+	if err == nil {
+		return c, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c = entity.Cart{ID: r.nextID("cart-"), UserID: k.UserID, SessionID: k.SessionID}
+	r.carts[c.ID] = c
+
+	return c, nil
+}
+
+func (r *fakeRepo) DeleteCart(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.carts[id]; !ok {
+		return fmt.Errorf("cart %s: %w", id, entity.ErrNotFound)
+	}
+
+	delete(r.carts, id)
+	r.cartItems = slices.DeleteFunc(r.cartItems, func(item entity.CartItem) bool { return item.CartID == id })
+
+	return nil
+}
+
+// MergeCarts adds the items of from to to, summing the counts of items in
+// both, and deletes from.
+func (r *fakeRepo) MergeCarts(ctx context.Context, from, to string) error {
+	r.mu.Lock()
+
+	for _, item := range r.cartItems {
+		if item.CartID != from {
+			continue
+		}
+
+		idx := slices.IndexFunc(r.cartItems, func(i entity.CartItem) bool {
+			return i.CartID == to && i.ProductID == item.ProductID && i.VariantID == item.VariantID
+		})
+		if idx >= 0 {
+			r.cartItems[idx].Count += item.Count
+
+			continue
+		}
+
+		item.CartID = to
+		r.cartItems = append(r.cartItems, item)
+	}
+
+	r.mu.Unlock()
+
+	return r.DeleteCart(ctx, from)
+}
+
+func (r *fakeRepo) GetCartItems(_ context.Context, cartID string) ([]entity.CartItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := make([]entity.CartItem, 0, len(r.cartItems))
+
+	for _, item := range r.cartItems {
+		if item.CartID == cartID {
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+func (r *fakeRepo) addCategory(c entity.Category) {
+	r.categories[c.ID] = c
+}
+
+func (r *fakeRepo) GetCategory(_ context.Context, id string) (entity.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.categories[id]
+	if !ok || c.DeletedAt != nil {
+		return entity.Category{}, entity.ErrNotFound
+	}
+
+	return c, nil
+}
+
+// GetCategorySubtree returns rootID and its descendants, or every category
+// when rootID is empty, ordered by parent, position and name like the
+// Postgres repo.
+func (r *fakeRepo) GetCategorySubtree(_ context.Context, rootID string) ([]entity.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var categories []entity.Category
+
+	for _, c := range r.categories {
+		for id := c.ID; ; id = r.categories[id].ParentID {
+			if id == rootID {
+				categories = append(categories, c)
+				break
+			}
+
+			if id == "" {
+				break
+			}
+		}
+	}
+
+	slices.SortFunc(categories, func(a, b entity.Category) int {
+		return cmp.Or(cmp.Compare(a.ParentID, b.ParentID), a.Position-b.Position, cmp.Compare(a.Name, b.Name))
+	})
+
+	return categories, nil
+}
+
+func (r *fakeRepo) GetCategoryPath(_ context.Context, id string) ([]entity.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.categories[id]; !ok {
+		return nil, entity.ErrNotFound
+	}
+
+	var path []entity.Category
+
+	for ; id != ""; id = r.categories[id].ParentID {
+		path = append(path, r.categories[id])
+	}
+
+	slices.Reverse(path)
+
+	return path, nil
+}
+
+func (r *fakeRepo) CountCategoryChildren(_ context.Context, parentID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+
+	for _, c := range r.categories {
+		if c.ParentID == parentID {
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// MoveCategory closes the gap c leaves and opens one at the new position,
+// like the Postgres repo.
+func (r *fakeRepo) MoveCategory(_ context.Context, c entity.Category, m entity.CategoryMove) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.shiftSiblings(c.ParentID, c.Position+1, -1, c.ID)
+	r.shiftSiblings(m.ParentID, m.Position, 1, c.ID)
+
+	c.ParentID, c.Position = m.ParentID, m.Position
+	r.categories[c.ID] = c
+
+	return nil
+}
+
+func (r *fakeRepo) shiftSiblings(parentID string, from, delta int, except string) {
+	for id, c := range r.categories {
+		if c.ParentID == parentID && c.Position >= from && id != except {
+			c.Position += delta
+			r.categories[id] = c
+		}
+	}
+}
+
+func (r *fakeRepo) ReorderCategories(_ context.Context, _ string, ids []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, id := range ids {
+		c := r.categories[id]
+		c.Position = i
+		r.categories[id] = c
+	}
+
+	return nil
+}
+
+func (r *fakeRepo) FeedVersion(context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.feedVersion, nil
+}
+
+// ListFeedProducts counts its calls in feedReads, which tells a cached feed
+// from a regenerated one.
+func (r *fakeRepo) ListFeedProducts(context.Context) ([]entity.FeedProduct, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.feedReads++
+
+	return r.feedProducts, nil
+}
+
+func (r *fakeRepo) GetFeedMappings(context.Context) ([]entity.CategoryFeedMapping, error) {
+	return r.feedMappings, nil
+}
+
+func (r *fakeRepo) DeletePromotionUsageByOrder(context.Context, string) error {
+	return nil
+}
+
+func (r *fakeRepo) RestoreProduct(_ context.Context, id string) (entity.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.products[id]
+	if !ok || p.DeletedAt == nil {
+		return entity.Product{}, entity.ErrNotFound
+	}
+
+	p.DeletedAt = nil
+	r.products[id] = p
+
+	return p, nil
+}
+
+// RestoreCategory places the category after its live siblings, like the
+// Postgres repo.
+func (r *fakeRepo) RestoreCategory(_ context.Context, id string) (entity.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.categories[id]
+	if !ok || c.DeletedAt == nil {
+		return entity.Category{}, entity.ErrNotFound
+	}
+
+	c.DeletedAt, c.Position = nil, 0
+
+	for _, s := range r.categories {
+		if s.ID != id && s.ParentID == c.ParentID && s.DeletedAt == nil {
+			c.Position = max(c.Position, s.Position+1)
+		}
+	}
+
+	r.categories[id] = c
+
+	return c, nil
+}
@@ -0,0 +1,431 @@
+package product
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"ai-seller/internal/entity"
+)
+
+const (
+	_feedProductIDPlaceholder = "{id}"
+	_feedCategorySeparator    = " > "
+	_feedCondition            = "new"
+	_googleNamespace          = "http://base.google.com/ns/1.0"
+)
+
+// GetFeed returns the feed of the given format. Feeds are kept in memory and
+// only regenerated once the products, categories, images or category
+// mappings they show have changed.
+func (uc *UseCase) GetFeed(ctx context.Context, format string) (entity.Feed, error) {
+	generate, ok := map[string]func([]entity.FeedProduct, []entity.Category, map[string]entity.CategoryFeedMapping, entity.Currency) ([]byte, error){
+		entity.FeedGoogle:   uc.googleFeed,
+		entity.FeedFacebook: uc.facebookFeed,
+		entity.FeedYandex:   uc.yandexFeed,
+	}[format]
+	if !ok {
+		return entity.Feed{}, fmt.Errorf("ProductUseCase - GetFeed - format %q: %w", format, entity.ErrNotFound)
+	}
+
+	uc.feedsMu.Lock()
+	defer uc.feedsMu.Unlock()
+
+	version, err := uc.product.FeedVersion(ctx)
+	if err != nil {
+		return entity.Feed{}, fmt.Errorf("ProductUseCase - GetFeed - s.product.FeedVersion: %w", err)
+	}
+
+	sum := sha256.Sum256([]byte(format + "/" + version))
+	version = hex.EncodeToString(sum[:16])
+
+	if feed, ok := uc.feeds[format]; ok && feed.Version == version {
+		return feed, nil
+	}
+
+	products, err := uc.product.ListFeedProducts(ctx)
+	if err != nil {
+		return entity.Feed{}, fmt.Errorf("ProductUseCase - GetFeed - s.product.ListFeedProducts: %w", err)
+	}
+
+	categories, err := uc.product.GetCategorySubtree(ctx, "")
+	if err != nil {
+		return entity.Feed{}, fmt.Errorf("ProductUseCase - GetFeed - s.product.GetCategorySubtree: %w", err)
+	}
+
+	mappings, err := uc.product.GetFeedMappings(ctx)
+	if err != nil {
+		return entity.Feed{}, fmt.Errorf("ProductUseCase - GetFeed - s.product.GetFeedMappings: %w", err)
+	}
+
+	byCategory := make(map[string]entity.CategoryFeedMapping, len(mappings))
+	for _, m := range mappings {
+		byCategory[m.CategoryID] = m
+	}
+
+	currency, err := uc.baseCurrency(ctx)
+	if err != nil {
+		return entity.Feed{}, fmt.Errorf("ProductUseCase - GetFeed - uc.baseCurrency: %w", err)
+	}
+
+	data, err := generate(products, categories, byCategory, currency)
+	if err != nil {
+		return entity.Feed{}, fmt.Errorf("ProductUseCase - GetFeed - generate %s: %w", format, err)
+	}
+
+	feed := entity.Feed{Format: format, Data: data, Version: version, GeneratedAt: time.Now()}
+	uc.feeds[format] = feed
+
+	return feed, nil
+}
+
+// GetCategoryFeedMapping -.
+func (uc *UseCase) GetCategoryFeedMapping(ctx context.Context, categoryID string) (entity.CategoryFeedMapping, error) {
+	_, err := uc.product.GetCategory(ctx, categoryID)
+	if err != nil {
+		return entity.CategoryFeedMapping{}, fmt.Errorf("ProductUseCase - GetCategoryFeedMapping - s.product.GetCategory: %w", err)
+	}
+
+	m, err := uc.product.GetFeedMapping(ctx, categoryID)
+	if err != nil {
+		return entity.CategoryFeedMapping{}, fmt.Errorf("ProductUseCase - GetCategoryFeedMapping - s.product.GetFeedMapping: %w", err)
+	}
+
+	return m, nil
+}
+
+// SetCategoryFeedMapping stores the marketplace categories of a category.
+// Its subcategories without a mapping of their own inherit them.
+func (uc *UseCase) SetCategoryFeedMapping(ctx context.Context, m entity.CategoryFeedMapping) (entity.CategoryFeedMapping, error) {
+	_, err := uc.product.GetCategory(ctx, m.CategoryID)
+	if err != nil {
+		return entity.CategoryFeedMapping{}, fmt.Errorf("ProductUseCase - SetCategoryFeedMapping - s.product.GetCategory: %w", err)
+	}
+
+	err = uc.product.SetFeedMapping(ctx, m)
+	if err != nil {
+		return entity.CategoryFeedMapping{}, fmt.Errorf("ProductUseCase - SetCategoryFeedMapping - s.product.SetFeedMapping: %w", err)
+	}
+
+	m, err = uc.product.GetFeedMapping(ctx, m.CategoryID)
+	if err != nil {
+		return entity.CategoryFeedMapping{}, fmt.Errorf("ProductUseCase - SetCategoryFeedMapping - s.product.GetFeedMapping: %w", err)
+	}
+
+	return m, nil
+}
+
+type (
+	googleFeed struct {
+		XMLName xml.Name      `xml:"rss"`
+		Version string        `xml:"version,attr"`
+		G       string        `xml:"xmlns:g,attr"`
+		Channel googleChannel `xml:"channel"`
+	}
+
+	googleChannel struct {
+		Title       string       `xml:"title"`
+		Link        string       `xml:"link"`
+		Description string       `xml:"description"`
+		Items       []googleItem `xml:"item"`
+	}
+
+	googleItem struct {
+		ID              string `xml:"g:id"`
+		Title           string `xml:"g:title"`
+		Description     string `xml:"g:description"`
+		Link            string `xml:"g:link"`
+		ImageLink       string `xml:"g:image_link,omitempty"`
+		Availability    string `xml:"g:availability"`
+		Price           string `xml:"g:price"`
+		SalePrice       string `xml:"g:sale_price,omitempty"`
+		ProductCategory string `xml:"g:google_product_category,omitempty"`
+		ProductType     string `xml:"g:product_type,omitempty"`
+		Condition       string `xml:"g:condition"`
+		MPN             string `xml:"g:mpn,omitempty"`
+	}
+)
+
+// googleFeed renders a Google Merchant Center RSS 2.0 feed.
+func (uc *UseCase) googleFeed(products []entity.FeedProduct, categories []entity.Category, mappings map[string]entity.CategoryFeedMapping, currency entity.Currency) ([]byte, error) {
+	paths := categoryPaths(categories, _feedCategorySeparator)
+	mapped := mappedCategories(categories, mappings)
+
+	feed := googleFeed{
+		Version: "2.0",
+		G:       _googleNamespace,
+		Channel: googleChannel{
+			Title:       uc.feed.ShopName,
+			Link:        uc.feed.ShopURL,
+			Description: uc.feed.Company,
+			Items:       make([]googleItem, 0, len(products)),
+		},
+	}
+
+	for _, p := range products {
+		item := googleItem{
+			ID:              p.ID,
+			Title:           p.Name,
+			Description:     feedDescription(p.Product),
+			Link:            uc.productURL(p.ID),
+			ImageLink:       uc.imageURL(p.ImageKey),
+			Availability:    "out_of_stock",
+			Price:           feedPrice(p.Cost, currency),
+			ProductCategory: mapped[p.CategoryID].GoogleCategory,
+			ProductType:     paths[p.CategoryID],
+			Condition:       _feedCondition,
+			MPN:             p.SKU,
+		}
+
+		if p.Available > 0 {
+			item.Availability = "in_stock"
+		}
+
+		if onSale(p.Product) {
+			item.SalePrice = feedPrice(p.DiscountCost, currency)
+		}
+
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+
+	return marshalXML(feed)
+}
+
+var _facebookColumns = []string{
+	"id", "title", "description", "availability", "condition", "price", "sale_price", "link", "image_link",
+	"brand", "google_product_category", "fb_product_category", "product_type",
+}
+
+// facebookFeed renders a Facebook/Instagram catalog CSV. Products have no
+// brand of their own, so the shop name stands in for it.
+func (uc *UseCase) facebookFeed(products []entity.FeedProduct, categories []entity.Category, mappings map[string]entity.CategoryFeedMapping, currency entity.Currency) ([]byte, error) {
+	paths := categoryPaths(categories, _feedCategorySeparator)
+	mapped := mappedCategories(categories, mappings)
+
+	var buf bytes.Buffer
+
+	w := csv.NewWriter(&buf)
+
+	err := w.Write(_facebookColumns)
+	if err != nil {
+		return nil, fmt.Errorf("w.Write: %w", err)
+	}
+
+	for _, p := range products {
+		availability := "out of stock"
+		if p.Available > 0 {
+			availability = "in stock"
+		}
+
+		var salePrice string
+		if onSale(p.Product) {
+			salePrice = feedPrice(p.DiscountCost, currency)
+		}
+
+		err = w.Write([]string{
+			p.ID, p.Name, feedDescription(p.Product), availability, _feedCondition, feedPrice(p.Cost, currency), salePrice,
+			uc.productURL(p.ID), uc.imageURL(p.ImageKey), uc.feed.ShopName,
+			mapped[p.CategoryID].GoogleCategory, mapped[p.CategoryID].FacebookCategory, paths[p.CategoryID],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("w.Write: %w", err)
+		}
+	}
+
+	w.Flush()
+
+	err = w.Error()
+	if err != nil {
+		return nil, fmt.Errorf("w.Flush: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+type (
+	ymlCatalog struct {
+		XMLName xml.Name `xml:"yml_catalog"`
+		Date    string   `xml:"date,attr"`
+		Shop    ymlShop  `xml:"shop"`
+	}
+
+	ymlShop struct {
+		Name       string        `xml:"name"`
+		Company    string        `xml:"company"`
+		URL        string        `xml:"url"`
+		Currencies []ymlCurrency `xml:"currencies>currency"`
+		Categories []ymlCategory `xml:"categories>category"`
+		Offers     []ymlOffer    `xml:"offers>offer"`
+	}
+
+	ymlCurrency struct {
+		ID   string `xml:"id,attr"`
+		Rate string `xml:"rate,attr"`
+	}
+
+	ymlCategory struct {
+		ID       int    `xml:"id,attr"`
+		ParentID int    `xml:"parentId,attr,omitempty"`
+		Name     string `xml:",chardata"`
+	}
+
+	ymlOffer struct {
+		ID          string `xml:"id,attr"`
+		Available   bool   `xml:"available,attr"`
+		URL         string `xml:"url"`
+		Price       string `xml:"price"`
+		OldPrice    string `xml:"oldprice,omitempty"`
+		CurrencyID  string `xml:"currencyId"`
+		CategoryID  int    `xml:"categoryId,omitempty"`
+		Picture     string `xml:"picture,omitempty"`
+		Name        string `xml:"name"`
+		Description string `xml:"description,omitempty"`
+		VendorCode  string `xml:"vendorCode,omitempty"`
+		Count       int    `xml:"count"`
+	}
+)
+
+// yandexFeed renders a Yandex Market YML catalog. YML wants numeric category
+// IDs, so categories are numbered in the order they are listed.
+func (uc *UseCase) yandexFeed(products []entity.FeedProduct, categories []entity.Category, _ map[string]entity.CategoryFeedMapping, currency entity.Currency) ([]byte, error) {
+	numbers := make(map[string]int, len(categories))
+	for i, c := range categories {
+		numbers[c.ID] = i + 1
+	}
+
+	catalog := ymlCatalog{
+		Date: time.Now().Format("2006-01-02T15:04:05-07:00"),
+		Shop: ymlShop{
+			Name:       uc.feed.ShopName,
+			Company:    uc.feed.Company,
+			URL:        uc.feed.ShopURL,
+			Currencies: []ymlCurrency{{ID: currency.Code, Rate: "1"}},
+			Categories: make([]ymlCategory, 0, len(categories)),
+			Offers:     make([]ymlOffer, 0, len(products)),
+		},
+	}
+
+	for _, c := range categories {
+		catalog.Shop.Categories = append(catalog.Shop.Categories, ymlCategory{ID: numbers[c.ID], ParentID: numbers[c.ParentID], Name: c.Name})
+	}
+
+	for _, p := range products {
+		offer := ymlOffer{
+			ID:          p.ID,
+			Available:   p.Available > 0,
+			URL:         uc.productURL(p.ID),
+			Price:       currency.Format(p.UnitPrice()),
+			CurrencyID:  currency.Code,
+			CategoryID:  numbers[p.CategoryID],
+			Picture:     uc.imageURL(p.ImageKey),
+			Name:        p.Name,
+			Description: feedDescription(p.Product),
+			VendorCode:  p.SKU,
+			Count:       p.Available,
+		}
+
+		if onSale(p.Product) {
+			offer.OldPrice = currency.Format(p.Cost)
+		}
+
+		catalog.Shop.Offers = append(catalog.Shop.Offers, offer)
+	}
+
+	return marshalXML(catalog)
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("xml.MarshalIndent: %w", err)
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+// mappedCategories resolves the feed mapping of every category: its own, or
+// else that of its nearest mapped ancestor.
+func mappedCategories(categories []entity.Category, mappings map[string]entity.CategoryFeedMapping) map[string]entity.CategoryFeedMapping {
+	byID := make(map[string]entity.Category, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+
+	mapped := make(map[string]entity.CategoryFeedMapping, len(categories))
+
+	for _, c := range categories {
+		var m entity.CategoryFeedMapping
+
+		for category, ok := c, true; ok; category, ok = byID[category.ParentID] {
+			own := mappings[category.ID]
+			if m.GoogleCategory == "" {
+				m.GoogleCategory = own.GoogleCategory
+			}
+
+			if m.FacebookCategory == "" {
+				m.FacebookCategory = own.FacebookCategory
+			}
+		}
+
+		mapped[c.ID] = m
+	}
+
+	return mapped
+}
+
+// onSale reports whether discount_cost is a real reduction of the price.
+func onSale(p entity.Product) bool {
+	return p.UnitPrice() < p.Cost
+}
+
+// feedDescription falls back to the short info and then the name, since
+// marketplaces reject products without a description.
+func feedDescription(p entity.Product) string {
+	for _, s := range []string{p.Description, p.ShortInfo} {
+		if s = strings.TrimSpace(s); s != "" {
+			return s
+		}
+	}
+
+	return p.Name
+}
+
+// feedPrice writes an amount the way marketplaces want it: "10.50 USD".
+func feedPrice(amount int, currency entity.Currency) string {
+	return currency.Format(amount) + " " + currency.Code
+}
+
+func (uc *UseCase) productURL(id string) string {
+	return uc.absoluteURL(strings.ReplaceAll(uc.feed.ProductURL, _feedProductIDPlaceholder, url.PathEscape(id)))
+}
+
+func (uc *UseCase) imageURL(key string) string {
+	if key == "" {
+		return ""
+	}
+
+	return uc.absoluteURL(uc.mediaURL + "/" + key)
+}
+
+// absoluteURL resolves ref against the shop URL; marketplaces only accept
+// absolute links.
+func (uc *UseCase) absoluteURL(ref string) string {
+	base, err := url.Parse(uc.feed.ShopURL)
+	if err != nil {
+		return ref
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+
+	return base.ResolveReference(u).String()
+}
@@ -0,0 +1,262 @@
+package product
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/internal/entity"
+)
+
+// feedRepo holds a discounted phone in electronics > phones and a cable
+// without a category that is out of stock and whose discount_cost is no
+// reduction. Electronics is mapped to Google, phones to Facebook.
+func feedRepo() *fakeRepo {
+	r := newFakeRepo()
+	r.addCategory(entity.Category{ID: "electronics", Name: "Electronics"})
+	r.addCategory(entity.Category{ID: "phones", Name: "Phones", ParentID: "electronics"})
+	r.feedVersion = "1"
+	r.feedProducts = []entity.FeedProduct{
+		{
+			Product: entity.Product{
+				ID: "p1", Name: "Phone", SKU: "PH-1", CategoryID: "phones", ShortInfo: " Smart phone ",
+				Cost: 100000, DiscountCost: 90000, Available: 3,
+			},
+			ImageKey: "p1/main.jpg",
+		},
+		{Product: entity.Product{ID: "p2", Name: "Cable", Cost: 1050, DiscountCost: 2000}},
+	}
+	r.feedMappings = []entity.CategoryFeedMapping{
+		{CategoryID: "electronics", GoogleCategory: "Electronics"},
+		{CategoryID: "phones", FacebookCategory: "Phones & Accessories"},
+	}
+
+	return r
+}
+
+func newFeedUseCase(t *testing.T, r *fakeRepo) *UseCase {
+	t.Helper()
+
+	return newTestUseCase(t, r,
+		FeedSettings(entity.FeedSettings{ShopName: "Shop", Company: "Shop LLC", ShopURL: "https://shop.test", ProductURL: "/product/{id}"}),
+		MediaURL("/v1/media"),
+	)
+}
+
+func renderFeed(t *testing.T, format string) []byte {
+	t.Helper()
+
+	feed, err := newFeedUseCase(t, feedRepo()).GetFeed(context.Background(), format)
+	require.NoError(t, err)
+	require.Equal(t, format, feed.Format)
+
+	return feed.Data
+}
+
+func TestGoogleFeed(t *testing.T) {
+	t.Parallel()
+
+	type item struct {
+		ID              string `xml:"http://base.google.com/ns/1.0 id"`
+		Title           string `xml:"http://base.google.com/ns/1.0 title"`
+		Description     string `xml:"http://base.google.com/ns/1.0 description"`
+		Link            string `xml:"http://base.google.com/ns/1.0 link"`
+		ImageLink       string `xml:"http://base.google.com/ns/1.0 image_link"`
+		Availability    string `xml:"http://base.google.com/ns/1.0 availability"`
+		Price           string `xml:"http://base.google.com/ns/1.0 price"`
+		SalePrice       string `xml:"http://base.google.com/ns/1.0 sale_price"`
+		ProductCategory string `xml:"http://base.google.com/ns/1.0 google_product_category"`
+		ProductType     string `xml:"http://base.google.com/ns/1.0 product_type"`
+		Condition       string `xml:"http://base.google.com/ns/1.0 condition"`
+		MPN             string `xml:"http://base.google.com/ns/1.0 mpn"`
+	}
+
+	var rss struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title string `xml:"title"`
+			Link  string `xml:"link"`
+			Items []item `xml:"item"`
+		} `xml:"channel"`
+	}
+
+	require.NoError(t, xml.Unmarshal(renderFeed(t, entity.FeedGoogle), &rss))
+	require.Equal(t, "2.0", rss.Version)
+	require.Equal(t, "Shop", rss.Channel.Title)
+	require.Equal(t, "https://shop.test", rss.Channel.Link)
+	require.Equal(t, []item{
+		{
+			ID: "p1", Title: "Phone", Description: "Smart phone",
+			Link: "https://shop.test/product/p1", ImageLink: "https://shop.test/v1/media/p1/main.jpg",
+			Availability: "in_stock", Price: "1000.00 USD", SalePrice: "900.00 USD",
+			ProductCategory: "Electronics", ProductType: "Electronics > Phones", Condition: "new", MPN: "PH-1",
+		},
+		{
+			ID: "p2", Title: "Cable", Description: "Cable", Link: "https://shop.test/product/p2",
+			Availability: "out_of_stock", Price: "10.50 USD", Condition: "new",
+		},
+	}, rss.Channel.Items)
+}
+
+func TestFacebookFeed(t *testing.T) {
+	t.Parallel()
+
+	rows, err := csv.NewReader(bytes.NewReader(renderFeed(t, entity.FeedFacebook))).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		_facebookColumns,
+		{
+			"p1", "Phone", "Smart phone", "in stock", "new", "1000.00 USD", "900.00 USD",
+			"https://shop.test/product/p1", "https://shop.test/v1/media/p1/main.jpg", "Shop",
+			"Electronics", "Phones & Accessories", "Electronics > Phones",
+		},
+		{
+			"p2", "Cable", "Cable", "out of stock", "new", "10.50 USD", "",
+			"https://shop.test/product/p2", "", "Shop", "", "", "",
+		},
+	}, rows)
+}
+
+func TestYandexFeed(t *testing.T) {
+	t.Parallel()
+
+	var catalog ymlCatalog
+
+	require.NoError(t, xml.Unmarshal(renderFeed(t, entity.FeedYandex), &catalog))
+	require.NotEmpty(t, catalog.Date)
+
+	shop := catalog.Shop
+	require.Equal(t, "Shop", shop.Name)
+	require.Equal(t, "Shop LLC", shop.Company)
+	require.Equal(t, []ymlCurrency{{ID: "USD", Rate: "1"}}, shop.Currencies)
+	require.Equal(t, []ymlCategory{{ID: 1, Name: "Electronics"}, {ID: 2, ParentID: 1, Name: "Phones"}}, shop.Categories)
+	require.Equal(t, []ymlOffer{
+		{
+			ID: "p1", Available: true, URL: "https://shop.test/product/p1", Price: "900.00", OldPrice: "1000.00",
+			CurrencyID: "USD", CategoryID: 2, Picture: "https://shop.test/v1/media/p1/main.jpg",
+			Name: "Phone", Description: "Smart phone", VendorCode: "PH-1", Count: 3,
+		},
+		{
+			ID: "p2", URL: "https://shop.test/product/p2", Price: "10.50", CurrencyID: "USD",
+			Name: "Cable", Description: "Cable",
+		},
+	}, shop.Offers)
+}
+
+func TestGetFeed(t *testing.T) {
+	t.Parallel()
+
+	r := feedRepo()
+	uc := newFeedUseCase(t, r)
+	ctx := context.Background()
+
+	first, err := uc.GetFeed(ctx, entity.FeedGoogle)
+	require.NoError(t, err)
+
+	cached, err := uc.GetFeed(ctx, entity.FeedGoogle)
+	require.NoError(t, err)
+	require.Equal(t, first, cached)
+	require.Equal(t, 1, r.feedReads)
+
+	other, err := uc.GetFeed(ctx, entity.FeedYandex)
+	require.NoError(t, err)
+	require.NotEqual(t, first.Version, other.Version)
+	require.Equal(t, 2, r.feedReads)
+
+	r.feedVersion = "2"
+
+	changed, err := uc.GetFeed(ctx, entity.FeedGoogle)
+	require.NoError(t, err)
+	require.NotEqual(t, first.Version, changed.Version)
+	require.Equal(t, 3, r.feedReads)
+
+	_, err = uc.GetFeed(ctx, "amazon")
+	require.ErrorIs(t, err, entity.ErrNotFound)
+}
+
+func TestMappedCategories(t *testing.T) {
+	t.Parallel()
+
+	categories := []entity.Category{
+		{ID: "electronics"},
+		{ID: "phones", ParentID: "electronics"},
+		{ID: "android", ParentID: "phones"},
+		{ID: "clothes"},
+	}
+
+	tests := []struct {
+		name     string
+		mappings []entity.CategoryFeedMapping
+		want     map[string]entity.CategoryFeedMapping
+	}{
+		{
+			name: "none",
+			want: map[string]entity.CategoryFeedMapping{"electronics": {}, "phones": {}, "android": {}, "clothes": {}},
+		},
+		{
+			name:     "inherited by descendants",
+			mappings: []entity.CategoryFeedMapping{{CategoryID: "electronics", GoogleCategory: "G", FacebookCategory: "F"}},
+			want: map[string]entity.CategoryFeedMapping{
+				"electronics": {GoogleCategory: "G", FacebookCategory: "F"},
+				"phones":      {GoogleCategory: "G", FacebookCategory: "F"},
+				"android":     {GoogleCategory: "G", FacebookCategory: "F"},
+				"clothes":     {},
+			},
+		},
+		{
+			name: "nearest mapping wins per marketplace",
+			mappings: []entity.CategoryFeedMapping{
+				{CategoryID: "electronics", GoogleCategory: "G", FacebookCategory: "F"},
+				{CategoryID: "phones", GoogleCategory: "G phones"},
+			},
+			want: map[string]entity.CategoryFeedMapping{
+				"electronics": {GoogleCategory: "G", FacebookCategory: "F"},
+				"phones":      {GoogleCategory: "G phones", FacebookCategory: "F"},
+				"android":     {GoogleCategory: "G phones", FacebookCategory: "F"},
+				"clothes":     {},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mappings := make(map[string]entity.CategoryFeedMapping, len(tc.mappings))
+			for _, m := range tc.mappings {
+				mappings[m.CategoryID] = m
+			}
+
+			require.Equal(t, tc.want, mappedCategories(categories, mappings))
+		})
+	}
+}
+
+func TestFeedURLs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		settings entity.FeedSettings
+		id       string
+		want     string
+	}{
+		{name: "relative", settings: entity.FeedSettings{ShopURL: "https://shop.test", ProductURL: "/product/{id}"}, id: "p1", want: "https://shop.test/product/p1"},
+		{name: "shop path", settings: entity.FeedSettings{ShopURL: "https://shop.test/uz/", ProductURL: "p/{id}"}, id: "p1", want: "https://shop.test/uz/p/p1"},
+		{name: "absolute", settings: entity.FeedSettings{ShopURL: "https://shop.test", ProductURL: "https://m.shop.test/{id}"}, id: "p1", want: "https://m.shop.test/p1"},
+		{name: "escaped id", settings: entity.FeedSettings{ShopURL: "https://shop.test", ProductURL: "/product/{id}"}, id: "a/b c", want: "https://shop.test/product/a%2Fb%20c"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			uc := newTestUseCase(t, newFakeRepo(), FeedSettings(tc.settings))
+			require.Equal(t, tc.want, uc.productURL(tc.id))
+		})
+	}
+}
@@ -0,0 +1,754 @@
+package product
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"ai-seller/internal/entity"
+)
+
+const (
+	_maxImportSize    = 32 << 20
+	_importBatchSize  = 100
+	_maxImportErrors  = 1000
+	_utf8BOM          = "\uFEFF"
+	_xlsxSignature    = "PK\x03\x04"
+	_csvDelimiters    = ",;\t"
+	_defaultDelimiter = ','
+)
+
+// ImportProducts reads a CSV or XLSX catalog file and imports its rows in the
+// background. The file is parsed up front, so malformed files and mappings
+// are rejected right away; the returned job reports the progress.
+//
+// Rows are matched to products by SKU, or by name when they have no SKU, and
+// create the product when nothing matches. A row whose SKU is a variant's
+// updates the prices and stock of that variant. Categories are given as paths
+// such as "Electronics / Phones" and created when missing, as are string
+// attributes named in attribute columns. Empty cells leave a field as it is.
+func (uc *UseCase) ImportProducts(ctx context.Context, i entity.CatalogImport, r io.Reader) (entity.ImportJob, error) {
+	data, err := io.ReadAll(io.LimitReader(r, _maxImportSize+1))
+	if err != nil {
+		return entity.ImportJob{}, fmt.Errorf("ProductUseCase - ImportProducts - io.ReadAll: %w", err)
+	}
+
+	if len(data) > _maxImportSize {
+		return entity.ImportJob{}, fmt.Errorf("ProductUseCase - ImportProducts - over %d bytes: %w", _maxImportSize, entity.ErrTooLarge)
+	}
+
+	format := entity.CatalogFormatCSV
+	if bytes.HasPrefix(data, []byte(_xlsxSignature)) {
+		format = entity.CatalogFormatXLSX
+	}
+
+	records, err := readCatalog(format, data)
+	if err != nil {
+		return entity.ImportJob{}, err
+	}
+
+	currency, err := uc.baseCurrency(ctx)
+	if err != nil {
+		return entity.ImportJob{}, fmt.Errorf("ProductUseCase - ImportProducts - uc.baseCurrency: %w", err)
+	}
+
+	rows, rowErrors, err := importRows(records, i.Mapping, currency)
+	if err != nil {
+		return entity.ImportJob{}, err
+	}
+
+	job, err := uc.product.CreateImportJob(ctx, entity.ImportJob{
+		Status:    entity.ImportStatusPending,
+		Format:    format,
+		FileName:  i.FileName,
+		TotalRows: len(rows) + len(rowErrors),
+		CreatedBy: i.CreatedBy,
+	})
+	if err != nil {
+		return entity.ImportJob{}, fmt.Errorf("ProductUseCase - ImportProducts - s.product.CreateImportJob: %w", err)
+	}
+
+	j := importJob{job}
+	j.Errors = []entity.ImportRowError{}
+
+	for _, e := range rowErrors {
+		j.addError(e)
+	}
+
+	uc.background("runImport", func(ctx context.Context) error {
+		return uc.runImport(ctx, j, rows)
+	})
+
+	return j.ImportJob, nil
+}
+
+// GetImportJob -.
+func (uc *UseCase) GetImportJob(ctx context.Context, id string) (entity.ImportJob, error) {
+	job, err := uc.product.GetImportJob(ctx, id)
+	if err != nil {
+		return entity.ImportJob{}, fmt.Errorf("ProductUseCase - GetImportJob - s.product.GetImportJob: %w", err)
+	}
+
+	return job, nil
+}
+
+// RecoverImportJobs fails the jobs left pending or running by a previous
+// run of the service. Imports run in the process that accepted them, so on
+// startup no such job is making progress any more.
+func (uc *UseCase) RecoverImportJobs(ctx context.Context) (int, error) {
+	n, err := uc.product.FailImportJobs(ctx, []string{entity.ImportStatusPending, entity.ImportStatusRunning}, "interrupted by a restart")
+	if err != nil {
+		return 0, fmt.Errorf("ProductUseCase - RecoverImportJobs - s.product.FailImportJobs: %w", err)
+	}
+
+	return n, nil
+}
+
+// runImport imports rows batch by batch, storing the progress of job after
+// every batch. When the import cannot go on, the job is marked failed with
+// the error; the rows imported so far stay committed.
+func (uc *UseCase) runImport(ctx context.Context, job importJob, rows []entity.ImportRow) error {
+	job.Status = entity.ImportStatusRunning
+	job.ProcessedRows = job.FailedRows
+
+	err := uc.product.UpdateImportJob(ctx, job.ImportJob)
+	if err != nil {
+		return uc.failImport(ctx, &job, fmt.Errorf("s.product.UpdateImportJob: %w", err))
+	}
+
+	for batch := range slices.Chunk(rows, _importBatchSize) {
+		err = uc.importBatch(ctx, &job, batch)
+		if err != nil {
+			return uc.failImport(ctx, &job, err)
+		}
+
+		err = uc.product.UpdateImportJob(ctx, job.ImportJob)
+		if err != nil {
+			return uc.failImport(ctx, &job, fmt.Errorf("s.product.UpdateImportJob: %w", err))
+		}
+	}
+
+	finished := time.Now()
+	job.Status = entity.ImportStatusDone
+	job.FinishedAt = &finished
+
+	err = uc.product.UpdateImportJob(ctx, job.ImportJob)
+	if err != nil {
+		return fmt.Errorf("job %s - s.product.UpdateImportJob: %w", job.ID, err)
+	}
+
+	return nil
+}
+
+// failImport marks job failed with cause and returns cause. The job is
+// stored even when ctx was cancelled by shutdown.
+func (uc *UseCase) failImport(ctx context.Context, job *importJob, cause error) error {
+	finished := time.Now()
+	job.Status = entity.ImportStatusFailed
+	job.Error = cause.Error()
+	job.FinishedAt = &finished
+
+	cause = fmt.Errorf("job %s: %w", job.ID, cause)
+
+	err := uc.product.UpdateImportJob(context.WithoutCancel(ctx), job.ImportJob)
+	if err != nil {
+		return errors.Join(cause, fmt.Errorf("s.product.UpdateImportJob: %w", err))
+	}
+
+	return cause
+}
+
+// importBatch imports rows in one transaction. When a row fails the batch is
+// rolled back and retried row by row, so that only the failing rows are lost.
+// The import is only stopped, with an error, when ctx is done.
+func (uc *UseCase) importBatch(ctx context.Context, job *importJob, rows []entity.ImportRow) error {
+	var (
+		created int
+		ids     []string
+	)
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		created, ids = 0, ids[:0]
+
+		for _, row := range rows {
+			id, isNew, err := uc.importRow(ctx, row)
+			if err != nil {
+				return err
+			}
+
+			if isNew {
+				created++
+			}
+
+			ids = append(ids, id)
+		}
+
+		return nil
+	})
+	if err == nil {
+		job.ProcessedRows += len(rows)
+		job.CreatedRows += created
+		job.UpdatedRows += len(rows) - created
+
+		uc.stockChanged(ctx, ids...)
+
+		return nil
+	}
+
+	ids = ids[:0]
+
+	for _, row := range rows {
+		if ctx.Err() != nil {
+			uc.stockChanged(ctx, ids...)
+
+			return fmt.Errorf("interrupted: %w", ctx.Err())
+		}
+
+		var (
+			id    string
+			isNew bool
+		)
+
+		err = uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			id, isNew, err = uc.importRow(ctx, row)
+
+			return err
+		})
+
+		job.ProcessedRows++
+
+		switch {
+		case err != nil:
+			job.addError(entity.ImportRowError{Row: row.Line, Message: importErrorMessage(err)})
+
+			continue
+		case isNew:
+			job.CreatedRows++
+		default:
+			job.UpdatedRows++
+		}
+
+		ids = append(ids, id)
+	}
+
+	uc.stockChanged(ctx, ids...)
+
+	return nil
+}
+
+// importRow creates or updates the product of row and returns its ID and
+// whether it was created.
+func (uc *UseCase) importRow(ctx context.Context, row entity.ImportRow) (string, bool, error) {
+	matches, err := uc.product.FindProducts(ctx, row.SKU, row.Name)
+	if err != nil {
+		return "", false, fmt.Errorf("s.product.FindProducts: %w", err)
+	}
+
+	if len(matches) > 1 {
+		return "", false, entity.NewValidationError("name", "matches several products, add a sku column")
+	}
+
+	if len(matches) == 0 && row.SKU != "" {
+		variant, err := uc.product.FindVariant(ctx, row.SKU)
+
+		switch {
+		case err == nil:
+			return variant.ProductID, false, uc.importVariantRow(ctx, variant, row)
+		case !errors.Is(err, entity.ErrNotFound):
+			return "", false, fmt.Errorf("s.product.FindVariant: %w", err)
+		}
+	}
+
+	var p entity.Product
+
+	isNew := len(matches) == 0
+	if !isNew {
+		p = matches[0]
+	}
+
+	if row.Columns[entity.ImportColumnCategory] {
+		p.CategoryID, err = uc.importCategory(ctx, row.Category)
+		if err != nil {
+			return "", false, err
+		}
+	}
+
+	count := p.Count
+
+	applyImportRow(&p, row)
+
+	if isNew {
+		if p.Name == "" {
+			return "", false, entity.NewValidationError("name", "is required for new products")
+		}
+
+		p.ID, err = uc.product.CreateProduct(ctx, p)
+		if err != nil {
+			return "", false, fmt.Errorf("s.product.CreateProduct: %w", err)
+		}
+
+		err = uc.setStock(ctx, p.ID, "", 0, p.Count, entity.ReasonInitial)
+	} else {
+		err = uc.product.UpdateProduct(ctx, p)
+		if err != nil {
+			return "", false, fmt.Errorf("s.product.UpdateProduct: %w", err)
+		}
+
+		err = uc.setStock(ctx, p.ID, "", count, p.Count, entity.ReasonCorrection)
+	}
+
+	if err != nil {
+		return "", false, err
+	}
+
+	if len(row.Attributes) > 0 {
+		err = uc.importAttributes(ctx, p, row.Attributes)
+		if err != nil {
+			return "", false, err
+		}
+	}
+
+	return p.ID, isNew, nil
+}
+
+func applyImportRow(p *entity.Product, row entity.ImportRow) {
+	for column := range row.Columns {
+		switch column {
+		case entity.ImportColumnSKU:
+			p.SKU = row.SKU
+		case entity.ImportColumnName:
+			p.Name = row.Name
+		case entity.ImportColumnShortInfo:
+			p.ShortInfo = row.ShortInfo
+		case entity.ImportColumnDescription:
+			p.Description = row.Description
+		case entity.ImportColumnCost:
+			p.Cost = row.Cost
+		case entity.ImportColumnCount:
+			p.Count = row.Count
+		case entity.ImportColumnDiscountCost:
+			p.DiscountCost = row.DiscountCost
+		case entity.ImportColumnDiscount:
+			p.Discount = row.Discount
+		}
+	}
+}
+
+// importVariantRow updates the prices and stock of a variant matched by its
+// SKU. The other columns describe the product and are ignored.
+func (uc *UseCase) importVariantRow(ctx context.Context, v entity.ProductVariant, row entity.ImportRow) error {
+	count := v.Count
+
+	for column := range row.Columns {
+		switch column {
+		case entity.ImportColumnCost:
+			v.Cost = row.Cost
+		case entity.ImportColumnCount:
+			v.Count = row.Count
+		case entity.ImportColumnDiscountCost:
+			v.DiscountCost = row.DiscountCost
+		case entity.ImportColumnDiscount:
+			v.Discount = row.Discount
+		}
+	}
+
+	err := uc.product.UpdateVariant(ctx, v)
+	if err != nil {
+		return fmt.Errorf("s.product.UpdateVariant: %w", err)
+	}
+
+	return uc.setStock(ctx, v.ProductID, v.ID, count, v.Count, entity.ReasonCorrection)
+}
+
+// importCategory returns the ID of the category at path, creating the
+// missing levels.
+func (uc *UseCase) importCategory(ctx context.Context, path string) (string, error) {
+	var parentID string
+
+	for _, name := range strings.Split(path, strings.TrimSpace(entity.ImportCategorySeparator)) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return "", entity.NewValidationError("category", "has an empty level")
+		}
+
+		category, err := uc.product.FindCategory(ctx, parentID, name)
+
+		switch {
+		case err == nil:
+			parentID = category.ID
+		case errors.Is(err, entity.ErrNotFound):
+			parentID, err = uc.product.CreateCategory(ctx, entity.Category{Name: name, ParentID: parentID})
+			if err != nil {
+				return "", fmt.Errorf("s.product.CreateCategory: %w", err)
+			}
+		default:
+			return "", fmt.Errorf("s.product.FindCategory: %w", err)
+		}
+	}
+
+	return parentID, nil
+}
+
+// importAttributes sets the attribute values of p given by attribute name,
+// keeping its other values. Unknown attributes are created as strings.
+func (uc *UseCase) importAttributes(ctx context.Context, p entity.Product, values map[string]string) error {
+	if p.CategoryID == "" {
+		return entity.NewValidationError("category", "is required to set attributes")
+	}
+
+	definitions, err := uc.product.GetAttributesByCategory(ctx, p.CategoryID)
+	if err != nil {
+		return fmt.Errorf("s.product.GetAttributesByCategory: %w", err)
+	}
+
+	current, err := uc.product.GetProductAttributes(ctx, p.ID)
+	if err != nil {
+		return fmt.Errorf("s.product.GetProductAttributes: %w", err)
+	}
+
+	for name, raw := range values {
+		idx := slices.IndexFunc(definitions, func(a entity.Attribute) bool { return a.Name == name })
+		if idx < 0 {
+			a := entity.Attribute{Name: name, CategoryID: p.CategoryID, Type: entity.AttributeTypeString}
+
+			a.ID, err = uc.product.CreateAttribute(ctx, a)
+			if err != nil {
+				return fmt.Errorf("s.product.CreateAttribute: %w", err)
+			}
+
+			definitions = append(definitions, a)
+			idx = len(definitions) - 1
+		}
+
+		value, err := parseAttributeValue(definitions[idx], raw)
+		if err != nil {
+			return err
+		}
+
+		current = slices.DeleteFunc(current, func(v entity.ProductAttribute) bool { return v.AttributeID == definitions[idx].ID })
+		current = append(current, entity.ProductAttribute{AttributeID: definitions[idx].ID, Value: value})
+	}
+
+	checked, err := checkProductAttributes(definitions, current)
+	if err != nil {
+		return err
+	}
+
+	err = uc.product.ReplaceProductAttributes(ctx, p.ID, checked)
+	if err != nil {
+		return fmt.Errorf("s.product.ReplaceProductAttributes: %w", err)
+	}
+
+	return nil
+}
+
+// parseAttributeValue converts a cell into the JSON type of the attribute.
+func parseAttributeValue(a entity.Attribute, raw string) (interface{}, error) {
+	switch a.Type {
+	case entity.AttributeTypeNumber, entity.AttributeTypeUnit:
+		f, err := strconv.ParseFloat(strings.ReplaceAll(raw, ",", "."), 64)
+		if err != nil {
+			return nil, entity.NewValidationError("attributes."+a.Name, "must be a number")
+		}
+
+		return f, nil
+	case entity.AttributeTypeBoolean:
+		b, err := strconv.ParseBool(strings.ToLower(raw))
+		if err != nil {
+			return nil, entity.NewValidationError("attributes."+a.Name, "must be true or false")
+		}
+
+		return b, nil
+	default:
+		return raw, nil
+	}
+}
+
+// importJob is an ImportJob whose error list stops growing at
+// _maxImportErrors; later failures are only counted.
+type importJob struct {
+	entity.ImportJob
+}
+
+func (j *importJob) addError(e entity.ImportRowError) {
+	j.FailedRows++
+
+	if len(j.Errors) < _maxImportErrors {
+		j.Errors = append(j.Errors, e)
+	}
+}
+
+// importErrorMessage describes why a row failed without exposing internals.
+func importErrorMessage(err error) string {
+	var validationErr *entity.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Error()
+	}
+
+	for _, target := range []error{entity.ErrConflict, entity.ErrForeignKey, entity.ErrPreconditionFailed, entity.ErrValidation} {
+		if errors.Is(err, target) {
+			return target.Error()
+		}
+	}
+
+	return "internal error"
+}
+
+// readCatalog returns the records of the first sheet of an XLSX file, or of
+// a CSV file separated by commas, semicolons or tabs.
+func readCatalog(format string, data []byte) ([][]string, error) {
+	if format == entity.CatalogFormatXLSX {
+		f, err := excelize.OpenReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, entity.NewValidationError("file", "is not a valid XLSX file")
+		}
+		defer f.Close()
+
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, entity.NewValidationError("file", "has no sheets")
+		}
+
+		records, err := f.GetRows(sheets[0])
+		if err != nil {
+			return nil, entity.NewValidationError("file", "is not a valid XLSX file")
+		}
+
+		return records, nil
+	}
+
+	data = bytes.TrimPrefix(data, []byte(_utf8BOM))
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = csvDelimiter(data)
+	r.FieldsPerRecord = -1
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, entity.NewValidationError("file", "is not a valid CSV file: "+err.Error())
+	}
+
+	return records, nil
+}
+
+// csvDelimiter guesses the delimiter from the header line.
+func csvDelimiter(data []byte) rune {
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+
+	delimiter, most := rune(_defaultDelimiter), 0
+	for _, d := range _csvDelimiters {
+		if n := bytes.Count(header, []byte(string(d))); n > most {
+			delimiter, most = d, n
+		}
+	}
+
+	return delimiter
+}
+
+// importRows maps the records of a catalog file to rows. Costs are decimals
+// in the base currency. Rows with malformed cells are reported as row errors
+// rather than failing the whole file.
+func importRows(records [][]string, mapping map[string]string, currency entity.Currency) ([]entity.ImportRow, []entity.ImportRowError, error) {
+	if len(records) < 2 {
+		return nil, nil, entity.NewValidationError("file", "has no rows below the header")
+	}
+
+	columns, err := importColumns(records[0], mapping)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows := make([]entity.ImportRow, 0, len(records)-1)
+
+	var rowErrors []entity.ImportRowError
+
+	for i, record := range records[1:] {
+		line := i + 2
+
+		if !slices.ContainsFunc(record, func(cell string) bool { return strings.TrimSpace(cell) != "" }) {
+			continue
+		}
+
+		row, err := readImportRow(line, record, columns, currency)
+		if err != nil {
+			rowErrors = append(rowErrors, entity.ImportRowError{Row: line, Message: importErrorMessage(err)})
+			continue
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, rowErrors, nil
+}
+
+// importColumns resolves catalog columns to their index in header.
+func importColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		index[strings.TrimSpace(h)] = i
+	}
+
+	columns := make(map[string]int, len(header))
+
+	if len(mapping) == 0 {
+		for i, h := range header {
+			h = strings.TrimSpace(h)
+
+			if column := strings.ToLower(h); slices.Contains(entity.ImportColumns, column) {
+				columns[column] = i
+			} else if name, ok := strings.CutPrefix(h, entity.ImportAttributePrefix); ok && name != "" {
+				columns[entity.ImportAttributePrefix+name] = i
+			}
+		}
+	}
+
+	for column, h := range mapping {
+		name, isAttribute := strings.CutPrefix(column, entity.ImportAttributePrefix)
+		if !slices.Contains(entity.ImportColumns, column) && (!isAttribute || name == "") {
+			return nil, entity.NewValidationError("mapping", "unknown column "+column)
+		}
+
+		i, ok := index[strings.TrimSpace(h)]
+		if !ok {
+			return nil, entity.NewValidationError("mapping."+column, "header "+h+" is not in the file")
+		}
+
+		columns[column] = i
+	}
+
+	_, hasSKU := columns[entity.ImportColumnSKU]
+	_, hasName := columns[entity.ImportColumnName]
+
+	if !hasSKU && !hasName {
+		return nil, entity.NewValidationError("mapping", "needs a sku or name column")
+	}
+
+	return columns, nil
+}
+
+// readImportRow reads the cells of one record. Empty cells are left out of
+// Columns so that they do not overwrite the product.
+func readImportRow(line int, record []string, columns map[string]int, currency entity.Currency) (entity.ImportRow, error) {
+	row := entity.ImportRow{Line: line, Columns: map[string]bool{}, Attributes: map[string]string{}}
+
+	for column, i := range columns {
+		if i >= len(record) {
+			continue
+		}
+
+		cell := unescapeFormula(strings.TrimSpace(record[i]))
+		if cell == "" {
+			continue
+		}
+
+		if name, ok := strings.CutPrefix(column, entity.ImportAttributePrefix); ok {
+			row.Attributes[name] = cell
+			continue
+		}
+
+		row.Columns[column] = true
+
+		var err error
+
+		switch column {
+		case entity.ImportColumnSKU:
+			row.SKU = cell
+		case entity.ImportColumnName:
+			row.Name = cell
+		case entity.ImportColumnCategory:
+			row.Category = cell
+		case entity.ImportColumnShortInfo:
+			row.ShortInfo = cell
+		case entity.ImportColumnDescription:
+			row.Description = cell
+		case entity.ImportColumnCost:
+			row.Cost, err = parseImportAmount(column, cell, currency)
+		case entity.ImportColumnCount:
+			row.Count, err = parseImportInt(column, cell)
+		case entity.ImportColumnDiscountCost:
+			row.DiscountCost, err = parseImportAmount(column, cell, currency)
+		case entity.ImportColumnDiscount:
+			row.Discount, err = parseImportInt(column, cell)
+		}
+
+		if err != nil {
+			return entity.ImportRow{}, err
+		}
+	}
+
+	if row.SKU == "" && row.Name == "" {
+		return entity.ImportRow{}, entity.NewValidationError("sku", "or name is required")
+	}
+
+	return row, nil
+}
+
+// unescapeFormula drops the apostrophe that ExportProducts puts before cells
+// starting like a formula.
+func unescapeFormula(cell string) string {
+	if len(cell) > 1 && cell[0] == '\'' && strings.ContainsRune(_formulaPrefixes, rune(cell[1])) {
+		return cell[1:]
+	}
+
+	return cell
+}
+
+// importNumber removes the spaces and thousands separators spreadsheets write
+// into numbers and makes the decimal separator a point. The last comma or
+// point is the decimal separator, unless it occurs more than once, as in
+// "1,200,000", when it only groups thousands.
+func importNumber(cell string) string {
+	cell = strings.NewReplacer(" ", "", "\u00A0", "", "\u202F", "").Replace(cell)
+
+	i := strings.LastIndexAny(cell, ",.")
+	if i < 0 {
+		return cell
+	}
+
+	separator, other := cell[i:i+1], ","
+	if separator == "," {
+		other = "."
+	}
+
+	if strings.Contains(cell[:i], separator) {
+		if strings.Contains(cell, other) {
+			return cell
+		}
+
+		return strings.ReplaceAll(cell, separator, "")
+	}
+
+	return strings.ReplaceAll(cell[:i], other, "") + "." + cell[i+1:]
+}
+
+// parseImportAmount reads a non-negative decimal amount in major units, e.g.
+// "1 200,50" or "1,200.50", into minor units of currency.
+func parseImportAmount(column, cell string, currency entity.Currency) (int, error) {
+	amount, err := currency.ParseAmount(importNumber(cell))
+	if err != nil || amount < 0 {
+		return 0, entity.NewValidationError(column, "must be a non-negative amount")
+	}
+
+	return amount, nil
+}
+
+// parseImportInt accepts whole non-negative numbers as spreadsheets write
+// them, e.g. "1 200", "1.200.000" or "1200.00".
+func parseImportInt(column, cell string) (int, error) {
+	f, err := strconv.ParseFloat(importNumber(cell), 64)
+	if err != nil || f < 0 || f != math.Trunc(f) || f > math.MaxInt32 {
+		return 0, entity.NewValidationError(column, "must be a whole non-negative number")
+	}
+
+	return int(f), nil
+}
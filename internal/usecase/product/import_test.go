@@ -0,0 +1,252 @@
+package product
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/internal/entity"
+)
+
+func TestCSVDelimiter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data string
+		want rune
+	}{
+		{name: "comma", data: "sku,name,cost\n1,a,2", want: ','},
+		{name: "semicolon", data: "sku;name;cost\n1;\"a,b,c,d\";2", want: ';'},
+		{name: "tab", data: "sku\tname\tcost\n", want: '\t'},
+		{name: "single column", data: "name\nshirt", want: ','},
+		{name: "empty", data: "", want: ','},
+		{name: "ties go to the comma", data: "a,b;c\n", want: ','},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, csvDelimiter([]byte(tc.data)))
+		})
+	}
+}
+
+func TestImportColumns(t *testing.T) {
+	t.Parallel()
+
+	header := []string{" SKU ", "Name", "Price", "attribute:color", "attribute:", "notes"}
+
+	tests := []struct {
+		name    string
+		mapping map[string]string
+		want    map[string]int
+		field   string
+	}{
+		{
+			name: "default mapping",
+			want: map[string]int{"sku": 0, "name": 1, "attribute:color": 3},
+		},
+		{
+			name:    "mapping",
+			mapping: map[string]string{"name": "Name", "cost": " Price", "attribute:note": "notes"},
+			want:    map[string]int{"name": 1, "cost": 2, "attribute:note": 5},
+		},
+		{
+			name:    "unknown column",
+			mapping: map[string]string{"price": "Price"},
+			field:   "mapping",
+		},
+		{
+			name:    "attribute without a name",
+			mapping: map[string]string{"attribute:": "notes"},
+			field:   "mapping",
+		},
+		{
+			name:    "header not in the file",
+			mapping: map[string]string{"name": "Title"},
+			field:   "mapping.name",
+		},
+		{
+			name:    "no sku or name",
+			mapping: map[string]string{"cost": "Price"},
+			field:   "mapping",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := importColumns(header, tc.mapping)
+			if tc.field != "" {
+				var v *entity.ValidationError
+				require.ErrorAs(t, err, &v)
+				require.Equal(t, tc.field, v.Field)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestReadImportRow(t *testing.T) {
+	t.Parallel()
+
+	columns := map[string]int{
+		entity.ImportColumnSKU:      0,
+		entity.ImportColumnName:     1,
+		entity.ImportColumnCost:     2,
+		entity.ImportColumnCount:    3,
+		entity.ImportColumnDiscount: 4,
+		"attribute:color":           5,
+	}
+
+	tests := []struct {
+		name   string
+		record []string
+		want   entity.ImportRow
+		field  string
+	}{
+		{
+			name:   "all cells",
+			record: []string{" A-1 ", "Shirt", "1 200,50", "3", "10", "red"},
+			want: entity.ImportRow{
+				Line:       2,
+				Columns:    map[string]bool{"sku": true, "name": true, "cost": true, "count": true, "discount": true},
+				SKU:        "A-1",
+				Name:       "Shirt",
+				Cost:       120050,
+				Count:      3,
+				Discount:   10,
+				Attributes: map[string]string{"color": "red"},
+			},
+		},
+		{
+			name:   "empty and missing cells are left out",
+			record: []string{"", "Shirt", "  ", "0"},
+			want: entity.ImportRow{
+				Line:       2,
+				Columns:    map[string]bool{"name": true, "count": true},
+				Name:       "Shirt",
+				Attributes: map[string]string{},
+			},
+		},
+		{
+			name:   "escaped formula",
+			record: []string{"'-1", "'=HYPERLINK()", "", "", "", "'red"},
+			want: entity.ImportRow{
+				Line:       2,
+				Columns:    map[string]bool{"sku": true, "name": true},
+				SKU:        "-1",
+				Name:       "=HYPERLINK()",
+				Attributes: map[string]string{"color": "'red"},
+			},
+		},
+		{name: "no sku or name", record: []string{"", "", "10"}, field: "sku"},
+		{name: "negative cost", record: []string{"A-1", "", "-1"}, field: "cost"},
+		{name: "fractional count", record: []string{"A-1", "", "", "1.5"}, field: "count"},
+		{name: "bad discount", record: []string{"A-1", "", "", "", "ten"}, field: "discount"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := readImportRow(2, tc.record, columns, _usd)
+			if tc.field != "" {
+				var v *entity.ValidationError
+				require.ErrorAs(t, err, &v)
+				require.Equal(t, tc.field, v.Field)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParseImportAmount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cell string
+		want int
+		err  bool
+	}{
+		{name: "plain", cell: "12.5", want: 1250},
+		{name: "decimal comma", cell: "12,5", want: 1250},
+		{name: "spaces", cell: "1 200,50", want: 120050},
+		{name: "no-break spaces", cell: "1 200 000", want: 120000000},
+		{name: "comma thousands", cell: "1,200.50", want: 120050},
+		{name: "point thousands", cell: "1.200,50", want: 120050},
+		{name: "repeated comma thousands", cell: "1,200,000", want: 120000000},
+		{name: "repeated point thousands", cell: "1.200.000", want: 120000000},
+		{name: "both thousands", cell: "1,234,567.89", want: 123456789},
+		{name: "single separator is decimal", cell: "1,200", want: 120},
+		{name: "mixed repeated", cell: "1.200,000,5", err: true},
+		{name: "negative", cell: "-1", err: true},
+		{name: "letters", cell: "ten", err: true},
+		{name: "separator only", cell: ",", err: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseImportAmount("cost", tc.cell, _usd)
+			if tc.err {
+				require.ErrorIs(t, err, entity.ErrValidation)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParseImportInt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cell string
+		want int
+		err  bool
+	}{
+		{name: "plain", cell: "12", want: 12},
+		{name: "spaces", cell: "1 200", want: 1200},
+		{name: "zero decimals", cell: "1200.00", want: 1200},
+		{name: "zero decimals comma", cell: "1200,00", want: 1200},
+		{name: "repeated thousands", cell: "1.200.000", want: 1200000},
+		{name: "thousands and decimals", cell: "1,200.00", want: 1200},
+		{name: "fraction", cell: "1,5", err: true},
+		{name: "negative", cell: "-1", err: true},
+		{name: "too big", cell: "3000000000", err: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseImportInt("count", tc.cell)
+			if tc.err {
+				require.ErrorIs(t, err, entity.ErrValidation)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
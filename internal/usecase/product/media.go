@@ -0,0 +1,327 @@
+package product
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/fs"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/google/uuid"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // registers the WebP decoder
+
+	"ai-seller/internal/entity"
+)
+
+const (
+	_maxImagePixels   = 50_000_000
+	_thumbnailQuality = 85
+)
+
+// _mediaExtensions lists the accepted image types, detected from the content
+// rather than the file name, with the extension they are stored under.
+var _mediaExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// ListMedia -.
+func (uc *UseCase) ListMedia(ctx context.Context, productID string) ([]entity.ProductMedia, error) {
+	_, err := uc.product.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - ListMedia - s.product.GetProduct: %w", err)
+	}
+
+	media, err := uc.product.GetMediaByProduct(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - ListMedia - s.product.GetMediaByProduct: %w", err)
+	}
+
+	uc.setMediaURLs(media)
+
+	return media, nil
+}
+
+// UploadMedia stores an image of a product with its thumbnails and appends
+// it to the product's images. The type is sniffed from the content; JPEG,
+// PNG, GIF and WebP up to the configured size are accepted.
+func (uc *UseCase) UploadMedia(ctx context.Context, productID string, r io.Reader) (entity.ProductMedia, error) {
+	_, err := uc.product.GetProduct(ctx, productID)
+	if err != nil {
+		return entity.ProductMedia{}, fmt.Errorf("ProductUseCase - UploadMedia - s.product.GetProduct: %w", err)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, uc.mediaMaxSize+1))
+	if err != nil {
+		return entity.ProductMedia{}, fmt.Errorf("ProductUseCase - UploadMedia - io.ReadAll: %w", err)
+	}
+
+	if int64(len(data)) > uc.mediaMaxSize {
+		return entity.ProductMedia{}, fmt.Errorf("ProductUseCase - UploadMedia - over %d bytes: %w", uc.mediaMaxSize, entity.ErrTooLarge)
+	}
+
+	contentType := http.DetectContentType(data)
+
+	ext, ok := _mediaExtensions[contentType]
+	if !ok {
+		return entity.ProductMedia{}, fmt.Errorf("ProductUseCase - UploadMedia - %s: %w", contentType, entity.ErrUnsupportedMedia)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width == 0 || config.Height == 0 {
+		return entity.ProductMedia{}, entity.NewValidationError("file", "is not a valid image")
+	}
+
+	if config.Width*config.Height > _maxImagePixels {
+		return entity.ProductMedia{}, entity.NewValidationError("file", "has too many pixels")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return entity.ProductMedia{}, entity.NewValidationError("file", "is not a valid image")
+	}
+
+	base := "product/" + productID + "/" + uuid.NewString()
+	m := entity.ProductMedia{
+		ProductID:   productID,
+		Key:         base + ext,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Width:       config.Width,
+		Height:      config.Height,
+	}
+
+	files := map[string][]byte{m.Key: data}
+
+	for _, size := range uc.thumbnailSizes {
+		if size >= max(m.Width, m.Height) {
+			continue
+		}
+
+		t, thumb, err := thumbnail(img, size, contentType)
+		if err != nil {
+			return entity.ProductMedia{}, fmt.Errorf("ProductUseCase - UploadMedia - thumbnail: %w", err)
+		}
+
+		t.Key = base + "_" + strconv.Itoa(size) + thumbnailExtension(contentType)
+		m.Thumbnails = append(m.Thumbnails, t)
+		files[t.Key] = thumb
+	}
+
+	stored := make([]string, 0, len(files))
+
+	for key, content := range files {
+		err = uc.media.Put(ctx, key, bytes.NewReader(content), int64(len(content)), http.DetectContentType(content))
+		if err != nil {
+			uc.deleteMediaFiles(ctx, stored)
+
+			return entity.ProductMedia{}, fmt.Errorf("ProductUseCase - UploadMedia - s.media.Put: %w", err)
+		}
+
+		stored = append(stored, key)
+	}
+
+	err = uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		m, err = uc.product.CreateMedia(ctx, m)
+		if err != nil {
+			return fmt.Errorf("s.product.CreateMedia: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		uc.deleteMediaFiles(ctx, stored)
+
+		return entity.ProductMedia{}, fmt.Errorf("ProductUseCase - UploadMedia - s.tx.WithinTransaction: %w", err)
+	}
+
+	if m.Thumbnails == nil {
+		m.Thumbnails = []entity.MediaThumbnail{}
+	}
+
+	uc.setMediaURL(&m)
+
+	return m, nil
+}
+
+// SetPrimaryMedia makes an image the one that represents its product.
+func (uc *UseCase) SetPrimaryMedia(ctx context.Context, productID, id string) (entity.ProductMedia, error) {
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := uc.product.SetPrimaryMedia(ctx, productID, id)
+		if err != nil {
+			return fmt.Errorf("s.product.SetPrimaryMedia: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.ProductMedia{}, fmt.Errorf("ProductUseCase - SetPrimaryMedia - s.tx.WithinTransaction: %w", err)
+	}
+
+	m, err := uc.product.GetMedia(ctx, productID, id)
+	if err != nil {
+		return entity.ProductMedia{}, fmt.Errorf("ProductUseCase - SetPrimaryMedia - s.product.GetMedia: %w", err)
+	}
+
+	uc.setMediaURL(&m)
+
+	return m, nil
+}
+
+// ReorderMedia sets the order of the images of a product. o must list every
+// image exactly once.
+func (uc *UseCase) ReorderMedia(ctx context.Context, productID string, o entity.MediaOrder) ([]entity.ProductMedia, error) {
+	var media []entity.ProductMedia
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, err := uc.product.GetMediaByProduct(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("s.product.GetMediaByProduct: %w", err)
+		}
+
+		ids := slices.Sorted(slices.Values(o.Media))
+		if len(ids) != len(current) || len(slices.Compact(ids)) != len(current) {
+			return entity.NewValidationError("media", "must list every image of the product exactly once")
+		}
+
+		for _, m := range current {
+			if _, found := slices.BinarySearch(ids, m.ID); !found {
+				return entity.NewValidationError("media", "must list every image of the product exactly once")
+			}
+		}
+
+		err = uc.product.ReorderMedia(ctx, productID, o.Media)
+		if err != nil {
+			return fmt.Errorf("s.product.ReorderMedia: %w", err)
+		}
+
+		media, err = uc.product.GetMediaByProduct(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("s.product.GetMediaByProduct: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - ReorderMedia - s.tx.WithinTransaction: %w", err)
+	}
+
+	uc.setMediaURLs(media)
+
+	return media, nil
+}
+
+// DeleteMedia removes an image of a product and its files.
+func (uc *UseCase) DeleteMedia(ctx context.Context, productID, id string) error {
+	var m entity.ProductMedia
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+
+		m, err = uc.product.GetMedia(ctx, productID, id)
+		if err != nil {
+			return fmt.Errorf("s.product.GetMedia: %w", err)
+		}
+
+		err = uc.product.DeleteMedia(ctx, m)
+		if err != nil {
+			return fmt.Errorf("s.product.DeleteMedia: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - DeleteMedia - s.tx.WithinTransaction: %w", err)
+	}
+
+	uc.deleteMediaFiles(ctx, m.Keys())
+
+	return nil
+}
+
+// OpenMedia returns a stored file and its size.
+func (uc *UseCase) OpenMedia(ctx context.Context, key string) (io.ReadCloser, int64, error) {
+	r, size, err := uc.media.Get(ctx, key)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, 0, fmt.Errorf("ProductUseCase - OpenMedia - s.media.Get: %w", errors.Join(entity.ErrNotFound, err))
+	}
+
+	if err != nil {
+		return nil, 0, fmt.Errorf("ProductUseCase - OpenMedia - s.media.Get: %w", err)
+	}
+
+	return r, size, nil
+}
+
+// deleteMediaFiles removes stored files on a best-effort basis: the rows
+// referencing them are already gone, and an orphaned file is harmless.
+func (uc *UseCase) deleteMediaFiles(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		_ = uc.media.Delete(ctx, key)
+	}
+}
+
+func (uc *UseCase) setMediaURLs(media []entity.ProductMedia) {
+	for i := range media {
+		uc.setMediaURL(&media[i])
+	}
+}
+
+func (uc *UseCase) setMediaURL(m *entity.ProductMedia) {
+	m.URL = uc.mediaURL + "/" + m.Key
+	for i := range m.Thumbnails {
+		m.Thumbnails[i].URL = uc.mediaURL + "/" + m.Thumbnails[i].Key
+	}
+}
+
+// thumbnail scales img down so that its longer side is size pixels. Images
+// that may be transparent stay PNG, everything else becomes JPEG.
+func thumbnail(img image.Image, size int, contentType string) (entity.MediaThumbnail, []byte, error) {
+	bounds := img.Bounds()
+
+	t := entity.MediaThumbnail{Size: size, Width: size, Height: size}
+	if bounds.Dx() >= bounds.Dy() {
+		t.Height = max(1, bounds.Dy()*size/bounds.Dx())
+	} else {
+		t.Width = max(1, bounds.Dx()*size/bounds.Dy())
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, t.Width, t.Height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+
+	var (
+		buf bytes.Buffer
+		err error
+	)
+
+	if thumbnailExtension(contentType) == ".png" {
+		err = png.Encode(&buf, dst)
+	} else {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: _thumbnailQuality})
+	}
+
+	if err != nil {
+		return entity.MediaThumbnail{}, nil, err
+	}
+
+	return t, buf.Bytes(), nil
+}
+
+func thumbnailExtension(contentType string) string {
+	if contentType == "image/png" || contentType == "image/gif" {
+		return ".png"
+	}
+
+	return ".jpg"
+}
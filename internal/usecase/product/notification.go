@@ -0,0 +1,230 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"ai-seller/internal/entity"
+)
+
+// GetLowStockThreshold -.
+func (uc *UseCase) GetLowStockThreshold(ctx context.Context, productID string) (entity.LowStockThreshold, error) {
+	threshold, err := uc.product.GetLowStockThreshold(ctx, productID)
+	if err != nil {
+		return entity.LowStockThreshold{}, fmt.Errorf("ProductUseCase - GetLowStockThreshold - s.product.GetLowStockThreshold: %w", err)
+	}
+
+	return threshold, nil
+}
+
+// SetLowStockThreshold sets the count below which managers are alerted about
+// a product. A product already below it is alerted right away.
+func (uc *UseCase) SetLowStockThreshold(ctx context.Context, t entity.LowStockThreshold) (entity.LowStockThreshold, error) {
+	_, err := uc.product.GetProduct(ctx, t.ProductID)
+	if err != nil {
+		return entity.LowStockThreshold{}, fmt.Errorf("ProductUseCase - SetLowStockThreshold - s.product.GetProduct: %w", err)
+	}
+
+	threshold, err := uc.product.SetLowStockThreshold(ctx, t)
+	if err != nil {
+		return entity.LowStockThreshold{}, fmt.Errorf("ProductUseCase - SetLowStockThreshold - s.product.SetLowStockThreshold: %w", err)
+	}
+
+	uc.stockChanged(ctx, t.ProductID)
+
+	return threshold, nil
+}
+
+// DeleteLowStockThreshold stops low-stock alerts for a product.
+func (uc *UseCase) DeleteLowStockThreshold(ctx context.Context, productID string) error {
+	err := uc.product.DeleteLowStockThreshold(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - DeleteLowStockThreshold - s.product.DeleteLowStockThreshold: %w", err)
+	}
+
+	return nil
+}
+
+// SubscribeToStock asks for a message to the user once an out-of-stock
+// product is back.
+func (uc *UseCase) SubscribeToStock(ctx context.Context, s entity.StockSubscription) (entity.StockSubscription, error) {
+	if _, err := strconv.ParseInt(s.ChatID, 10, 64); err != nil {
+		return entity.StockSubscription{}, fmt.Errorf("ProductUseCase - SubscribeToStock: %w",
+			entity.NewValidationError("chat_id", "must be a numeric Telegram chat id"))
+	}
+
+	product, err := uc.product.GetProduct(ctx, s.ProductID)
+	if err != nil {
+		return entity.StockSubscription{}, fmt.Errorf("ProductUseCase - SubscribeToStock - s.product.GetProduct: %w", err)
+	}
+
+	if product.Available > 0 {
+		return entity.StockSubscription{}, fmt.Errorf("ProductUseCase - SubscribeToStock: %w",
+			entity.NewValidationError("product_id", "the product is in stock"))
+	}
+
+	subscription, err := uc.product.CreateStockSubscription(ctx, s)
+	if err != nil {
+		return entity.StockSubscription{}, fmt.Errorf("ProductUseCase - SubscribeToStock - s.product.CreateStockSubscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// UnsubscribeFromStock -.
+func (uc *UseCase) UnsubscribeFromStock(ctx context.Context, userID, productID string) error {
+	err := uc.product.DeleteStockSubscription(ctx, userID, productID)
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - UnsubscribeFromStock - s.product.DeleteStockSubscription: %w", err)
+	}
+
+	return nil
+}
+
+// ListStockSubscriptions returns who waits for a product, oldest first.
+func (uc *UseCase) ListStockSubscriptions(ctx context.Context, productID string) ([]entity.StockSubscription, error) {
+	_, err := uc.product.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - ListStockSubscriptions - s.product.GetProduct: %w", err)
+	}
+
+	subscriptions, err := uc.product.GetStockSubscriptions(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - ListStockSubscriptions - s.product.GetStockSubscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// stockChanged checks the products in the background once their stock was
+// changed and committed, see notifyStock.
+func (uc *UseCase) stockChanged(_ context.Context, productIDs ...string) {
+	uc.background("notifyStock", func(ctx context.Context) error {
+		return uc.notifyStock(ctx, productIDs)
+	})
+}
+
+// orderStockChanged is stockChanged for the products of an order.
+func (uc *UseCase) orderStockChanged(_ context.Context, orderID string) {
+	uc.background("notifyStock", func(ctx context.Context) error {
+		lines, err := uc.product.GetOrderProductsByOrder(ctx, orderID)
+		if err != nil {
+			return fmt.Errorf("s.product.GetOrderProductsByOrder: %w", err)
+		}
+
+		return uc.notifyStock(ctx, orderProductIDs(lines))
+	})
+}
+
+// notifyStock alerts managers about products whose count dropped below their
+// threshold and messages the customers waiting for products available again.
+// Each alert and subscription is claimed before it is sent, so concurrent
+// checks send it once, and released when sending fails, so the next check
+// tries again.
+func (uc *UseCase) notifyStock(ctx context.Context, productIDs []string) error {
+	seen := make(map[string]bool, len(productIDs))
+
+	var errs []error
+
+	for _, id := range productIDs {
+		if seen[id] {
+			continue
+		}
+
+		seen[id] = true
+
+		product, err := uc.product.GetProduct(ctx, id)
+		if errors.Is(err, entity.ErrNotFound) {
+			continue
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("s.product.GetProduct: %w", err))
+
+			continue
+		}
+
+		err = uc.alertLowStock(ctx, product)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		if product.Available == 0 {
+			continue
+		}
+
+		err = uc.notifySubscribers(ctx, product)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (uc *UseCase) alertLowStock(ctx context.Context, product entity.Product) error {
+	threshold, err := uc.product.ClaimLowStockAlert(ctx, product.ID)
+	if errors.Is(err, entity.ErrNotFound) {
+		err = uc.product.ResetLowStockAlert(ctx, product.ID)
+		if err != nil {
+			return fmt.Errorf("s.product.ResetLowStockAlert: %w", err)
+		}
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("s.product.ClaimLowStockAlert: %w", err)
+	}
+
+	err = uc.notifier.Notify(ctx, "", fmt.Sprintf("Low stock: %s has %d left, threshold %d",
+		product.Name, product.Count, threshold.Threshold))
+	if err != nil {
+		err = fmt.Errorf("product %s - s.notifier.Notify: %w", product.ID, err)
+
+		releaseErr := uc.product.ReleaseLowStockAlert(ctx, product.ID)
+		if releaseErr != nil {
+			return errors.Join(err, fmt.Errorf("s.product.ReleaseLowStockAlert: %w", releaseErr))
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (uc *UseCase) notifySubscribers(ctx context.Context, product entity.Product) error {
+	subscriptions, err := uc.product.ClaimStockSubscriptions(ctx, product.ID)
+	if err != nil {
+		return fmt.Errorf("s.product.ClaimStockSubscriptions: %w", err)
+	}
+
+	var errs []error
+
+	for _, s := range subscriptions {
+		err = uc.notifier.Notify(ctx, s.ChatID, fmt.Sprintf("%s is back in stock", product.Name))
+		if err == nil {
+			continue
+		}
+
+		errs = append(errs, fmt.Errorf("subscription %s - s.notifier.Notify: %w", s.ID, err))
+
+		err = uc.product.ReleaseStockSubscription(ctx, s)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("s.product.ReleaseStockSubscription: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func orderProductIDs(lines []entity.OrderProducts) []string {
+	ids := make([]string, 0, len(lines))
+	for _, line := range lines {
+		ids = append(ids, line.ProductID)
+	}
+
+	return ids
+}
@@ -0,0 +1,61 @@
+package product
+
+import (
+	"time"
+
+	"ai-seller/internal/entity"
+	"ai-seller/pkg/logger"
+)
+
+// Option -.
+type Option func(*UseCase)
+
+// Logger sets where the errors of background work are logged.
+func Logger(l logger.Interface) Option {
+	return func(uc *UseCase) {
+		uc.l = l
+	}
+}
+
+// MediaMaxSize -.
+func MediaMaxSize(size int64) Option {
+	return func(uc *UseCase) {
+		uc.mediaMaxSize = size
+	}
+}
+
+// MediaURL sets the base URL under which stored media keys are served.
+func MediaURL(base string) Option {
+	return func(uc *UseCase) {
+		uc.mediaURL = base
+	}
+}
+
+// ThumbnailSizes sets the lengths of the longer side of generated thumbnails.
+func ThumbnailSizes(sizes ...int) Option {
+	return func(uc *UseCase) {
+		uc.thumbnailSizes = sizes
+	}
+}
+
+// FeedSettings sets how the shop is described in marketplace feeds.
+func FeedSettings(s entity.FeedSettings) Option {
+	return func(uc *UseCase) {
+		uc.feed = s
+	}
+}
+
+// ReservationTTL sets how long stock stays reserved for an unpaid order.
+func ReservationTTL(d time.Duration) Option {
+	return func(uc *UseCase) {
+		uc.reservationTTL = d
+	}
+}
+
+// TrashRetention sets how long deleted records stay restorable before they
+// are purged.
+func TrashRetention(d time.Duration) Option {
+	return func(uc *UseCase) {
+		uc.trashRetention = d
+	}
+}
@@ -0,0 +1,346 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ai-seller/internal/entity"
+)
+
+// PlaceOrder creates an order with its lines in a single transaction: the
+// referenced products are locked, available stock is checked and reserved
+// until the order is paid or the reservation expires, unit prices are
+// snapshotted, promotions and coupons are applied and the total is computed
+// server side. Items of products with variants must name a variant, whose
+// price and stock apply.
+func (uc *UseCase) PlaceOrder(ctx context.Context, no entity.NewOrder) (entity.Order, error) {
+	items := mergeOrderItems(no.Items)
+	if len(items) == 0 {
+		return entity.Order{}, fmt.Errorf("ProductUseCase - PlaceOrder: %w", entity.NewValidationError("items", "must not be empty"))
+	}
+
+	var order entity.Order
+
+	expiresAt := time.Now().Add(uc.reservationTTL)
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		quote, err := uc.priceOrder(ctx, no, items, true)
+		if err != nil {
+			return err
+		}
+
+		order = entity.Order{
+			UserID:        no.UserID,
+			IntegrationID: no.IntegrationID,
+			Status:        entity.OrderStatusNew,
+			SubtotalCost:  quote.SubtotalCost,
+			DiscountCost:  quote.DiscountCost,
+			TotalCost:     quote.TotalCost,
+			Currency:      quote.Currency,
+			ExchangeRate:  quote.ExchangeRate,
+		}
+
+		order.ID, err = uc.product.CreateOrder(ctx, order)
+		if err != nil {
+			return fmt.Errorf("s.product.CreateOrder: %w", err)
+		}
+
+		err = uc.product.CreateOrderStatusChange(ctx, entity.OrderStatusChange{
+			OrderID:   order.ID,
+			ToStatus:  order.Status,
+			ChangedBy: no.PlacedBy,
+		})
+		if err != nil {
+			return fmt.Errorf("s.product.CreateOrderStatusChange: %w", err)
+		}
+
+		for _, line := range quote.Products {
+			line.OrderID = order.ID
+
+			_, err = uc.product.CreateOrderProducts(ctx, line)
+			if err != nil {
+				return fmt.Errorf("s.product.CreateOrderProducts: %w", err)
+			}
+
+			err = uc.reserveStock(ctx, line, expiresAt)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, d := range quote.Discounts {
+			d.OrderID = order.ID
+
+			err = uc.product.CreateOrderDiscount(ctx, d)
+			if err != nil {
+				return fmt.Errorf("s.product.CreateOrderDiscount: %w", err)
+			}
+
+			err = uc.product.CreatePromotionUsage(ctx, entity.PromotionUsage{
+				PromotionID: d.PromotionID,
+				CouponID:    d.CouponID,
+				OrderID:     order.ID,
+				UserID:      no.UserID,
+			})
+			if err != nil {
+				return fmt.Errorf("s.product.CreatePromotionUsage: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.Order{}, fmt.Errorf("ProductUseCase - PlaceOrder - s.tx.WithinTransaction: %w", err)
+	}
+
+	placed, err := uc.GetOrder(ctx, order.ID)
+	if err != nil {
+		return entity.Order{}, fmt.Errorf("ProductUseCase - PlaceOrder - uc.GetOrder: %w", err)
+	}
+
+	return placed, nil
+}
+
+// QuoteOrder prices an order the way PlaceOrder would, with the discounts
+// that would apply and why, without placing it.
+func (uc *UseCase) QuoteOrder(ctx context.Context, no entity.NewOrder) (entity.OrderQuote, error) {
+	items := mergeOrderItems(no.Items)
+	if len(items) == 0 {
+		return entity.OrderQuote{}, fmt.Errorf("ProductUseCase - QuoteOrder: %w", entity.NewValidationError("items", "must not be empty"))
+	}
+
+	var quote entity.OrderQuote
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+
+		quote, err = uc.priceOrder(ctx, no, items, false)
+
+		return err
+	})
+	if err != nil {
+		return entity.OrderQuote{}, fmt.Errorf("ProductUseCase - QuoteOrder - s.tx.WithinTransaction: %w", err)
+	}
+
+	return quote, nil
+}
+
+// priceOrder checks the stock of the ordered products and computes the order
+// lines, discounts and totals in the order currency. With place, the products
+// and the promotions with usage limits are locked. Must run in a transaction.
+func (uc *UseCase) priceOrder(ctx context.Context, no entity.NewOrder, items []entity.NewOrderItem, place bool) (entity.OrderQuote, error) {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ProductID)
+	}
+
+	products, variants, err := uc.orderStock(ctx, ids, place)
+	if err != nil {
+		return entity.OrderQuote{}, err
+	}
+
+	pr, err := uc.newPricing(ctx, no.Currency, ids)
+	if err != nil {
+		return entity.OrderQuote{}, err
+	}
+
+	byID := make(map[string]entity.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+
+	variantByID := make(map[string]entity.ProductVariant, len(variants))
+	hasVariants := make(map[string]bool, len(products))
+
+	for _, v := range variants {
+		variantByID[v.ID] = v
+		hasVariants[v.ProductID] = true
+	}
+
+	quote := entity.OrderQuote{
+		Currency:     pr.currency.Code,
+		ExchangeRate: pr.rate,
+		Products:     make([]entity.OrderProducts, 0, len(items)),
+	}
+	categories := make([]string, 0, len(items))
+	subtotal := pr.zero()
+
+	for _, item := range items {
+		p, ok := byID[item.ProductID]
+		if !ok {
+			return entity.OrderQuote{}, fmt.Errorf("product %s: %w", item.ProductID, entity.ErrForeignKey)
+		}
+
+		line := entity.OrderProducts{ProductID: p.ID, VariantID: item.VariantID, Count: item.Count}
+		stock, basePrice := p.Available, p.UnitPrice()
+
+		switch {
+		case item.VariantID != "":
+			v, ok := variantByID[item.VariantID]
+			if !ok || v.ProductID != p.ID {
+				return entity.OrderQuote{}, fmt.Errorf("variant %s of product %s: %w", item.VariantID, p.ID, entity.ErrForeignKey)
+			}
+
+			stock, basePrice = v.Available, v.UnitPrice()
+		case hasVariants[p.ID]:
+			return entity.OrderQuote{}, entity.NewValidationError("items.variant_id", "is required for product "+p.ID)
+		}
+
+		if stock < item.Count {
+			return entity.OrderQuote{}, fmt.Errorf("product %s has %d available, requested %d: %w", p.ID, stock, item.Count, entity.ErrInsufficientStock)
+		}
+
+		price := pr.unitPrice(p.ID, item.VariantID, basePrice)
+		subtotal = subtotal.Add(price.Mul(line.Count))
+
+		line.Cost = price.Amount
+		quote.Products = append(quote.Products, line)
+		categories = append(categories, p.CategoryID)
+	}
+
+	quote.Discounts, err = uc.applyPromotions(ctx, no, quote.Products, categories, pr, place)
+	if err != nil {
+		return entity.OrderQuote{}, err
+	}
+
+	quote.SubtotalCost = subtotal.Amount
+	quote.DiscountCost = totalDiscount(quote.Discounts)
+	quote.TotalCost = quote.Subtotal().Sub(quote.Discount()).Amount
+
+	return quote, nil
+}
+
+// orderStock reads the ordered products and their variants. Only placing an
+// order locks them; a quote or a cart is priced from a plain read.
+func (uc *UseCase) orderStock(ctx context.Context, ids []string, place bool) ([]entity.Product, []entity.ProductVariant, error) {
+	if !place {
+		products, err := uc.product.GetProducts(ctx, ids)
+		if err != nil {
+			return nil, nil, fmt.Errorf("s.product.GetProducts: %w", err)
+		}
+
+		variants, err := uc.product.GetVariantsByProducts(ctx, ids)
+		if err != nil {
+			return nil, nil, fmt.Errorf("s.product.GetVariantsByProducts: %w", err)
+		}
+
+		return products, variants, nil
+	}
+
+	products, err := uc.product.LockProducts(ctx, ids)
+	if err != nil {
+		return nil, nil, fmt.Errorf("s.product.LockProducts: %w", err)
+	}
+
+	variants, err := uc.product.LockVariantsByProducts(ctx, ids)
+	if err != nil {
+		return nil, nil, fmt.Errorf("s.product.LockVariantsByProducts: %w", err)
+	}
+
+	return products, variants, nil
+}
+
+// ChangeOrderStatus moves an order along its lifecycle and records the change
+// in the order's timeline. Paying an order takes its reserved items off the
+// stock; cancelling or returning it releases the reservation or puts the
+// items back on stock.
+func (uc *UseCase) ChangeOrderStatus(ctx context.Context, orderID string, u entity.OrderStatusUpdate) (entity.Order, error) {
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		order, err := uc.product.LockOrder(ctx, orderID)
+		if err != nil {
+			return fmt.Errorf("s.product.LockOrder: %w", err)
+		}
+
+		if !entity.CanTransitionOrder(order.Status, u.Status) {
+			return fmt.Errorf("%s -> %s: %w", order.Status, u.Status, entity.ErrInvalidTransition)
+		}
+
+		return uc.moveOrder(ctx, order, u, entity.ReservationReleased)
+	})
+	if err != nil {
+		return entity.Order{}, fmt.Errorf("ProductUseCase - ChangeOrderStatus - s.tx.WithinTransaction: %w", err)
+	}
+
+	uc.orderStockChanged(ctx, orderID)
+
+	order, err := uc.GetOrder(ctx, orderID)
+	if err != nil {
+		return entity.Order{}, fmt.Errorf("ProductUseCase - ChangeOrderStatus - uc.GetOrder: %w", err)
+	}
+
+	return order, nil
+}
+
+// moveOrder changes the status of a locked order, see ChangeOrderStatus.
+// Active reservations of a cancelled order end up in status release.
+func (uc *UseCase) moveOrder(ctx context.Context, order entity.Order, u entity.OrderStatusUpdate, release string) error {
+	err := uc.product.UpdateOrderStatus(ctx, order.ID, order.Status, u.Status)
+	if err != nil {
+		return fmt.Errorf("s.product.UpdateOrderStatus: %w", err)
+	}
+
+	err = uc.product.CreateOrderStatusChange(ctx, entity.OrderStatusChange{
+		OrderID:    order.ID,
+		FromStatus: order.Status,
+		ToStatus:   u.Status,
+		ChangedBy:  u.ChangedBy,
+		Comment:    u.Comment,
+	})
+	if err != nil {
+		return fmt.Errorf("s.product.CreateOrderStatusChange: %w", err)
+	}
+
+	// A cancelled order gives its promotions and coupons back.
+	if u.Status == entity.OrderStatusCancelled {
+		err = uc.product.DeletePromotionUsageByOrder(ctx, order.ID)
+		if err != nil {
+			return fmt.Errorf("s.product.DeletePromotionUsageByOrder: %w", err)
+		}
+	}
+
+	switch {
+	case u.Status == entity.OrderStatusPaid:
+		return uc.consumeReservations(ctx, order.ID)
+	case entity.RestocksOrder(u.Status):
+		return uc.restockOrder(ctx, order.ID, release)
+	default:
+		return nil
+	}
+}
+
+// GetOrderTimeline returns the status changes of an order, oldest first.
+func (uc *UseCase) GetOrderTimeline(ctx context.Context, orderID string) ([]entity.OrderStatusChange, error) {
+	_, err := uc.product.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - GetOrderTimeline - s.product.GetOrder: %w", err)
+	}
+
+	history, err := uc.product.GetOrderStatusHistory(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - GetOrderTimeline - s.product.GetOrderStatusHistory: %w", err)
+	}
+
+	return history, nil
+}
+
+// mergeOrderItems sums counts of repeated products and variants, keeping
+// first-seen order.
+func mergeOrderItems(items []entity.NewOrderItem) []entity.NewOrderItem {
+	merged := make([]entity.NewOrderItem, 0, len(items))
+	index := make(map[[2]string]int, len(items))
+
+	for _, item := range items {
+		key := [2]string{item.ProductID, item.VariantID}
+
+		if i, ok := index[key]; ok {
+			merged[i].Count += item.Count
+			continue
+		}
+
+		index[key] = len(merged)
+		merged = append(merged, item)
+	}
+
+	return merged
+}
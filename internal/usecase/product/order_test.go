@@ -0,0 +1,205 @@
+package product
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/internal/entity"
+)
+
+func TestMergeOrderItems(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		items []entity.NewOrderItem
+		want  []entity.NewOrderItem
+	}{
+		{name: "none", items: nil, want: []entity.NewOrderItem{}},
+		{
+			name:  "distinct",
+			items: []entity.NewOrderItem{{ProductID: "p1", Count: 1}, {ProductID: "p2", Count: 2}},
+			want:  []entity.NewOrderItem{{ProductID: "p1", Count: 1}, {ProductID: "p2", Count: 2}},
+		},
+		{
+			name:  "repeated product",
+			items: []entity.NewOrderItem{{ProductID: "p1", Count: 1}, {ProductID: "p2", Count: 2}, {ProductID: "p1", Count: 3}},
+			want:  []entity.NewOrderItem{{ProductID: "p1", Count: 4}, {ProductID: "p2", Count: 2}},
+		},
+		{
+			name: "variants apart",
+			items: []entity.NewOrderItem{
+				{ProductID: "p1", VariantID: "v1", Count: 1},
+				{ProductID: "p1", VariantID: "v2", Count: 1},
+				{ProductID: "p1", VariantID: "v1", Count: 2},
+			},
+			want: []entity.NewOrderItem{
+				{ProductID: "p1", VariantID: "v1", Count: 3},
+				{ProductID: "p1", VariantID: "v2", Count: 1},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, mergeOrderItems(tc.items))
+		})
+	}
+}
+
+// orderRepo holds a plain product, a product with two variants and a
+// product of its own with a variant.
+func orderRepo() *fakeRepo {
+	r := newFakeRepo()
+	r.addProduct(entity.Product{ID: "plain", Cost: 1000, Count: 5, Available: 5})
+	r.addProduct(entity.Product{ID: "shirt", Cost: 2000, Count: 3, Available: 3})
+	r.addVariant(entity.ProductVariant{ID: "shirt-s", ProductID: "shirt", Cost: 2000, Count: 1, Available: 1})
+	r.addVariant(entity.ProductVariant{ID: "shirt-m", ProductID: "shirt", Cost: 2500, DiscountCost: 2200, Count: 2, Available: 2})
+	r.addProduct(entity.Product{ID: "hat", Cost: 500, Count: 1, Available: 1})
+	r.addVariant(entity.ProductVariant{ID: "hat-red", ProductID: "hat", Cost: 500, Count: 1, Available: 1})
+
+	return r
+}
+
+func TestPriceOrder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		items []entity.NewOrderItem
+		want  []entity.OrderProducts
+		total int
+		err   error
+	}{
+		{
+			name:  "product",
+			items: []entity.NewOrderItem{{ProductID: "plain", Count: 2}},
+			want:  []entity.OrderProducts{{ProductID: "plain", Count: 2, Cost: 1000}},
+			total: 2000,
+		},
+		{
+			name:  "variant prices",
+			items: []entity.NewOrderItem{{ProductID: "shirt", VariantID: "shirt-m", Count: 2}, {ProductID: "plain", Count: 1}},
+			want: []entity.OrderProducts{
+				{ProductID: "shirt", VariantID: "shirt-m", Count: 2, Cost: 2200},
+				{ProductID: "plain", Count: 1, Cost: 1000},
+			},
+			total: 5400,
+		},
+		{
+			name:  "variant required",
+			items: []entity.NewOrderItem{{ProductID: "shirt", Count: 1}},
+			err:   entity.ErrValidation,
+		},
+		{
+			name:  "foreign variant",
+			items: []entity.NewOrderItem{{ProductID: "shirt", VariantID: "hat-red", Count: 1}},
+			err:   entity.ErrForeignKey,
+		},
+		{
+			name:  "unknown variant",
+			items: []entity.NewOrderItem{{ProductID: "plain", VariantID: "shirt-s", Count: 1}},
+			err:   entity.ErrForeignKey,
+		},
+		{
+			name:  "unknown product",
+			items: []entity.NewOrderItem{{ProductID: "missing", Count: 1}},
+			err:   entity.ErrForeignKey,
+		},
+		{
+			name:  "insufficient product stock",
+			items: []entity.NewOrderItem{{ProductID: "plain", Count: 6}},
+			err:   entity.ErrInsufficientStock,
+		},
+		{
+			name:  "insufficient variant stock",
+			items: []entity.NewOrderItem{{ProductID: "shirt", VariantID: "shirt-s", Count: 2}},
+			err:   entity.ErrInsufficientStock,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			uc := newTestUseCase(t, orderRepo())
+
+			quote, err := uc.priceOrder(context.Background(), entity.NewOrder{Items: tc.items}, tc.items, false)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, quote.Products)
+			require.Equal(t, "USD", quote.Currency)
+			require.Equal(t, tc.total, quote.SubtotalCost)
+			require.Equal(t, tc.total, quote.TotalCost)
+		})
+	}
+}
+
+func TestPlaceOrder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reserves the items", func(t *testing.T) {
+		t.Parallel()
+
+		r := orderRepo()
+		uc := newTestUseCase(t, r)
+
+		order, err := uc.PlaceOrder(context.Background(), entity.NewOrder{
+			UserID: "u1",
+			Items: []entity.NewOrderItem{
+				{ProductID: "plain", Count: 1},
+				{ProductID: "shirt", VariantID: "shirt-m", Count: 1},
+				{ProductID: "plain", Count: 2},
+			},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, entity.OrderStatusNew, order.Status)
+		require.Equal(t, "u1", order.UserID)
+		require.Equal(t, 5200, order.TotalCost)
+		require.Len(t, order.Products, 2)
+		require.Len(t, order.Reservations, 2)
+		require.Equal(t, 3, order.Reservations[0].Count)
+		require.Equal(t, 2, r.products["plain"].Available)
+		require.Equal(t, 1, r.variants["shirt-m"].Available)
+	})
+
+	t.Run("no items", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newTestUseCase(t, orderRepo()).PlaceOrder(context.Background(), entity.NewOrder{UserID: "u1"})
+		require.ErrorIs(t, err, entity.ErrValidation)
+	})
+
+	t.Run("merged items over the stock", func(t *testing.T) {
+		t.Parallel()
+
+		r := orderRepo()
+
+		_, err := newTestUseCase(t, r).PlaceOrder(context.Background(), entity.NewOrder{
+			UserID: "u1",
+			Items:  []entity.NewOrderItem{{ProductID: "plain", Count: 3}, {ProductID: "plain", Count: 3}},
+		})
+		require.ErrorIs(t, err, entity.ErrInsufficientStock)
+		require.Empty(t, r.orders)
+	})
+
+	t.Run("variant required", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newTestUseCase(t, orderRepo()).PlaceOrder(context.Background(), entity.NewOrder{
+			UserID: "u1",
+			Items:  []entity.NewOrderItem{{ProductID: "hat", Count: 1}},
+		})
+		require.ErrorIs(t, err, entity.ErrValidation)
+	})
+}
@@ -2,33 +2,111 @@ package product
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"ai-seller/internal/entity"
 	"ai-seller/internal/repo"
+	"ai-seller/pkg/logger"
 )
 
+const (
+	_defaultMediaMaxSize = 10 << 20
+	_defaultMediaURL     = "/v1/media"
+	_defaultProductURL   = "/product/{id}"
+
+	_defaultReservationTTL = 30 * time.Minute
+	_defaultTrashRetention = 30 * 24 * time.Hour
+
+	_defaultLogLevel = "info"
+)
+
+var _defaultThumbnailSizes = []int{160, 480, 1024}
+
 // UseCase -.
 type UseCase struct {
 	auth        repo.AuthRepo
 	product     repo.ProductRepo
 	integration repo.IntegrationRepo
+	hasher      repo.PasswordHasher
+	dummyHash   func() string
+	tokens      repo.TokenManager
+	tx          repo.Transactor
+	media       repo.MediaStorage
+	notifier    repo.Notifier
+	l           logger.Interface
+
+	mediaMaxSize   int64
+	mediaURL       string
+	thumbnailSizes []int
+
+	reservationTTL time.Duration
+	trashRetention time.Duration
+
+	feed    entity.FeedSettings
+	feedsMu sync.Mutex
+	feeds   map[string]entity.Feed
+
+	bgCtx    context.Context
+	bgCancel context.CancelFunc
+	bgMu     sync.Mutex
+	bgWG     sync.WaitGroup
+	bgClosed bool
 }
 
 // New -.
-func New(a repo.AuthRepo, p repo.ProductRepo, i repo.IntegrationRepo) *UseCase {
-	return &UseCase{
-		auth:        a,
-		product:     p,
-		integration: i,
+func New(a repo.AuthRepo, p repo.ProductRepo, i repo.IntegrationRepo, h repo.PasswordHasher, t repo.TokenManager, tx repo.Transactor, m repo.MediaStorage, n repo.Notifier, opts ...Option) *UseCase {
+	uc := &UseCase{
+		auth:           a,
+		product:        p,
+		integration:    i,
+		hasher:         h,
+		tokens:         t,
+		tx:             tx,
+		media:          m,
+		notifier:       n,
+		l:              logger.New(_defaultLogLevel),
+		mediaMaxSize:   _defaultMediaMaxSize,
+		mediaURL:       _defaultMediaURL,
+		thumbnailSizes: _defaultThumbnailSizes,
+		reservationTTL: _defaultReservationTTL,
+		trashRetention: _defaultTrashRetention,
+		feed:           entity.FeedSettings{ProductURL: _defaultProductURL},
+		feeds:          make(map[string]entity.Feed),
 	}
+
+	uc.bgCtx, uc.bgCancel = context.WithCancel(context.Background())
+
+	// Verified against when a user is unknown, so that signing in with an
+	// unknown username takes as long as with a wrong password.
+	uc.dummyHash = sync.OnceValue(func() string {
+		hash, _ := h.Hash("")
+
+		return hash
+	})
+
+	// Custom options
+	for _, opt := range opts {
+		opt(uc)
+	}
+
+	return uc
 }
 
 // -------------- Auth --------------
 
 // CreateUser -.
 func (uc *UseCase) CreateUser(ctx context.Context, u entity.User) error {
-	err := uc.auth.CreateUser(ctx, u)
+	hash, err := uc.hasher.Hash(u.Password)
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - CreateUser - s.hasher.Hash: %w", err)
+	}
+
+	u.Password = hash
+
+	err = uc.auth.CreateUser(ctx, u)
 	if err != nil {
 		return fmt.Errorf("ProductUseCase - CreateUser - s.auth.CreateUser: %w", err)
 	}
@@ -47,6 +125,15 @@ func (uc *UseCase) GetUser(ctx context.Context, id string) (entity.User, error)
 
 // UpdateUser -.
 func (uc *UseCase) UpdateUser(ctx context.Context, u entity.User) error {
+	if u.Password != "" {
+		hash, err := uc.hasher.Hash(u.Password)
+		if err != nil {
+			return fmt.Errorf("ProductUseCase - UpdateUser - s.hasher.Hash: %w", err)
+		}
+
+		u.Password = hash
+	}
+
 	err := uc.auth.UpdateUser(ctx, u)
 	if err != nil {
 		return fmt.Errorf("ProductUseCase - UpdateUser - s.auth.UpdateUser: %w", err)
@@ -55,11 +142,88 @@ func (uc *UseCase) UpdateUser(ctx context.Context, u entity.User) error {
 	return nil
 }
 
-// DeleteUser -.
-func (uc *UseCase) DeleteUser(ctx context.Context, id string) error {
-	err := uc.auth.DeleteUser(ctx, id)
+// PatchUser applies a merge patch and returns the updated user. A supplied
+// password is hashed like in UpdateUser.
+func (uc *UseCase) PatchUser(ctx context.Context, id string, p entity.UserPatch) (entity.User, error) {
+	if p.Password.Set {
+		if p.Password.Null || p.Password.Value == "" {
+			return entity.User{}, fmt.Errorf("ProductUseCase - PatchUser: %w", entity.NewValidationError("password", "must not be empty"))
+		}
+
+		hash, err := uc.hasher.Hash(p.Password.Value)
+		if err != nil {
+			return entity.User{}, fmt.Errorf("ProductUseCase - PatchUser - s.hasher.Hash: %w", err)
+		}
+
+		p.Password = entity.Some(hash)
+	}
+
+	err := uc.auth.PatchUser(ctx, id, p)
+	if err != nil {
+		return entity.User{}, fmt.Errorf("ProductUseCase - PatchUser - s.auth.PatchUser: %w", err)
+	}
+
+	user, err := uc.auth.GetUser(ctx, id)
+	if err != nil {
+		return entity.User{}, fmt.Errorf("ProductUseCase - PatchUser - s.auth.GetUser: %w", err)
+	}
+
+	return user, nil
+}
+
+// Authenticate checks user credentials and transparently upgrades outdated
+// password hashes. The returned user never carries the password hash.
+func (uc *UseCase) Authenticate(ctx context.Context, username, password string) (entity.User, error) {
+	user, err := uc.auth.GetUserByUsername(ctx, username)
+	if errors.Is(err, entity.ErrNotFound) {
+		_, _ = uc.hasher.Verify(uc.dummyHash(), password)
+
+		return entity.User{}, fmt.Errorf("ProductUseCase - Authenticate - s.auth.GetUserByUsername: %w", entity.ErrInvalidCredentials)
+	}
+
+	if err != nil {
+		return entity.User{}, fmt.Errorf("ProductUseCase - Authenticate - s.auth.GetUserByUsername: %w", err)
+	}
+
+	needsRehash, err := uc.hasher.Verify(user.Password, password)
+	if err != nil {
+		return entity.User{}, fmt.Errorf("ProductUseCase - Authenticate - s.hasher.Verify: %w", errors.Join(entity.ErrInvalidCredentials, err))
+	}
+
+	if needsRehash {
+		hash, err := uc.hasher.Hash(password)
+		if err != nil {
+			return entity.User{}, fmt.Errorf("ProductUseCase - Authenticate - s.hasher.Hash: %w", err)
+		}
+
+		err = uc.auth.UpdateUserPassword(ctx, user.ID, hash)
+		if err != nil {
+			return entity.User{}, fmt.Errorf("ProductUseCase - Authenticate - s.auth.UpdateUserPassword: %w", err)
+		}
+	}
+
+	user.Password = ""
+
+	return user, nil
+}
+
+// DeleteUser moves a user to the trash and signs it out everywhere.
+func (uc *UseCase) DeleteUser(ctx context.Context, id string, version int) error {
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := uc.auth.DeleteUser(ctx, id, version)
+		if err != nil {
+			return fmt.Errorf("s.auth.DeleteUser: %w", err)
+		}
+
+		err = uc.auth.RevokeUserRefreshTokens(ctx, id)
+		if err != nil {
+			return fmt.Errorf("s.auth.RevokeUserRefreshTokens: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("ProductUseCase - DeleteUser - s.auth.DeleteUser: %w", err)
+		return fmt.Errorf("ProductUseCase - DeleteUser - s.tx.WithinTransaction: %w", err)
 	}
 
 	return nil
@@ -107,6 +271,62 @@ func (uc *UseCase) DeleteRole(ctx context.Context, id string) error {
 	return nil
 }
 
+// -------------- Permission --------------
+
+// GetPermissions -.
+func (uc *UseCase) GetPermissions(ctx context.Context) ([]entity.Permission, error) {
+	permissions, err := uc.auth.GetPermissions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - GetPermissions - s.auth.GetPermissions: %w", err)
+	}
+
+	return permissions, nil
+}
+
+// GetRolePermissions -.
+func (uc *UseCase) GetRolePermissions(ctx context.Context, roleID string) ([]entity.Permission, error) {
+	permissions, err := uc.auth.GetRolePermissions(ctx, roleID)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - GetRolePermissions - s.auth.GetRolePermissions: %w", err)
+	}
+
+	return permissions, nil
+}
+
+// AddRolePermission -.
+func (uc *UseCase) AddRolePermission(ctx context.Context, roleID, permissionID string) error {
+	err := uc.auth.AddRolePermission(ctx, roleID, permissionID)
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - AddRolePermission - s.auth.AddRolePermission: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteRolePermission -.
+func (uc *UseCase) DeleteRolePermission(ctx context.Context, roleID, permissionID string) error {
+	err := uc.auth.DeleteRolePermission(ctx, roleID, permissionID)
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - DeleteRolePermission - s.auth.DeleteRolePermission: %w", err)
+	}
+
+	return nil
+}
+
+// HasPermission -.
+func (uc *UseCase) HasPermission(ctx context.Context, roleID, permission string) (bool, error) {
+	if roleID == "" {
+		return false, nil
+	}
+
+	ok, err := uc.auth.HasPermission(ctx, roleID, permission)
+	if err != nil {
+		return false, fmt.Errorf("ProductUseCase - HasPermission - s.auth.HasPermission: %w", err)
+	}
+
+	return ok, nil
+}
+
 // -------------- ClientType --------------
 
 // CreateClientType -.
@@ -192,11 +412,19 @@ func (uc *UseCase) DeleteIntegration(ctx context.Context, id string) error {
 
 // -------------- Product --------------
 
-// CreateProduct -.
+// CreateProduct -. Its Count is recorded as the opening stock of the default
+// warehouse.
 func (uc *UseCase) CreateProduct(ctx context.Context, p entity.Product) error {
-	err := uc.product.CreateProduct(ctx, p)
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		id, err := uc.product.CreateProduct(ctx, p)
+		if err != nil {
+			return fmt.Errorf("s.product.CreateProduct: %w", err)
+		}
+
+		return uc.setStock(ctx, id, "", 0, p.Count, entity.ReasonInitial)
+	})
 	if err != nil {
-		return fmt.Errorf("ProductUseCase - CreateProduct - s.product.CreateProduct: %w", err)
+		return fmt.Errorf("ProductUseCase - CreateProduct - s.tx.WithinTransaction: %w", err)
 	}
 
 	return nil
@@ -209,22 +437,105 @@ func (uc *UseCase) GetProduct(ctx context.Context, id string) (entity.Product, e
 		return entity.Product{}, fmt.Errorf("ProductUseCase - GetProduct - s.product.GetProduct: %w", err)
 	}
 
+	product.Attributes, err = uc.product.GetProductAttributes(ctx, id)
+	if err != nil {
+		return entity.Product{}, fmt.Errorf("ProductUseCase - GetProduct - s.product.GetProductAttributes: %w", err)
+	}
+
+	product.Variants, err = uc.product.GetVariantsByProduct(ctx, id)
+	if err != nil {
+		return entity.Product{}, fmt.Errorf("ProductUseCase - GetProduct - s.product.GetVariantsByProduct: %w", err)
+	}
+
+	product.Media, err = uc.product.GetMediaByProduct(ctx, id)
+	if err != nil {
+		return entity.Product{}, fmt.Errorf("ProductUseCase - GetProduct - s.product.GetMediaByProduct: %w", err)
+	}
+
+	uc.setMediaURLs(product.Media)
+
 	return product, nil
 }
 
-// UpdateProduct -.
+// ListProducts -.
+func (uc *UseCase) ListProducts(ctx context.Context, f entity.ProductFilter) (entity.Page[entity.Product], error) {
+	page, err := uc.product.ListProducts(ctx, f)
+	if err != nil {
+		return entity.Page[entity.Product]{}, fmt.Errorf("ProductUseCase - ListProducts - s.product.ListProducts: %w", err)
+	}
+
+	return page, nil
+}
+
+// UpdateProduct -. A changed Count is recorded as a correction in the
+// default warehouse.
 func (uc *UseCase) UpdateProduct(ctx context.Context, p entity.Product) error {
-	err := uc.product.UpdateProduct(ctx, p)
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := uc.product.UpdateProduct(ctx, p)
+		if err != nil {
+			return fmt.Errorf("s.product.UpdateProduct: %w", err)
+		}
+
+		return uc.correctProductStock(ctx, p.ID, p.Count)
+	})
 	if err != nil {
-		return fmt.Errorf("ProductUseCase - UpdateProduct - s.product.UpdateProduct: %w", err)
+		return fmt.Errorf("ProductUseCase - UpdateProduct - s.tx.WithinTransaction: %w", err)
 	}
 
+	uc.stockChanged(ctx, p.ID)
+
 	return nil
 }
 
-// DeleteProduct -.
-func (uc *UseCase) DeleteProduct(ctx context.Context, id string) error {
-	err := uc.product.DeleteProduct(ctx, id)
+// PatchProduct applies a merge patch and returns the updated product. A
+// count is recorded as for UpdateProduct.
+func (uc *UseCase) PatchProduct(ctx context.Context, id string, p entity.ProductPatch) (entity.Product, error) {
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := uc.product.PatchProduct(ctx, id, p)
+		if err != nil {
+			return fmt.Errorf("s.product.PatchProduct: %w", err)
+		}
+
+		if !p.Count.Set {
+			return nil
+		}
+
+		return uc.correctProductStock(ctx, id, p.Count.Value)
+	})
+	if err != nil {
+		return entity.Product{}, fmt.Errorf("ProductUseCase - PatchProduct - s.tx.WithinTransaction: %w", err)
+	}
+
+	if p.Count.Set {
+		uc.stockChanged(ctx, id)
+	}
+
+	product, err := uc.product.GetProduct(ctx, id)
+	if err != nil {
+		return entity.Product{}, fmt.Errorf("ProductUseCase - PatchProduct - s.product.GetProduct: %w", err)
+	}
+
+	return product, nil
+}
+
+// correctProductStock sets the stock of a product to count, see setStock.
+func (uc *UseCase) correctProductStock(ctx context.Context, id string, count int) error {
+	products, err := uc.product.LockProducts(ctx, []string{id})
+	if err != nil {
+		return fmt.Errorf("s.product.LockProducts: %w", err)
+	}
+
+	if len(products) == 0 {
+		return entity.ErrNotFound
+	}
+
+	return uc.setStock(ctx, id, "", products[0].Count, count, entity.ReasonCorrection)
+}
+
+// DeleteProduct moves a product to the trash. The files of its images are
+// kept until the product is purged.
+func (uc *UseCase) DeleteProduct(ctx context.Context, id string, version int) error {
+	err := uc.product.DeleteProduct(ctx, id, version)
 	if err != nil {
 		return fmt.Errorf("ProductUseCase - DeleteProduct - s.product.DeleteProduct: %w", err)
 	}
@@ -234,7 +545,7 @@ func (uc *UseCase) DeleteProduct(ctx context.Context, id string) error {
 
 // CreateCategory -.
 func (uc *UseCase) CreateCategory(ctx context.Context, c entity.Category) error {
-	err := uc.product.CreateCategory(ctx, c)
+	_, err := uc.product.CreateCategory(ctx, c)
 	if err != nil {
 		return fmt.Errorf("ProductUseCase - CreateCategory - s.product.CreateCategory: %w", err)
 	}
@@ -252,6 +563,16 @@ func (uc *UseCase) GetCategory(ctx context.Context, id string) (entity.Category,
 	return category, nil
 }
 
+// ListCategories -.
+func (uc *UseCase) ListCategories(ctx context.Context, f entity.CategoryFilter) (entity.Page[entity.Category], error) {
+	page, err := uc.product.ListCategories(ctx, f)
+	if err != nil {
+		return entity.Page[entity.Category]{}, fmt.Errorf("ProductUseCase - ListCategories - s.product.ListCategories: %w", err)
+	}
+
+	return page, nil
+}
+
 // UpdateCategory -.
 func (uc *UseCase) UpdateCategory(ctx context.Context, c entity.Category) error {
 	err := uc.product.UpdateCategory(ctx, c)
@@ -262,11 +583,64 @@ func (uc *UseCase) UpdateCategory(ctx context.Context, c entity.Category) error
 	return nil
 }
 
-// DeleteCategory -.
-func (uc *UseCase) DeleteCategory(ctx context.Context, id string) error {
-	err := uc.product.DeleteCategory(ctx, id)
+// PatchCategory applies a merge patch and returns the updated category.
+func (uc *UseCase) PatchCategory(ctx context.Context, id string, p entity.CategoryPatch) (entity.Category, error) {
+	err := uc.product.PatchCategory(ctx, id, p)
+	if err != nil {
+		return entity.Category{}, fmt.Errorf("ProductUseCase - PatchCategory - s.product.PatchCategory: %w", err)
+	}
+
+	category, err := uc.product.GetCategory(ctx, id)
 	if err != nil {
-		return fmt.Errorf("ProductUseCase - DeleteCategory - s.product.DeleteCategory: %w", err)
+		return entity.Category{}, fmt.Errorf("ProductUseCase - PatchCategory - s.product.GetCategory: %w", err)
+	}
+
+	return category, nil
+}
+
+// DeleteCategory moves a category to the trash. A category that still has
+// subcategories, products or attributes cannot be deleted.
+func (uc *UseCase) DeleteCategory(ctx context.Context, id string, version int) error {
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		children, err := uc.product.CountCategoryChildren(ctx, id)
+		if err != nil {
+			return fmt.Errorf("s.product.CountCategoryChildren: %w", err)
+		}
+
+		if children > 0 {
+			return fmt.Errorf("%w: the category has subcategories", entity.ErrInUse)
+		}
+
+		products, err := uc.product.ListProducts(ctx, entity.ProductFilter{
+			ListParams: entity.ListParams{Limit: 1},
+			CategoryID: id,
+		})
+		if err != nil {
+			return fmt.Errorf("s.product.ListProducts: %w", err)
+		}
+
+		if products.Total > 0 {
+			return fmt.Errorf("%w: the category has products", entity.ErrInUse)
+		}
+
+		attributes, err := uc.product.GetAttributesByCategory(ctx, id)
+		if err != nil {
+			return fmt.Errorf("s.product.GetAttributesByCategory: %w", err)
+		}
+
+		if len(attributes) > 0 {
+			return fmt.Errorf("%w: the category has attributes", entity.ErrInUse)
+		}
+
+		err = uc.product.DeleteCategory(ctx, id, version)
+		if err != nil {
+			return fmt.Errorf("s.product.DeleteCategory: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - DeleteCategory - s.tx.WithinTransaction: %w", err)
 	}
 
 	return nil
@@ -274,7 +648,16 @@ func (uc *UseCase) DeleteCategory(ctx context.Context, id string) error {
 
 // CreateAttribute -.
 func (uc *UseCase) CreateAttribute(ctx context.Context, a entity.Attribute) error {
-	err := uc.product.CreateAttribute(ctx, a)
+	if a.Type == "" {
+		a.Type = entity.AttributeTypeString
+	}
+
+	err := a.ValidateDefinition()
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - CreateAttribute - a.ValidateDefinition: %w", err)
+	}
+
+	_, err = uc.product.CreateAttribute(ctx, a)
 	if err != nil {
 		return fmt.Errorf("ProductUseCase - CreateAttribute - s.product.CreateAttribute: %w", err)
 	}
@@ -292,9 +675,28 @@ func (uc *UseCase) GetAttribute(ctx context.Context, id string) (entity.Attribut
 	return attribute, nil
 }
 
+// ListAttributes -.
+func (uc *UseCase) ListAttributes(ctx context.Context, f entity.AttributeFilter) (entity.Page[entity.Attribute], error) {
+	page, err := uc.product.ListAttributes(ctx, f)
+	if err != nil {
+		return entity.Page[entity.Attribute]{}, fmt.Errorf("ProductUseCase - ListAttributes - s.product.ListAttributes: %w", err)
+	}
+
+	return page, nil
+}
+
 // UpdateAttribute -.
 func (uc *UseCase) UpdateAttribute(ctx context.Context, a entity.Attribute) error {
-	err := uc.product.UpdateAttribute(ctx, a)
+	if a.Type == "" {
+		a.Type = entity.AttributeTypeString
+	}
+
+	err := a.ValidateDefinition()
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - UpdateAttribute - a.ValidateDefinition: %w", err)
+	}
+
+	err = uc.product.UpdateAttribute(ctx, a)
 	if err != nil {
 		return fmt.Errorf("ProductUseCase - UpdateAttribute - s.product.UpdateAttribute: %w", err)
 	}
@@ -302,6 +704,31 @@ func (uc *UseCase) UpdateAttribute(ctx context.Context, a entity.Attribute) erro
 	return nil
 }
 
+// PatchAttribute applies a merge patch and returns the updated attribute. The
+// patched definition is validated before the transaction commits.
+func (uc *UseCase) PatchAttribute(ctx context.Context, id string, p entity.AttributePatch) (entity.Attribute, error) {
+	var attribute entity.Attribute
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := uc.product.PatchAttribute(ctx, id, p)
+		if err != nil {
+			return fmt.Errorf("s.product.PatchAttribute: %w", err)
+		}
+
+		attribute, err = uc.product.GetAttribute(ctx, id)
+		if err != nil {
+			return fmt.Errorf("s.product.GetAttribute: %w", err)
+		}
+
+		return attribute.ValidateDefinition()
+	})
+	if err != nil {
+		return entity.Attribute{}, fmt.Errorf("ProductUseCase - PatchAttribute - s.tx.WithinTransaction: %w", err)
+	}
+
+	return attribute, nil
+}
+
 // DeleteAttribute -.
 func (uc *UseCase) DeleteAttribute(ctx context.Context, id string) error {
 	err := uc.product.DeleteAttribute(ctx, id)
@@ -314,7 +741,9 @@ func (uc *UseCase) DeleteAttribute(ctx context.Context, id string) error {
 
 // CreateOrder -.
 func (uc *UseCase) CreateOrder(ctx context.Context, o entity.Order) error {
-	err := uc.product.CreateOrder(ctx, o)
+	o.Status = entity.OrderStatusNew
+
+	_, err := uc.product.CreateOrder(ctx, o)
 	if err != nil {
 		return fmt.Errorf("ProductUseCase - CreateOrder - s.product.CreateOrder: %w", err)
 	}
@@ -329,9 +758,34 @@ func (uc *UseCase) GetOrder(ctx context.Context, id string) (entity.Order, error
 		return entity.Order{}, fmt.Errorf("ProductUseCase - GetOrder - s.product.GetOrder: %w", err)
 	}
 
+	order.Products, err = uc.product.GetOrderProductsByOrder(ctx, id)
+	if err != nil {
+		return entity.Order{}, fmt.Errorf("ProductUseCase - GetOrder - s.product.GetOrderProductsByOrder: %w", err)
+	}
+
+	order.Discounts, err = uc.product.GetOrderDiscounts(ctx, id)
+	if err != nil {
+		return entity.Order{}, fmt.Errorf("ProductUseCase - GetOrder - s.product.GetOrderDiscounts: %w", err)
+	}
+
+	order.Reservations, err = uc.product.GetReservationsByOrder(ctx, id)
+	if err != nil {
+		return entity.Order{}, fmt.Errorf("ProductUseCase - GetOrder - s.product.GetReservationsByOrder: %w", err)
+	}
+
 	return order, nil
 }
 
+// ListOrders -.
+func (uc *UseCase) ListOrders(ctx context.Context, f entity.OrderFilter) (entity.Page[entity.Order], error) {
+	page, err := uc.product.ListOrders(ctx, f)
+	if err != nil {
+		return entity.Page[entity.Order]{}, fmt.Errorf("ProductUseCase - ListOrders - s.product.ListOrders: %w", err)
+	}
+
+	return page, nil
+}
+
 // UpdateOrder -.
 func (uc *UseCase) UpdateOrder(ctx context.Context, o entity.Order) error {
 	err := uc.product.UpdateOrder(ctx, o)
@@ -342,11 +796,51 @@ func (uc *UseCase) UpdateOrder(ctx context.Context, o entity.Order) error {
 	return nil
 }
 
-// DeleteOrder -.
-func (uc *UseCase) DeleteOrder(ctx context.Context, id string) error {
-	err := uc.product.DeleteOrder(ctx, id)
+// PatchOrder applies a merge patch and returns the updated order.
+func (uc *UseCase) PatchOrder(ctx context.Context, id string, p entity.OrderPatch) (entity.Order, error) {
+	err := uc.product.PatchOrder(ctx, id, p)
+	if err != nil {
+		return entity.Order{}, fmt.Errorf("ProductUseCase - PatchOrder - s.product.PatchOrder: %w", err)
+	}
+
+	order, err := uc.GetOrder(ctx, id)
+	if err != nil {
+		return entity.Order{}, fmt.Errorf("ProductUseCase - PatchOrder - uc.GetOrder: %w", err)
+	}
+
+	return order, nil
+}
+
+// DeleteOrder deletes an order, releasing the stock it still holds.
+func (uc *UseCase) DeleteOrder(ctx context.Context, id string, version int) error {
+	var lines []entity.OrderProducts
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		released, err := uc.releaseReservations(ctx, id, entity.ReservationReleased)
+		if err != nil {
+			return err
+		}
+
+		if released > 0 {
+			lines, err = uc.product.GetOrderProductsByOrder(ctx, id)
+			if err != nil {
+				return fmt.Errorf("s.product.GetOrderProductsByOrder: %w", err)
+			}
+		}
+
+		err = uc.product.DeleteOrder(ctx, id, version)
+		if err != nil {
+			return fmt.Errorf("s.product.DeleteOrder: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("ProductUseCase - DeleteOrder - s.product.DeleteOrder: %w", err)
+		return fmt.Errorf("ProductUseCase - DeleteOrder - s.tx.WithinTransaction: %w", err)
+	}
+
+	if len(lines) > 0 {
+		uc.stockChanged(ctx, orderProductIDs(lines)...)
 	}
 
 	return nil
@@ -354,7 +848,7 @@ func (uc *UseCase) DeleteOrder(ctx context.Context, id string) error {
 
 // CreateOrderProduct -.
 func (uc *UseCase) CreateOrderProducts(ctx context.Context, op entity.OrderProducts) error {
-	err := uc.product.CreateOrderProducts(ctx, op)
+	_, err := uc.product.CreateOrderProducts(ctx, op)
 	if err != nil {
 		return fmt.Errorf("ProductUseCase - CreateOrderProducts - s.product.CreateOrderProducts: %w", err)
 	}
@@ -0,0 +1,592 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"ai-seller/internal/entity"
+)
+
+// CreatePromotion -.
+func (uc *UseCase) CreatePromotion(ctx context.Context, p entity.Promotion) (entity.Promotion, error) {
+	err := p.Validate()
+	if err != nil {
+		return entity.Promotion{}, fmt.Errorf("ProductUseCase - CreatePromotion - p.Validate: %w", err)
+	}
+
+	p.ID, err = uc.product.CreatePromotion(ctx, p)
+	if err != nil {
+		return entity.Promotion{}, fmt.Errorf("ProductUseCase - CreatePromotion - s.product.CreatePromotion: %w", err)
+	}
+
+	promotion, err := uc.product.GetPromotion(ctx, p.ID)
+	if err != nil {
+		return entity.Promotion{}, fmt.Errorf("ProductUseCase - CreatePromotion - s.product.GetPromotion: %w", err)
+	}
+
+	return promotion, nil
+}
+
+// GetPromotion -.
+func (uc *UseCase) GetPromotion(ctx context.Context, id string) (entity.Promotion, error) {
+	promotion, err := uc.product.GetPromotion(ctx, id)
+	if err != nil {
+		return entity.Promotion{}, fmt.Errorf("ProductUseCase - GetPromotion - s.product.GetPromotion: %w", err)
+	}
+
+	return promotion, nil
+}
+
+// ListPromotions -.
+func (uc *UseCase) ListPromotions(ctx context.Context, f entity.PromotionFilter) (entity.Page[entity.Promotion], error) {
+	page, err := uc.product.ListPromotions(ctx, f)
+	if err != nil {
+		return entity.Page[entity.Promotion]{}, fmt.Errorf("ProductUseCase - ListPromotions - s.product.ListPromotions: %w", err)
+	}
+
+	return page, nil
+}
+
+// UpdatePromotion -.
+func (uc *UseCase) UpdatePromotion(ctx context.Context, p entity.Promotion) (entity.Promotion, error) {
+	err := p.Validate()
+	if err != nil {
+		return entity.Promotion{}, fmt.Errorf("ProductUseCase - UpdatePromotion - p.Validate: %w", err)
+	}
+
+	err = uc.product.UpdatePromotion(ctx, p)
+	if err != nil {
+		return entity.Promotion{}, fmt.Errorf("ProductUseCase - UpdatePromotion - s.product.UpdatePromotion: %w", err)
+	}
+
+	promotion, err := uc.product.GetPromotion(ctx, p.ID)
+	if err != nil {
+		return entity.Promotion{}, fmt.Errorf("ProductUseCase - UpdatePromotion - s.product.GetPromotion: %w", err)
+	}
+
+	return promotion, nil
+}
+
+// DeletePromotion -.
+func (uc *UseCase) DeletePromotion(ctx context.Context, id string, version int) error {
+	err := uc.product.DeletePromotion(ctx, id, version)
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - DeletePromotion - s.product.DeletePromotion: %w", err)
+	}
+
+	return nil
+}
+
+// ListCoupons -.
+func (uc *UseCase) ListCoupons(ctx context.Context, promotionID string) ([]entity.Coupon, error) {
+	_, err := uc.product.GetPromotion(ctx, promotionID)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - ListCoupons - s.product.GetPromotion: %w", err)
+	}
+
+	coupons, err := uc.product.GetCouponsByPromotion(ctx, promotionID)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - ListCoupons - s.product.GetCouponsByPromotion: %w", err)
+	}
+
+	return coupons, nil
+}
+
+// CreateCoupon adds a code to a promotion. Codes are case insensitive.
+func (uc *UseCase) CreateCoupon(ctx context.Context, c entity.Coupon) (entity.Coupon, error) {
+	_, err := uc.product.GetPromotion(ctx, c.PromotionID)
+	if err != nil {
+		return entity.Coupon{}, fmt.Errorf("ProductUseCase - CreateCoupon - s.product.GetPromotion: %w", err)
+	}
+
+	c.Code = entity.NormalizeCouponCode(c.Code)
+	if c.Code == "" {
+		return entity.Coupon{}, fmt.Errorf("ProductUseCase - CreateCoupon: %w", entity.NewValidationError("code", "must not be blank"))
+	}
+
+	c.ID, err = uc.product.CreateCoupon(ctx, c)
+	if err != nil {
+		return entity.Coupon{}, fmt.Errorf("ProductUseCase - CreateCoupon - s.product.CreateCoupon: %w", err)
+	}
+
+	coupon, err := uc.product.GetCoupon(ctx, c.PromotionID, c.ID)
+	if err != nil {
+		return entity.Coupon{}, fmt.Errorf("ProductUseCase - CreateCoupon - s.product.GetCoupon: %w", err)
+	}
+
+	return coupon, nil
+}
+
+// DeleteCoupon -.
+func (uc *UseCase) DeleteCoupon(ctx context.Context, promotionID, id string) error {
+	err := uc.product.DeleteCoupon(ctx, promotionID, id)
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - DeleteCoupon - s.product.DeleteCoupon: %w", err)
+	}
+
+	return nil
+}
+
+// offer is a promotion an order qualifies for, with the coupon that
+// unlocked it, if any.
+type offer struct {
+	promotion entity.Promotion
+	coupon    entity.Coupon
+	eligible  []bool
+}
+
+// applyPromotions finds the running promotions the order qualifies for and
+// applies the best combination for the customer: either all stackable
+// promotions in priority order, or the single best promotion that does not
+// stack. Fixed amounts and minimum order costs are converted into the order
+// currency. Line
+// discounts are added to lines. With lock, promotions with usage
+// limits are locked before their usage is counted. Must run in a
+// transaction.
+func (uc *UseCase) applyPromotions(ctx context.Context, no entity.NewOrder, lines []entity.OrderProducts, categories []string, pr pricing, lock bool) ([]entity.OrderDiscount, error) {
+	offers, err := uc.findOffers(ctx, no, lines, categories, lock)
+	if err != nil {
+		return nil, err
+	}
+
+	var stackable, exclusive []offer
+
+	for _, o := range offers {
+		if o.promotion.Type == entity.PromotionFixed {
+			o.promotion.Value = pr.convert(o.promotion.Value).Amount
+		}
+
+		o.promotion.MinOrderCost = pr.convert(o.promotion.MinOrderCost).Amount
+
+		if o.promotion.Stackable {
+			stackable = append(stackable, o)
+		} else {
+			exclusive = append(exclusive, o)
+		}
+	}
+
+	best, amounts := bestDiscounts(stackable, exclusive, lines, pr.currency)
+
+	for i := range lines {
+		lines[i].Discount = amounts[i]
+	}
+
+	return best, nil
+}
+
+// bestDiscounts returns the discounts of all stackable offers together or of
+// the single exclusive offer that takes off the most, whichever is more, and
+// the discount of every line. Ties go to the stackable offers.
+func bestDiscounts(stackable, exclusive []offer, lines []entity.OrderProducts, currency entity.Currency) ([]entity.OrderDiscount, []int) {
+	best, bestAmounts := discountOffers(stackable, lines, currency)
+
+	for _, o := range exclusive {
+		discounts, amounts := discountOffers([]offer{o}, lines, currency)
+		if totalDiscount(discounts) > totalDiscount(best) {
+			best, bestAmounts = discounts, amounts
+		}
+	}
+
+	return best, bestAmounts
+}
+
+// findOffers returns the running promotions that apply to at least one line
+// of the order and are within their usage limits. Coupons that cannot be
+// used are validation errors.
+func (uc *UseCase) findOffers(ctx context.Context, no entity.NewOrder, lines []entity.OrderProducts, categories []string, lock bool) ([]offer, error) {
+	coupons, err := uc.orderCoupons(ctx, no.Coupons)
+	if err != nil {
+		return nil, err
+	}
+
+	promotions, err := uc.product.GetActivePromotions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("s.product.GetActivePromotions: %w", err)
+	}
+
+	for code, c := range coupons {
+		if !slices.ContainsFunc(promotions, func(p entity.Promotion) bool { return p.ID == c.PromotionID }) {
+			return nil, entity.NewValidationError("coupons", "code "+code+" is not active")
+		}
+	}
+
+	scope, err := uc.newPromotionScope(ctx, no.UserID, promotions, categories)
+	if err != nil {
+		return nil, err
+	}
+
+	offers := make([]offer, 0, len(promotions))
+	limited := make([]string, 0, len(promotions))
+
+	for _, p := range promotions {
+		o := offer{promotion: p}
+
+		for _, c := range coupons {
+			if c.PromotionID == p.ID && o.coupon.ID == "" {
+				o.coupon = c
+			}
+		}
+
+		if p.RequiresCoupon && o.coupon.ID == "" {
+			continue
+		}
+
+		if !p.RequiresCoupon {
+			o.coupon = entity.Coupon{}
+		}
+
+		if !scope.allowsClient(p) {
+			if o.coupon.ID != "" {
+				return nil, entity.NewValidationError("coupons", "code "+o.coupon.Code+" is not available to this customer")
+			}
+
+			continue
+		}
+
+		o.eligible = scope.eligibleLines(p, lines)
+		if !slices.Contains(o.eligible, true) {
+			if o.coupon.ID != "" {
+				return nil, entity.NewValidationError("coupons", "code "+o.coupon.Code+" does not apply to any item")
+			}
+
+			continue
+		}
+
+		if p.UsageLimit > 0 || p.UsageLimitPerUser > 0 || o.coupon.UsageLimit > 0 || o.coupon.UsageLimitPerUser > 0 {
+			limited = append(limited, p.ID)
+		}
+
+		offers = append(offers, o)
+	}
+
+	if lock && len(limited) > 0 {
+		_, err = uc.product.LockPromotions(ctx, limited)
+		if err != nil {
+			return nil, fmt.Errorf("s.product.LockPromotions: %w", err)
+		}
+	}
+
+	available := offers[:0]
+
+	for _, o := range offers {
+		if !slices.Contains(limited, o.promotion.ID) {
+			available = append(available, o)
+			continue
+		}
+
+		ok, err := uc.withinUsageLimits(ctx, o, no.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			available = append(available, o)
+			continue
+		}
+
+		if o.coupon.ID != "" {
+			return nil, entity.NewValidationError("coupons", "code "+o.coupon.Code+" has reached its usage limit")
+		}
+	}
+
+	return available, nil
+}
+
+// orderCoupons looks up the coupon codes of an order, keyed by normalized
+// code. Unknown codes are validation errors.
+func (uc *UseCase) orderCoupons(ctx context.Context, codes []string) (map[string]entity.Coupon, error) {
+	normalized := make([]string, 0, len(codes))
+	for _, code := range codes {
+		if code = entity.NormalizeCouponCode(code); !slices.Contains(normalized, code) {
+			normalized = append(normalized, code)
+		}
+	}
+
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+
+	found, err := uc.product.GetCouponsByCodes(ctx, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("s.product.GetCouponsByCodes: %w", err)
+	}
+
+	coupons := make(map[string]entity.Coupon, len(found))
+	for _, c := range found {
+		coupons[c.Code] = c
+	}
+
+	for _, code := range normalized {
+		if _, ok := coupons[code]; !ok {
+			return nil, entity.NewValidationError("coupons", "code "+code+" is not valid")
+		}
+	}
+
+	return coupons, nil
+}
+
+func (uc *UseCase) withinUsageLimits(ctx context.Context, o offer, userID string) (bool, error) {
+	p := o.promotion
+
+	total, byUser, err := uc.product.CountPromotionUsage(ctx, p.ID, userID)
+	if err != nil {
+		return false, fmt.Errorf("s.product.CountPromotionUsage: %w", err)
+	}
+
+	if (p.UsageLimit > 0 && total >= p.UsageLimit) || (p.UsageLimitPerUser > 0 && byUser >= p.UsageLimitPerUser) {
+		return false, nil
+	}
+
+	if o.coupon.ID == "" {
+		return true, nil
+	}
+
+	c := o.coupon
+
+	total, byUser, err = uc.product.CountCouponUsage(ctx, c.ID, userID)
+	if err != nil {
+		return false, fmt.Errorf("s.product.CountCouponUsage: %w", err)
+	}
+
+	return (c.UsageLimit == 0 || total < c.UsageLimit) && (c.UsageLimitPerUser == 0 || byUser < c.UsageLimitPerUser), nil
+}
+
+// promotionScope answers which customers and order lines promotions apply to.
+type promotionScope struct {
+	clientType string
+	// ancestors holds, per line, its product's category and all the
+	// category's ancestors.
+	ancestors [][]string
+}
+
+// newPromotionScope only loads the customer's client type and the category
+// tree when some promotion is scoped by them.
+func (uc *UseCase) newPromotionScope(ctx context.Context, userID string, promotions []entity.Promotion, categories []string) (promotionScope, error) {
+	var s promotionScope
+
+	if slices.ContainsFunc(promotions, func(p entity.Promotion) bool { return len(p.ClientTypeIDs) > 0 }) {
+		clientType, err := uc.clientType(ctx, userID)
+		if err != nil {
+			return s, err
+		}
+
+		s.clientType = clientType
+	}
+
+	s.ancestors = make([][]string, len(categories))
+
+	if !slices.ContainsFunc(promotions, func(p entity.Promotion) bool { return len(p.CategoryIDs) > 0 }) {
+		return s, nil
+	}
+
+	tree, err := uc.product.GetCategorySubtree(ctx, "")
+	if err != nil {
+		return s, fmt.Errorf("s.product.GetCategorySubtree: %w", err)
+	}
+
+	parents := make(map[string]string, len(tree))
+	for _, c := range tree {
+		parents[c.ID] = c.ParentID
+	}
+
+	for i, id := range categories {
+		for ; id != "" && !slices.Contains(s.ancestors[i], id); id = parents[id] {
+			s.ancestors[i] = append(s.ancestors[i], id)
+		}
+	}
+
+	return s, nil
+}
+
+// clientType is the client type of the user's role, if any. An anonymous
+// cart has no user.
+func (uc *UseCase) clientType(ctx context.Context, userID string) (string, error) {
+	if userID == "" {
+		return "", nil
+	}
+
+	user, err := uc.auth.GetUser(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("s.auth.GetUser: %w", err)
+	}
+
+	if user.RoleID == "" {
+		return "", nil
+	}
+
+	role, err := uc.auth.GetRole(ctx, user.RoleID)
+	if errors.Is(err, entity.ErrNotFound) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("s.auth.GetRole: %w", err)
+	}
+
+	return role.ClientTypeId, nil
+}
+
+func (s promotionScope) allowsClient(p entity.Promotion) bool {
+	return len(p.ClientTypeIDs) == 0 || slices.Contains(p.ClientTypeIDs, s.clientType)
+}
+
+func (s promotionScope) eligibleLines(p entity.Promotion, lines []entity.OrderProducts) []bool {
+	eligible := make([]bool, len(lines))
+
+	for i, line := range lines {
+		eligible[i] = len(p.ProductIDs) == 0 && len(p.CategoryIDs) == 0 ||
+			slices.Contains(p.ProductIDs, line.ProductID) ||
+			slices.ContainsFunc(s.ancestors[i], func(id string) bool { return slices.Contains(p.CategoryIDs, id) })
+	}
+
+	return eligible
+}
+
+// discountOffers applies offers one after another, each to what is left of
+// the line amounts after the previous ones. It returns the explanation of
+// every offer that gave a discount and the discount of every line.
+func discountOffers(offers []offer, lines []entity.OrderProducts, currency entity.Currency) ([]entity.OrderDiscount, []int) {
+	remaining := make([]int, len(lines))
+	for i, line := range lines {
+		remaining[i] = line.Cost * line.Count
+	}
+
+	total := make([]int, len(lines))
+	discounts := make([]entity.OrderDiscount, 0, len(offers))
+
+	for _, o := range offers {
+		amounts, description := discountLines(o.promotion, o.eligible, lines, remaining, currency)
+
+		amount := 0
+		for i, a := range amounts {
+			remaining[i] -= a
+			total[i] += a
+			amount += a
+		}
+
+		if amount == 0 {
+			continue
+		}
+
+		discounts = append(discounts, entity.OrderDiscount{
+			PromotionID: o.promotion.ID,
+			CouponID:    o.coupon.ID,
+			CouponCode:  o.coupon.Code,
+			Name:        o.promotion.Name,
+			Description: description,
+			Amount:      amount,
+		})
+	}
+
+	return discounts, total
+}
+
+// discountLines computes what a promotion takes off every eligible line,
+// never more than remains of it, and describes the discount. An order whose
+// subtotal is below the promotion's minimum gets nothing.
+func discountLines(p entity.Promotion, eligible []bool, lines []entity.OrderProducts, remaining []int, currency entity.Currency) ([]int, string) {
+	amounts := make([]int, len(lines))
+
+	items, base, subtotal := 0, 0, 0
+
+	for i, line := range lines {
+		subtotal += line.Cost * line.Count
+
+		if eligible[i] {
+			items += line.Count
+			base += remaining[i]
+		}
+	}
+
+	if subtotal < p.MinOrderCost {
+		return amounts, ""
+	}
+
+	switch p.Type {
+	case entity.PromotionPercentage:
+		for i := range lines {
+			if eligible[i] {
+				amounts[i] = remaining[i] * p.Value / 100
+			}
+		}
+
+		return amounts, fmt.Sprintf("%d%% off %s", p.Value, itemsText(items))
+	case entity.PromotionFixed:
+		off := min(p.Value, base)
+		left := off
+
+		// Split the amount across lines in proportion to what remains of
+		// them; the last eligible line takes the rounding difference.
+		last := -1
+		for i := range lines {
+			if eligible[i] && remaining[i] > 0 {
+				last = i
+			}
+		}
+
+		for i := range lines {
+			if !eligible[i] || remaining[i] == 0 {
+				continue
+			}
+
+			if i == last {
+				amounts[i] = min(left, remaining[i])
+				break
+			}
+
+			amounts[i] = off * remaining[i] / base
+			left -= amounts[i]
+		}
+
+		return amounts, fmt.Sprintf("%s %s off %s", currency.Format(off), currency.Code, itemsText(items))
+	case entity.PromotionBuyXGetY:
+		// Every BuyCount+GetCount items make GetCount of them discounted,
+		// the cheapest ones first.
+		free := items / (p.BuyCount + p.GetCount) * p.GetCount
+		left := free
+
+		order := make([]int, 0, len(lines))
+		for i := range lines {
+			if eligible[i] {
+				order = append(order, i)
+			}
+		}
+
+		sort.SliceStable(order, func(a, b int) bool { return lines[order[a]].Cost < lines[order[b]].Cost })
+
+		for _, i := range order {
+			n := min(left, lines[i].Count)
+			amounts[i] = min(remaining[i], n*lines[i].Cost*p.Value/100)
+			left -= n
+		}
+
+		discount := fmt.Sprintf("%d%% off", p.Value)
+		if p.Value == 100 {
+			discount = "free"
+		}
+
+		return amounts, fmt.Sprintf("buy %d get %d: %s %s", p.BuyCount, p.GetCount, itemsText(free), discount)
+	default:
+		return amounts, ""
+	}
+}
+
+func itemsText(n int) string {
+	if n == 1 {
+		return "1 item"
+	}
+
+	return fmt.Sprintf("%d items", n)
+}
+
+func totalDiscount(discounts []entity.OrderDiscount) int {
+	total := 0
+	for _, d := range discounts {
+		total += d.Amount
+	}
+
+	return total
+}
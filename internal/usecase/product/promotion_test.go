@@ -0,0 +1,328 @@
+package product
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/internal/entity"
+)
+
+var _usd = entity.Currency{Code: "USD", MinorUnits: 2}
+
+func line(cost, count int) entity.OrderProducts {
+	return entity.OrderProducts{Cost: cost, Count: count}
+}
+
+func remainingOf(lines []entity.OrderProducts) []int {
+	remaining := make([]int, len(lines))
+	for i, l := range lines {
+		remaining[i] = l.Cost * l.Count
+	}
+
+	return remaining
+}
+
+func TestDiscountLines(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		promotion   entity.Promotion
+		eligible    []bool
+		lines       []entity.OrderProducts
+		remaining   []int
+		amounts     []int
+		description string
+	}{
+		{
+			name:        "percentage",
+			promotion:   entity.Promotion{Type: entity.PromotionPercentage, Value: 10},
+			eligible:    []bool{true, true},
+			lines:       []entity.OrderProducts{line(1000, 2), line(333, 1)},
+			amounts:     []int{200, 33},
+			description: "10% off 3 items",
+		},
+		{
+			name:        "percentage of eligible lines",
+			promotion:   entity.Promotion{Type: entity.PromotionPercentage, Value: 25},
+			eligible:    []bool{false, true},
+			lines:       []entity.OrderProducts{line(1000, 2), line(400, 1)},
+			amounts:     []int{0, 100},
+			description: "25% off 1 item",
+		},
+		{
+			name:        "percentage of what remains",
+			promotion:   entity.Promotion{Type: entity.PromotionPercentage, Value: 50},
+			eligible:    []bool{true},
+			lines:       []entity.OrderProducts{line(1000, 1)},
+			remaining:   []int{600},
+			amounts:     []int{300},
+			description: "50% off 1 item",
+		},
+		{
+			name:        "fixed split in proportion",
+			promotion:   entity.Promotion{Type: entity.PromotionFixed, Value: 300},
+			eligible:    []bool{true, true},
+			lines:       []entity.OrderProducts{line(1000, 1), line(1000, 2)},
+			amounts:     []int{100, 200},
+			description: "3.00 USD off 3 items",
+		},
+		{
+			name:        "fixed remainder cents on the last line",
+			promotion:   entity.Promotion{Type: entity.PromotionFixed, Value: 100},
+			eligible:    []bool{true, true, true},
+			lines:       []entity.OrderProducts{line(1000, 1), line(1000, 1), line(1000, 1)},
+			amounts:     []int{33, 33, 34},
+			description: "1.00 USD off 3 items",
+		},
+		{
+			name:        "fixed remainder skips ineligible last line",
+			promotion:   entity.Promotion{Type: entity.PromotionFixed, Value: 100},
+			eligible:    []bool{true, true, false},
+			lines:       []entity.OrderProducts{line(1000, 1), line(2000, 1), line(500, 1)},
+			amounts:     []int{33, 67, 0},
+			description: "1.00 USD off 2 items",
+		},
+		{
+			name:        "fixed capped at what remains",
+			promotion:   entity.Promotion{Type: entity.PromotionFixed, Value: 5000},
+			eligible:    []bool{true, true},
+			lines:       []entity.OrderProducts{line(1000, 1), line(2000, 1)},
+			amounts:     []int{1000, 2000},
+			description: "30.00 USD off 2 items",
+		},
+		{
+			name:        "fixed skips used up lines",
+			promotion:   entity.Promotion{Type: entity.PromotionFixed, Value: 100},
+			eligible:    []bool{true, true},
+			lines:       []entity.OrderProducts{line(1000, 1), line(1000, 1)},
+			remaining:   []int{1000, 0},
+			amounts:     []int{100, 0},
+			description: "1.00 USD off 2 items",
+		},
+		{
+			name:        "buy x get y free, cheapest first",
+			promotion:   entity.Promotion{Type: entity.PromotionBuyXGetY, Value: 100, BuyCount: 2, GetCount: 1},
+			eligible:    []bool{true, true},
+			lines:       []entity.OrderProducts{line(1000, 2), line(500, 1)},
+			amounts:     []int{0, 500},
+			description: "buy 2 get 1: 1 item free",
+		},
+		{
+			name:        "buy x get y percentage across lines",
+			promotion:   entity.Promotion{Type: entity.PromotionBuyXGetY, Value: 50, BuyCount: 1, GetCount: 1},
+			eligible:    []bool{true, true},
+			lines:       []entity.OrderProducts{line(1000, 3), line(500, 1)},
+			amounts:     []int{500, 250},
+			description: "buy 1 get 1: 2 items 50% off",
+		},
+		{
+			name:        "buy x get y below the item count",
+			promotion:   entity.Promotion{Type: entity.PromotionBuyXGetY, Value: 100, BuyCount: 2, GetCount: 1},
+			eligible:    []bool{true, false},
+			lines:       []entity.OrderProducts{line(1000, 2), line(500, 1)},
+			amounts:     []int{0, 0},
+			description: "buy 2 get 1: 0 items free",
+		},
+		{
+			name:        "buy x get y capped at what remains",
+			promotion:   entity.Promotion{Type: entity.PromotionBuyXGetY, Value: 100, BuyCount: 1, GetCount: 1},
+			eligible:    []bool{true},
+			lines:       []entity.OrderProducts{line(1000, 2)},
+			remaining:   []int{800},
+			amounts:     []int{800},
+			description: "buy 1 get 1: 1 item free",
+		},
+		{
+			name:        "below min order",
+			promotion:   entity.Promotion{Type: entity.PromotionPercentage, Value: 10, MinOrderCost: 2001},
+			eligible:    []bool{true},
+			lines:       []entity.OrderProducts{line(1000, 2)},
+			amounts:     []int{0},
+			description: "",
+		},
+		{
+			name:        "at min order",
+			promotion:   entity.Promotion{Type: entity.PromotionPercentage, Value: 10, MinOrderCost: 2000},
+			eligible:    []bool{true},
+			lines:       []entity.OrderProducts{line(1000, 2)},
+			amounts:     []int{200},
+			description: "10% off 2 items",
+		},
+		{
+			name:        "min order counts every line",
+			promotion:   entity.Promotion{Type: entity.PromotionFixed, Value: 100, MinOrderCost: 2500},
+			eligible:    []bool{true, false},
+			lines:       []entity.OrderProducts{line(1000, 2), line(1000, 1)},
+			amounts:     []int{100, 0},
+			description: "1.00 USD off 2 items",
+		},
+		{
+			name:        "min order before earlier discounts",
+			promotion:   entity.Promotion{Type: entity.PromotionPercentage, Value: 10, MinOrderCost: 2000},
+			eligible:    []bool{true},
+			lines:       []entity.OrderProducts{line(1000, 2)},
+			remaining:   []int{1500},
+			amounts:     []int{150},
+			description: "10% off 2 items",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			remaining := tc.remaining
+			if remaining == nil {
+				remaining = remainingOf(tc.lines)
+			}
+
+			amounts, description := discountLines(tc.promotion, tc.eligible, tc.lines, remaining, _usd)
+
+			require.Equal(t, tc.amounts, amounts)
+			require.Equal(t, tc.description, description)
+		})
+	}
+}
+
+func TestDiscountOffers(t *testing.T) {
+	t.Parallel()
+
+	tenPercent := offer{
+		promotion: entity.Promotion{ID: "p1", Name: "Ten", Type: entity.PromotionPercentage, Value: 10},
+		eligible:  []bool{true, true},
+	}
+	fixed := offer{
+		promotion: entity.Promotion{ID: "p2", Name: "Fixed", Type: entity.PromotionFixed, Value: 300},
+		coupon:    entity.Coupon{ID: "c2", Code: "SAVE3"},
+		eligible:  []bool{true, true},
+	}
+	unmet := offer{
+		promotion: entity.Promotion{ID: "p3", Name: "Big spender", Type: entity.PromotionPercentage, Value: 50, MinOrderCost: 100000},
+		eligible:  []bool{true, true},
+	}
+
+	tests := []struct {
+		name      string
+		offers    []offer
+		lines     []entity.OrderProducts
+		discounts []entity.OrderDiscount
+		amounts   []int
+	}{
+		{
+			name:      "none",
+			lines:     []entity.OrderProducts{line(1000, 1)},
+			discounts: []entity.OrderDiscount{},
+			amounts:   []int{0},
+		},
+		{
+			name:   "stacked on what remains",
+			offers: []offer{tenPercent, fixed},
+			lines:  []entity.OrderProducts{line(1000, 2), line(1000, 1)},
+			discounts: []entity.OrderDiscount{
+				{PromotionID: "p1", Name: "Ten", Description: "10% off 3 items", Amount: 300},
+				{PromotionID: "p2", CouponID: "c2", CouponCode: "SAVE3", Name: "Fixed", Description: "3.00 USD off 3 items", Amount: 300},
+			},
+			amounts: []int{400, 200},
+		},
+		{
+			name:   "order matters",
+			offers: []offer{fixed, tenPercent},
+			lines:  []entity.OrderProducts{line(1000, 2), line(1000, 1)},
+			discounts: []entity.OrderDiscount{
+				{PromotionID: "p2", CouponID: "c2", CouponCode: "SAVE3", Name: "Fixed", Description: "3.00 USD off 3 items", Amount: 300},
+				{PromotionID: "p1", Name: "Ten", Description: "10% off 3 items", Amount: 270},
+			},
+			amounts: []int{380, 190},
+		},
+		{
+			name:   "offers without a discount are left out",
+			offers: []offer{unmet, tenPercent},
+			lines:  []entity.OrderProducts{line(1000, 2), line(1000, 1)},
+			discounts: []entity.OrderDiscount{
+				{PromotionID: "p1", Name: "Ten", Description: "10% off 3 items", Amount: 300},
+			},
+			amounts: []int{200, 100},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			discounts, amounts := discountOffers(tc.offers, tc.lines, _usd)
+
+			require.Equal(t, tc.discounts, discounts)
+			require.Equal(t, tc.amounts, amounts)
+		})
+	}
+}
+
+func TestBestDiscounts(t *testing.T) {
+	t.Parallel()
+
+	lines := []entity.OrderProducts{line(1000, 2), line(1000, 1)}
+	percentage := func(id string, value int) offer {
+		return offer{
+			promotion: entity.Promotion{ID: id, Type: entity.PromotionPercentage, Value: value},
+			eligible:  []bool{true, true},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		stackable []offer
+		exclusive []offer
+		applied   []string
+		amounts   []int
+	}{
+		{
+			name:    "none",
+			amounts: []int{0, 0},
+		},
+		{
+			name:      "stackable together beat an exclusive",
+			stackable: []offer{percentage("s1", 10), percentage("s2", 10)},
+			exclusive: []offer{percentage("e1", 15)},
+			applied:   []string{"s1", "s2"},
+			amounts:   []int{380, 190},
+		},
+		{
+			name:      "a better exclusive replaces the stackable",
+			stackable: []offer{percentage("s1", 10), percentage("s2", 10)},
+			exclusive: []offer{percentage("e1", 15), percentage("e2", 20)},
+			applied:   []string{"e2"},
+			amounts:   []int{400, 200},
+		},
+		{
+			name:      "ties go to the stackable",
+			stackable: []offer{percentage("s1", 20)},
+			exclusive: []offer{percentage("e1", 20)},
+			applied:   []string{"s1"},
+			amounts:   []int{400, 200},
+		},
+		{
+			name:      "exclusive ties go to the first",
+			exclusive: []offer{percentage("e1", 20), percentage("e2", 20)},
+			applied:   []string{"e1"},
+			amounts:   []int{400, 200},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			discounts, amounts := bestDiscounts(tc.stackable, tc.exclusive, lines, _usd)
+
+			applied := make([]string, 0, len(discounts))
+			for _, d := range discounts {
+				applied = append(applied, d.PromotionID)
+			}
+
+			require.ElementsMatch(t, tc.applied, applied)
+			require.Equal(t, tc.amounts, amounts)
+		})
+	}
+}
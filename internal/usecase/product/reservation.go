@@ -0,0 +1,199 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"ai-seller/internal/entity"
+)
+
+const _expireReservationBatch = 100
+
+// reserveStock sets the items of an order line aside until expiresAt.
+func (uc *UseCase) reserveStock(ctx context.Context, line entity.OrderProducts, expiresAt time.Time) error {
+	err := uc.product.ReserveStock(ctx, line.ProductID, line.VariantID, line.Count)
+	if err != nil {
+		return fmt.Errorf("s.product.ReserveStock: %w", err)
+	}
+
+	err = uc.product.CreateReservation(ctx, entity.StockReservation{
+		OrderID:   line.OrderID,
+		ProductID: line.ProductID,
+		VariantID: line.VariantID,
+		Count:     line.Count,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("s.product.CreateReservation: %w", err)
+	}
+
+	return nil
+}
+
+// consumeReservations turns the active reservations of a paid order into
+// stock taken off the shelf.
+func (uc *UseCase) consumeReservations(ctx context.Context, orderID string) error {
+	reservations, err := uc.product.UpdateReservationStatus(ctx, orderID, entity.ReservationActive, entity.ReservationConsumed)
+	if err != nil {
+		return fmt.Errorf("s.product.UpdateReservationStatus: %w", err)
+	}
+
+	for _, s := range reservations {
+		err = uc.product.UnreserveStock(ctx, s.ProductID, s.VariantID, s.Count)
+		if err != nil {
+			return fmt.Errorf("s.product.UnreserveStock: %w", err)
+		}
+
+		err = uc.takeStock(ctx, entity.OrderProducts{OrderID: orderID, ProductID: s.ProductID, VariantID: s.VariantID, Count: s.Count})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releaseReservations ends the active reservations of an order in status
+// and makes their items available again. It returns how many it released.
+func (uc *UseCase) releaseReservations(ctx context.Context, orderID, status string) (int, error) {
+	reservations, err := uc.product.UpdateReservationStatus(ctx, orderID, entity.ReservationActive, status)
+	if err != nil {
+		return 0, fmt.Errorf("s.product.UpdateReservationStatus: %w", err)
+	}
+
+	for _, s := range reservations {
+		err = uc.product.UnreserveStock(ctx, s.ProductID, s.VariantID, s.Count)
+		if err != nil {
+			return 0, fmt.Errorf("s.product.UnreserveStock: %w", err)
+		}
+	}
+
+	return len(reservations), nil
+}
+
+// restockOrder gives the items of a cancelled or returned order back. Items
+// that are still reserved are released; items already taken off the stock,
+// on payment or by orders placed before reservations, are put back.
+func (uc *UseCase) restockOrder(ctx context.Context, orderID, release string) error {
+	released, err := uc.releaseReservations(ctx, orderID, release)
+	if err != nil {
+		return err
+	}
+
+	if released > 0 {
+		return nil
+	}
+
+	lines, err := uc.product.GetOrderProductsByOrder(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("s.product.GetOrderProductsByOrder: %w", err)
+	}
+
+	for _, line := range lines {
+		err = uc.returnStock(ctx, line)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExpireReservations cancels the unpaid orders whose stock reservations
+// have expired and releases their items. It returns the number of orders
+// cancelled.
+func (uc *UseCase) ExpireReservations(ctx context.Context) (int, error) {
+	var (
+		expired int
+		errs    []error
+	)
+
+	for {
+		ids, err := uc.product.GetExpiredReservationOrders(ctx, _expireReservationBatch)
+		if err != nil {
+			return expired, fmt.Errorf("ProductUseCase - ExpireReservations - s.product.GetExpiredReservationOrders: %w", err)
+		}
+
+		before := expired
+
+		for _, id := range ids {
+			var ok bool
+
+			err = uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+				ok, err = uc.expireOrder(ctx, id)
+
+				return err
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("order %s: %w", id, err))
+
+				continue
+			}
+
+			uc.orderStockChanged(ctx, id)
+
+			if ok {
+				expired++
+			}
+		}
+
+		// Orders that failed or were skipped stay listed; leave them to the
+		// next run.
+		if len(ids) < _expireReservationBatch || len(errs) > 0 || expired == before {
+			break
+		}
+	}
+
+	if len(errs) > 0 {
+		return expired, fmt.Errorf("ProductUseCase - ExpireReservations - uc.expireOrder: %w", errors.Join(errs...))
+	}
+
+	return expired, nil
+}
+
+// expireOrder cancels an order whose reservations expired and reports
+// whether it did. The order is locked first, so an order paid or cancelled
+// in the meantime is left alone.
+func (uc *UseCase) expireOrder(ctx context.Context, orderID string) (bool, error) {
+	order, err := uc.product.LockOrder(ctx, orderID)
+	if err != nil {
+		return false, fmt.Errorf("s.product.LockOrder: %w", err)
+	}
+
+	reservations, err := uc.product.GetReservationsByOrder(ctx, orderID)
+	if err != nil {
+		return false, fmt.Errorf("s.product.GetReservationsByOrder: %w", err)
+	}
+
+	if !hasExpired(reservations, time.Now()) {
+		return false, nil
+	}
+
+	if !entity.CanTransitionOrder(order.Status, entity.OrderStatusCancelled) {
+		_, err = uc.releaseReservations(ctx, orderID, entity.ReservationExpired)
+
+		return false, err
+	}
+
+	err = uc.moveOrder(ctx, order, entity.OrderStatusUpdate{
+		Status:  entity.OrderStatusCancelled,
+		Comment: "Stock reservation expired",
+	}, entity.ReservationExpired)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func hasExpired(reservations []entity.StockReservation, now time.Time) bool {
+	for _, s := range reservations {
+		if s.Status == entity.ReservationActive && !s.ExpiresAt.After(now) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,175 @@
+package product
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/internal/entity"
+)
+
+func TestHasExpired(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	reservation := func(status string, expiresAt time.Time) entity.StockReservation {
+		return entity.StockReservation{Status: status, ExpiresAt: expiresAt}
+	}
+
+	tests := []struct {
+		name         string
+		reservations []entity.StockReservation
+		want         bool
+	}{
+		{name: "none"},
+		{name: "active", reservations: []entity.StockReservation{reservation(entity.ReservationActive, now.Add(time.Minute))}},
+		{name: "active expired", reservations: []entity.StockReservation{reservation(entity.ReservationActive, now.Add(-time.Minute))}, want: true},
+		{name: "expiring now", reservations: []entity.StockReservation{reservation(entity.ReservationActive, now)}, want: true},
+		{name: "consumed", reservations: []entity.StockReservation{reservation(entity.ReservationConsumed, now.Add(-time.Minute))}},
+		{name: "already expired", reservations: []entity.StockReservation{reservation(entity.ReservationExpired, now.Add(-time.Minute))}},
+		{
+			name: "one of several",
+			reservations: []entity.StockReservation{
+				reservation(entity.ReservationActive, now.Add(time.Minute)),
+				reservation(entity.ReservationActive, now.Add(-time.Minute)),
+			},
+			want: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, hasExpired(tc.reservations, now))
+		})
+	}
+}
+
+// reservationRepo holds a product with 10 items and orders in the given
+// statuses, with an active reservation of 1 item for each expiry offset.
+func reservationRepo(orders map[string]string, expiresAt map[string][]time.Duration) *fakeRepo {
+	r := newFakeRepo()
+	r.addProduct(entity.Product{ID: "plain", Count: 10, Available: 10})
+
+	for id, status := range orders {
+		r.orders[id] = entity.Order{ID: id, Status: status}
+
+		for _, d := range expiresAt[id] {
+			r.reservations = append(r.reservations, entity.StockReservation{
+				ID: r.nextID("reservation-"), OrderID: id, ProductID: "plain", Count: 1,
+				Status: entity.ReservationActive, ExpiresAt: time.Now().Add(d),
+			})
+
+			p := r.products["plain"]
+			p.Available--
+			r.products["plain"] = p
+		}
+	}
+
+	return r
+}
+
+func TestExpireReservations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		status       string
+		expiresAt    []time.Duration
+		wantStatus   string
+		reservations []string
+		available    int
+		expired      int
+	}{
+		{
+			name:         "expired",
+			status:       entity.OrderStatusNew,
+			expiresAt:    []time.Duration{-time.Minute},
+			wantStatus:   entity.OrderStatusCancelled,
+			reservations: []string{entity.ReservationExpired},
+			available:    10,
+			expired:      1,
+		},
+		{
+			name:         "expired after confirmation",
+			status:       entity.OrderStatusConfirmed,
+			expiresAt:    []time.Duration{-time.Minute, time.Minute},
+			wantStatus:   entity.OrderStatusCancelled,
+			reservations: []string{entity.ReservationExpired, entity.ReservationExpired},
+			available:    10,
+			expired:      1,
+		},
+		{
+			name:         "not yet expired",
+			status:       entity.OrderStatusNew,
+			expiresAt:    []time.Duration{time.Minute},
+			wantStatus:   entity.OrderStatusNew,
+			reservations: []string{entity.ReservationActive},
+			available:    9,
+		},
+		{
+			name:         "order that can no longer be cancelled",
+			status:       entity.OrderStatusShipped,
+			expiresAt:    []time.Duration{-time.Minute},
+			wantStatus:   entity.OrderStatusShipped,
+			reservations: []string{entity.ReservationExpired},
+			available:    10,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := reservationRepo(map[string]string{"o1": tc.status}, map[string][]time.Duration{"o1": tc.expiresAt})
+			uc := newTestUseCase(t, r)
+
+			expired, err := uc.ExpireReservations(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tc.expired, expired)
+			require.Equal(t, tc.wantStatus, r.orders["o1"].Status)
+
+			statuses := make([]string, 0, len(r.reservations))
+			for _, s := range r.reservations {
+				statuses = append(statuses, s.Status)
+			}
+
+			require.Equal(t, tc.reservations, statuses)
+			require.Equal(t, tc.available, r.products["plain"].Available)
+		})
+	}
+}
+
+func TestExpireReservationsBatches(t *testing.T) {
+	t.Parallel()
+
+	const n = _expireReservationBatch + _expireReservationBatch/2
+
+	orders := make(map[string]string, n)
+	expiresAt := make(map[string][]time.Duration, n)
+
+	for i := range n {
+		id := "o" + strconv.Itoa(i)
+		orders[id] = entity.OrderStatusNew
+		expiresAt[id] = []time.Duration{-time.Minute}
+	}
+
+	// Every order holds one of the product's items.
+	r := reservationRepo(orders, expiresAt)
+	p := r.products["plain"]
+	p.Count, p.Available = n, 0
+	r.products["plain"] = p
+
+	expired, err := newTestUseCase(t, r).ExpireReservations(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, n, expired)
+	require.Equal(t, n, r.products["plain"].Available)
+
+	for id, o := range r.orders {
+		require.Equal(t, entity.OrderStatusCancelled, o.Status, id)
+	}
+}
@@ -0,0 +1,131 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"ai-seller/internal/entity"
+)
+
+// _maxSearchWords bounds the size of the generated text search query.
+const _maxSearchWords = 8
+
+// _apostrophes turns the marks Uzbek Latin is written with, as in o‘zbek,
+// g‘isht and ma’no, into a plain apostrophe, the one the index is built with.
+var _apostrophes = strings.NewReplacer("ʻ", "'", "ʼ", "'", "’", "'", "‘", "'", "`", "'", "´", "'")
+
+// SearchProducts splits the query into words, expands every word with its
+// synonyms and runs a ranked full text search.
+func (uc *UseCase) SearchProducts(ctx context.Context, s entity.ProductSearch) (entity.Page[entity.SearchHit], error) {
+	words := searchWords(s.Query)
+	if len(words) == 0 {
+		return entity.Page[entity.SearchHit]{}, entity.NewValidationError("q", "must contain a letter or digit")
+	}
+
+	synonyms, err := uc.product.GetSynonyms(ctx, words)
+	if err != nil {
+		return entity.Page[entity.SearchHit]{}, fmt.Errorf("ProductUseCase - SearchProducts - s.product.GetSynonyms: %w", err)
+	}
+
+	alternatives := make([][]string, 0, len(words))
+
+	for _, w := range words {
+		group := []string{w}
+
+		for _, syn := range synonyms {
+			switch w {
+			case syn.Term:
+				group = append(group, searchWords(syn.Synonym)...)
+			case syn.Synonym:
+				group = append(group, searchWords(syn.Term)...)
+			}
+		}
+
+		alternatives = append(alternatives, group)
+	}
+
+	page, err := uc.product.SearchProducts(ctx, s, alternatives)
+	if err != nil {
+		return entity.Page[entity.SearchHit]{}, fmt.Errorf("ProductUseCase - SearchProducts - s.product.SearchProducts: %w", err)
+	}
+
+	return page, nil
+}
+
+// searchWords lowercases q and splits it into runs of letters, digits and
+// apostrophes, which are safe to embed in a text search query. Apostrophes
+// are normalised and kept inside words, so that "ko‘ylak" stays one word.
+func searchWords(q string) []string {
+	fields := strings.FieldsFunc(_apostrophes.Replace(strings.ToLower(q)), func(r rune) bool {
+		return r != '\'' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	words := make([]string, 0, len(fields))
+
+	for _, f := range fields {
+		if f = strings.Trim(f, "'"); f != "" {
+			words = append(words, f)
+		}
+	}
+
+	if len(words) > _maxSearchWords {
+		words = words[:_maxSearchWords]
+	}
+
+	return words
+}
+
+// SuggestProducts -.
+func (uc *UseCase) SuggestProducts(ctx context.Context, prefix string, limit int) ([]string, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return []string{}, nil
+	}
+
+	names, err := uc.product.SuggestProducts(ctx, prefix, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - SuggestProducts - s.product.SuggestProducts: %w", err)
+	}
+
+	return names, nil
+}
+
+// ListSynonyms -.
+func (uc *UseCase) ListSynonyms(ctx context.Context) ([]entity.SearchSynonym, error) {
+	synonyms, err := uc.product.ListSynonyms(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - ListSynonyms - s.product.ListSynonyms: %w", err)
+	}
+
+	return synonyms, nil
+}
+
+// CreateSynonym stores a synonym pair in lower case with plain apostrophes,
+// as searches are.
+func (uc *UseCase) CreateSynonym(ctx context.Context, s entity.SearchSynonym) (entity.SearchSynonym, error) {
+	s.Term = _apostrophes.Replace(strings.ToLower(strings.TrimSpace(s.Term)))
+	s.Synonym = _apostrophes.Replace(strings.ToLower(strings.TrimSpace(s.Synonym)))
+
+	if s.Term == s.Synonym {
+		return entity.SearchSynonym{}, entity.NewValidationError("synonym", "must differ from term")
+	}
+
+	s, err := uc.product.CreateSynonym(ctx, s)
+	if err != nil {
+		return entity.SearchSynonym{}, fmt.Errorf("ProductUseCase - CreateSynonym - s.product.CreateSynonym: %w", err)
+	}
+
+	return s, nil
+}
+
+// DeleteSynonym -.
+func (uc *UseCase) DeleteSynonym(ctx context.Context, term, synonym string) error {
+	err := uc.product.DeleteSynonym(ctx, _apostrophes.Replace(strings.ToLower(term)), _apostrophes.Replace(strings.ToLower(synonym)))
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - DeleteSynonym - s.product.DeleteSynonym: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package product
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchWords(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "empty", query: "", want: []string{}},
+		{name: "punctuation only", query: " -!? ", want: []string{}},
+		{name: "lower case", query: "Red SHIRT", want: []string{"red", "shirt"}},
+		{name: "separators", query: "  t-shirt,cotton/linen  ", want: []string{"t", "shirt", "cotton", "linen"}},
+		{name: "digits", query: "iPhone15 Pro 256gb", want: []string{"iphone15", "pro", "256gb"}},
+		{name: "cyrillic", query: "Футболка ЁЛКА", want: []string{"футболка", "ёлка"}},
+		{name: "uzbek latin", query: "Ko'ylak", want: []string{"ko'ylak"}},
+		{name: "turned comma", query: "Oʻzbek gʻisht", want: []string{"o'zbek", "g'isht"}},
+		{name: "right quote", query: "Ko’ylak ma’no", want: []string{"ko'ylak", "ma'no"}},
+		{name: "left quote and backtick", query: "o‘rik g`isht", want: []string{"o'rik", "g'isht"}},
+		{name: "quotes around words", query: "'ko'ylak' ' ''", want: []string{"ko'ylak"}},
+		{name: "tsquery operators", query: "a:* | !b & (c) <-> d", want: []string{"a", "b", "c", "d"}},
+		{name: "sql quoting", query: "x'); DROP TABLE product;--", want: []string{"x", "drop", "table", "product"}},
+		{
+			name:  "bounded",
+			query: "one two three four five six seven eight nine ten",
+			want:  []string{"one", "two", "three", "four", "five", "six", "seven", "eight"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, searchWords(tc.query))
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-seller/internal/entity"
+)
+
+const _tokenType = "Bearer"
+
+// Login signs a user in. The cart of the anonymous session token cartSession,
+// if given, is merged into the user's cart.
+func (uc *UseCase) Login(ctx context.Context, username, password, cartSession string) (entity.Tokens, error) {
+	user, err := uc.Authenticate(ctx, username, password)
+	if err != nil {
+		return entity.Tokens{}, fmt.Errorf("ProductUseCase - Login - uc.Authenticate: %w", err)
+	}
+
+	tokens, err := uc.issueTokens(ctx, user, uuid.NewString())
+	if err != nil {
+		return entity.Tokens{}, fmt.Errorf("ProductUseCase - Login - uc.issueTokens: %w", err)
+	}
+
+	if cartSession != "" {
+		// Best effort: the session cart stays around for another merge.
+		err = uc.MergeCart(ctx, user.ID, cartSession)
+		if err != nil {
+			uc.l.Error(fmt.Errorf("ProductUseCase - Login - uc.MergeCart: %w", err))
+		}
+	}
+
+	return tokens, nil
+}
+
+// Refresh rotates a refresh token. Presenting an already rotated token is
+// treated as theft and revokes every token of its family.
+func (uc *UseCase) Refresh(ctx context.Context, refreshToken string) (entity.Tokens, error) {
+	stored, err := uc.auth.GetRefreshToken(ctx, uc.tokens.HashRefreshToken(refreshToken))
+	if errors.Is(err, entity.ErrNotFound) {
+		return entity.Tokens{}, fmt.Errorf("ProductUseCase - Refresh - s.auth.GetRefreshToken: %w", entity.ErrInvalidToken)
+	}
+
+	if err != nil {
+		return entity.Tokens{}, fmt.Errorf("ProductUseCase - Refresh - s.auth.GetRefreshToken: %w", err)
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return entity.Tokens{}, fmt.Errorf("ProductUseCase - Refresh - expired: %w", entity.ErrInvalidToken)
+	}
+
+	revoked, err := uc.auth.RevokeRefreshToken(ctx, stored.ID)
+	if err != nil {
+		return entity.Tokens{}, fmt.Errorf("ProductUseCase - Refresh - s.auth.RevokeRefreshToken: %w", err)
+	}
+
+	if stored.RevokedAt != nil || !revoked {
+		err = uc.auth.RevokeRefreshTokenFamily(ctx, stored.FamilyID)
+		if err != nil {
+			return entity.Tokens{}, fmt.Errorf("ProductUseCase - Refresh - s.auth.RevokeRefreshTokenFamily: %w", err)
+		}
+
+		return entity.Tokens{}, fmt.Errorf("ProductUseCase - Refresh - reused: %w", entity.ErrInvalidToken)
+	}
+
+	user, err := uc.auth.GetUser(ctx, stored.UserID)
+	if errors.Is(err, entity.ErrNotFound) {
+		return entity.Tokens{}, fmt.Errorf("ProductUseCase - Refresh - s.auth.GetUser: %w", entity.ErrInvalidToken)
+	}
+
+	if err != nil {
+		return entity.Tokens{}, fmt.Errorf("ProductUseCase - Refresh - s.auth.GetUser: %w", err)
+	}
+
+	tokens, err := uc.issueTokens(ctx, user, stored.FamilyID)
+	if err != nil {
+		return entity.Tokens{}, fmt.Errorf("ProductUseCase - Refresh - uc.issueTokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// Logout revokes the refresh token together with every token rotated from it.
+func (uc *UseCase) Logout(ctx context.Context, refreshToken string) error {
+	stored, err := uc.auth.GetRefreshToken(ctx, uc.tokens.HashRefreshToken(refreshToken))
+	if errors.Is(err, entity.ErrNotFound) {
+		return fmt.Errorf("ProductUseCase - Logout - s.auth.GetRefreshToken: %w", entity.ErrInvalidToken)
+	}
+
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - Logout - s.auth.GetRefreshToken: %w", err)
+	}
+
+	err = uc.auth.RevokeRefreshTokenFamily(ctx, stored.FamilyID)
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - Logout - s.auth.RevokeRefreshTokenFamily: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyAccessToken -.
+func (uc *UseCase) VerifyAccessToken(accessToken string) (entity.AccessClaims, error) {
+	userID, roleID, err := uc.tokens.ParseAccessToken(accessToken)
+	if err != nil {
+		return entity.AccessClaims{}, fmt.Errorf("ProductUseCase - VerifyAccessToken - s.tokens.ParseAccessToken: %w", errors.Join(entity.ErrInvalidToken, err))
+	}
+
+	return entity.AccessClaims{UserID: userID, RoleID: roleID}, nil
+}
+
+func (uc *UseCase) issueTokens(ctx context.Context, user entity.User, familyID string) (entity.Tokens, error) {
+	accessToken, accessExpiresAt, err := uc.tokens.NewAccessToken(user.ID, user.RoleID)
+	if err != nil {
+		return entity.Tokens{}, fmt.Errorf("ProductUseCase - issueTokens - s.tokens.NewAccessToken: %w", err)
+	}
+
+	refreshToken, hash, refreshExpiresAt, err := uc.tokens.NewRefreshToken()
+	if err != nil {
+		return entity.Tokens{}, fmt.Errorf("ProductUseCase - issueTokens - s.tokens.NewRefreshToken: %w", err)
+	}
+
+	err = uc.auth.CreateRefreshToken(ctx, entity.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hash,
+		ExpiresAt: refreshExpiresAt,
+	})
+	if err != nil {
+		return entity.Tokens{}, fmt.Errorf("ProductUseCase - issueTokens - s.auth.CreateRefreshToken: %w", err)
+	}
+
+	return entity.Tokens{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshExpiresAt,
+		TokenType:             _tokenType,
+	}, nil
+}
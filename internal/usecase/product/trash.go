@@ -0,0 +1,205 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"ai-seller/internal/entity"
+)
+
+const _purgeProductBatch = 100
+
+// ListDeletedProducts -.
+func (uc *UseCase) ListDeletedProducts(ctx context.Context, p entity.ListParams) (entity.Page[entity.Product], error) {
+	page, err := uc.product.ListDeletedProducts(ctx, p)
+	if err != nil {
+		return entity.Page[entity.Product]{}, fmt.Errorf("ProductUseCase - ListDeletedProducts - s.product.ListDeletedProducts: %w", err)
+	}
+
+	return page, nil
+}
+
+// RestoreProduct takes a product out of the trash. Its category has to be
+// restored first.
+func (uc *UseCase) RestoreProduct(ctx context.Context, id string) (entity.Product, error) {
+	var product entity.Product
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+
+		product, err = uc.product.RestoreProduct(ctx, id)
+		if err != nil {
+			return fmt.Errorf("s.product.RestoreProduct: %w", err)
+		}
+
+		if product.CategoryID == "" {
+			return nil
+		}
+
+		_, err = uc.product.GetCategory(ctx, product.CategoryID)
+		if errors.Is(err, entity.ErrNotFound) {
+			return entity.NewValidationError("category_id", "category "+product.CategoryID+" is in the trash")
+		}
+
+		if err != nil {
+			return fmt.Errorf("s.product.GetCategory: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.Product{}, fmt.Errorf("ProductUseCase - RestoreProduct - s.tx.WithinTransaction: %w", err)
+	}
+
+	uc.stockChanged(ctx, id)
+
+	return product, nil
+}
+
+// ListDeletedCategories -.
+func (uc *UseCase) ListDeletedCategories(ctx context.Context, p entity.ListParams) (entity.Page[entity.Category], error) {
+	page, err := uc.product.ListDeletedCategories(ctx, p)
+	if err != nil {
+		return entity.Page[entity.Category]{}, fmt.Errorf("ProductUseCase - ListDeletedCategories - s.product.ListDeletedCategories: %w", err)
+	}
+
+	return page, nil
+}
+
+// RestoreCategory takes a category out of the trash and places it after its
+// siblings. Its parent has to be restored first.
+func (uc *UseCase) RestoreCategory(ctx context.Context, id string) (entity.Category, error) {
+	var category entity.Category
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+
+		category, err = uc.product.RestoreCategory(ctx, id)
+		if err != nil {
+			return fmt.Errorf("s.product.RestoreCategory: %w", err)
+		}
+
+		if category.ParentID == "" {
+			return nil
+		}
+
+		_, err = uc.product.GetCategory(ctx, category.ParentID)
+		if errors.Is(err, entity.ErrNotFound) {
+			return entity.NewValidationError("parent_id", "category "+category.ParentID+" is in the trash")
+		}
+
+		if err != nil {
+			return fmt.Errorf("s.product.GetCategory: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.Category{}, fmt.Errorf("ProductUseCase - RestoreCategory - s.tx.WithinTransaction: %w", err)
+	}
+
+	return category, nil
+}
+
+// ListDeletedUsers -.
+func (uc *UseCase) ListDeletedUsers(ctx context.Context, p entity.ListParams) (entity.Page[entity.User], error) {
+	page, err := uc.auth.ListDeletedUsers(ctx, p)
+	if err != nil {
+		return entity.Page[entity.User]{}, fmt.Errorf("ProductUseCase - ListDeletedUsers - s.auth.ListDeletedUsers: %w", err)
+	}
+
+	return page, nil
+}
+
+// RestoreUser takes a user out of the trash. Its sessions were revoked on
+// deletion, so the user has to sign in again.
+func (uc *UseCase) RestoreUser(ctx context.Context, id string) (entity.User, error) {
+	user, err := uc.auth.RestoreUser(ctx, id)
+	if err != nil {
+		return entity.User{}, fmt.Errorf("ProductUseCase - RestoreUser - s.auth.RestoreUser: %w", err)
+	}
+
+	return user, nil
+}
+
+// PurgeTrash removes the products, categories and users that have been in
+// the trash longer than the retention window and that nothing refers to any
+// more. Products go first, so that their categories can follow in the same
+// run; the image files of a purged product are deleted with it.
+func (uc *UseCase) PurgeTrash(ctx context.Context) (entity.TrashPurge, error) {
+	var purged entity.TrashPurge
+
+	before := time.Now().Add(-uc.trashRetention)
+
+	for {
+		ids, err := uc.product.GetPurgeableProducts(ctx, before, _purgeProductBatch)
+		if err != nil {
+			return purged, fmt.Errorf("ProductUseCase - PurgeTrash - s.product.GetPurgeableProducts: %w", err)
+		}
+
+		n := purged.Products
+
+		for _, id := range ids {
+			err = uc.purgeProduct(ctx, id)
+			if errors.Is(err, entity.ErrInUse) || errors.Is(err, entity.ErrNotFound) {
+				// Ordered or restored in the meantime.
+				continue
+			}
+
+			if err != nil {
+				return purged, fmt.Errorf("ProductUseCase - PurgeTrash - uc.purgeProduct: %w", err)
+			}
+
+			purged.Products++
+		}
+
+		if len(ids) < _purgeProductBatch || purged.Products == n {
+			break
+		}
+	}
+
+	// A category is purged after its subcategories, one level per pass.
+	for {
+		n, err := uc.product.PurgeCategories(ctx, before)
+		if err != nil {
+			return purged, fmt.Errorf("ProductUseCase - PurgeTrash - s.product.PurgeCategories: %w", err)
+		}
+
+		if n == 0 {
+			break
+		}
+
+		purged.Categories += n
+	}
+
+	users, err := uc.auth.PurgeUsers(ctx, before)
+	if err != nil {
+		return purged, fmt.Errorf("ProductUseCase - PurgeTrash - s.auth.PurgeUsers: %w", err)
+	}
+
+	purged.Users = users
+
+	return purged, nil
+}
+
+// purgeProduct removes a product in the trash and then the files of its
+// images.
+func (uc *UseCase) purgeProduct(ctx context.Context, id string) error {
+	media, err := uc.product.GetMediaByProduct(ctx, id)
+	if err != nil {
+		return fmt.Errorf("s.product.GetMediaByProduct: %w", err)
+	}
+
+	err = uc.product.PurgeProduct(ctx, id)
+	if err != nil {
+		return fmt.Errorf("s.product.PurgeProduct: %w", err)
+	}
+
+	for _, m := range media {
+		uc.deleteMediaFiles(ctx, m.Keys())
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package product
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/internal/entity"
+)
+
+// trashRepo holds the live categories electronics > phones, laptops under
+// electronics and clothes > shoes in the trash, and products in the trash in
+// a live category, in a deleted one and in none, next to a live product.
+func trashRepo() *fakeRepo {
+	deletedAt := time.Now().Add(-time.Hour)
+
+	r := newFakeRepo()
+	r.addCategory(entity.Category{ID: "electronics", Position: 0})
+	r.addCategory(entity.Category{ID: "phones", ParentID: "electronics", Position: 0})
+	r.addCategory(entity.Category{ID: "laptops", ParentID: "electronics", Position: 4, DeletedAt: &deletedAt})
+	r.addCategory(entity.Category{ID: "clothes", Position: 3, DeletedAt: &deletedAt})
+	r.addCategory(entity.Category{ID: "shoes", ParentID: "clothes", Position: 0, DeletedAt: &deletedAt})
+	r.addProduct(entity.Product{ID: "phone", CategoryID: "phones", DeletedAt: &deletedAt})
+	r.addProduct(entity.Product{ID: "shirt", CategoryID: "clothes", DeletedAt: &deletedAt})
+	r.addProduct(entity.Product{ID: "cable", DeletedAt: &deletedAt})
+	r.addProduct(entity.Product{ID: "plain"})
+
+	return r
+}
+
+func TestRestoreProduct(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		id    string
+		field string
+		err   error
+	}{
+		{name: "in a live category", id: "phone"},
+		{name: "without a category", id: "cable"},
+		{name: "category in the trash", id: "shirt", field: "category_id"},
+		{name: "not in the trash", id: "plain", err: entity.ErrNotFound},
+		{name: "missing", id: "hat", err: entity.ErrNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := newTestUseCase(t, trashRepo()).RestoreProduct(context.Background(), tc.id)
+
+			switch {
+			case tc.field != "":
+				var v *entity.ValidationError
+				require.ErrorAs(t, err, &v)
+				require.Equal(t, tc.field, v.Field)
+			case tc.err != nil:
+				require.ErrorIs(t, err, tc.err)
+			default:
+				require.NoError(t, err)
+				require.Equal(t, tc.id, got.ID)
+				require.Nil(t, got.DeletedAt)
+			}
+		})
+	}
+}
+
+func TestRestoreCategory(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		id       string
+		position int
+		field    string
+		err      error
+	}{
+		{name: "root after its siblings", id: "clothes", position: 1},
+		{name: "under a live parent after its siblings", id: "laptops", position: 1},
+		{name: "parent in the trash", id: "shoes", field: "parent_id"},
+		{name: "not in the trash", id: "phones", err: entity.ErrNotFound},
+		{name: "missing", id: "books", err: entity.ErrNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := newTestUseCase(t, trashRepo()).RestoreCategory(context.Background(), tc.id)
+
+			switch {
+			case tc.field != "":
+				var v *entity.ValidationError
+				require.ErrorAs(t, err, &v)
+				require.Equal(t, tc.field, v.Field)
+			case tc.err != nil:
+				require.ErrorIs(t, err, tc.err)
+			default:
+				require.NoError(t, err)
+				require.Equal(t, tc.id, got.ID)
+				require.Equal(t, tc.position, got.Position)
+				require.Nil(t, got.DeletedAt)
+			}
+		})
+	}
+}
+
+func TestRestoreCategoryThenProduct(t *testing.T) {
+	t.Parallel()
+
+	uc := newTestUseCase(t, trashRepo())
+
+	_, err := uc.RestoreCategory(context.Background(), "clothes")
+	require.NoError(t, err)
+
+	product, err := uc.RestoreProduct(context.Background(), "shirt")
+	require.NoError(t, err)
+	require.Equal(t, "clothes", product.CategoryID)
+}
@@ -0,0 +1,195 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"ai-seller/internal/entity"
+)
+
+// ListVariants -.
+func (uc *UseCase) ListVariants(ctx context.Context, productID string) ([]entity.ProductVariant, error) {
+	_, err := uc.product.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - ListVariants - s.product.GetProduct: %w", err)
+	}
+
+	variants, err := uc.product.GetVariantsByProduct(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - ListVariants - s.product.GetVariantsByProduct: %w", err)
+	}
+
+	return variants, nil
+}
+
+// GetVariant -.
+func (uc *UseCase) GetVariant(ctx context.Context, productID, id string) (entity.ProductVariant, error) {
+	variant, err := uc.product.GetVariant(ctx, productID, id)
+	if err != nil {
+		return entity.ProductVariant{}, fmt.Errorf("ProductUseCase - GetVariant - s.product.GetVariant: %w", err)
+	}
+
+	return variant, nil
+}
+
+// CreateVariant adds a variant to a product. Its options must be attributes
+// of the product's category and differ from those of every other variant.
+// Its Count is recorded as the opening stock of the default warehouse.
+func (uc *UseCase) CreateVariant(ctx context.Context, v entity.ProductVariant) (entity.ProductVariant, error) {
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := uc.checkVariantOptions(ctx, v)
+		if err != nil {
+			return err
+		}
+
+		v.ID, err = uc.product.CreateVariant(ctx, v)
+		if err != nil {
+			return fmt.Errorf("s.product.CreateVariant: %w", err)
+		}
+
+		err = uc.setStock(ctx, v.ProductID, v.ID, 0, v.Count, entity.ReasonInitial)
+		if err != nil {
+			return err
+		}
+
+		err = uc.product.ReplaceVariantOptions(ctx, v.ID, v.Options)
+		if err != nil {
+			return fmt.Errorf("s.product.ReplaceVariantOptions: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.ProductVariant{}, fmt.Errorf("ProductUseCase - CreateVariant - s.tx.WithinTransaction: %w", err)
+	}
+
+	uc.stockChanged(ctx, v.ProductID)
+
+	variant, err := uc.product.GetVariant(ctx, v.ProductID, v.ID)
+	if err != nil {
+		return entity.ProductVariant{}, fmt.Errorf("ProductUseCase - CreateVariant - s.product.GetVariant: %w", err)
+	}
+
+	return variant, nil
+}
+
+// UpdateVariant replaces a variant, options included. A changed Count is
+// recorded as a correction in the default warehouse.
+func (uc *UseCase) UpdateVariant(ctx context.Context, v entity.ProductVariant) (entity.ProductVariant, error) {
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := uc.checkVariantOptions(ctx, v)
+		if err != nil {
+			return err
+		}
+
+		current, err := uc.product.GetVariant(ctx, v.ProductID, v.ID)
+		if err != nil {
+			return fmt.Errorf("s.product.GetVariant: %w", err)
+		}
+
+		err = uc.product.UpdateVariant(ctx, v)
+		if err != nil {
+			return fmt.Errorf("s.product.UpdateVariant: %w", err)
+		}
+
+		err = uc.setStock(ctx, v.ProductID, v.ID, current.Count, v.Count, entity.ReasonCorrection)
+		if err != nil {
+			return err
+		}
+
+		err = uc.product.ReplaceVariantOptions(ctx, v.ID, v.Options)
+		if err != nil {
+			return fmt.Errorf("s.product.ReplaceVariantOptions: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.ProductVariant{}, fmt.Errorf("ProductUseCase - UpdateVariant - s.tx.WithinTransaction: %w", err)
+	}
+
+	uc.stockChanged(ctx, v.ProductID)
+
+	variant, err := uc.product.GetVariant(ctx, v.ProductID, v.ID)
+	if err != nil {
+		return entity.ProductVariant{}, fmt.Errorf("ProductUseCase - UpdateVariant - s.product.GetVariant: %w", err)
+	}
+
+	return variant, nil
+}
+
+// DeleteVariant -.
+func (uc *UseCase) DeleteVariant(ctx context.Context, productID, id string, version int) error {
+	err := uc.product.DeleteVariant(ctx, productID, id, version)
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - DeleteVariant - s.product.DeleteVariant: %w", err)
+	}
+
+	return nil
+}
+
+// checkVariantOptions validates the options of v against the attributes of
+// the product's category and the options of the product's other variants.
+func (uc *UseCase) checkVariantOptions(ctx context.Context, v entity.ProductVariant) error {
+	product, err := uc.product.GetProduct(ctx, v.ProductID)
+	if err != nil {
+		return fmt.Errorf("s.product.GetProduct: %w", err)
+	}
+
+	var definitions []entity.Attribute
+	if product.CategoryID != "" && len(v.Options) > 0 {
+		definitions, err = uc.product.GetAttributesByCategory(ctx, product.CategoryID)
+		if err != nil {
+			return fmt.Errorf("s.product.GetAttributesByCategory: %w", err)
+		}
+	}
+
+	seen := make(map[string]bool, len(v.Options))
+
+	for _, o := range v.Options {
+		idx := slices.IndexFunc(definitions, func(a entity.Attribute) bool { return a.ID == o.AttributeID })
+		if idx < 0 {
+			return entity.NewValidationError("options", "attribute "+o.AttributeID+" is not defined for the product's category")
+		}
+
+		if seen[o.AttributeID] {
+			return entity.NewValidationError("options."+definitions[idx].Name, "is given more than once")
+		}
+
+		seen[o.AttributeID] = true
+
+		err = definitions[idx].ValidateOption(o.Value)
+		if err != nil {
+			return err
+		}
+	}
+
+	siblings, err := uc.product.GetVariantsByProduct(ctx, v.ProductID)
+	if err != nil {
+		return fmt.Errorf("s.product.GetVariantsByProduct: %w", err)
+	}
+
+	key := optionsKey(v.Options)
+
+	for _, s := range siblings {
+		if s.ID != v.ID && optionsKey(s.Options) == key {
+			return fmt.Errorf("variant %s has the same options: %w", s.SKU, entity.ErrConflict)
+		}
+	}
+
+	return nil
+}
+
+// optionsKey identifies a combination of option values regardless of order.
+func optionsKey(options []entity.VariantOption) string {
+	parts := make([]string, 0, len(options))
+	for _, o := range options {
+		parts = append(parts, o.AttributeID+"="+o.Value)
+	}
+
+	slices.Sort(parts)
+
+	return strings.Join(parts, "&")
+}
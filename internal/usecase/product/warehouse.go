@@ -0,0 +1,552 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"ai-seller/internal/entity"
+)
+
+// ListWarehouses returns the warehouses, the default one first.
+func (uc *UseCase) ListWarehouses(ctx context.Context) ([]entity.Warehouse, error) {
+	warehouses, err := uc.product.GetWarehouses(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - ListWarehouses - s.product.GetWarehouses: %w", err)
+	}
+
+	return warehouses, nil
+}
+
+// GetWarehouse -.
+func (uc *UseCase) GetWarehouse(ctx context.Context, id string) (entity.Warehouse, error) {
+	warehouse, err := uc.product.GetWarehouse(ctx, id)
+	if err != nil {
+		return entity.Warehouse{}, fmt.Errorf("ProductUseCase - GetWarehouse - s.product.GetWarehouse: %w", err)
+	}
+
+	return warehouse, nil
+}
+
+// CreateWarehouse adds a warehouse, making it the default one when asked.
+func (uc *UseCase) CreateWarehouse(ctx context.Context, w entity.Warehouse) (entity.Warehouse, error) {
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		created, err := uc.product.CreateWarehouse(ctx, w)
+		if err != nil {
+			return fmt.Errorf("s.product.CreateWarehouse: %w", err)
+		}
+
+		w.ID = created.ID
+
+		if w.Default {
+			err = uc.product.SetDefaultWarehouse(ctx, w.ID)
+			if err != nil {
+				return fmt.Errorf("s.product.SetDefaultWarehouse: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.Warehouse{}, fmt.Errorf("ProductUseCase - CreateWarehouse - s.tx.WithinTransaction: %w", err)
+	}
+
+	return uc.GetWarehouse(ctx, w.ID)
+}
+
+// UpdateWarehouse replaces a warehouse. Default moves the default to it;
+// there is always one default warehouse, so it cannot be unset.
+func (uc *UseCase) UpdateWarehouse(ctx context.Context, w entity.Warehouse) (entity.Warehouse, error) {
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := uc.product.UpdateWarehouse(ctx, w)
+		if err != nil {
+			return fmt.Errorf("s.product.UpdateWarehouse: %w", err)
+		}
+
+		if w.Default {
+			err = uc.product.SetDefaultWarehouse(ctx, w.ID)
+			if err != nil {
+				return fmt.Errorf("s.product.SetDefaultWarehouse: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.Warehouse{}, fmt.Errorf("ProductUseCase - UpdateWarehouse - s.tx.WithinTransaction: %w", err)
+	}
+
+	return uc.GetWarehouse(ctx, w.ID)
+}
+
+// DeleteWarehouse deletes a warehouse without stock history. The default
+// warehouse cannot be deleted.
+func (uc *UseCase) DeleteWarehouse(ctx context.Context, id string) error {
+	warehouse, err := uc.product.GetWarehouse(ctx, id)
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - DeleteWarehouse - s.product.GetWarehouse: %w", err)
+	}
+
+	if warehouse.Default {
+		return fmt.Errorf("ProductUseCase - DeleteWarehouse: %w",
+			entity.NewValidationError("id", "the default warehouse cannot be deleted"))
+	}
+
+	err = uc.product.DeleteWarehouse(ctx, id)
+	if err != nil {
+		return fmt.Errorf("ProductUseCase - DeleteWarehouse - s.product.DeleteWarehouse: %w", err)
+	}
+
+	return nil
+}
+
+// GetStockLevels -.
+func (uc *UseCase) GetStockLevels(ctx context.Context, f entity.StockLevelFilter) ([]entity.StockLevel, error) {
+	levels, err := uc.product.GetStockLevels(ctx, f)
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - GetStockLevels - s.product.GetStockLevels: %w", err)
+	}
+
+	return levels, nil
+}
+
+// ListStockMovements pages through the stock ledger, newest first by default.
+func (uc *UseCase) ListStockMovements(ctx context.Context, f entity.StockMovementFilter) (entity.Page[entity.StockMovement], error) {
+	page, err := uc.product.ListStockMovements(ctx, f)
+	if err != nil {
+		return entity.Page[entity.StockMovement]{}, fmt.Errorf("ProductUseCase - ListStockMovements - s.product.ListStockMovements: %w", err)
+	}
+
+	return page, nil
+}
+
+// ReceiveStock records goods received into a warehouse.
+func (uc *UseCase) ReceiveStock(ctx context.Context, r entity.StockReceipt, createdBy string) (entity.StockMovement, error) {
+	var movement entity.StockMovement
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := uc.checkStockItem(ctx, r.WarehouseID, r.ProductID, r.VariantID)
+		if err != nil {
+			return err
+		}
+
+		movement, err = uc.product.CreateStockMovement(ctx, entity.StockMovement{
+			WarehouseID: r.WarehouseID,
+			ProductID:   r.ProductID,
+			VariantID:   r.VariantID,
+			Type:        entity.MovementReceipt,
+			Quantity:    r.Quantity,
+			Comment:     r.Comment,
+			CreatedBy:   createdBy,
+		})
+		if err != nil {
+			return fmt.Errorf("s.product.CreateStockMovement: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.StockMovement{}, fmt.Errorf("ProductUseCase - ReceiveStock - s.tx.WithinTransaction: %w", err)
+	}
+
+	uc.stockChanged(ctx, r.ProductID)
+
+	return movement, nil
+}
+
+// TransferStock moves items between warehouses. It returns the movement out
+// of the source warehouse and the one into the destination.
+func (uc *UseCase) TransferStock(ctx context.Context, t entity.StockTransfer, createdBy string) ([]entity.StockMovement, error) {
+	movements := make([]entity.StockMovement, 0, 2)
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := uc.checkStockItem(ctx, t.FromWarehouseID, t.ProductID, t.VariantID)
+		if err != nil {
+			return err
+		}
+
+		err = uc.checkStockItem(ctx, t.ToWarehouseID, t.ProductID, t.VariantID)
+		if err != nil {
+			return err
+		}
+
+		transferID := uuid.NewString()
+
+		for _, leg := range []struct {
+			warehouseID string
+			quantity    int
+		}{
+			{t.FromWarehouseID, -t.Quantity},
+			{t.ToWarehouseID, t.Quantity},
+		} {
+			movement, err := uc.product.CreateStockMovement(ctx, entity.StockMovement{
+				WarehouseID: leg.warehouseID,
+				ProductID:   t.ProductID,
+				VariantID:   t.VariantID,
+				Type:        entity.MovementTransfer,
+				Quantity:    leg.quantity,
+				TransferID:  transferID,
+				Comment:     t.Comment,
+				CreatedBy:   createdBy,
+			})
+			if err != nil {
+				return fmt.Errorf("s.product.CreateStockMovement: %w", err)
+			}
+
+			movements = append(movements, movement)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ProductUseCase - TransferStock - s.tx.WithinTransaction: %w", err)
+	}
+
+	return movements, nil
+}
+
+// AdjustStock corrects the stock in a warehouse to the count found and
+// records the difference. It reports false when the count was right.
+func (uc *UseCase) AdjustStock(ctx context.Context, a entity.StockAdjustment, createdBy string) (entity.StockMovement, bool, error) {
+	var movement entity.StockMovement
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		// The product lock keeps the level from moving between reading and
+		// correcting it.
+		_, err := uc.product.LockProducts(ctx, []string{a.ProductID})
+		if err != nil {
+			return fmt.Errorf("s.product.LockProducts: %w", err)
+		}
+
+		err = uc.checkStockItem(ctx, a.WarehouseID, a.ProductID, a.VariantID)
+		if err != nil {
+			return err
+		}
+
+		levels, err := uc.product.GetStockLevels(ctx, entity.StockLevelFilter{
+			WarehouseID: a.WarehouseID,
+			ProductID:   a.ProductID,
+			VariantID:   a.VariantID,
+		})
+		if err != nil {
+			return fmt.Errorf("s.product.GetStockLevels: %w", err)
+		}
+
+		var current int
+		if level, ok := findLevel(levels, a.VariantID); ok {
+			current = level.Count
+		}
+
+		if a.Count == current {
+			return nil
+		}
+
+		err = uc.checkReserved(ctx, a.ProductID, a.VariantID, a.Count-current)
+		if err != nil {
+			return err
+		}
+
+		movement, err = uc.product.CreateStockMovement(ctx, entity.StockMovement{
+			WarehouseID: a.WarehouseID,
+			ProductID:   a.ProductID,
+			VariantID:   a.VariantID,
+			Type:        entity.MovementAdjustment,
+			Quantity:    a.Count - current,
+			Reason:      a.Reason,
+			Comment:     a.Comment,
+			CreatedBy:   createdBy,
+		})
+		if err != nil {
+			return fmt.Errorf("s.product.CreateStockMovement: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.StockMovement{}, false, fmt.Errorf("ProductUseCase - AdjustStock - s.tx.WithinTransaction: %w", err)
+	}
+
+	if movement.ID == "" {
+		return movement, false, nil
+	}
+
+	uc.stockChanged(ctx, a.ProductID)
+
+	return movement, true, nil
+}
+
+// checkStockItem validates the warehouse, product and variant of a stock
+// request. Stock of products with variants is kept per variant.
+func (uc *UseCase) checkStockItem(ctx context.Context, warehouseID, productID, variantID string) error {
+	_, err := uc.product.GetWarehouse(ctx, warehouseID)
+	if errors.Is(err, entity.ErrNotFound) {
+		return entity.NewValidationError("warehouse_id", "warehouse "+warehouseID+" does not exist")
+	}
+
+	if err != nil {
+		return fmt.Errorf("s.product.GetWarehouse: %w", err)
+	}
+
+	_, err = uc.product.GetProduct(ctx, productID)
+	if errors.Is(err, entity.ErrNotFound) {
+		return entity.NewValidationError("product_id", "product "+productID+" does not exist")
+	}
+
+	if err != nil {
+		return fmt.Errorf("s.product.GetProduct: %w", err)
+	}
+
+	if variantID != "" {
+		_, err = uc.product.GetVariant(ctx, productID, variantID)
+		if errors.Is(err, entity.ErrNotFound) {
+			return entity.NewValidationError("variant_id", "variant "+variantID+" does not belong to the product")
+		}
+
+		if err != nil {
+			return fmt.Errorf("s.product.GetVariant: %w", err)
+		}
+
+		return nil
+	}
+
+	variants, err := uc.product.GetVariantsByProduct(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("s.product.GetVariantsByProduct: %w", err)
+	}
+
+	if len(variants) > 0 {
+		return entity.NewValidationError("variant_id", "is required for products with variants")
+	}
+
+	return nil
+}
+
+// setStock records the change of the stock of a product or variant from
+// one count to another, as edited on the product or imported, as
+// adjustments. Items are added to the default warehouse and taken off like
+// takeStock does; the items reserved by orders cannot be taken off.
+func (uc *UseCase) setStock(ctx context.Context, productID, variantID string, from, to int, reason string) error {
+	if from == to {
+		return nil
+	}
+
+	if variantID == "" {
+		variants, err := uc.product.GetVariantsByProduct(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("s.product.GetVariantsByProduct: %w", err)
+		}
+
+		if len(variants) > 0 {
+			return entity.NewValidationError("count", "of a product with variants is the sum of its variants")
+		}
+	}
+
+	movement := entity.StockMovement{ProductID: productID, VariantID: variantID, Type: entity.MovementAdjustment, Reason: reason}
+
+	if to < from {
+		err := uc.checkReserved(ctx, productID, variantID, to-from)
+		if err != nil {
+			return err
+		}
+
+		left, err := uc.takeFromWarehouses(ctx, movement, from-to)
+		if err != nil {
+			return err
+		}
+
+		if left > 0 {
+			return fmt.Errorf("product %s lacks %d: %w", productID, left, entity.ErrInsufficientStock)
+		}
+
+		return nil
+	}
+
+	warehouse, err := uc.product.GetDefaultWarehouse(ctx)
+	if err != nil {
+		return fmt.Errorf("s.product.GetDefaultWarehouse: %w", err)
+	}
+
+	movement.WarehouseID = warehouse.ID
+	movement.Quantity = to - from
+
+	_, err = uc.product.CreateStockMovement(ctx, movement)
+	if err != nil {
+		return fmt.Errorf("s.product.CreateStockMovement: %w", err)
+	}
+
+	return nil
+}
+
+// checkReserved rejects changing the stock of a product or variant by
+// quantity when that leaves fewer items than orders have reserved.
+func (uc *UseCase) checkReserved(ctx context.Context, productID, variantID string, quantity int) error {
+	if quantity >= 0 {
+		return nil
+	}
+
+	var count, available int
+
+	if variantID != "" {
+		v, err := uc.product.GetVariant(ctx, productID, variantID)
+		if err != nil {
+			return fmt.Errorf("s.product.GetVariant: %w", err)
+		}
+
+		count, available = v.Count, v.Available
+	} else {
+		p, err := uc.product.GetProduct(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("s.product.GetProduct: %w", err)
+		}
+
+		count, available = p.Count, p.Available
+	}
+
+	if available+quantity < 0 {
+		return entity.NewValidationError("count", fmt.Sprintf("must not be below the %d items reserved by orders", count-available))
+	}
+
+	return nil
+}
+
+// takeStock takes the items of an order line off the stock of its variant,
+// or of its product when it has none: from the default warehouse first and
+// then from the fullest ones.
+func (uc *UseCase) takeStock(ctx context.Context, line entity.OrderProducts) error {
+	left, err := uc.takeFromWarehouses(ctx, entity.StockMovement{
+		ProductID: line.ProductID,
+		VariantID: line.VariantID,
+		Type:      entity.MovementSale,
+		OrderID:   line.OrderID,
+	}, line.Count)
+	if err != nil {
+		return err
+	}
+
+	if left > 0 {
+		return fmt.Errorf("product %s lacks %d: %w", line.ProductID, left, entity.ErrInsufficientStock)
+	}
+
+	return nil
+}
+
+// takeFromWarehouses takes count items of the product or variant of m off
+// the stock, from the default warehouse first and then from the fullest
+// ones, recording a movement like m for each. It returns how many items the
+// warehouses lacked.
+func (uc *UseCase) takeFromWarehouses(ctx context.Context, m entity.StockMovement, count int) (int, error) {
+	levels, err := uc.product.GetStockLevels(ctx, entity.StockLevelFilter{ProductID: m.ProductID, VariantID: m.VariantID})
+	if err != nil {
+		return 0, fmt.Errorf("s.product.GetStockLevels: %w", err)
+	}
+
+	left := count
+
+	for _, level := range levels {
+		if left == 0 {
+			break
+		}
+
+		if level.VariantID != m.VariantID || level.Count <= 0 {
+			continue
+		}
+
+		n := min(left, level.Count)
+
+		m.WarehouseID, m.Quantity = level.WarehouseID, -n
+
+		_, err = uc.product.CreateStockMovement(ctx, m)
+		if err != nil {
+			return 0, fmt.Errorf("s.product.CreateStockMovement: %w", err)
+		}
+
+		left -= n
+	}
+
+	return left, nil
+}
+
+// returnStock puts the items of an order line back into the warehouses
+// takeStock took them from. Items of orders placed before the ledger go to
+// the default warehouse.
+func (uc *UseCase) returnStock(ctx context.Context, line entity.OrderProducts) error {
+	movements, err := uc.product.GetStockMovementsByOrder(ctx, line.OrderID)
+	if err != nil {
+		return fmt.Errorf("s.product.GetStockMovementsByOrder: %w", err)
+	}
+
+	// What is still out per warehouse: sold less already returned.
+	out := make(map[string]int)
+	warehouses := make([]string, 0, len(movements))
+
+	for _, m := range movements {
+		if m.ProductID != line.ProductID || m.VariantID != line.VariantID {
+			continue
+		}
+
+		if m.Type != entity.MovementSale && m.Type != entity.MovementReturn {
+			continue
+		}
+
+		if _, ok := out[m.WarehouseID]; !ok {
+			warehouses = append(warehouses, m.WarehouseID)
+		}
+
+		out[m.WarehouseID] -= m.Quantity
+	}
+
+	left := line.Count
+
+	for _, warehouseID := range warehouses {
+		n := min(left, out[warehouseID])
+		if n <= 0 {
+			continue
+		}
+
+		err = uc.putBack(ctx, line, warehouseID, n)
+		if err != nil {
+			return err
+		}
+
+		left -= n
+	}
+
+	if left == 0 {
+		return nil
+	}
+
+	warehouse, err := uc.product.GetDefaultWarehouse(ctx)
+	if err != nil {
+		return fmt.Errorf("s.product.GetDefaultWarehouse: %w", err)
+	}
+
+	return uc.putBack(ctx, line, warehouse.ID, left)
+}
+
+func (uc *UseCase) putBack(ctx context.Context, line entity.OrderProducts, warehouseID string, n int) error {
+	_, err := uc.product.CreateStockMovement(ctx, entity.StockMovement{
+		WarehouseID: warehouseID,
+		ProductID:   line.ProductID,
+		VariantID:   line.VariantID,
+		Type:        entity.MovementReturn,
+		Quantity:    n,
+		OrderID:     line.OrderID,
+	})
+	if err != nil {
+		return fmt.Errorf("s.product.CreateStockMovement: %w", err)
+	}
+
+	return nil
+}
+
+func findLevel(levels []entity.StockLevel, variantID string) (entity.StockLevel, bool) {
+	for _, l := range levels {
+		if l.VariantID == variantID {
+			return l, true
+		}
+	}
+
+	return entity.StockLevel{}, false
+}
@@ -0,0 +1,194 @@
+package product
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/internal/entity"
+)
+
+// stockRepo holds a product with 10 items, 3 of them reserved, kept in the
+// default warehouse (4), a small one (2) and a big one (4), and a variant of
+// another product with 5 items in the big warehouse, 3 of them reserved.
+func stockRepo() *fakeRepo {
+	r := newFakeRepo()
+	r.warehouses = []entity.Warehouse{{ID: "main", Default: true}, {ID: "small"}, {ID: "big"}}
+	r.addProduct(entity.Product{ID: "plain", Count: 10, Available: 7})
+	r.addProduct(entity.Product{ID: "shirt", Count: 5, Available: 2})
+	r.addVariant(entity.ProductVariant{ID: "shirt-m", ProductID: "shirt", Count: 5, Available: 2})
+	r.levels = []entity.StockLevel{
+		{WarehouseID: "small", ProductID: "plain", Count: 2},
+		{WarehouseID: "big", ProductID: "plain", Count: 4},
+		{WarehouseID: "main", ProductID: "plain", Count: 4},
+		{WarehouseID: "big", ProductID: "shirt", VariantID: "shirt-m", Count: 5},
+	}
+
+	return r
+}
+
+func levelsOf(r *fakeRepo, productID string) map[string]int {
+	levels := make(map[string]int)
+
+	for _, l := range r.levels {
+		if l.ProductID == productID {
+			levels[l.WarehouseID] = l.Count
+		}
+	}
+
+	return levels
+}
+
+func TestSetStock(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		productID string
+		variantID string
+		from, to  int
+		levels    map[string]int
+		field     string
+	}{
+		{
+			name:      "unchanged",
+			productID: "plain", from: 10, to: 10,
+			levels: map[string]int{"main": 4, "small": 2, "big": 4},
+		},
+		{
+			name:      "increase goes to the default warehouse",
+			productID: "plain", from: 10, to: 12,
+			levels: map[string]int{"main": 6, "small": 2, "big": 4},
+		},
+		{
+			name:      "decrease from the default warehouse first",
+			productID: "plain", from: 10, to: 8,
+			levels: map[string]int{"main": 2, "small": 2, "big": 4},
+		},
+		{
+			name:      "decrease spread over the fullest warehouses",
+			productID: "plain", from: 10, to: 3,
+			levels: map[string]int{"main": 0, "small": 2, "big": 1},
+		},
+		{
+			name:      "decrease into the reserved items",
+			productID: "plain", from: 10, to: 2,
+			field: "count",
+		},
+		{
+			name:      "variant decrease",
+			productID: "shirt", variantID: "shirt-m", from: 5, to: 3,
+			levels: map[string]int{"big": 3},
+		},
+		{
+			name:      "variant decrease into the reserved items",
+			productID: "shirt", variantID: "shirt-m", from: 5, to: 2,
+			field: "count",
+		},
+		{
+			name:      "product with variants",
+			productID: "shirt", from: 5, to: 4,
+			field: "count",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := stockRepo()
+			before := levelsOf(r, tc.productID)
+
+			err := newTestUseCase(t, r).setStock(context.Background(), tc.productID, tc.variantID, tc.from, tc.to, entity.ReasonCorrection)
+			if tc.field != "" {
+				var v *entity.ValidationError
+				require.ErrorAs(t, err, &v)
+				require.Equal(t, tc.field, v.Field)
+				require.Equal(t, before, levelsOf(r, tc.productID))
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.levels, levelsOf(r, tc.productID))
+
+			for _, m := range r.movements {
+				require.Equal(t, entity.MovementAdjustment, m.Type)
+				require.Equal(t, entity.ReasonCorrection, m.Reason)
+			}
+		})
+	}
+}
+
+func TestAdjustStock(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		adjustment entity.StockAdjustment
+		adjusted   bool
+		quantity   int
+		field      string
+	}{
+		{
+			name:       "count found is right",
+			adjustment: entity.StockAdjustment{WarehouseID: "big", ProductID: "plain", Count: 4},
+		},
+		{
+			name:       "more found",
+			adjustment: entity.StockAdjustment{WarehouseID: "small", ProductID: "plain", Count: 5},
+			adjusted:   true,
+			quantity:   3,
+		},
+		{
+			name:       "fewer found",
+			adjustment: entity.StockAdjustment{WarehouseID: "big", ProductID: "plain", Count: 1},
+			adjusted:   true,
+			quantity:   -3,
+		},
+		{
+			name:       "none found, other warehouses hold the reserved items",
+			adjustment: entity.StockAdjustment{WarehouseID: "big", ProductID: "plain", Count: 0},
+			adjusted:   true,
+			quantity:   -4,
+		},
+		{
+			name:       "variant fewer found than reserved",
+			adjustment: entity.StockAdjustment{WarehouseID: "big", ProductID: "shirt", VariantID: "shirt-m", Count: 2},
+			field:      "count",
+		},
+		{
+			name:       "variant required",
+			adjustment: entity.StockAdjustment{WarehouseID: "big", ProductID: "shirt", Count: 2},
+			field:      "variant_id",
+		},
+		{
+			name:       "unknown warehouse",
+			adjustment: entity.StockAdjustment{WarehouseID: "far", ProductID: "plain", Count: 2},
+			field:      "warehouse_id",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := stockRepo()
+
+			movement, adjusted, err := newTestUseCase(t, r).AdjustStock(context.Background(), tc.adjustment, "u1")
+			if tc.field != "" {
+				var v *entity.ValidationError
+				require.ErrorAs(t, err, &v)
+				require.Equal(t, tc.field, v.Field)
+				require.Empty(t, r.movements)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.adjusted, adjusted)
+			require.Equal(t, tc.quantity, movement.Quantity)
+		})
+	}
+}
@@ -0,0 +1,8 @@
+CREATE EXTENSION IF NOT EXISTS "pgcrypto";
+
+-- Existing plain text passwords are wrapped into bcrypt; the application
+-- upgrades them to argon2id on the next successful login. Only values shaped
+-- like a bcrypt or argon2id hash are kept, as a plain text password may start
+-- with "$" too.
+UPDATE "user" SET "password" = crypt("password", gen_salt('bf'))
+WHERE "password" !~ '^\$2[aby]\$[0-9]{2}\$[./A-Za-z0-9]{53}$' AND "password" NOT LIKE '$argon2id$%';
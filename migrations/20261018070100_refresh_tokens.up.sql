@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS "refresh_token" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "user_id" UUID NOT NULL REFERENCES "user"("id") ON DELETE CASCADE,
+    "family_id" UUID NOT NULL,
+    "token_hash" VARCHAR(64) NOT NULL UNIQUE,
+    "expires_at" TIMESTAMP NOT NULL,
+    "revoked_at" TIMESTAMP,
+    "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "refresh_token_family_id_idx" ON "refresh_token"("family_id");
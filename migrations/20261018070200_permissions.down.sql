@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "role_permission";
+DROP TABLE IF EXISTS "permission";
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS "permission" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "name" VARCHAR(255) NOT NULL UNIQUE,
+    "description" VARCHAR(255),
+    "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "role_permission" (
+    "role_id" UUID NOT NULL REFERENCES "role"("id") ON DELETE CASCADE,
+    "permission_id" UUID NOT NULL REFERENCES "permission"("id") ON DELETE CASCADE,
+    "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("role_id", "permission_id")
+);
+
+INSERT INTO "permission" (name, description) VALUES
+  ('product:create', 'Create products'),
+  ('product:update', 'Update products'),
+  ('product:delete', 'Delete products'),
+  ('role:manage',    'Manage role permissions')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO "role_permission" (role_id, permission_id)
+SELECT r.id, p.id FROM "role" r CROSS JOIN "permission" p
+WHERE r.name = 'Admin'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "role_permission" (role_id, permission_id)
+SELECT r.id, p.id FROM "role" r CROSS JOIN "permission" p
+WHERE r.name = 'Manager' AND p.name IN ('product:create', 'product:update', 'product:delete')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,9 @@
+DELETE FROM "permission" WHERE name = 'order:read';
+
+DROP INDEX IF EXISTS "order_integration_id_idx";
+DROP INDEX IF EXISTS "order_user_id_idx";
+DROP INDEX IF EXISTS "attribute_category_id_idx";
+DROP INDEX IF EXISTS "product_category_id_idx";
+
+ALTER TABLE "order_products" DROP COLUMN IF EXISTS "cost";
+ALTER TABLE "order" DROP COLUMN IF EXISTS "total_cost";
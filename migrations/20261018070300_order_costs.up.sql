@@ -0,0 +1,16 @@
+ALTER TABLE "order" ADD COLUMN IF NOT EXISTS "total_cost" INT NOT NULL DEFAULT 0;
+ALTER TABLE "order_products" ADD COLUMN IF NOT EXISTS "cost" INT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS "product_category_id_idx" ON "product"("category_id");
+CREATE INDEX IF NOT EXISTS "attribute_category_id_idx" ON "attribute"("category_id");
+CREATE INDEX IF NOT EXISTS "order_user_id_idx" ON "order"("user_id");
+CREATE INDEX IF NOT EXISTS "order_integration_id_idx" ON "order"("integration_id");
+
+INSERT INTO "permission" (name, description) VALUES
+  ('order:read', 'List and view orders')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO "role_permission" (role_id, permission_id)
+SELECT r.id, p.id FROM "role" r CROSS JOIN "permission" p
+WHERE r.name IN ('Admin', 'Manager') AND p.name = 'order:read'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+ALTER TABLE "product" DROP CONSTRAINT IF EXISTS "product_count_non_negative";
+
+DELETE FROM "permission" WHERE name = 'order:create';
@@ -0,0 +1,10 @@
+INSERT INTO "permission" (name, description) VALUES
+  ('order:create', 'Place orders')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO "role_permission" (role_id, permission_id)
+SELECT r.id, p.id FROM "role" r CROSS JOIN "permission" p
+WHERE r.name IN ('Admin', 'Manager', 'Sales') AND p.name = 'order:create'
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE "product" ADD CONSTRAINT "product_count_non_negative" CHECK ("count" >= 0);
@@ -0,0 +1,7 @@
+DELETE FROM "permission" WHERE name = 'order:update';
+
+ALTER TABLE "order" DROP CONSTRAINT IF EXISTS "order_status_check";
+ALTER TABLE "order" ALTER COLUMN "status" DROP NOT NULL;
+ALTER TABLE "order" ALTER COLUMN "status" DROP DEFAULT;
+
+DROP TABLE IF EXISTS "order_status_history";
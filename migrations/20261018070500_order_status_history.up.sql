@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS "order_status_history" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "order_id" UUID NOT NULL REFERENCES "order"("id") ON DELETE CASCADE,
+    "from_status" VARCHAR(255),
+    "to_status" VARCHAR(255) NOT NULL,
+    "changed_by" UUID REFERENCES "user"("id") ON DELETE SET NULL,
+    "comment" TEXT,
+    "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "order_status_history_order_id_idx" ON "order_status_history"("order_id", "created_at");
+
+UPDATE "order" SET "status" = CASE lower("status")
+    WHEN 'confirmed' THEN 'confirmed'
+    WHEN 'processing' THEN 'confirmed'
+    WHEN 'paid' THEN 'paid'
+    WHEN 'shipped' THEN 'shipped'
+    WHEN 'delivered' THEN 'delivered'
+    WHEN 'completed' THEN 'delivered'
+    WHEN 'cancelled' THEN 'cancelled'
+    WHEN 'canceled' THEN 'cancelled'
+    WHEN 'returned' THEN 'returned'
+    ELSE 'new'
+END;
+
+INSERT INTO "order_status_history" (order_id, from_status, to_status, created_at)
+SELECT id, NULL, status, COALESCE(status_changed_time, created_at, CURRENT_TIMESTAMP) FROM "order";
+
+ALTER TABLE "order" ALTER COLUMN "status" SET DEFAULT 'new';
+ALTER TABLE "order" ALTER COLUMN "status" SET NOT NULL;
+ALTER TABLE "order" ADD CONSTRAINT "order_status_check"
+    CHECK ("status" IN ('new', 'confirmed', 'paid', 'shipped', 'delivered', 'cancelled', 'returned'));
+
+INSERT INTO "permission" (name, description) VALUES
+  ('order:update', 'Change order status')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO "role_permission" (role_id, permission_id)
+SELECT r.id, p.id FROM "role" r CROSS JOIN "permission" p
+WHERE r.name IN ('Admin', 'Manager') AND p.name = 'order:update'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,28 @@
+ALTER TABLE "user" ALTER COLUMN "birth_date" TYPE VARCHAR USING to_char("birth_date", 'YYYY-MM-DD');
+ALTER TABLE "user" RENAME COLUMN "birth_date" TO "bith_date";
+
+ALTER TABLE "refresh_token"
+    ALTER COLUMN "expires_at" TYPE TIMESTAMP USING "expires_at" AT TIME ZONE 'UTC',
+    ALTER COLUMN "revoked_at" TYPE TIMESTAMP USING "revoked_at" AT TIME ZONE 'UTC',
+    ALTER COLUMN "created_at" TYPE TIMESTAMP USING "created_at" AT TIME ZONE 'UTC';
+ALTER TABLE "role_permission" ALTER COLUMN "created_at" TYPE TIMESTAMP USING "created_at" AT TIME ZONE 'UTC';
+ALTER TABLE "order_status_history" ALTER COLUMN "created_at" TYPE TIMESTAMP USING "created_at" AT TIME ZONE 'UTC';
+
+ALTER TABLE "order" ALTER COLUMN "status_changed_time" DROP NOT NULL;
+ALTER TABLE "order" ALTER COLUMN "status_changed_time" DROP DEFAULT;
+ALTER TABLE "order" ALTER COLUMN "status_changed_time" TYPE TIMESTAMP USING "status_changed_time" AT TIME ZONE 'UTC';
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['client_type', 'role', 'user', 'category', 'attribute', 'product', 'integration', 'order', 'order_products', 'permission']
+    LOOP
+        EXECUTE format('DROP TRIGGER IF EXISTS set_updated_at ON %I', t);
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN created_at DROP NOT NULL, ALTER COLUMN updated_at DROP NOT NULL', t);
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE ''UTC''', t);
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE ''UTC''', t);
+    END LOOP;
+END $$;
+
+DROP FUNCTION IF EXISTS set_updated_at();
@@ -0,0 +1,40 @@
+CREATE OR REPLACE FUNCTION set_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['client_type', 'role', 'user', 'category', 'attribute', 'product', 'integration', 'order', 'order_products', 'permission']
+    LOOP
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE ''UTC''', t);
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE ''UTC''', t);
+        EXECUTE format('UPDATE %I SET created_at = COALESCE(created_at, CURRENT_TIMESTAMP), updated_at = COALESCE(updated_at, created_at, CURRENT_TIMESTAMP) WHERE created_at IS NULL OR updated_at IS NULL', t);
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN created_at SET NOT NULL, ALTER COLUMN updated_at SET NOT NULL', t);
+        EXECUTE format('DROP TRIGGER IF EXISTS set_updated_at ON %I', t);
+        EXECUTE format('CREATE TRIGGER set_updated_at BEFORE UPDATE ON %I FOR EACH ROW EXECUTE FUNCTION set_updated_at()', t);
+    END LOOP;
+END $$;
+
+ALTER TABLE "order" ALTER COLUMN "status_changed_time" TYPE TIMESTAMPTZ USING "status_changed_time" AT TIME ZONE 'UTC';
+UPDATE "order" SET "status_changed_time" = "created_at" WHERE "status_changed_time" IS NULL;
+ALTER TABLE "order" ALTER COLUMN "status_changed_time" SET DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE "order" ALTER COLUMN "status_changed_time" SET NOT NULL;
+
+ALTER TABLE "order_status_history" ALTER COLUMN "created_at" TYPE TIMESTAMPTZ USING "created_at" AT TIME ZONE 'UTC';
+ALTER TABLE "role_permission" ALTER COLUMN "created_at" TYPE TIMESTAMPTZ USING "created_at" AT TIME ZONE 'UTC';
+ALTER TABLE "refresh_token"
+    ALTER COLUMN "expires_at" TYPE TIMESTAMPTZ USING "expires_at" AT TIME ZONE 'UTC',
+    ALTER COLUMN "revoked_at" TYPE TIMESTAMPTZ USING "revoked_at" AT TIME ZONE 'UTC',
+    ALTER COLUMN "created_at" TYPE TIMESTAMPTZ USING "created_at" AT TIME ZONE 'UTC';
+
+-- The column was created as "bith_date" while the code has always used
+-- "birth_date"; values that are not a valid ISO date are dropped.
+ALTER TABLE "user" RENAME COLUMN "bith_date" TO "birth_date";
+ALTER TABLE "user" ALTER COLUMN "birth_date" TYPE DATE USING (
+    CASE WHEN "birth_date" ~ '^\d{4}-\d{2}-\d{2}$' THEN "birth_date"::DATE END
+);
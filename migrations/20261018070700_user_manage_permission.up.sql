@@ -0,0 +1,8 @@
+INSERT INTO "permission" (name, description) VALUES
+  ('user:manage', 'Read and edit users')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO "role_permission" (role_id, permission_id)
+SELECT r.id, p.id FROM "role" r CROSS JOIN "permission" p
+WHERE r.name = 'Admin' AND p.name = 'user:manage'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,12 @@
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['product', 'category', 'order', 'user']
+    LOOP
+        EXECUTE format('DROP TRIGGER IF EXISTS bump_version ON %I', t);
+        EXECUTE format('ALTER TABLE %I DROP COLUMN IF EXISTS version', t);
+    END LOOP;
+END $$;
+
+DROP FUNCTION IF EXISTS bump_version();
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS "product_attribute_value";
+
+ALTER TABLE "attribute" DROP CONSTRAINT IF EXISTS "attribute_type_check";
+ALTER TABLE "attribute"
+    DROP COLUMN IF EXISTS "required",
+    DROP COLUMN IF EXISTS "options",
+    DROP COLUMN IF EXISTS "unit",
+    DROP COLUMN IF EXISTS "type";
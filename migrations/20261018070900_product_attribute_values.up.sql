@@ -0,0 +1,25 @@
+ALTER TABLE "attribute"
+    ADD COLUMN IF NOT EXISTS "type" VARCHAR(32) NOT NULL DEFAULT 'string',
+    ADD COLUMN IF NOT EXISTS "unit" VARCHAR(32),
+    ADD COLUMN IF NOT EXISTS "options" TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS "required" BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE "attribute" ADD CONSTRAINT "attribute_type_check"
+    CHECK ("type" IN ('string', 'number', 'enum', 'boolean', 'unit'));
+
+CREATE TABLE IF NOT EXISTS "product_attribute_value" (
+    "product_id" UUID NOT NULL REFERENCES "product"("id") ON DELETE CASCADE,
+    "attribute_id" UUID NOT NULL REFERENCES "attribute"("id") ON DELETE CASCADE,
+    "value_string" TEXT,
+    "value_number" NUMERIC,
+    "value_boolean" BOOLEAN,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("product_id", "attribute_id"),
+    CONSTRAINT "product_attribute_value_one_value" CHECK (num_nonnulls("value_string", "value_number", "value_boolean") = 1)
+);
+
+CREATE INDEX IF NOT EXISTS "product_attribute_value_string_idx" ON "product_attribute_value"("attribute_id", "value_string");
+CREATE INDEX IF NOT EXISTS "product_attribute_value_number_idx" ON "product_attribute_value"("attribute_id", "value_number");
+
+CREATE TRIGGER set_updated_at BEFORE UPDATE ON "product_attribute_value" FOR EACH ROW EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,41 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Uzbek has no text search configuration, so every field is also indexed
+-- unstemmed with 'simple'; Russian and English add stemmed lexemes.
+ALTER TABLE "product" ADD COLUMN IF NOT EXISTS "search_vector" TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', COALESCE("name", '')), 'A') ||
+    setweight(to_tsvector('russian', COALESCE("name", '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE("name", '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE("short_info", '')), 'B') ||
+    setweight(to_tsvector('russian', COALESCE("short_info", '')), 'B') ||
+    setweight(to_tsvector('english', COALESCE("short_info", '')), 'B') ||
+    setweight(to_tsvector('simple', COALESCE("description", '')), 'C') ||
+    setweight(to_tsvector('russian', COALESCE("description", '')), 'C') ||
+    setweight(to_tsvector('english', COALESCE("description", '')), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS "product_search_vector_idx" ON "product" USING GIN ("search_vector");
+CREATE INDEX IF NOT EXISTS "product_name_trgm_idx" ON "product" USING GIN ("name" gin_trgm_ops);
+
+CREATE TABLE IF NOT EXISTS "search_synonym" (
+    "term" VARCHAR(64) NOT NULL,
+    "synonym" VARCHAR(64) NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("term", "synonym"),
+    CONSTRAINT "search_synonym_lower" CHECK ("term" = lower("term") AND "synonym" = lower("synonym")),
+    CONSTRAINT "search_synonym_distinct" CHECK ("term" <> "synonym")
+);
+
+CREATE INDEX IF NOT EXISTS "search_synonym_synonym_idx" ON "search_synonym"("synonym");
+
+INSERT INTO "search_synonym" ("term", "synonym") VALUES
+  ('phone', 'telefon'),
+  ('phone', 'телефон'),
+  ('telefon', 'телефон'),
+  ('laptop', 'noutbuk'),
+  ('laptop', 'ноутбук'),
+  ('noutbuk', 'ноутбук'),
+  ('shoes', 'poyabzal'),
+  ('shoes', 'обувь'),
+  ('poyabzal', 'обувь')
+ON CONFLICT DO NOTHING;
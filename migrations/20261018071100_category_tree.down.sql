@@ -0,0 +1,13 @@
+DROP TRIGGER IF EXISTS category_prevent_cycle ON "category";
+DROP FUNCTION IF EXISTS category_prevent_cycle();
+
+DROP INDEX IF EXISTS "category_parent_position_idx";
+DROP INDEX IF EXISTS "category_parent_name_key";
+DROP INDEX IF EXISTS "category_root_name_key";
+
+ALTER TABLE "category" DROP CONSTRAINT IF EXISTS "category_parent_not_self";
+ALTER TABLE "category"
+    DROP COLUMN IF EXISTS "position",
+    DROP COLUMN IF EXISTS "parent_id";
+
+ALTER TABLE "category" ADD CONSTRAINT "category_name_key" UNIQUE ("name");
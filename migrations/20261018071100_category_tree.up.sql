@@ -0,0 +1,38 @@
+ALTER TABLE "category"
+    ADD COLUMN IF NOT EXISTS "parent_id" UUID REFERENCES "category"("id") ON DELETE RESTRICT,
+    ADD COLUMN IF NOT EXISTS "position" INT NOT NULL DEFAULT 0;
+
+ALTER TABLE "category" ADD CONSTRAINT "category_parent_not_self" CHECK ("parent_id" <> "id");
+
+-- Names are unique among siblings only.
+ALTER TABLE "category" DROP CONSTRAINT IF EXISTS "category_name_key";
+CREATE UNIQUE INDEX IF NOT EXISTS "category_root_name_key" ON "category"("name") WHERE "parent_id" IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS "category_parent_name_key" ON "category"("parent_id", "name") WHERE "parent_id" IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS "category_parent_position_idx" ON "category"("parent_id", "position");
+
+-- Existing categories become roots ordered by name.
+UPDATE "category" c SET "position" = o.rn - 1
+FROM (SELECT "id", row_number() OVER (ORDER BY "name") AS rn FROM "category") o
+WHERE c."id" = o."id";
+
+CREATE OR REPLACE FUNCTION category_prevent_cycle() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.parent_id IS NOT NULL AND EXISTS (
+        WITH RECURSIVE ancestors AS (
+            SELECT id, parent_id FROM category WHERE id = NEW.parent_id
+            UNION
+            SELECT c.id, c.parent_id FROM category c JOIN ancestors a ON c.id = a.parent_id
+        )
+        SELECT 1 FROM ancestors WHERE id = NEW.id
+    ) THEN
+        RAISE EXCEPTION 'category % cannot be moved under its own descendant', NEW.id
+            USING ERRCODE = 'check_violation';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER category_prevent_cycle BEFORE INSERT OR UPDATE OF "parent_id" ON "category"
+    FOR EACH ROW EXECUTE FUNCTION category_prevent_cycle();
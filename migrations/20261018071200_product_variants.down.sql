@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS "order_products_variant_idx";
+ALTER TABLE "order_products" DROP COLUMN IF EXISTS "variant_id";
+
+DROP TRIGGER IF EXISTS product_variant_totals ON "product_variant";
+DROP FUNCTION IF EXISTS product_variant_totals();
+
+DROP TABLE IF EXISTS "product_variant_option";
+DROP TABLE IF EXISTS "product_variant";
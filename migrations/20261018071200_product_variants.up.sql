@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS "product_variant" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "product_id" UUID NOT NULL REFERENCES "product"("id") ON DELETE CASCADE,
+    "sku" VARCHAR(64) NOT NULL UNIQUE,
+    "barcode" VARCHAR(64) UNIQUE,
+    "cost" INT NOT NULL CHECK ("cost" >= 0),
+    "discount_cost" INT,
+    "discount" INT,
+    "count" INT NOT NULL DEFAULT 0 CHECK ("count" >= 0),
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "version" INT NOT NULL DEFAULT 1
+);
+
+CREATE INDEX IF NOT EXISTS "product_variant_product_idx" ON "product_variant"("product_id");
+
+CREATE TRIGGER set_updated_at BEFORE UPDATE ON "product_variant" FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+CREATE TRIGGER bump_version BEFORE UPDATE ON "product_variant" FOR EACH ROW EXECUTE FUNCTION bump_version();
+
+-- Option values tell the variants of a product apart, e.g. size and color.
+CREATE TABLE IF NOT EXISTS "product_variant_option" (
+    "variant_id" UUID NOT NULL REFERENCES "product_variant"("id") ON DELETE CASCADE,
+    "attribute_id" UUID NOT NULL REFERENCES "attribute"("id") ON DELETE CASCADE,
+    "value" TEXT NOT NULL,
+    PRIMARY KEY ("variant_id", "attribute_id")
+);
+
+-- A product with variants shows their total stock and lowest price.
+CREATE OR REPLACE FUNCTION product_variant_totals() RETURNS TRIGGER AS $$
+DECLARE
+    pid UUID := COALESCE(NEW.product_id, OLD.product_id);
+BEGIN
+    UPDATE product p SET count = v.count, cost = v.cost
+    FROM (SELECT SUM(count)::INT AS count, MIN(cost) AS cost FROM product_variant WHERE product_id = pid) v
+    WHERE p.id = pid AND v.cost IS NOT NULL AND (p.count, p.cost) IS DISTINCT FROM (v.count, v.cost);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER product_variant_totals AFTER INSERT OR DELETE OR UPDATE OF "count", "cost" ON "product_variant"
+    FOR EACH ROW EXECUTE FUNCTION product_variant_totals();
+
+ALTER TABLE "order_products" ADD COLUMN IF NOT EXISTS "variant_id" UUID REFERENCES "product_variant"("id");
+CREATE INDEX IF NOT EXISTS "order_products_variant_idx" ON "order_products"("variant_id");
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "product_media_thumbnail";
+DROP TABLE IF EXISTS "product_media";
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS "product_media" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "product_id" UUID NOT NULL REFERENCES "product"("id") ON DELETE CASCADE,
+    "key" TEXT NOT NULL UNIQUE,
+    "content_type" VARCHAR(64) NOT NULL,
+    "size" BIGINT NOT NULL CHECK ("size" > 0),
+    "width" INT NOT NULL CHECK ("width" > 0),
+    "height" INT NOT NULL CHECK ("height" > 0),
+    "position" INT NOT NULL DEFAULT 0,
+    "is_primary" BOOLEAN NOT NULL DEFAULT FALSE,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "product_media_product_idx" ON "product_media"("product_id", "position");
+
+-- A product has at most one primary image.
+CREATE UNIQUE INDEX IF NOT EXISTS "product_media_primary_key" ON "product_media"("product_id") WHERE "is_primary";
+
+-- Downscaled copies of an image, keyed by the length of their longer side.
+CREATE TABLE IF NOT EXISTS "product_media_thumbnail" (
+    "media_id" UUID NOT NULL REFERENCES "product_media"("id") ON DELETE CASCADE,
+    "size" INT NOT NULL CHECK ("size" > 0),
+    "key" TEXT NOT NULL UNIQUE,
+    "width" INT NOT NULL,
+    "height" INT NOT NULL,
+    PRIMARY KEY ("media_id", "size")
+);
@@ -0,0 +1,5 @@
+DELETE FROM "permission" WHERE name = 'product:import';
+
+DROP TABLE IF EXISTS "import_job";
+
+ALTER TABLE "product" DROP COLUMN IF EXISTS "sku";
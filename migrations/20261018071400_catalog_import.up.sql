@@ -0,0 +1,32 @@
+-- Stock keeping unit of products sold without variants, used to match rows
+-- of imported price lists.
+ALTER TABLE "product" ADD COLUMN IF NOT EXISTS "sku" VARCHAR(64) UNIQUE;
+
+CREATE TABLE IF NOT EXISTS "import_job" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "status" VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK ("status" IN ('pending', 'running', 'done', 'failed')),
+    "format" VARCHAR(8) NOT NULL,
+    "file_name" VARCHAR(255) NOT NULL DEFAULT '',
+    "total_rows" INT NOT NULL DEFAULT 0,
+    "processed_rows" INT NOT NULL DEFAULT 0,
+    "created_rows" INT NOT NULL DEFAULT 0,
+    "updated_rows" INT NOT NULL DEFAULT 0,
+    "failed_rows" INT NOT NULL DEFAULT 0,
+    "errors" JSONB NOT NULL DEFAULT '[]',
+    "error" TEXT NOT NULL DEFAULT '',
+    "created_by" UUID REFERENCES "user"("id") ON DELETE SET NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "finished_at" TIMESTAMPTZ
+);
+
+CREATE TRIGGER set_updated_at BEFORE UPDATE ON "import_job" FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+INSERT INTO "permission" (name, description) VALUES
+  ('product:import', 'Import and export the catalog')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO "role_permission" (role_id, permission_id)
+SELECT r.id, p.id FROM "role" r CROSS JOIN "permission" p
+WHERE r.name IN ('Admin', 'Manager') AND p.name = 'product:import'
+ON CONFLICT DO NOTHING;
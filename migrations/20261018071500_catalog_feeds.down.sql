@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS set_updated_at ON "product_media";
+ALTER TABLE "product_media" DROP COLUMN IF EXISTS "updated_at";
+
+DROP TABLE IF EXISTS "category_feed_mapping";
@@ -0,0 +1,17 @@
+-- Marketplace taxonomy categories for our categories. Subcategories without
+-- a mapping of their own use the nearest mapped ancestor's.
+CREATE TABLE IF NOT EXISTS "category_feed_mapping" (
+    "category_id" UUID PRIMARY KEY REFERENCES "category"("id") ON DELETE CASCADE,
+    "google_category" VARCHAR(255) NOT NULL DEFAULT '',
+    "facebook_category" VARCHAR(255) NOT NULL DEFAULT '',
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER set_updated_at BEFORE UPDATE ON "category_feed_mapping" FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- Feeds are regenerated when anything they show changes, including which
+-- image is primary.
+ALTER TABLE "product_media" ADD COLUMN IF NOT EXISTS "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+CREATE TRIGGER set_updated_at BEFORE UPDATE ON "product_media" FOR EACH ROW EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,12 @@
+DELETE FROM "permission" WHERE name = 'promotion:manage';
+
+ALTER TABLE "order"
+    DROP COLUMN IF EXISTS "discount_cost",
+    DROP COLUMN IF EXISTS "subtotal_cost";
+
+ALTER TABLE "order_products" DROP COLUMN IF EXISTS "discount";
+
+DROP TABLE IF EXISTS "order_discount";
+DROP TABLE IF EXISTS "promotion_usage";
+DROP TABLE IF EXISTS "coupon";
+DROP TABLE IF EXISTS "promotion";
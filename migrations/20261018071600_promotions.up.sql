@@ -0,0 +1,93 @@
+-- Promotions discount the items of an order. Empty scope arrays match every
+-- product, category or client type; categories include their descendants.
+CREATE TABLE IF NOT EXISTS "promotion" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "name" VARCHAR(255) NOT NULL,
+    "description" TEXT NOT NULL DEFAULT '',
+    "type" VARCHAR(16) NOT NULL CHECK ("type" IN ('percentage', 'fixed', 'buy_x_get_y')),
+    "value" INT NOT NULL CHECK ("value" > 0),
+    "buy_count" INT NOT NULL DEFAULT 0 CHECK ("buy_count" >= 0),
+    "get_count" INT NOT NULL DEFAULT 0 CHECK ("get_count" >= 0),
+    "product_ids" UUID[] NOT NULL DEFAULT '{}',
+    "category_ids" UUID[] NOT NULL DEFAULT '{}',
+    "client_type_ids" UUID[] NOT NULL DEFAULT '{}',
+    "starts_at" TIMESTAMPTZ,
+    "ends_at" TIMESTAMPTZ,
+    "active" BOOLEAN NOT NULL DEFAULT TRUE,
+    "stackable" BOOLEAN NOT NULL DEFAULT FALSE,
+    "priority" INT NOT NULL DEFAULT 0,
+    "requires_coupon" BOOLEAN NOT NULL DEFAULT FALSE,
+    "usage_limit" INT CHECK ("usage_limit" > 0),
+    "usage_limit_per_user" INT CHECK ("usage_limit_per_user" > 0),
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "version" INT NOT NULL DEFAULT 1,
+    CHECK ("ends_at" IS NULL OR "starts_at" IS NULL OR "ends_at" > "starts_at"),
+    CHECK ("type" <> 'percentage' OR "value" <= 100),
+    CHECK ("type" <> 'buy_x_get_y' OR ("value" <= 100 AND "buy_count" > 0 AND "get_count" > 0))
+);
+
+CREATE INDEX IF NOT EXISTS "promotion_active_idx" ON "promotion"("starts_at", "ends_at") WHERE "active";
+
+CREATE TRIGGER set_updated_at BEFORE UPDATE ON "promotion" FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+CREATE TRIGGER bump_version BEFORE UPDATE ON "promotion" FOR EACH ROW EXECUTE FUNCTION bump_version();
+
+-- Coupon codes are stored upper case and unlock promotions that require one.
+CREATE TABLE IF NOT EXISTS "coupon" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "promotion_id" UUID NOT NULL REFERENCES "promotion"("id") ON DELETE CASCADE,
+    "code" VARCHAR(64) NOT NULL UNIQUE CHECK ("code" = upper("code")),
+    "usage_limit" INT CHECK ("usage_limit" > 0),
+    "usage_limit_per_user" INT CHECK ("usage_limit_per_user" > 0),
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "coupon_promotion_idx" ON "coupon"("promotion_id");
+
+CREATE TRIGGER set_updated_at BEFORE UPDATE ON "coupon" FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- One row per promotion applied to an order, counted against usage limits.
+CREATE TABLE IF NOT EXISTS "promotion_usage" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "promotion_id" UUID NOT NULL REFERENCES "promotion"("id") ON DELETE CASCADE,
+    "coupon_id" UUID REFERENCES "coupon"("id") ON DELETE CASCADE,
+    "order_id" UUID NOT NULL REFERENCES "order"("id") ON DELETE CASCADE,
+    "user_id" UUID REFERENCES "user"("id") ON DELETE SET NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "promotion_usage_promotion_user_idx" ON "promotion_usage"("promotion_id", "user_id");
+CREATE INDEX IF NOT EXISTS "promotion_usage_coupon_user_idx" ON "promotion_usage"("coupon_id", "user_id");
+CREATE INDEX IF NOT EXISTS "promotion_usage_order_idx" ON "promotion_usage"("order_id");
+
+-- The discounts applied to an order, kept to explain its total.
+CREATE TABLE IF NOT EXISTS "order_discount" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "order_id" UUID NOT NULL REFERENCES "order"("id") ON DELETE CASCADE,
+    "promotion_id" UUID REFERENCES "promotion"("id") ON DELETE SET NULL,
+    "coupon_code" VARCHAR(64) NOT NULL DEFAULT '',
+    "name" VARCHAR(255) NOT NULL,
+    "description" TEXT NOT NULL DEFAULT '',
+    "amount" INT NOT NULL CHECK ("amount" > 0),
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "order_discount_order_idx" ON "order_discount"("order_id");
+
+ALTER TABLE "order_products" ADD COLUMN IF NOT EXISTS "discount" INT NOT NULL DEFAULT 0;
+
+ALTER TABLE "order"
+    ADD COLUMN IF NOT EXISTS "subtotal_cost" INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "discount_cost" INT NOT NULL DEFAULT 0;
+
+UPDATE "order" SET "subtotal_cost" = "total_cost";
+
+INSERT INTO "permission" (name, description) VALUES
+  ('promotion:manage', 'Create and edit promotions and coupons')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO "role_permission" (role_id, permission_id)
+SELECT r.id, p.id FROM "role" r CROSS JOIN "permission" p
+WHERE r.name IN ('Admin', 'Manager') AND p.name = 'promotion:manage'
+ON CONFLICT DO NOTHING;
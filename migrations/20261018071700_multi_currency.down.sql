@@ -0,0 +1,33 @@
+DELETE FROM "permission" WHERE name = 'currency:manage';
+
+ALTER TABLE "promotion" ALTER COLUMN "value" TYPE INT USING CASE WHEN "type" = 'fixed' THEN "value" / 100 ELSE "value" END;
+
+ALTER TABLE "order_discount" ALTER COLUMN "amount" TYPE INT USING "amount" / 100;
+
+ALTER TABLE "order_products"
+    ALTER COLUMN "cost" TYPE INT USING "cost" / 100,
+    ALTER COLUMN "discount" TYPE INT USING "discount" / 100;
+
+ALTER TABLE "order"
+    DROP COLUMN IF EXISTS "exchange_rate",
+    DROP COLUMN IF EXISTS "currency",
+    ALTER COLUMN "subtotal_cost" TYPE INT USING "subtotal_cost" / 100,
+    ALTER COLUMN "discount_cost" TYPE INT USING "discount_cost" / 100,
+    ALTER COLUMN "total_cost" TYPE INT USING "total_cost" / 100;
+
+DROP TRIGGER IF EXISTS product_variant_totals ON "product_variant";
+
+ALTER TABLE "product_variant"
+    ALTER COLUMN "cost" TYPE INT USING "cost" / 100,
+    ALTER COLUMN "discount_cost" TYPE INT USING "discount_cost" / 100;
+
+CREATE TRIGGER product_variant_totals AFTER INSERT OR DELETE OR UPDATE OF "count", "cost" ON "product_variant"
+    FOR EACH ROW EXECUTE FUNCTION product_variant_totals();
+
+ALTER TABLE "product"
+    ALTER COLUMN "cost" TYPE INT USING "cost" / 100,
+    ALTER COLUMN "discount_cost" TYPE INT USING "discount_cost" / 100;
+
+DROP TABLE IF EXISTS "product_price";
+DROP TABLE IF EXISTS "exchange_rate";
+DROP TABLE IF EXISTS "currency";
@@ -0,0 +1,94 @@
+-- Amounts become integers in minor units of their currency (tiyin for UZS,
+-- cents for USD). Existing amounts were whole units of the base currency.
+CREATE TABLE IF NOT EXISTS "currency" (
+    "code" CHAR(3) PRIMARY KEY CHECK ("code" ~ '^[A-Z]{3}$'),
+    "name" VARCHAR(64) NOT NULL,
+    "minor_units" SMALLINT NOT NULL DEFAULT 2 CHECK ("minor_units" BETWEEN 0 AND 4),
+    "base" BOOLEAN NOT NULL DEFAULT FALSE,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Exactly one currency is the base: product costs are in it and exchange
+-- rates are quoted against it.
+CREATE UNIQUE INDEX IF NOT EXISTS "currency_base_key" ON "currency"("base") WHERE "base";
+
+INSERT INTO "currency" (code, name, minor_units, base) VALUES
+  ('UZS', 'Uzbekistani som', 2, TRUE),
+  ('USD', 'US dollar', 2, FALSE),
+  ('EUR', 'Euro', 2, FALSE),
+  ('RUB', 'Russian ruble', 2, FALSE),
+  ('KZT', 'Kazakhstani tenge', 2, FALSE)
+ON CONFLICT (code) DO NOTHING;
+
+-- rate is the price of one unit of currency in the base currency, e.g.
+-- 12650.50 for USD. The latest effective rate is the current one.
+CREATE TABLE IF NOT EXISTS "exchange_rate" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "currency" CHAR(3) NOT NULL REFERENCES "currency"("code") ON DELETE CASCADE,
+    "rate" NUMERIC(24, 10) NOT NULL CHECK ("rate" > 0),
+    "source" VARCHAR(16) NOT NULL CHECK ("source" IN ('manual', 'import')),
+    "effective_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "created_by" UUID REFERENCES "user"("id") ON DELETE SET NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "exchange_rate_currency_effective_idx" ON "exchange_rate"("currency", "effective_at" DESC);
+
+-- Prices in other currencies override converting the base cost. A price
+-- without variant_id applies to every variant without its own.
+CREATE TABLE IF NOT EXISTS "product_price" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "product_id" UUID NOT NULL REFERENCES "product"("id") ON DELETE CASCADE,
+    "variant_id" UUID REFERENCES "product_variant"("id") ON DELETE CASCADE,
+    "currency" CHAR(3) NOT NULL REFERENCES "currency"("code") ON DELETE CASCADE,
+    "cost" BIGINT NOT NULL CHECK ("cost" >= 0),
+    "discount_cost" BIGINT CHECK ("discount_cost" >= 0),
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE NULLS NOT DISTINCT ("product_id", "variant_id", "currency")
+);
+
+CREATE INDEX IF NOT EXISTS "product_price_currency_idx" ON "product_price"("currency");
+
+CREATE TRIGGER set_updated_at BEFORE UPDATE ON "product_price" FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+ALTER TABLE "product"
+    ALTER COLUMN "cost" TYPE BIGINT USING "cost" * 100,
+    ALTER COLUMN "discount_cost" TYPE BIGINT USING "discount_cost" * 100;
+
+-- A column a trigger fires on cannot change its type.
+DROP TRIGGER IF EXISTS product_variant_totals ON "product_variant";
+
+ALTER TABLE "product_variant"
+    ALTER COLUMN "cost" TYPE BIGINT USING "cost" * 100,
+    ALTER COLUMN "discount_cost" TYPE BIGINT USING "discount_cost" * 100;
+
+CREATE TRIGGER product_variant_totals AFTER INSERT OR DELETE OR UPDATE OF "count", "cost" ON "product_variant"
+    FOR EACH ROW EXECUTE FUNCTION product_variant_totals();
+
+ALTER TABLE "order"
+    ALTER COLUMN "subtotal_cost" TYPE BIGINT USING "subtotal_cost" * 100,
+    ALTER COLUMN "discount_cost" TYPE BIGINT USING "discount_cost" * 100,
+    ALTER COLUMN "total_cost" TYPE BIGINT USING "total_cost" * 100,
+    ADD COLUMN IF NOT EXISTS "currency" CHAR(3) NOT NULL DEFAULT 'UZS' REFERENCES "currency"("code"),
+    ADD COLUMN IF NOT EXISTS "exchange_rate" NUMERIC(24, 10) NOT NULL DEFAULT 1;
+
+ALTER TABLE "order" ALTER COLUMN "currency" DROP DEFAULT;
+
+ALTER TABLE "order_products"
+    ALTER COLUMN "cost" TYPE BIGINT USING "cost" * 100,
+    ALTER COLUMN "discount" TYPE BIGINT USING "discount" * 100;
+
+ALTER TABLE "order_discount" ALTER COLUMN "amount" TYPE BIGINT USING "amount" * 100;
+
+-- Fixed promotion amounts are in the base currency.
+ALTER TABLE "promotion" ALTER COLUMN "value" TYPE BIGINT USING CASE WHEN "type" = 'fixed' THEN "value" * 100 ELSE "value" END;
+
+INSERT INTO "permission" (name, description) VALUES
+  ('currency:manage', 'Manage exchange rates')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO "role_permission" (role_id, permission_id)
+SELECT r.id, p.id FROM "role" r CROSS JOIN "permission" p
+WHERE r.name IN ('Admin', 'Manager') AND p.name = 'currency:manage'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "stock_reservation";
+
+ALTER TABLE "product_variant" DROP COLUMN IF EXISTS "reserved";
+ALTER TABLE "product" DROP COLUMN IF EXISTS "reserved";
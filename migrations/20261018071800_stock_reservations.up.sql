@@ -0,0 +1,23 @@
+-- count stays the stock on hand; reserved is what unpaid orders hold of it.
+-- For products with variants it is the sum over the variants.
+ALTER TABLE "product" ADD COLUMN IF NOT EXISTS "reserved" INT NOT NULL DEFAULT 0 CHECK ("reserved" >= 0);
+ALTER TABLE "product_variant" ADD COLUMN IF NOT EXISTS "reserved" INT NOT NULL DEFAULT 0 CHECK ("reserved" >= 0);
+
+-- An active reservation holds stock for an order until it is paid
+-- (consumed), cancelled (released) or expires_at passes (expired).
+CREATE TABLE IF NOT EXISTS "stock_reservation" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "order_id" UUID NOT NULL REFERENCES "order"("id") ON DELETE CASCADE,
+    "product_id" UUID NOT NULL REFERENCES "product"("id") ON DELETE CASCADE,
+    "variant_id" UUID REFERENCES "product_variant"("id") ON DELETE CASCADE,
+    "count" INT NOT NULL CHECK ("count" > 0),
+    "status" VARCHAR(16) NOT NULL DEFAULT 'active' CHECK ("status" IN ('active', 'consumed', 'released', 'expired')),
+    "expires_at" TIMESTAMPTZ NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "stock_reservation_order_idx" ON "stock_reservation"("order_id");
+CREATE INDEX IF NOT EXISTS "stock_reservation_expiry_idx" ON "stock_reservation"("expires_at") WHERE "status" = 'active';
+
+CREATE TRIGGER set_updated_at BEFORE UPDATE ON "stock_reservation" FOR EACH ROW EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,7 @@
+DELETE FROM "permission" WHERE name = 'stock:manage';
+
+DROP TABLE IF EXISTS "stock_movement";
+DROP FUNCTION IF EXISTS apply_stock_movement();
+DROP FUNCTION IF EXISTS stock_movement_append_only();
+DROP TABLE IF EXISTS "stock_level";
+DROP TABLE IF EXISTS "warehouse";
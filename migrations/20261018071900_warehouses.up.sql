@@ -0,0 +1,111 @@
+CREATE TABLE IF NOT EXISTS "warehouse" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "code" VARCHAR(32) NOT NULL UNIQUE,
+    "name" VARCHAR(255) NOT NULL,
+    "address" TEXT,
+    "is_default" BOOLEAN NOT NULL DEFAULT FALSE,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Stock edited without naming a warehouse goes to the default one.
+CREATE UNIQUE INDEX IF NOT EXISTS "warehouse_default_idx" ON "warehouse"("is_default") WHERE "is_default";
+
+CREATE TRIGGER set_updated_at BEFORE UPDATE ON "warehouse" FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+INSERT INTO "warehouse" (code, name, is_default) VALUES ('main', 'Main warehouse', TRUE)
+ON CONFLICT (code) DO NOTHING;
+
+-- stock_level is derived from stock_movement by apply_stock_movement and
+-- never written otherwise. Products with variants keep their stock on the
+-- variants only.
+CREATE TABLE IF NOT EXISTS "stock_level" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "warehouse_id" UUID NOT NULL REFERENCES "warehouse"("id"),
+    "product_id" UUID NOT NULL REFERENCES "product"("id") ON DELETE CASCADE,
+    "variant_id" UUID REFERENCES "product_variant"("id") ON DELETE CASCADE,
+    "count" INT NOT NULL CONSTRAINT "stock_level_count_check" CHECK ("count" >= 0),
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE NULLS NOT DISTINCT ("warehouse_id", "product_id", "variant_id")
+);
+
+CREATE INDEX IF NOT EXISTS "stock_level_product_idx" ON "stock_level"("product_id", "variant_id");
+
+CREATE TRIGGER set_updated_at BEFORE UPDATE ON "stock_level" FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- The ledger. quantity is signed: a transfer is two rows sharing transfer_id,
+-- one taking the items out of a warehouse and one putting them into another.
+CREATE TABLE IF NOT EXISTS "stock_movement" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "warehouse_id" UUID NOT NULL REFERENCES "warehouse"("id"),
+    "product_id" UUID NOT NULL REFERENCES "product"("id") ON DELETE CASCADE,
+    "variant_id" UUID REFERENCES "product_variant"("id") ON DELETE CASCADE,
+    "type" VARCHAR(16) NOT NULL CHECK ("type" IN ('receipt', 'sale', 'return', 'adjustment', 'transfer')),
+    "quantity" INT NOT NULL CHECK ("quantity" <> 0),
+    "reason" VARCHAR(32) CHECK ("type" <> 'adjustment' OR "reason" IS NOT NULL),
+    "order_id" UUID REFERENCES "order"("id") ON DELETE SET NULL,
+    "transfer_id" UUID,
+    "comment" TEXT,
+    "created_by" UUID REFERENCES "user"("id") ON DELETE SET NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "stock_movement_product_idx" ON "stock_movement"("product_id", "created_at");
+CREATE INDEX IF NOT EXISTS "stock_movement_warehouse_idx" ON "stock_movement"("warehouse_id", "created_at");
+CREATE INDEX IF NOT EXISTS "stock_movement_order_idx" ON "stock_movement"("order_id");
+
+-- Opening balances: the current stock lands in the default warehouse.
+INSERT INTO "stock_movement" (warehouse_id, product_id, variant_id, type, quantity, reason)
+SELECT w.id, v.product_id, v.id, 'adjustment', v.count, 'initial'
+FROM "product_variant" v CROSS JOIN "warehouse" w
+WHERE w.is_default AND v.count > 0;
+
+INSERT INTO "stock_movement" (warehouse_id, product_id, type, quantity, reason)
+SELECT w.id, p.id, 'adjustment', p.count, 'initial'
+FROM "product" p CROSS JOIN "warehouse" w
+WHERE w.is_default AND p.count > 0 AND NOT EXISTS (SELECT 1 FROM "product_variant" v WHERE v.product_id = p.id);
+
+INSERT INTO "stock_level" (warehouse_id, product_id, variant_id, count)
+SELECT warehouse_id, product_id, variant_id, quantity FROM "stock_movement";
+
+-- Every movement is added to its stock level and to the count of its
+-- variant, which product_variant_totals passes on, or product.
+CREATE OR REPLACE FUNCTION apply_stock_movement() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO "stock_level" (warehouse_id, product_id, variant_id, count)
+    VALUES (NEW.warehouse_id, NEW.product_id, NEW.variant_id, NEW.quantity)
+    ON CONFLICT (warehouse_id, product_id, variant_id) DO UPDATE SET count = "stock_level".count + EXCLUDED.count;
+
+    IF NEW.variant_id IS NOT NULL THEN
+        UPDATE "product_variant" SET count = count + NEW.quantity WHERE id = NEW.variant_id;
+    ELSE
+        UPDATE "product" SET count = count + NEW.quantity WHERE id = NEW.product_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER apply_stock_movement AFTER INSERT ON "stock_movement"
+    FOR EACH ROW EXECUTE FUNCTION apply_stock_movement();
+
+-- Movements are never changed; order_id and created_by may still be set to
+-- NULL when the order or user goes away.
+CREATE OR REPLACE FUNCTION stock_movement_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'stock_movement is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER stock_movement_append_only
+    BEFORE UPDATE OF "warehouse_id", "product_id", "variant_id", "type", "quantity", "reason", "transfer_id", "comment", "created_at" ON "stock_movement"
+    FOR EACH ROW EXECUTE FUNCTION stock_movement_append_only();
+
+INSERT INTO "permission" (name, description) VALUES
+  ('stock:manage', 'Manage warehouses and stock')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO "role_permission" (role_id, permission_id)
+SELECT r.id, p.id FROM "role" r CROSS JOIN "permission" p
+WHERE r.name IN ('Admin', 'Manager') AND p.name = 'stock:manage'
+ON CONFLICT DO NOTHING;
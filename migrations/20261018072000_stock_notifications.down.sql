@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "stock_subscription";
+DROP TABLE IF EXISTS "low_stock_threshold";
@@ -0,0 +1,24 @@
+-- Managers are alerted once when the count of a product drops below its
+-- threshold; alerted_at is cleared when the count is back at or above it.
+CREATE TABLE IF NOT EXISTS "low_stock_threshold" (
+    "product_id" UUID PRIMARY KEY REFERENCES "product"("id") ON DELETE CASCADE,
+    "threshold" INT NOT NULL CHECK ("threshold" > 0),
+    "alerted_at" TIMESTAMPTZ,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER set_updated_at BEFORE UPDATE ON "low_stock_threshold" FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- A customer waiting for a product to be back in stock, messaged in chat_id.
+-- The subscription is removed once the customer is notified.
+CREATE TABLE IF NOT EXISTS "stock_subscription" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "user_id" UUID NOT NULL REFERENCES "user"("id") ON DELETE CASCADE,
+    "product_id" UUID NOT NULL REFERENCES "product"("id") ON DELETE CASCADE,
+    "chat_id" VARCHAR(64) NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE ("user_id", "product_id")
+);
+
+CREATE INDEX IF NOT EXISTS "stock_subscription_product_idx" ON "stock_subscription"("product_id");
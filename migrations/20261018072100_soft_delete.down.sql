@@ -0,0 +1,21 @@
+ALTER TABLE "user" DROP CONSTRAINT IF EXISTS "user_role_id_fkey";
+ALTER TABLE "user" ADD CONSTRAINT "user_role_id_fkey" FOREIGN KEY ("role_id") REFERENCES "role"("id") ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS "user_username_key";
+ALTER TABLE "user" ADD CONSTRAINT "user_username_key" UNIQUE ("username");
+
+DROP INDEX IF EXISTS "category_root_name_key";
+DROP INDEX IF EXISTS "category_parent_name_key";
+CREATE UNIQUE INDEX IF NOT EXISTS "category_root_name_key" ON "category"("name") WHERE "parent_id" IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS "category_parent_name_key" ON "category"("parent_id", "name") WHERE "parent_id" IS NOT NULL;
+
+DROP INDEX IF EXISTS "product_sku_key";
+ALTER TABLE "product" ADD CONSTRAINT "product_sku_key" UNIQUE ("sku");
+
+DROP INDEX IF EXISTS "user_deleted_at_idx";
+DROP INDEX IF EXISTS "category_deleted_at_idx";
+DROP INDEX IF EXISTS "product_deleted_at_idx";
+
+ALTER TABLE "user" DROP COLUMN IF EXISTS "deleted_at";
+ALTER TABLE "category" DROP COLUMN IF EXISTS "deleted_at";
+ALTER TABLE "product" DROP COLUMN IF EXISTS "deleted_at";
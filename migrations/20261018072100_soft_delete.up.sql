@@ -0,0 +1,30 @@
+-- Deleted products, categories and users go to the trash: deleted_at is set
+-- and the row is hidden until it is restored, or purged once the retention
+-- window has passed and nothing references it any more.
+ALTER TABLE "product" ADD COLUMN IF NOT EXISTS "deleted_at" TIMESTAMPTZ;
+ALTER TABLE "category" ADD COLUMN IF NOT EXISTS "deleted_at" TIMESTAMPTZ;
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS "deleted_at" TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS "product_deleted_at_idx" ON "product"("deleted_at") WHERE "deleted_at" IS NOT NULL;
+CREATE INDEX IF NOT EXISTS "category_deleted_at_idx" ON "category"("deleted_at") WHERE "deleted_at" IS NOT NULL;
+CREATE INDEX IF NOT EXISTS "user_deleted_at_idx" ON "user"("deleted_at") WHERE "deleted_at" IS NOT NULL;
+
+-- Records in the trash do not hold on to their SKU, name or username;
+-- restoring one that was taken in the meantime is a conflict.
+ALTER TABLE "product" DROP CONSTRAINT IF EXISTS "product_sku_key";
+CREATE UNIQUE INDEX IF NOT EXISTS "product_sku_key" ON "product"("sku") WHERE "deleted_at" IS NULL;
+
+DROP INDEX IF EXISTS "category_root_name_key";
+DROP INDEX IF EXISTS "category_parent_name_key";
+CREATE UNIQUE INDEX IF NOT EXISTS "category_root_name_key" ON "category"("name")
+    WHERE "parent_id" IS NULL AND "deleted_at" IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS "category_parent_name_key" ON "category"("parent_id", "name")
+    WHERE "parent_id" IS NOT NULL AND "deleted_at" IS NULL;
+
+ALTER TABLE "user" DROP CONSTRAINT IF EXISTS "user_username_key";
+CREATE UNIQUE INDEX IF NOT EXISTS "user_username_key" ON "user"("username") WHERE "deleted_at" IS NULL;
+
+-- Removing a role used to delete its users; a role still assigned to a user,
+-- in the trash or not, cannot be deleted any more.
+ALTER TABLE "user" DROP CONSTRAINT IF EXISTS "user_role_id_fkey";
+ALTER TABLE "user" ADD CONSTRAINT "user_role_id_fkey" FOREIGN KEY ("role_id") REFERENCES "role"("id") ON DELETE RESTRICT;
@@ -0,0 +1,29 @@
+-- A cart belongs to a signed-in user or to an anonymous session, such as a
+-- web visitor or a chat; a session cart is merged into the user's on login.
+CREATE TABLE IF NOT EXISTS "cart" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "user_id" UUID REFERENCES "user"("id") ON DELETE CASCADE,
+    "session_id" VARCHAR(128),
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (("user_id" IS NULL) <> ("session_id" IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "cart_user_id_key" ON "cart"("user_id") WHERE "user_id" IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS "cart_session_id_key" ON "cart"("session_id") WHERE "session_id" IS NOT NULL;
+
+CREATE TRIGGER set_updated_at BEFORE UPDATE ON "cart" FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- Items hold no price: carts are priced when they are read.
+CREATE TABLE IF NOT EXISTS "cart_item" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "cart_id" UUID NOT NULL REFERENCES "cart"("id") ON DELETE CASCADE,
+    "product_id" UUID NOT NULL REFERENCES "product"("id") ON DELETE CASCADE,
+    "variant_id" UUID REFERENCES "product_variant"("id") ON DELETE CASCADE,
+    "count" INT NOT NULL CHECK ("count" > 0),
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE NULLS NOT DISTINCT ("cart_id", "product_id", "variant_id")
+);
+
+CREATE TRIGGER set_updated_at BEFORE UPDATE ON "cart_item" FOR EACH ROW EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1 @@
+ALTER TABLE "stock_subscription" DROP CONSTRAINT IF EXISTS "stock_subscription_chat_id_check";
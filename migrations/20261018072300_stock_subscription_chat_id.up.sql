@@ -0,0 +1,5 @@
+-- Bots cannot message users by @username, so subscriptions made that way
+-- were never delivered. Only numeric chat ids are kept and accepted.
+DELETE FROM "stock_subscription" WHERE "chat_id" !~ '^-?[0-9]+$';
+
+ALTER TABLE "stock_subscription" ADD CONSTRAINT "stock_subscription_chat_id_check" CHECK ("chat_id" ~ '^-?[0-9]+$');
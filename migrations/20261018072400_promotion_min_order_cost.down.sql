@@ -0,0 +1 @@
+ALTER TABLE "promotion" DROP COLUMN IF EXISTS "min_order_cost";
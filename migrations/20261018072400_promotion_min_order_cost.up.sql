@@ -0,0 +1,3 @@
+-- A promotion with a minimum only applies to orders whose subtotal, before
+-- discounts, reaches it. It is in minor units of the base currency.
+ALTER TABLE "promotion" ADD COLUMN IF NOT EXISTS "min_order_cost" INT NOT NULL DEFAULT 0 CHECK ("min_order_cost" >= 0);
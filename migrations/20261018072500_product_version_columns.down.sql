@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS bump_version ON "product";
+
+CREATE TRIGGER bump_version BEFORE UPDATE ON "product" FOR EACH ROW EXECUTE FUNCTION bump_version();
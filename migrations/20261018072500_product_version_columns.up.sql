@@ -0,0 +1,8 @@
+-- Stock bookkeeping (count, reserved) changes with every order, payment,
+-- receipt and expired reservation. Only the fields operators edit bump the
+-- row version, so those changes do not make their ETags stale.
+DROP TRIGGER IF EXISTS bump_version ON "product";
+
+CREATE TRIGGER bump_version BEFORE UPDATE OF
+    "name", "sku", "category_id", "short_info", "description", "cost", "discount_cost", "discount", "deleted_at"
+    ON "product" FOR EACH ROW EXECUTE FUNCTION bump_version();
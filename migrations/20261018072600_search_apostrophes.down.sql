@@ -0,0 +1,19 @@
+DROP INDEX IF EXISTS "product_search_vector_idx";
+
+ALTER TABLE "product" DROP COLUMN IF EXISTS "search_vector";
+
+ALTER TABLE "product" ADD COLUMN "search_vector" TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', COALESCE("name", '')), 'A') ||
+    setweight(to_tsvector('russian', COALESCE("name", '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE("name", '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE("short_info", '')), 'B') ||
+    setweight(to_tsvector('russian', COALESCE("short_info", '')), 'B') ||
+    setweight(to_tsvector('english', COALESCE("short_info", '')), 'B') ||
+    setweight(to_tsvector('simple', COALESCE("description", '')), 'C') ||
+    setweight(to_tsvector('russian', COALESCE("description", '')), 'C') ||
+    setweight(to_tsvector('english', COALESCE("description", '')), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS "product_search_vector_idx" ON "product" USING GIN ("search_vector");
+
+DROP FUNCTION IF EXISTS search_text(TEXT);
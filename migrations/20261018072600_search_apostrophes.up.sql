@@ -0,0 +1,28 @@
+-- Uzbek Latin writes o‘, g‘ and the tutuq belgisi with several marks. Some
+-- of them are letters to the text search parser and some are not, so the
+-- same word was indexed differently depending on how it was typed. They
+-- are indexed as a plain apostrophe, which searches send too.
+CREATE OR REPLACE FUNCTION search_text(t TEXT) RETURNS TEXT AS $$
+    SELECT translate(COALESCE(t, ''), 'ʻʼ’‘`´', repeat('''', 6));
+$$ LANGUAGE SQL IMMUTABLE;
+
+DROP INDEX IF EXISTS "product_search_vector_idx";
+
+ALTER TABLE "product" DROP COLUMN IF EXISTS "search_vector";
+
+ALTER TABLE "product" ADD COLUMN "search_vector" TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', search_text("name")), 'A') ||
+    setweight(to_tsvector('russian', search_text("name")), 'A') ||
+    setweight(to_tsvector('english', search_text("name")), 'A') ||
+    setweight(to_tsvector('simple', search_text("short_info")), 'B') ||
+    setweight(to_tsvector('russian', search_text("short_info")), 'B') ||
+    setweight(to_tsvector('english', search_text("short_info")), 'B') ||
+    setweight(to_tsvector('simple', search_text("description")), 'C') ||
+    setweight(to_tsvector('russian', search_text("description")), 'C') ||
+    setweight(to_tsvector('english', search_text("description")), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS "product_search_vector_idx" ON "product" USING GIN ("search_vector");
+
+UPDATE "search_synonym" SET "term" = search_text("term"), "synonym" = search_text("synonym")
+WHERE ("term", "synonym") IS DISTINCT FROM (search_text("term"), search_text("synonym"));
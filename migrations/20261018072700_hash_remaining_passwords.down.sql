@@ -0,0 +1 @@
+-- Password hashes cannot be reverted to plain text.
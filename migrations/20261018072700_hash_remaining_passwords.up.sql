@@ -0,0 +1,5 @@
+-- Plain text passwords starting with "$" were taken for hashes and left as
+-- they were by hash_user_passwords. Values that are not shaped like a bcrypt
+-- or argon2id hash are wrapped into bcrypt now.
+UPDATE "user" SET "password" = crypt("password", gen_salt('bf'))
+WHERE "password" !~ '^\$2[aby]\$[0-9]{2}\$[./A-Za-z0-9]{53}$' AND "password" NOT LIKE '$argon2id$%';
@@ -0,0 +1,155 @@
+// Package hasher implements password hashing.
+//
+// Hashes are stored in the PHC string format, e.g.
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>, so parameters can be raised
+// later without breaking existing hashes. Legacy bcrypt hashes are accepted
+// for verification and reported as needing a rehash.
+package hasher
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	_defaultMemory      = 64 * 1024
+	_defaultIterations  = 3
+	_defaultParallelism = 2
+	_defaultSaltLength  = 16
+	_defaultKeyLength   = 32
+
+	_argon2idPrefix = "$argon2id$"
+)
+
+var (
+	// ErrMismatchedPassword -.
+	ErrMismatchedPassword = errors.New("hasher - password does not match")
+	// ErrUnknownFormat -.
+	ErrUnknownFormat = errors.New("hasher - unknown hash format")
+)
+
+// Hasher -.
+type Hasher struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	saltLength  uint32
+	keyLength   uint32
+}
+
+// New -.
+func New(opts ...Option) *Hasher {
+	h := &Hasher{
+		memory:      _defaultMemory,
+		iterations:  _defaultIterations,
+		parallelism: _defaultParallelism,
+		saltLength:  _defaultSaltLength,
+		keyLength:   _defaultKeyLength,
+	}
+
+	// Custom options
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Hash -.
+func (h *Hasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.saltLength)
+
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", fmt.Errorf("hasher - Hash - rand.Read: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.iterations, h.memory, h.parallelism, h.keyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		_argon2idPrefix,
+		argon2.Version,
+		h.memory, h.iterations, h.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify checks password against encoded hash. needsRehash is true when the
+// hash was produced by a legacy algorithm or with outdated parameters.
+func (h *Hasher) Verify(encoded, password string) (needsRehash bool, err error) {
+	switch {
+	case strings.HasPrefix(encoded, _argon2idPrefix):
+		return h.verifyArgon2id(encoded, password)
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		err = bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, ErrMismatchedPassword
+		}
+
+		if err != nil {
+			return false, fmt.Errorf("hasher - Verify - bcrypt.CompareHashAndPassword: %w", err)
+		}
+
+		return true, nil
+	default:
+		return false, ErrUnknownFormat
+	}
+}
+
+func (h *Hasher) verifyArgon2id(encoded, password string) (bool, error) {
+	// "", "argon2id", "v=19", "m=65536,t=3,p=2", salt, key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return false, ErrUnknownFormat
+	}
+
+	var version int
+
+	_, err := fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil {
+		return false, fmt.Errorf("hasher - verifyArgon2id - version: %w", ErrUnknownFormat)
+	}
+
+	var (
+		memory, iterations uint32
+		parallelism        uint8
+	)
+
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &parallelism)
+	if err != nil || iterations == 0 || parallelism == 0 {
+		return false, fmt.Errorf("hasher - verifyArgon2id - params: %w", ErrUnknownFormat)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, fmt.Errorf("hasher - verifyArgon2id - salt: %w", ErrUnknownFormat)
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, fmt.Errorf("hasher - verifyArgon2id - key: %w", ErrUnknownFormat)
+	}
+
+	//nolint:gosec // key length is bounded by the stored hash
+	otherKey := argon2.IDKey([]byte(password), salt, iterations, memory, parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, otherKey) != 1 {
+		return false, ErrMismatchedPassword
+	}
+
+	needsRehash := version != argon2.Version ||
+		memory != h.memory ||
+		iterations != h.iterations ||
+		parallelism != h.parallelism ||
+		uint32(len(salt)) != h.saltLength || //nolint:gosec // bounded by the stored hash
+		uint32(len(key)) != h.keyLength //nolint:gosec // bounded by the stored hash
+
+	return needsRehash, nil
+}
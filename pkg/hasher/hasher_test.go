@@ -0,0 +1,121 @@
+package hasher_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"ai-seller/pkg/hasher"
+)
+
+// fast keeps the argon2id cost low for tests.
+func fast(opts ...hasher.Option) *hasher.Hasher {
+	return hasher.New(append([]hasher.Option{hasher.Memory(1024), hasher.Iterations(1), hasher.Parallelism(1)}, opts...)...)
+}
+
+func TestHashVerify(t *testing.T) {
+	t.Parallel()
+
+	h := fast()
+
+	encoded, err := h.Hash("correct horse")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=1024,t=1,p=1$"), encoded)
+
+	other, err := h.Hash("correct horse")
+	require.NoError(t, err)
+	require.NotEqual(t, encoded, other, "salts must differ")
+
+	needsRehash, err := h.Verify(encoded, "correct horse")
+	require.NoError(t, err)
+	require.False(t, needsRehash)
+
+	_, err = h.Verify(encoded, "wrong horse")
+	require.ErrorIs(t, err, hasher.ErrMismatchedPassword)
+
+	_, err = h.Verify(encoded, "")
+	require.ErrorIs(t, err, hasher.ErrMismatchedPassword)
+}
+
+func TestVerifyNeedsRehash(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := fast().Hash("secret")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		hasher      *hasher.Hasher
+		needsRehash bool
+	}{
+		{name: "same parameters", hasher: fast(), needsRehash: false},
+		{name: "memory", hasher: fast(hasher.Memory(2048)), needsRehash: true},
+		{name: "iterations", hasher: fast(hasher.Iterations(2)), needsRehash: true},
+		{name: "parallelism", hasher: fast(hasher.Parallelism(2)), needsRehash: true},
+		{name: "salt length", hasher: fast(hasher.SaltLength(32)), needsRehash: true},
+		{name: "key length", hasher: fast(hasher.KeyLength(64)), needsRehash: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			needsRehash, err := tc.hasher.Verify(encoded, "secret")
+			require.NoError(t, err)
+			require.Equal(t, tc.needsRehash, needsRehash)
+		})
+	}
+}
+
+func TestVerifyBcrypt(t *testing.T) {
+	t.Parallel()
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	h := fast()
+
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		encoded := prefix + string(legacy[len(prefix):])
+
+		needsRehash, err := h.Verify(encoded, "secret")
+		require.NoError(t, err, prefix)
+		require.True(t, needsRehash, prefix)
+
+		_, err = h.Verify(encoded, "wrong")
+		require.ErrorIs(t, err, hasher.ErrMismatchedPassword, prefix)
+	}
+}
+
+func TestVerifyUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{name: "empty", encoded: ""},
+		{name: "plain text", encoded: "secret"},
+		{name: "other algorithm", encoded: "$scrypt$ln=16,r=8,p=1$c2FsdA$a2V5"},
+		{name: "missing parts", encoded: "$argon2id$v=19$m=1024,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA"},
+		{name: "bad version", encoded: "$argon2id$v=x$m=1024,t=1,p=1$c2FsdA$a2V5"},
+		{name: "bad parameters", encoded: "$argon2id$v=19$m=1024$c2FsdA$a2V5"},
+		{name: "zero iterations", encoded: "$argon2id$v=19$m=1024,t=0,p=1$c2FsdA$a2V5"},
+		{name: "zero parallelism", encoded: "$argon2id$v=19$m=1024,t=1,p=0$c2FsdA$a2V5"},
+		{name: "bad salt", encoded: "$argon2id$v=19$m=1024,t=1,p=1$!!$a2V5"},
+		{name: "bad key", encoded: "$argon2id$v=19$m=1024,t=1,p=1$c2FsdA$!!"},
+	}
+
+	h := fast()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := h.Verify(tc.encoded, "secret")
+			require.ErrorIs(t, err, hasher.ErrUnknownFormat)
+		})
+	}
+}
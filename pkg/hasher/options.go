@@ -0,0 +1,39 @@
+package hasher
+
+// Option -.
+type Option func(*Hasher)
+
+// Memory sets argon2id memory cost in KiB.
+func Memory(memory uint32) Option {
+	return func(h *Hasher) {
+		h.memory = memory
+	}
+}
+
+// Iterations -.
+func Iterations(iterations uint32) Option {
+	return func(h *Hasher) {
+		h.iterations = iterations
+	}
+}
+
+// Parallelism -.
+func Parallelism(parallelism uint8) Option {
+	return func(h *Hasher) {
+		h.parallelism = parallelism
+	}
+}
+
+// SaltLength -.
+func SaltLength(length uint32) Option {
+	return func(h *Hasher) {
+		h.saltLength = length
+	}
+}
+
+// KeyLength -.
+func KeyLength(length uint32) Option {
+	return func(h *Hasher) {
+		h.keyLength = length
+	}
+}
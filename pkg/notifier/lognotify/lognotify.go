@@ -0,0 +1,30 @@
+// Package lognotify implements notifications written to the log, for
+// development and deployments without a messenger.
+package lognotify
+
+import (
+	"context"
+
+	"ai-seller/pkg/logger"
+)
+
+// Notifier logs every message instead of sending it.
+type Notifier struct {
+	l logger.Interface
+}
+
+// New -.
+func New(l logger.Interface) *Notifier {
+	return &Notifier{l: l}
+}
+
+// Notify logs text with its chat; an empty chatID is the managers' chat.
+func (n *Notifier) Notify(_ context.Context, chatID, text string) error {
+	if chatID == "" {
+		chatID = "managers"
+	}
+
+	n.l.Info("notify %s: %s", chatID, text)
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package telegram
+
+import "time"
+
+// Option -.
+type Option func(*Notifier)
+
+// BaseURL -.
+func BaseURL(url string) Option {
+	return func(n *Notifier) {
+		n.baseURL = url
+	}
+}
+
+// Timeout -.
+func Timeout(timeout time.Duration) Option {
+	return func(n *Notifier) {
+		n.timeout = timeout
+	}
+}
@@ -0,0 +1,110 @@
+// Package telegram implements sending messages through a Telegram bot.
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	_defaultBaseURL = "https://api.telegram.org"
+	_defaultTimeout = 10 * time.Second
+)
+
+// Notifier sends messages as the bot the token belongs to.
+type Notifier struct {
+	client        *http.Client
+	token         string
+	managerChatID string
+
+	baseURL string
+	timeout time.Duration
+}
+
+// New -. Messages without a chat go to managerChatID.
+func New(token, managerChatID string, opts ...Option) *Notifier {
+	n := &Notifier{
+		token:         token,
+		managerChatID: managerChatID,
+		baseURL:       _defaultBaseURL,
+		timeout:       _defaultTimeout,
+	}
+
+	// Custom options
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	n.client = &http.Client{Timeout: n.timeout}
+
+	return n
+}
+
+type sendMessageRequest struct {
+	ChatID string `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+type sendMessageResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+// Notify sends text to chatID, or to the managers' chat when chatID is empty.
+func (n *Notifier) Notify(ctx context.Context, chatID, text string) error {
+	if chatID == "" {
+		chatID = n.managerChatID
+	}
+
+	if chatID == "" {
+		return fmt.Errorf("telegram - Notify: no chat to send to")
+	}
+
+	body, err := json.Marshal(sendMessageRequest{ChatID: chatID, Text: text})
+	if err != nil {
+		return fmt.Errorf("telegram - Notify - json.Marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.baseURL+"/bot"+n.token+"/sendMessage", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("telegram - Notify - http.NewRequestWithContext: %w", withoutURL(err))
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("telegram - Notify - n.client.Do: %w", withoutURL(err))
+	}
+	defer resp.Body.Close()
+
+	var result sendMessageResponse
+
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return fmt.Errorf("telegram - Notify - json.Decode: status %d: %w", resp.StatusCode, err)
+	}
+
+	if !result.OK {
+		return fmt.Errorf("telegram - Notify: status %d: %s", resp.StatusCode, result.Description)
+	}
+
+	return nil
+}
+
+// withoutURL drops the request URL, which holds the bot token, from err so
+// that it never reaches the logs.
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+
+	return err
+}
@@ -0,0 +1,113 @@
+package telegram_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/pkg/notifier/telegram"
+)
+
+const _token = "123456:secret-bot-token"
+
+type sentMessage struct {
+	path   string
+	chatID string
+	text   string
+}
+
+func newServer(t *testing.T, reply string) (*httptest.Server, chan sentMessage) {
+	t.Helper()
+
+	sent := make(chan sentMessage, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ChatID string `json:"chat_id"`
+			Text   string `json:"text"`
+		}
+
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		sent <- sentMessage{path: r.URL.Path, chatID: body.ChatID, text: body.Text}
+
+		_, _ = io.WriteString(w, reply)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, sent
+}
+
+func TestNotify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		chatID string
+		want   string
+	}{
+		{name: "chat", chatID: "42", want: "42"},
+		{name: "managers' chat", chatID: "", want: "-100"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			server, sent := newServer(t, `{"ok":true}`)
+			n := telegram.New(_token, "-100", telegram.BaseURL(server.URL))
+
+			err := n.Notify(context.Background(), tc.chatID, "hello")
+			require.NoError(t, err)
+
+			msg := <-sent
+			require.Equal(t, "/bot"+_token+"/sendMessage", msg.path)
+			require.Equal(t, tc.want, msg.chatID)
+			require.Equal(t, "hello", msg.text)
+		})
+	}
+}
+
+func TestNotifyErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no chat", func(t *testing.T) {
+		t.Parallel()
+
+		err := telegram.New(_token, "").Notify(context.Background(), "", "hello")
+		require.Error(t, err)
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		t.Parallel()
+
+		server, _ := newServer(t, `{"ok":false,"description":"Bad Request: chat not found"}`)
+
+		err := telegram.New(_token, "-100", telegram.BaseURL(server.URL)).Notify(context.Background(), "42", "hello")
+		require.ErrorContains(t, err, "chat not found")
+		require.NotContains(t, err.Error(), _token)
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		err := telegram.New(_token, "-100", telegram.BaseURL(server.URL)).Notify(context.Background(), "42", "hello")
+		require.Error(t, err)
+		require.NotContains(t, err.Error(), _token)
+	})
+
+	t.Run("bad base url", func(t *testing.T) {
+		t.Parallel()
+
+		err := telegram.New(_token, "-100", telegram.BaseURL("http://[::1")).Notify(context.Background(), "42", "hello")
+		require.Error(t, err)
+		require.NotContains(t, err.Error(), _token)
+	})
+}
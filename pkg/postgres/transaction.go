@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type txKey struct{}
+
+// Querier is implemented by both the pool and a transaction.
+type Querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Querier returns the transaction started by WithinTransaction if ctx carries
+// one, and the pool otherwise.
+func (p *Postgres) Querier(ctx context.Context) Querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+
+	return p.Pool
+}
+
+// WithinTransaction runs fn in a transaction that is committed when fn returns
+// nil and rolled back otherwise. Nested calls join the outer transaction.
+func (p *Postgres) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("postgres - WithinTransaction - p.Pool.Begin: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rbErr := tx.Rollback(ctx)
+			if rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+				err = errors.Join(err, fmt.Errorf("postgres - WithinTransaction - tx.Rollback: %w", rbErr))
+			}
+		}
+	}()
+
+	err = fn(context.WithValue(ctx, txKey{}, tx))
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("postgres - WithinTransaction - tx.Commit: %w", err)
+	}
+
+	return nil
+}
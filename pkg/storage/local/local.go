@@ -0,0 +1,120 @@
+// Package local implements file storage in a directory of the local filesystem.
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const _dirPerm = 0o755
+
+// ErrInvalidKey -.
+var ErrInvalidKey = errors.New("local - key escapes the storage directory")
+
+// Storage keeps objects as files under dir, one per slash-separated key.
+type Storage struct {
+	dir string
+}
+
+// New -.
+func New(dir string) (*Storage, error) {
+	err := os.MkdirAll(dir, _dirPerm)
+	if err != nil {
+		return nil, fmt.Errorf("local - New - os.MkdirAll: %w", err)
+	}
+
+	return &Storage{dir: dir}, nil
+}
+
+// Put writes the object through a temporary file, so readers never see a
+// partially written one.
+func (s *Storage) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(name), _dirPerm)
+	if err != nil {
+		return fmt.Errorf("local - Put - os.MkdirAll: %w", err)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("local - Put - os.CreateTemp: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	_, err = io.Copy(f, r)
+	if err != nil {
+		f.Close()
+
+		return fmt.Errorf("local - Put - io.Copy: %w", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("local - Put - f.Close: %w", err)
+	}
+
+	err = os.Rename(f.Name(), name)
+	if err != nil {
+		return fmt.Errorf("local - Put - os.Rename: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the object and its size. A missing object, or a key outside
+// the directory, is fs.ErrNotExist.
+func (s *Storage) Get(_ context.Context, key string) (io.ReadCloser, int64, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %w", fs.ErrNotExist, err)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, 0, fmt.Errorf("local - Get - os.Open: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+
+		return nil, 0, fmt.Errorf("local - Get - f.Stat: %w", err)
+	}
+
+	return f, info.Size(), nil
+}
+
+// Delete removes the object. Deleting a missing object is not an error.
+func (s *Storage) Delete(_ context.Context, key string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(name)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("local - Delete - os.Remove: %w", err)
+	}
+
+	return nil
+}
+
+// path maps key to a file under dir. Keys that leave dir, or name dir
+// itself, such as "a/..", are rejected.
+func (s *Storage) path(key string) (string, error) {
+	name := filepath.FromSlash(key)
+	if !filepath.IsLocal(name) || filepath.Clean(name) == "." {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	return filepath.Join(s.dir, name), nil
+}
@@ -0,0 +1,122 @@
+package local_test
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/pkg/storage/local"
+)
+
+func TestStorage(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s, err := local.New(dir)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	key := "products/1/photo one.jpg"
+
+	err = s.Put(ctx, key, strings.NewReader("image"), 5, "image/jpeg")
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "products", "1", "photo one.jpg"))
+	require.NoError(t, err)
+	require.Equal(t, "image", string(data))
+
+	err = s.Put(ctx, key, strings.NewReader("replaced"), 8, "image/jpeg")
+	require.NoError(t, err)
+
+	r, size, err := s.Get(ctx, key)
+	require.NoError(t, err)
+
+	data, err = io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, "replaced", string(data))
+	require.Equal(t, int64(8), size)
+
+	entries, err := os.ReadDir(filepath.Join(dir, "products", "1"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "no temporary files are left behind")
+
+	require.NoError(t, s.Delete(ctx, key))
+	require.NoError(t, s.Delete(ctx, key), "deleting a missing object")
+
+	_, _, err = s.Get(ctx, key)
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestStorageInvalidKey(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "storage")
+
+	s, err := local.New(dir)
+	require.NoError(t, err)
+
+	outside := filepath.Join(root, "outside")
+	require.NoError(t, os.WriteFile(outside, []byte("secret"), 0o600))
+
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{name: "empty", key: ""},
+		{name: "parent", key: "../outside"},
+		{name: "nested parent", key: "products/../../outside"},
+		{name: "absolute", key: outside},
+		{name: "root", key: "/"},
+		{name: "dot", key: "."},
+		{name: "directory itself", key: "products/.."},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := s.Put(ctx, tc.key, strings.NewReader("overwritten"), 11, "text/plain")
+			require.ErrorIs(t, err, local.ErrInvalidKey)
+
+			_, _, err = s.Get(ctx, tc.key)
+			require.ErrorIs(t, err, fs.ErrNotExist)
+			require.ErrorIs(t, err, local.ErrInvalidKey)
+
+			err = s.Delete(ctx, tc.key)
+			require.ErrorIs(t, err, local.ErrInvalidKey)
+		})
+	}
+
+	data, err := os.ReadFile(outside)
+	require.NoError(t, err)
+	require.Equal(t, "secret", string(data))
+}
+
+func TestStorageKeyWithinDirectory(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s, err := local.New(dir)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// A key that only goes up within the directory stays inside it.
+	err = s.Put(ctx, "products/../photo.jpg", strings.NewReader("image"), 5, "image/jpeg")
+	require.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(dir, "photo.jpg"))
+	require.NoError(t, err)
+}
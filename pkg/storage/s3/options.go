@@ -0,0 +1,27 @@
+package s3
+
+import "time"
+
+// Option -.
+type Option func(*Storage)
+
+// Region -.
+func Region(region string) Option {
+	return func(s *Storage) {
+		s.region = region
+	}
+}
+
+// UseSSL -.
+func UseSSL(useSSL bool) Option {
+	return func(s *Storage) {
+		s.useSSL = useSSL
+	}
+}
+
+// ConnTimeout -.
+func ConnTimeout(timeout time.Duration) Option {
+	return func(s *Storage) {
+		s.connTimeout = timeout
+	}
+}
@@ -0,0 +1,113 @@
+// Package s3 implements file storage in a bucket of an S3-compatible service,
+// such as AWS S3 or MinIO.
+package s3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+const (
+	_defaultUseSSL      = true
+	_defaultConnTimeout = 10 * time.Second
+)
+
+// Storage keeps objects in a bucket under their keys.
+type Storage struct {
+	client *minio.Client
+	bucket string
+
+	region      string
+	useSSL      bool
+	connTimeout time.Duration
+}
+
+// New connects to endpoint and creates the bucket when it does not exist yet.
+func New(endpoint, accessKey, secretKey, bucket string, opts ...Option) (*Storage, error) {
+	s := &Storage{
+		bucket:      bucket,
+		useSSL:      _defaultUseSSL,
+		connTimeout: _defaultConnTimeout,
+	}
+
+	// Custom options
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	var err error
+
+	s.client, err = minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: s.useSSL,
+		Region: s.region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("s3 - New - minio.New: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.connTimeout)
+	defer cancel()
+
+	exists, err := s.client.BucketExists(ctx, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("s3 - New - s.client.BucketExists: %w", err)
+	}
+
+	if !exists {
+		err = s.client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{Region: s.region})
+		if err != nil {
+			return nil, fmt.Errorf("s3 - New - s.client.MakeBucket: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+// Put -.
+func (s *Storage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		return fmt.Errorf("s3 - Put - s.client.PutObject: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the object and its size. A missing object is fs.ErrNotExist.
+func (s *Storage) Get(ctx context.Context, key string) (io.ReadCloser, int64, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, 0, fmt.Errorf("s3 - Get - s.client.GetObject: %w", err)
+	}
+
+	info, err := object.Stat()
+	if err != nil {
+		object.Close()
+
+		if minio.ToErrorResponse(err).StatusCode == http.StatusNotFound {
+			return nil, 0, fmt.Errorf("s3 - Get - object.Stat: %w", fs.ErrNotExist)
+		}
+
+		return nil, 0, fmt.Errorf("s3 - Get - object.Stat: %w", err)
+	}
+
+	return object, info.Size, nil
+}
+
+// Delete removes the object. Deleting a missing object is not an error.
+func (s *Storage) Delete(ctx context.Context, key string) error {
+	err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("s3 - Delete - s.client.RemoveObject: %w", err)
+	}
+
+	return nil
+}
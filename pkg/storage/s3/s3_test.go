@@ -0,0 +1,257 @@
+package s3_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/pkg/storage/s3"
+)
+
+const _bucket = "media"
+
+// fakeS3 serves the path-style S3 requests the storage makes, keeping
+// objects in memory by their decoded keys.
+type fakeS3 struct {
+	mu      sync.Mutex
+	buckets map[string]bool
+	objects map[string]string
+	paths   []string
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{buckets: map[string]bool{}, objects: map[string]string{}}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.paths = append(f.paths, r.URL.EscapedPath())
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+
+	if key == "" {
+		f.serveBucket(w, r, bucket)
+
+		return
+	}
+
+	if !f.buckets[bucket] {
+		writeError(w, http.StatusNotFound, "NoSuchBucket")
+
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := readBody(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "IncompleteBody")
+
+			return
+		}
+
+		f.objects[key] = body
+
+		w.Header().Set("ETag", `"etag"`)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet, http.MethodHead:
+		body, ok := f.objects[key]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NoSuchKey")
+
+			return
+		}
+
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(http.StatusOK)
+
+		if r.Method == http.MethodGet {
+			_, _ = io.WriteString(w, body)
+		}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+func (f *fakeS3) serveBucket(w http.ResponseWriter, r *http.Request, bucket string) {
+	switch r.Method {
+	case http.MethodHead:
+		if !f.buckets[bucket] {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	case http.MethodPut:
+		f.buckets[bucket] = true
+		w.WriteHeader(http.StatusOK)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+func (f *fakeS3) object(key string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	body, ok := f.objects[key]
+
+	return body, ok
+}
+
+func (f *fakeS3) requested(path string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, p := range f.paths {
+		if p == path {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readBody decodes the aws-chunked bodies the client streams over plain
+// HTTP.
+func readBody(r *http.Request) (string, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		body, err := io.ReadAll(r.Body)
+
+		return string(body), err
+	}
+
+	var body strings.Builder
+
+	br := bufio.NewReader(r.Body)
+
+	for {
+		header, err := br.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(header), ";")
+
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return "", err
+		}
+
+		if size == 0 {
+			return body.String(), nil
+		}
+
+		_, err = io.CopyN(&body, br, size)
+		if err != nil {
+			return "", err
+		}
+
+		_, err = br.Discard(2)
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, "<Error><Code>"+code+"</Code><Message>"+code+"</Message></Error>")
+}
+
+func newStorage(t *testing.T) (*s3.Storage, *fakeS3) {
+	t.Helper()
+
+	fake := newFakeS3()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	s, err := s3.New(strings.TrimPrefix(server.URL, "http://"), "access", "secret", _bucket,
+		s3.Region("us-east-1"), s3.UseSSL(false), s3.ConnTimeout(5*time.Second))
+	require.NoError(t, err)
+
+	return s, fake
+}
+
+func TestNewCreatesBucket(t *testing.T) {
+	t.Parallel()
+
+	_, fake := newStorage(t)
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	require.True(t, fake.buckets[_bucket])
+}
+
+func TestStorage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		key     string
+		escaped string
+	}{
+		{name: "plain", key: "products/1/photo.jpg", escaped: "/media/products/1/photo.jpg"},
+		{name: "space", key: "products/1/photo one.jpg", escaped: "/media/products/1/photo%20one.jpg"},
+		{name: "query and fragment", key: "products/1/a?b#c.jpg", escaped: "/media/products/1/a%3Fb%23c.jpg"},
+		{name: "percent", key: "products/1/100%.jpg", escaped: "/media/products/1/100%25.jpg"},
+		{name: "plus", key: "products/1/a+b.jpg", escaped: "/media/products/1/a%2Bb.jpg"},
+		{name: "unicode", key: "products/1/фото.jpg", escaped: "/media/products/1/%D1%84%D0%BE%D1%82%D0%BE.jpg"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, fake := newStorage(t)
+			ctx := context.Background()
+
+			err := s.Put(ctx, tc.key, strings.NewReader("image"), 5, "image/jpeg")
+			require.NoError(t, err)
+
+			body, ok := fake.object(tc.key)
+			require.True(t, ok, "stored under the unescaped key")
+			require.Equal(t, "image", body)
+			require.True(t, fake.requested(tc.escaped), "requested %s", tc.escaped)
+
+			r, size, err := s.Get(ctx, tc.key)
+			require.NoError(t, err)
+
+			data, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+			require.Equal(t, "image", string(data))
+			require.Equal(t, int64(5), size)
+
+			require.NoError(t, s.Delete(ctx, tc.key))
+
+			_, ok = fake.object(tc.key)
+			require.False(t, ok)
+
+			_, _, err = s.Get(ctx, tc.key)
+			require.ErrorIs(t, err, fs.ErrNotExist)
+
+			require.NoError(t, s.Delete(ctx, tc.key), "deleting a missing object")
+		})
+	}
+}
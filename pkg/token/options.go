@@ -0,0 +1,27 @@
+package token
+
+import "time"
+
+// Option -.
+type Option func(*Manager)
+
+// AccessTTL -.
+func AccessTTL(ttl time.Duration) Option {
+	return func(m *Manager) {
+		m.accessTTL = ttl
+	}
+}
+
+// RefreshTTL -.
+func RefreshTTL(ttl time.Duration) Option {
+	return func(m *Manager) {
+		m.refreshTTL = ttl
+	}
+}
+
+// Issuer -.
+func Issuer(issuer string) Option {
+	return func(m *Manager) {
+		m.issuer = issuer
+	}
+}
@@ -0,0 +1,143 @@
+// Package token implements JWT access tokens and opaque refresh tokens.
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	_defaultAccessTTL    = 15 * time.Minute
+	_defaultRefreshTTL   = 30 * 24 * time.Hour
+	_defaultIssuer       = "ai-seller"
+	_opaqueTokenByteSize = 32
+)
+
+// ErrInvalidToken -.
+var ErrInvalidToken = errors.New("token - invalid token")
+
+// Manager -.
+type Manager struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	issuer     string
+}
+
+type claims struct {
+	RoleID string `json:"role_id"`
+	jwt.RegisteredClaims
+}
+
+// New -.
+func New(secret string, opts ...Option) *Manager {
+	m := &Manager{
+		secret:     []byte(secret),
+		accessTTL:  _defaultAccessTTL,
+		refreshTTL: _defaultRefreshTTL,
+		issuer:     _defaultIssuer,
+	}
+
+	// Custom options
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// NewAccessToken -.
+func (m *Manager) NewAccessToken(userID, roleID string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(m.accessTTL)
+
+	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		RoleID: roleID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    m.issuer,
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+
+	signed, err := t.SignedString(m.secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("token - NewAccessToken - t.SignedString: %w", err)
+	}
+
+	return signed, expiresAt, nil
+}
+
+// ParseAccessToken -.
+func (m *Manager) ParseAccessToken(accessToken string) (userID, roleID string, err error) {
+	var c claims
+
+	_, err = jwt.ParseWithClaims(accessToken, &c, func(*jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(m.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return "", "", fmt.Errorf("token - ParseAccessToken - jwt.ParseWithClaims: %w", errors.Join(ErrInvalidToken, err))
+	}
+
+	return c.Subject, c.RoleID, nil
+}
+
+// NewRefreshToken returns an opaque refresh token and the hash to be stored.
+func (m *Manager) NewRefreshToken() (refreshToken, hash string, expiresAt time.Time, err error) {
+	refreshToken, err = opaque()
+	if err != nil {
+		return "", "", time.Time{}, fmt.Errorf("token - NewRefreshToken - %w", err)
+	}
+
+	return refreshToken, m.HashRefreshToken(refreshToken), time.Now().Add(m.refreshTTL), nil
+}
+
+// HashRefreshToken -.
+func (m *Manager) HashRefreshToken(refreshToken string) string {
+	return digest(refreshToken)
+}
+
+// NewCartSession returns an opaque token for an anonymous cart and the hash
+// to be stored.
+func (m *Manager) NewCartSession() (session, hash string, err error) {
+	session, err = opaque()
+	if err != nil {
+		return "", "", fmt.Errorf("token - NewCartSession - %w", err)
+	}
+
+	return session, m.HashCartSession(session), nil
+}
+
+// HashCartSession -.
+func (m *Manager) HashCartSession(session string) string {
+	return digest(session)
+}
+
+func opaque() (string, error) {
+	b := make([]byte, _opaqueTokenByteSize)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("rand.Read: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func digest(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
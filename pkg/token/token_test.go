@@ -0,0 +1,188 @@
+package token_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+
+	"ai-seller/pkg/token"
+)
+
+const _secret = "test-secret"
+
+func TestAccessToken(t *testing.T) {
+	t.Parallel()
+
+	m := token.New(_secret, token.AccessTTL(time.Minute))
+
+	signed, expiresAt, err := m.NewAccessToken("user-1", "role-1")
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, 5*time.Second)
+
+	userID, roleID, err := m.ParseAccessToken(signed)
+	require.NoError(t, err)
+	require.Equal(t, "user-1", userID)
+	require.Equal(t, "role-1", roleID)
+}
+
+func TestParseAccessTokenInvalid(t *testing.T) {
+	t.Parallel()
+
+	m := token.New(_secret)
+
+	sign := func(t *testing.T, method jwt.SigningMethod, key interface{}, claims jwt.MapClaims) string {
+		t.Helper()
+
+		signed, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		require.NoError(t, err)
+
+		return signed
+	}
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{"sub": "user-1", "iss": "ai-seller", "exp": time.Now().Add(time.Minute).Unix()}
+	}
+
+	tests := []struct {
+		name  string
+		token func(t *testing.T) string
+	}{
+		{
+			name: "empty",
+			token: func(*testing.T) string {
+				return ""
+			},
+		},
+		{
+			name: "garbage",
+			token: func(*testing.T) string {
+				return "not.a.token"
+			},
+		},
+		{
+			name: "expired",
+			token: func(t *testing.T) string {
+				t.Helper()
+
+				signed, _, err := token.New(_secret, token.AccessTTL(-time.Minute)).NewAccessToken("user-1", "")
+				require.NoError(t, err)
+
+				return signed
+			},
+		},
+		{
+			name: "wrong secret",
+			token: func(t *testing.T) string {
+				t.Helper()
+
+				signed, _, err := token.New("other-secret").NewAccessToken("user-1", "")
+				require.NoError(t, err)
+
+				return signed
+			},
+		},
+		{
+			name: "wrong issuer",
+			token: func(t *testing.T) string {
+				t.Helper()
+
+				signed, _, err := token.New(_secret, token.Issuer("someone-else")).NewAccessToken("user-1", "")
+				require.NoError(t, err)
+
+				return signed
+			},
+		},
+		{
+			name: "other algorithm",
+			token: func(t *testing.T) string {
+				t.Helper()
+
+				return sign(t, jwt.SigningMethodHS512, []byte(_secret), valid())
+			},
+		},
+		{
+			name: "unsigned",
+			token: func(t *testing.T) string {
+				t.Helper()
+
+				return sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, valid())
+			},
+		},
+		{
+			name: "no expiry",
+			token: func(t *testing.T) string {
+				t.Helper()
+
+				claims := valid()
+				delete(claims, "exp")
+
+				return sign(t, jwt.SigningMethodHS256, []byte(_secret), claims)
+			},
+		},
+		{
+			name: "tampered",
+			token: func(t *testing.T) string {
+				t.Helper()
+
+				signed, _, err := m.NewAccessToken("user-1", "")
+				require.NoError(t, err)
+
+				forged := sign(t, jwt.SigningMethodHS256, []byte("other-secret"), jwt.MapClaims{
+					"sub": "admin", "iss": "ai-seller", "exp": time.Now().Add(time.Minute).Unix(),
+				})
+
+				// The forged claims with the original signature.
+				return forged[:len(forged)-43] + signed[len(signed)-43:]
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, err := m.ParseAccessToken(tc.token(t))
+			require.ErrorIs(t, err, token.ErrInvalidToken)
+		})
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	t.Parallel()
+
+	m := token.New(_secret, token.RefreshTTL(time.Hour))
+
+	refreshToken, hash, expiresAt, err := m.NewRefreshToken()
+	require.NoError(t, err)
+	require.Len(t, refreshToken, 43)
+	require.Equal(t, m.HashRefreshToken(refreshToken), hash)
+	require.NotEqual(t, refreshToken, hash)
+	require.Len(t, hash, 64)
+	require.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, 5*time.Second)
+
+	other, otherHash, _, err := m.NewRefreshToken()
+	require.NoError(t, err)
+	require.NotEqual(t, refreshToken, other)
+	require.NotEqual(t, hash, otherHash)
+
+	// Hashes are independent of the signing secret, so they survive its rotation.
+	require.Equal(t, hash, token.New("other-secret").HashRefreshToken(refreshToken))
+}
+
+func TestCartSession(t *testing.T) {
+	t.Parallel()
+
+	m := token.New(_secret)
+
+	session, hash, err := m.NewCartSession()
+	require.NoError(t, err)
+	require.Len(t, session, 43)
+	require.Equal(t, m.HashCartSession(session), hash)
+	require.NotEqual(t, session, hash)
+
+	other, _, err := m.NewCartSession()
+	require.NoError(t, err)
+	require.NotEqual(t, session, other)
+}